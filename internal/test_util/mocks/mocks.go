@@ -4,6 +4,8 @@ Package mocks provides mocked implementations of various interfaces for use in u
 package mocks
 
 import (
+	"context"
+
 	"github.com/go-zookeeper/zk"
 	"github.com/morphy76/zk/pkg/core"
 )
@@ -14,6 +16,7 @@ MockedStatusChangeListener is a mocked implementation of the StatusChangeListene
 type MockedStatusChangeListener struct {
 	ID           string
 	Interactions uint
+	Err          error
 }
 
 /*
@@ -28,7 +31,7 @@ OnStatusChange is a mocked implementation of the OnStatusChange method.
 */
 func (m *MockedStatusChangeListener) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
 	m.Interactions++
-	return nil
+	return m.Err
 }
 
 /*
@@ -65,3 +68,143 @@ Stop is a mocked implementation of the Stop method.
 */
 func (m *MockedShutdownListener) Stop() {
 }
+
+/*
+MockedSessionListener is a mocked implementation of the SessionListener interface, signalling the notifications on its channels when set.
+*/
+type MockedSessionListener struct {
+	ID            string
+	Expired       chan bool
+	Reestablished chan bool
+}
+
+/*
+UUID is a mocked implementation of the UUID method.
+*/
+func (m *MockedSessionListener) UUID() string {
+	return m.ID
+}
+
+/*
+OnSessionExpired is a mocked implementation of the OnSessionExpired method.
+*/
+func (m *MockedSessionListener) OnSessionExpired(zkFramework core.ZKFramework) error {
+	if m.Expired != nil {
+		m.Expired <- true
+	}
+	return nil
+}
+
+/*
+OnSessionReestablished is a mocked implementation of the OnSessionReestablished method.
+*/
+func (m *MockedSessionListener) OnSessionReestablished(zkFramework core.ZKFramework) error {
+	if m.Reestablished != nil {
+		m.Reestablished <- true
+	}
+	return nil
+}
+
+/*
+Stop is a mocked implementation of the Stop method.
+*/
+func (m *MockedSessionListener) Stop() {
+}
+
+/*
+MockedPrioritizedListener is a mocked status change and shutdown listener with a priority, recording the order of the notifications.
+*/
+type MockedPrioritizedListener struct {
+	ID       string
+	Rank     int
+	Notified *[]string
+}
+
+/*
+UUID is a mocked implementation of the UUID method.
+*/
+func (m *MockedPrioritizedListener) UUID() string {
+	return m.ID
+}
+
+/*
+Priority is a mocked implementation of the Priority method.
+*/
+func (m *MockedPrioritizedListener) Priority() int {
+	return m.Rank
+}
+
+/*
+OnStatusChange is a mocked implementation of the OnStatusChange method.
+*/
+func (m *MockedPrioritizedListener) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	*m.Notified = append(*m.Notified, m.ID)
+	return nil
+}
+
+/*
+OnShutdown is a mocked implementation of the OnShutdown method.
+*/
+func (m *MockedPrioritizedListener) OnShutdown(zkFramework core.ZKFramework) error {
+	*m.Notified = append(*m.Notified, m.ID)
+	return nil
+}
+
+/*
+Stop is a mocked implementation of the Stop method.
+*/
+func (m *MockedPrioritizedListener) Stop() {
+}
+
+/*
+MockedSlowListener is a mocked status change and shutdown listener blocking until its context is cancelled.
+*/
+type MockedSlowListener struct {
+	ID        string
+	Cancelled chan bool
+}
+
+/*
+UUID is a mocked implementation of the UUID method.
+*/
+func (m *MockedSlowListener) UUID() string {
+	return m.ID
+}
+
+/*
+OnStatusChange is a mocked implementation of the OnStatusChange method.
+*/
+func (m *MockedSlowListener) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	return m.OnStatusChangeWithContext(context.Background(), zkFramework, previous, current)
+}
+
+/*
+OnStatusChangeWithContext is a mocked implementation of the OnStatusChangeWithContext method.
+*/
+func (m *MockedSlowListener) OnStatusChangeWithContext(ctx context.Context, zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	<-ctx.Done()
+	m.Cancelled <- true
+	return ctx.Err()
+}
+
+/*
+OnShutdown is a mocked implementation of the OnShutdown method.
+*/
+func (m *MockedSlowListener) OnShutdown(zkFramework core.ZKFramework) error {
+	return m.OnShutdownWithContext(context.Background(), zkFramework)
+}
+
+/*
+OnShutdownWithContext is a mocked implementation of the OnShutdownWithContext method.
+*/
+func (m *MockedSlowListener) OnShutdownWithContext(ctx context.Context, zkFramework core.ZKFramework) error {
+	<-ctx.Done()
+	m.Cancelled <- true
+	return ctx.Err()
+}
+
+/*
+Stop is a mocked implementation of the Stop method.
+*/
+func (m *MockedSlowListener) Stop() {
+}
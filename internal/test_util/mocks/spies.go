@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+	"log/slog"
 	"time"
 
 	"github.com/go-zookeeper/zk"
@@ -49,6 +51,14 @@ func (s *SpiedFramework) AddStatusChangeListener(listener core.StatusChangeListe
 	return s.zkFramework.AddStatusChangeListener(listener)
 }
 
+/*
+SubscribeStatusChange subscribes a status change listener.
+*/
+func (s *SpiedFramework) SubscribeStatusChange(listener core.StatusChangeListener) (core.Subscription, error) {
+	s.Interactions["SubscribeStatusChange"]++
+	return s.zkFramework.SubscribeStatusChange(listener)
+}
+
 /*
 RemoveStatusChangeListener removes a status change listener.
 */
@@ -57,6 +67,14 @@ func (s *SpiedFramework) RemoveStatusChangeListener(listener core.StatusChangeLi
 	return s.zkFramework.RemoveStatusChangeListener(listener)
 }
 
+/*
+RemoveStatusChangeListenerByID removes a status change listener by its UUID.
+*/
+func (s *SpiedFramework) RemoveStatusChangeListenerByID(id string) error {
+	s.Interactions["RemoveStatusChangeListenerByID"]++
+	return s.zkFramework.RemoveStatusChangeListenerByID(id)
+}
+
 /*
 NotifyStatusChange notifies a status change.
 */
@@ -73,6 +91,14 @@ func (s *SpiedFramework) AddShutdownListener(listener core.ShutdownListener) err
 	return s.zkFramework.AddShutdownListener(listener)
 }
 
+/*
+SubscribeShutdown subscribes a shutdown listener.
+*/
+func (s *SpiedFramework) SubscribeShutdown(listener core.ShutdownListener) (core.Subscription, error) {
+	s.Interactions["SubscribeShutdown"]++
+	return s.zkFramework.SubscribeShutdown(listener)
+}
+
 /*
 RemoveShutdownListener removes a shutdown listener.
 */
@@ -81,6 +107,14 @@ func (s *SpiedFramework) RemoveShutdownListener(listener core.ShutdownListener)
 	return s.zkFramework.RemoveShutdownListener(listener)
 }
 
+/*
+RemoveShutdownListenerByID removes a shutdown listener by its UUID.
+*/
+func (s *SpiedFramework) RemoveShutdownListenerByID(id string) error {
+	s.Interactions["RemoveShutdownListenerByID"]++
+	return s.zkFramework.RemoveShutdownListenerByID(id)
+}
+
 /*
 NotifyShutdown notifies a shutdown.
 */
@@ -89,6 +123,38 @@ func (s *SpiedFramework) NotifyShutdown() {
 	s.zkFramework.NotifyShutdown()
 }
 
+/*
+AddSessionListener adds a session listener.
+*/
+func (s *SpiedFramework) AddSessionListener(listener core.SessionListener) error {
+	s.Interactions["AddSessionListener"]++
+	return s.zkFramework.AddSessionListener(listener)
+}
+
+/*
+SubscribeSession subscribes a session listener.
+*/
+func (s *SpiedFramework) SubscribeSession(listener core.SessionListener) (core.Subscription, error) {
+	s.Interactions["SubscribeSession"]++
+	return s.zkFramework.SubscribeSession(listener)
+}
+
+/*
+RemoveSessionListener removes a session listener.
+*/
+func (s *SpiedFramework) RemoveSessionListener(listener core.SessionListener) error {
+	s.Interactions["RemoveSessionListener"]++
+	return s.zkFramework.RemoveSessionListener(listener)
+}
+
+/*
+RemoveSessionListenerByID removes a session listener by its UUID.
+*/
+func (s *SpiedFramework) RemoveSessionListenerByID(id string) error {
+	s.Interactions["RemoveSessionListenerByID"]++
+	return s.zkFramework.RemoveSessionListenerByID(id)
+}
+
 /*
 Namespace gets the namespace.
 */
@@ -97,6 +163,30 @@ func (s *SpiedFramework) Namespace() string {
 	return s.zkFramework.Namespace()
 }
 
+/*
+OperationTimeout gets the deadline of the operations.
+*/
+func (s *SpiedFramework) OperationTimeout() time.Duration {
+	s.Interactions["OperationTimeout"]++
+	return s.zkFramework.OperationTimeout()
+}
+
+/*
+UsingOperationTimeout gets a view running the operations with the given deadline.
+*/
+func (s *SpiedFramework) UsingOperationTimeout(timeout time.Duration) core.ZKFramework {
+	s.Interactions["UsingOperationTimeout"]++
+	return s.zkFramework.UsingOperationTimeout(timeout)
+}
+
+/*
+UsingNamespace gets a view scoped to the child namespace.
+*/
+func (s *SpiedFramework) UsingNamespace(namespace string) core.ZKFramework {
+	s.Interactions["UsingNamespace"]++
+	return s.zkFramework.UsingNamespace(namespace)
+}
+
 /*
 Cn gets the Zookeeper connection.
 */
@@ -105,6 +195,14 @@ func (s *SpiedFramework) Cn() *zk.Conn {
 	return s.zkFramework.Cn()
 }
 
+/*
+Executor gets the executor of the requests on the Zookeeper connection.
+*/
+func (s *SpiedFramework) Executor() core.Executor {
+	s.Interactions["Executor"]++
+	return s.zkFramework.Executor()
+}
+
 /*
 URL gets the URL.
 */
@@ -113,6 +211,86 @@ func (s *SpiedFramework) URL() string {
 	return s.zkFramework.URL()
 }
 
+/*
+UpdateServers updates the Zookeeper servers.
+*/
+func (s *SpiedFramework) UpdateServers(hosts []string) error {
+	s.Interactions["UpdateServers"]++
+	return s.zkFramework.UpdateServers(hosts)
+}
+
+/*
+NegotiatedSessionTimeout returns the session timeout agreed with the Zookeeper server.
+*/
+func (s *SpiedFramework) NegotiatedSessionTimeout() time.Duration {
+	s.Interactions["NegotiatedSessionTimeout"]++
+	return s.zkFramework.NegotiatedSessionTimeout()
+}
+
+/*
+CircuitBreaker returns the circuit breaker guarding the operations.
+*/
+func (s *SpiedFramework) CircuitBreaker() core.CircuitBreaker {
+	s.Interactions["CircuitBreaker"]++
+	return s.zkFramework.CircuitBreaker()
+}
+
+/*
+ACLProvider returns the provider of the default ACL.
+*/
+func (s *SpiedFramework) ACLProvider() core.ACLProvider {
+	s.Interactions["ACLProvider"]++
+	return s.zkFramework.ACLProvider()
+}
+
+/*
+Extension gets the value attached to the framework with the key.
+*/
+func (s *SpiedFramework) Extension(key any, create func() any) any {
+	s.Interactions["Extension"]++
+	return s.zkFramework.Extension(key, create)
+}
+
+/*
+Logger returns the logger of the framework.
+*/
+func (s *SpiedFramework) Logger() *slog.Logger {
+	s.Interactions["Logger"]++
+	return s.zkFramework.Logger()
+}
+
+/*
+ConnectedServer returns the address of the ensemble member serving the session.
+*/
+func (s *SpiedFramework) ConnectedServer() string {
+	s.Interactions["ConnectedServer"]++
+	return s.zkFramework.ConnectedServer()
+}
+
+/*
+SessionID gets the ID of the current session.
+*/
+func (s *SpiedFramework) SessionID() int64 {
+	s.Interactions["SessionID"]++
+	return s.zkFramework.SessionID()
+}
+
+/*
+HealthCheck checks the health of the connection.
+*/
+func (s *SpiedFramework) HealthCheck(ctx context.Context) (core.Health, error) {
+	s.Interactions["HealthCheck"]++
+	return s.zkFramework.HealthCheck(ctx)
+}
+
+/*
+ConnectionStats returns the stats of the connection to the Zookeeper ensemble.
+*/
+func (s *SpiedFramework) ConnectionStats() core.ConnectionStats {
+	s.Interactions["ConnectionStats"]++
+	return s.zkFramework.ConnectionStats()
+}
+
 /*
 Started checks if the Zookeeper client is started.
 */
@@ -136,3 +314,67 @@ func (s *SpiedFramework) WaitConnection(timeout time.Duration) error {
 	s.Interactions["WaitConnection"]++
 	return s.zkFramework.WaitConnection(timeout)
 }
+
+/*
+StartAndWait starts the framework and waits for the connection.
+*/
+func (s *SpiedFramework) StartAndWait(timeout time.Duration) error {
+	s.Interactions["StartAndWait"]++
+	return s.zkFramework.StartAndWait(timeout)
+}
+
+/*
+StopGracefully stops the framework once the operations in flight completed.
+*/
+func (s *SpiedFramework) StopGracefully(ctx context.Context) error {
+	s.Interactions["StopGracefully"]++
+	return s.zkFramework.StopGracefully(ctx)
+}
+
+/*
+EnableAdminMode enables the admin mode.
+*/
+func (s *SpiedFramework) EnableAdminMode(superPassword string) error {
+	s.Interactions["EnableAdminMode"]++
+	return s.zkFramework.EnableAdminMode(superPassword)
+}
+
+/*
+AddAuth adds credentials to the session.
+*/
+func (s *SpiedFramework) AddAuth(scheme string, credentials []byte) error {
+	s.Interactions["AddAuth"]++
+	return s.zkFramework.AddAuth(scheme, credentials)
+}
+
+/*
+Failed checks if the Zookeeper client gave up reconnecting.
+*/
+func (s *SpiedFramework) Failed() bool {
+	s.Interactions["Failed"]++
+	return s.zkFramework.Failed()
+}
+
+/*
+ConnectionState returns the connection state derived by the framework.
+*/
+func (s *SpiedFramework) ConnectionState() core.ConnectionState {
+	s.Interactions["ConnectionState"]++
+	return s.zkFramework.ConnectionState()
+}
+
+/*
+Events returns a channel receiving the events of the framework.
+*/
+func (s *SpiedFramework) Events() <-chan core.FrameworkEvent {
+	s.Interactions["Events"]++
+	return s.zkFramework.Events()
+}
+
+/*
+AdminMode checks if the admin mode is enabled.
+*/
+func (s *SpiedFramework) AdminMode() bool {
+	s.Interactions["AdminMode"]++
+	return s.zkFramework.AdminMode()
+}
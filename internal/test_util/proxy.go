@@ -0,0 +1,103 @@
+package testutil
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+Proxy is a TCP proxy to the Zookeeper test server, closing it simulates the loss of the server.
+*/
+type Proxy struct {
+	listener net.Listener
+	target   string
+	conns    []net.Conn
+	blackout atomic.Bool
+	lock     sync.Mutex
+}
+
+/*
+StartProxy starts a TCP proxy to the given address, listening on a random local port.
+*/
+func StartProxy(target string) (*Proxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Proxy{
+		listener: listener,
+		target:   target,
+	}
+	go p.accept()
+	return p, nil
+}
+
+/*
+Addr returns the address the proxy listens on.
+*/
+func (p *Proxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+/*
+Close stops accepting connections and closes the proxied ones.
+*/
+func (p *Proxy) Close() {
+	p.listener.Close()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+}
+
+/*
+Blackout keeps the connections open but silently discards the traffic, simulating half-open connections.
+*/
+func (p *Proxy) Blackout() {
+	p.blackout.Store(true)
+}
+
+func (p *Proxy) accept() {
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		server, err := net.Dial("tcp", p.target)
+		if err != nil {
+			client.Close()
+			continue
+		}
+
+		p.lock.Lock()
+		p.conns = append(p.conns, client, server)
+		p.lock.Unlock()
+
+		go p.pipe(client, server)
+		go p.pipe(server, client)
+	}
+}
+
+func (p *Proxy) pipe(from net.Conn, to net.Conn) {
+	defer to.Close()
+	defer from.Close()
+
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := from.Read(buffer)
+		if err != nil {
+			return
+		}
+		if p.blackout.Load() {
+			continue
+		}
+		if _, err := to.Write(buffer[:n]); err != nil {
+			return
+		}
+	}
+}
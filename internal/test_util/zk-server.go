@@ -28,6 +28,7 @@ func StartTestServer() (testcontainers.Container, context.Context, error) {
 	req := testcontainers.ContainerRequest{
 		Image:        image,
 		ExposedPorts: []string{exposedPort},
+		Env:          map[string]string{"ZOO_4LW_COMMANDS_WHITELIST": "ruok,srvr"},
 		WaitingFor:   wait.ForListeningPort(exposedPort),
 	}
 	zkC, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
@@ -0,0 +1,187 @@
+/*
+Package acl provides builders, presets and validation for Zookeeper ACLs.
+*/
+package acl
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/acl/aclerr"
+)
+
+const (
+	// SchemeWorld is the scheme granting permissions to anyone.
+	SchemeWorld = "world"
+	// SchemeAuth is the scheme granting permissions to any authenticated identity of the creator.
+	SchemeAuth = "auth"
+	// SchemeDigest is the scheme granting permissions to a user:password identity.
+	SchemeDigest = "digest"
+	// SchemeIP is the scheme granting permissions to a client address or network.
+	SchemeIP = "ip"
+	// SchemeSASL is the scheme granting permissions to a Kerberos or SASL principal.
+	SchemeSASL = "sasl"
+	// SchemeX509 is the scheme granting permissions to the distinguished name of a client certificate.
+	SchemeX509 = "x509"
+	// SuperUser is the name of the Zookeeper super user.
+	SuperUser = "super"
+)
+
+/*
+Builder is a fluent builder for ACLs.
+*/
+type Builder struct {
+	entries []zk.ACL
+}
+
+/*
+NewBuilder creates a new ACL Builder.
+*/
+func NewBuilder() Builder {
+	return Builder{}
+}
+
+/*
+World grants the permissions to anyone.
+*/
+func (b Builder) World(perms int32) Builder {
+	return b.with(zk.ACL{Perms: perms, Scheme: SchemeWorld, ID: "anyone"})
+}
+
+/*
+Auth grants the permissions to the identities the creator is authenticated with.
+*/
+func (b Builder) Auth(perms int32) Builder {
+	return b.with(zk.ACL{Perms: perms, Scheme: SchemeAuth, ID: ""})
+}
+
+/*
+Digest grants the permissions to the user identified by the given password, the password is hashed client-side.
+*/
+func (b Builder) Digest(user string, password string, perms int32) Builder {
+	return b.with(zk.ACL{Perms: perms, Scheme: SchemeDigest, ID: DigestID(user, password)})
+}
+
+/*
+IP grants the permissions to the given client address or CIDR network.
+*/
+func (b Builder) IP(address string, perms int32) Builder {
+	return b.with(zk.ACL{Perms: perms, Scheme: SchemeIP, ID: address})
+}
+
+/*
+SASL grants the permissions to the given SASL principal, e.g. a Kerberos principal.
+*/
+func (b Builder) SASL(principal string, perms int32) Builder {
+	return b.with(zk.ACL{Perms: perms, Scheme: SchemeSASL, ID: principal})
+}
+
+/*
+X509 grants the permissions to the client certificate with the given distinguished name.
+*/
+func (b Builder) X509(distinguishedName string, perms int32) Builder {
+	return b.with(zk.ACL{Perms: perms, Scheme: SchemeX509, ID: distinguishedName})
+}
+
+/*
+Build validates and builds the ACL.
+*/
+func (b Builder) Build() ([]zk.ACL, error) {
+	if err := Validate(b.entries); err != nil {
+		return nil, err
+	}
+	return b.entries, nil
+}
+
+func (b Builder) with(entry zk.ACL) Builder {
+	b.entries = append(append([]zk.ACL{}, b.entries...), entry)
+	return b
+}
+
+/*
+DigestID computes the digest scheme ID of a user, user:base64(sha1(user:password)).
+*/
+func DigestID(user string, password string) string {
+	hash := sha1.Sum([]byte(user + ":" + password))
+	return user + ":" + base64.StdEncoding.EncodeToString(hash[:])
+}
+
+/*
+SuperDigest computes the value of the zookeeper.DigestAuthenticationProvider.superDigest server property for the given password.
+*/
+func SuperDigest(password string) string {
+	return DigestID(SuperUser, password)
+}
+
+/*
+OpenUnsafe is the preset granting all permissions to anyone.
+*/
+func OpenUnsafe() []zk.ACL {
+	return zk.WorldACL(zk.PermAll)
+}
+
+/*
+ReadUnsafe is the preset granting read permission to anyone.
+*/
+func ReadUnsafe() []zk.ACL {
+	return zk.WorldACL(zk.PermRead)
+}
+
+/*
+CreatorAll is the preset granting all permissions to the identities the creator is authenticated with.
+*/
+func CreatorAll() []zk.ACL {
+	return zk.AuthACL(zk.PermAll)
+}
+
+/*
+Validate checks that the ACL is not empty and that every entry has valid permissions, a scheme and an ID;
+the format of the ID is checked for the world, digest and ip schemes only, the other schemes, e.g. custom authentication providers, being accepted as is.
+*/
+func Validate(acl []zk.ACL) error {
+	if len(acl) == 0 {
+		return aclerr.ErrEmptyACL
+	}
+
+	for _, entry := range acl {
+		if entry.Perms <= 0 || entry.Perms&^zk.PermAll != 0 {
+			return aclerr.ErrInvalidPermissions
+		}
+		if err := validateID(entry.Scheme, entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateID(scheme string, id string) error {
+	switch scheme {
+	case SchemeWorld:
+		if id != "anyone" {
+			return aclerr.ErrInvalidID
+		}
+	case SchemeAuth:
+		return nil
+	case SchemeDigest:
+		user, hash, found := strings.Cut(id, ":")
+		if !found || user == "" || hash == "" {
+			return aclerr.ErrInvalidID
+		}
+	case SchemeIP:
+		if net.ParseIP(id) == nil {
+			if _, _, err := net.ParseCIDR(id); err != nil {
+				return aclerr.ErrInvalidID
+			}
+		}
+	case "":
+		return aclerr.ErrInvalidScheme
+	default:
+		if id == "" {
+			return aclerr.ErrInvalidID
+		}
+	}
+	return nil
+}
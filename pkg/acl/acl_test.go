@@ -0,0 +1,114 @@
+package acl_test
+
+import (
+	"testing"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/acl/aclerr"
+)
+
+func TestBuilder(t *testing.T) {
+	built, err := acl.NewBuilder().
+		World(zk.PermRead).
+		Digest("user", "password", zk.PermAll).
+		IP("10.0.0.0/8", zk.PermRead|zk.PermWrite).
+		Auth(zk.PermAdmin).
+		Build()
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(built) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(built))
+	}
+	if built[0].Scheme != acl.SchemeWorld || built[0].ID != "anyone" || built[0].Perms != zk.PermRead {
+		t.Errorf("unexpected world entry %v", built[0])
+	}
+	expectedDigest := zk.DigestACL(zk.PermAll, "user", "password")[0]
+	if built[1] != expectedDigest {
+		t.Errorf("expected %v, got %v", expectedDigest, built[1])
+	}
+}
+
+func TestBuilderSASLAndX509(t *testing.T) {
+	built, err := acl.NewBuilder().
+		SASL("client@EXAMPLE.COM", zk.PermRead).
+		X509("CN=client,O=example", zk.PermAll).
+		Build()
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(built) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(built))
+	}
+	if built[0] != (zk.ACL{Perms: zk.PermRead, Scheme: acl.SchemeSASL, ID: "client@EXAMPLE.COM"}) {
+		t.Errorf("unexpected sasl entry %v", built[0])
+	}
+	if built[1] != (zk.ACL{Perms: zk.PermAll, Scheme: acl.SchemeX509, ID: "CN=client,O=example"}) {
+		t.Errorf("unexpected x509 entry %v", built[1])
+	}
+
+	if _, err := acl.NewBuilder().SASL("", zk.PermAll).Build(); !aclerr.IsInvalidID(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrInvalidID, err)
+	}
+	if _, err := acl.NewBuilder().X509("", zk.PermAll).Build(); !aclerr.IsInvalidID(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrInvalidID, err)
+	}
+}
+
+func TestBuilderIsImmutable(t *testing.T) {
+	base := acl.NewBuilder().World(zk.PermRead)
+	first, _ := base.Auth(zk.PermAll).Build()
+	second, _ := base.IP("127.0.0.1", zk.PermAll).Build()
+
+	if first[1].Scheme != acl.SchemeAuth || second[1].Scheme != acl.SchemeIP {
+		t.Errorf("expected builders to be independent, got %v and %v", first, second)
+	}
+}
+
+func TestDigestID(t *testing.T) {
+	expected := zk.DigestACL(zk.PermAll, "super", "secret")[0].ID
+	if id := acl.DigestID("super", "secret"); id != expected {
+		t.Errorf("expected %s, got %s", expected, id)
+	}
+}
+
+func TestSuperDigest(t *testing.T) {
+	if digest := acl.SuperDigest("secret"); digest != acl.DigestID(acl.SuperUser, "secret") {
+		t.Errorf("unexpected super digest %s", digest)
+	}
+}
+
+func TestPresets(t *testing.T) {
+	for _, preset := range [][]zk.ACL{acl.OpenUnsafe(), acl.ReadUnsafe(), acl.CreatorAll()} {
+		if err := acl.Validate(preset); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := acl.Validate(nil); !aclerr.IsEmptyACL(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrEmptyACL, err)
+	}
+	if _, err := acl.NewBuilder().World(0).Build(); !aclerr.IsInvalidPermissions(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrInvalidPermissions, err)
+	}
+	if _, err := acl.NewBuilder().World(0x40).Build(); !aclerr.IsInvalidPermissions(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrInvalidPermissions, err)
+	}
+	if _, err := acl.NewBuilder().IP("not an address", zk.PermAll).Build(); !aclerr.IsInvalidID(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrInvalidID, err)
+	}
+	if err := acl.Validate([]zk.ACL{{Perms: zk.PermAll, Scheme: "", ID: "anyone"}}); !aclerr.IsInvalidScheme(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrInvalidScheme, err)
+	}
+	for _, scheme := range []string{acl.SchemeSASL, acl.SchemeX509, acl.SuperUser, "custom"} {
+		if err := acl.Validate([]zk.ACL{{Perms: zk.PermAll, Scheme: scheme, ID: "CN=client"}}); err != nil {
+			t.Errorf("expected scheme %s to be accepted, got %v", scheme, err)
+		}
+		if err := acl.Validate([]zk.ACL{{Perms: zk.PermAll, Scheme: scheme, ID: ""}}); !aclerr.IsInvalidID(err) {
+			t.Errorf("expected error %v, got %v", aclerr.ErrInvalidID, err)
+		}
+	}
+}
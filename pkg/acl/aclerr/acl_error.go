@@ -0,0 +1,54 @@
+/*
+Package aclerr provides error types for the acl package.
+*/
+package aclerr
+
+import "errors"
+
+/*
+ErrEmptyACL is returned when an ACL contains no entries.
+*/
+var ErrEmptyACL = errors.New("empty ACL")
+
+/*
+ErrInvalidPermissions is returned when an ACL entry grants no permissions or unknown ones.
+*/
+var ErrInvalidPermissions = errors.New("invalid ACL permissions")
+
+/*
+ErrInvalidScheme is returned when an ACL entry has no scheme.
+*/
+var ErrInvalidScheme = errors.New("invalid ACL scheme")
+
+/*
+ErrInvalidID is returned when an ACL entry ID is not valid for its scheme.
+*/
+var ErrInvalidID = errors.New("invalid ACL ID")
+
+/*
+IsEmptyACL checks if the error is ErrEmptyACL.
+*/
+func IsEmptyACL(err error) bool {
+	return err == ErrEmptyACL
+}
+
+/*
+IsInvalidPermissions checks if the error is ErrInvalidPermissions.
+*/
+func IsInvalidPermissions(err error) bool {
+	return err == ErrInvalidPermissions
+}
+
+/*
+IsInvalidScheme checks if the error is ErrInvalidScheme.
+*/
+func IsInvalidScheme(err error) bool {
+	return err == ErrInvalidScheme
+}
+
+/*
+IsInvalidID checks if the error is ErrInvalidID.
+*/
+func IsInvalidID(err error) bool {
+	return err == ErrInvalidID
+}
@@ -0,0 +1,64 @@
+package aclerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/acl/aclerr"
+)
+
+func TestIsEmptyACL(t *testing.T) {
+	err := aclerr.ErrEmptyACL
+	if !aclerr.IsEmptyACL(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsEmptyACLFalse(t *testing.T) {
+	err := errors.New("some error")
+	if aclerr.IsEmptyACL(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidPermissions(t *testing.T) {
+	err := aclerr.ErrInvalidPermissions
+	if !aclerr.IsInvalidPermissions(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidPermissionsFalse(t *testing.T) {
+	err := errors.New("some error")
+	if aclerr.IsInvalidPermissions(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidScheme(t *testing.T) {
+	err := aclerr.ErrInvalidScheme
+	if !aclerr.IsInvalidScheme(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidSchemeFalse(t *testing.T) {
+	err := errors.New("some error")
+	if aclerr.IsInvalidScheme(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidID(t *testing.T) {
+	err := aclerr.ErrInvalidID
+	if !aclerr.IsInvalidID(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidIDFalse(t *testing.T) {
+	err := errors.New("some error")
+	if aclerr.IsInvalidID(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,72 @@
+package acl
+
+import (
+	"path"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+Policy is the ACL policy applied to the nodes created under a subtree, see SubtreeProvider.SetSubtreePolicy.
+*/
+type Policy struct {
+	// DefaultACL is applied to nodes created without an explicit ACL.
+	DefaultACL []zk.ACL
+	// WarnOnWeakerACL is a flag to log a warning when an explicit ACL grants anyone more than DefaultACL does.
+	WarnOnWeakerACL bool
+}
+
+/*
+WeakerACLWarner is implemented by the ACL providers asking to log a warning when a node is created with an explicit ACL weaker than the one they give it.
+*/
+type WeakerACLWarner interface {
+	WarnOnWeakerACL(actualPath string) bool
+}
+
+/*
+DefaultFor returns the ACL of a node created without an explicit one: the one of the ACL provider of the framework, if any, otherwise the world ACL.
+*/
+func DefaultFor(aclProvider core.ACLProvider, actualPath string) []zk.ACL {
+	if aclProvider != nil {
+		return aclProvider.GetACLForPath(actualPath)
+	}
+	return zk.WorldACL(zk.PermAll)
+}
+
+/*
+IsWeakerThanDefault checks if the explicit ACL of a node is weaker than the default one, when the ACL provider asks to warn about it.
+*/
+func IsWeakerThanDefault(aclProvider core.ACLProvider, actualPath string, acl []zk.ACL) bool {
+	warner, ok := aclProvider.(WeakerACLWarner)
+	if !ok || !warner.WarnOnWeakerACL(actualPath) {
+		return false
+	}
+	return IsWeaker(acl, aclProvider.GetACLForPath(actualPath))
+}
+
+/*
+IsWeaker checks if the candidate ACL grants anyone a permission the reference ACL does not grant to anyone.
+*/
+func IsWeaker(candidate []zk.ACL, reference []zk.ACL) bool {
+	return worldPerms(candidate)&^worldPerms(reference) != 0
+}
+
+func worldPerms(acl []zk.ACL) int32 {
+	perms := int32(0)
+	for _, entry := range acl {
+		if entry.Scheme == SchemeWorld {
+			perms |= entry.Perms
+		}
+	}
+	return perms
+}
+
+func cleanNamespace(namespace string) string {
+	return path.Join("/", namespace)
+}
+
+func inNamespace(actualPath string, namespace string) bool {
+	return namespace == "/" || actualPath == namespace || strings.HasPrefix(actualPath, namespace+"/")
+}
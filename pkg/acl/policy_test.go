@@ -0,0 +1,78 @@
+package acl_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/acl/aclerr"
+)
+
+func TestSubtreePolicy(t *testing.T) {
+	provider, err := acl.NewSubtreeProvider(acl.OpenUnsafe())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	outer := acl.Policy{DefaultACL: acl.ReadUnsafe()}
+	inner := acl.Policy{DefaultACL: acl.CreatorAll(), WarnOnWeakerACL: true}
+	if err := provider.SetSubtreePolicy("outer", outer); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := provider.SetSubtreePolicy("/outer/inner/", inner); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if policy, ok := provider.PolicyFor("/outer/node"); !ok || policy.WarnOnWeakerACL {
+		t.Errorf("expected outer policy, got %v", policy)
+	}
+	if policy, ok := provider.PolicyFor("/outer/inner/node"); !ok || !policy.WarnOnWeakerACL {
+		t.Errorf("expected inner policy, got %v", policy)
+	}
+	if _, ok := provider.PolicyFor("/outerness/node"); ok {
+		t.Errorf("expected no policy")
+	}
+
+	if !acl.IsWeakerThanDefault(provider, "/outer/inner/node", acl.OpenUnsafe()) {
+		t.Errorf("expected open ACL to be weaker than the inner policy")
+	}
+	if acl.IsWeakerThanDefault(provider, "/outer/node", acl.OpenUnsafe()) {
+		t.Errorf("expected no warning outside the inner policy")
+	}
+}
+
+func TestInvalidSubtreePolicy(t *testing.T) {
+	provider, err := acl.NewSubtreeProvider(acl.OpenUnsafe())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := provider.SetSubtreePolicy("invalid", acl.Policy{}); !aclerr.IsEmptyACL(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrEmptyACL, err)
+	}
+}
+
+func TestDefaultFor(t *testing.T) {
+	if got := acl.DefaultFor(nil, "/node"); !slices.Equal(got, zk.WorldACL(zk.PermAll)) {
+		t.Errorf("expected the world ACL, got %v", got)
+	}
+
+	provider, err := acl.NewSubtreeProvider(acl.ReadUnsafe())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if got := acl.DefaultFor(provider, "/node"); !slices.Equal(got, acl.ReadUnsafe()) {
+		t.Errorf("expected %v, got %v", acl.ReadUnsafe(), got)
+	}
+	if acl.IsWeakerThanDefault(nil, "/node", acl.OpenUnsafe()) {
+		t.Errorf("expected no warning without a provider")
+	}
+}
+
+func TestIsWeaker(t *testing.T) {
+	if !acl.IsWeaker(acl.OpenUnsafe(), acl.CreatorAll()) {
+		t.Errorf("expected open ACL to be weaker than creator ACL")
+	}
+	if acl.IsWeaker(acl.ReadUnsafe(), zk.WorldACL(zk.PermRead|zk.PermWrite)) {
+		t.Errorf("expected read ACL not to be weaker than read-write ACL")
+	}
+}
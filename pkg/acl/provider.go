@@ -0,0 +1,102 @@
+package acl
+
+import (
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+)
+
+/*
+SubtreeProvider is an ACL provider, see framework.WithACLProvider, giving the nodes of each configured subtree the default ACL of its policy and the other nodes the default one.
+*/
+type SubtreeProvider struct {
+	defaultACL []zk.ACL
+	subtrees   map[string]Policy
+	lock       sync.RWMutex
+}
+
+/*
+NewSubtreeProvider creates a provider giving the default ACL to the nodes outside the configured subtrees.
+*/
+func NewSubtreeProvider(defaultACL []zk.ACL) (*SubtreeProvider, error) {
+	if err := Validate(defaultACL); err != nil {
+		return nil, err
+	}
+	return &SubtreeProvider{
+		defaultACL: defaultACL,
+		subtrees:   make(map[string]Policy),
+	}, nil
+}
+
+/*
+SetSubtreeACL gives the ACL to the nodes of the subtree at the given absolute path, the subtree node included, replacing any previous policy.
+*/
+func (p *SubtreeProvider) SetSubtreeACL(subtree string, acl []zk.ACL) error {
+	return p.SetSubtreePolicy(subtree, Policy{DefaultACL: acl})
+}
+
+/*
+SetSubtreePolicy applies the policy to the nodes of the subtree at the given absolute path, the subtree node included, replacing any previous one.
+*/
+func (p *SubtreeProvider) SetSubtreePolicy(subtree string, policy Policy) error {
+	if err := Validate(policy.DefaultACL); err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.subtrees[cleanNamespace(subtree)] = policy
+	return nil
+}
+
+/*
+RemoveSubtreeACL gives back the policy of the enclosing subtree, or the default ACL, to the nodes of the subtree.
+*/
+func (p *SubtreeProvider) RemoveSubtreeACL(subtree string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.subtrees, cleanNamespace(subtree))
+}
+
+/*
+PolicyFor returns the policy of the most specific subtree containing the given absolute path.
+*/
+func (p *SubtreeProvider) PolicyFor(actualPath string) (Policy, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	bestMatch := ""
+	found := false
+	for subtree := range p.subtrees {
+		if inNamespace(actualPath, subtree) && (!found || len(subtree) > len(bestMatch)) {
+			bestMatch = subtree
+			found = true
+		}
+	}
+	return p.subtrees[bestMatch], found
+}
+
+/*
+GetDefaultACL returns the ACL of the nodes outside the configured subtrees.
+*/
+func (p *SubtreeProvider) GetDefaultACL() []zk.ACL {
+	return p.defaultACL
+}
+
+/*
+GetACLForPath returns the default ACL of the policy of the most specific subtree containing the given absolute path, or the default one.
+*/
+func (p *SubtreeProvider) GetACLForPath(actualPath string) []zk.ACL {
+	if policy, found := p.PolicyFor(actualPath); found {
+		return policy.DefaultACL
+	}
+	return p.defaultACL
+}
+
+/*
+WarnOnWeakerACL tells whether the policy of the most specific subtree containing the given absolute path asks to warn about weaker explicit ACLs.
+*/
+func (p *SubtreeProvider) WarnOnWeakerACL(actualPath string) bool {
+	policy, found := p.PolicyFor(actualPath)
+	return found && policy.WarnOnWeakerACL
+}
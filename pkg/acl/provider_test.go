@@ -0,0 +1,57 @@
+package acl_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/acl/aclerr"
+)
+
+func TestSubtreeProvider(t *testing.T) {
+	provider, err := acl.NewSubtreeProvider(acl.ReadUnsafe())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := provider.SetSubtreeACL("secrets", acl.CreatorAll()); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := provider.SetSubtreeACL("/secrets/public/", acl.OpenUnsafe()); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	for actualPath, expected := range map[string][]zk.ACL{
+		"/config":             acl.ReadUnsafe(),
+		"/secrets":            acl.CreatorAll(),
+		"/secrets/key":        acl.CreatorAll(),
+		"/secrets/public/key": acl.OpenUnsafe(),
+		"/secretsness/key":    acl.ReadUnsafe(),
+	} {
+		if got := provider.GetACLForPath(actualPath); !slices.Equal(got, expected) {
+			t.Errorf("%s: expected %v, got %v", actualPath, expected, got)
+		}
+	}
+	if got := provider.GetDefaultACL(); !slices.Equal(got, acl.ReadUnsafe()) {
+		t.Errorf("expected %v, got %v", acl.ReadUnsafe(), got)
+	}
+
+	provider.RemoveSubtreeACL("/secrets/public")
+	if got := provider.GetACLForPath("/secrets/public/key"); !slices.Equal(got, acl.CreatorAll()) {
+		t.Errorf("expected %v, got %v", acl.CreatorAll(), got)
+	}
+}
+
+func TestInvalidSubtreeProvider(t *testing.T) {
+	if _, err := acl.NewSubtreeProvider(nil); !aclerr.IsEmptyACL(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrEmptyACL, err)
+	}
+
+	provider, err := acl.NewSubtreeProvider(acl.OpenUnsafe())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := provider.SetSubtreeACL("invalid", nil); !aclerr.IsEmptyACL(err) {
+		t.Errorf("expected error %v, got %v", aclerr.ErrEmptyACL, err)
+	}
+}
@@ -0,0 +1,75 @@
+package backup
+
+import "time"
+
+/*
+BackupOptions is used to configure the scheduled backup.
+*/
+type BackupOptions struct {
+	// Roots are the paths of the exported subtrees.
+	Roots []string
+	// Interval is the delay between two backups.
+	Interval time.Duration
+	// Retention is the number of snapshots kept for each root, zero keeps all of them.
+	Retention int
+}
+
+/*
+BackupOptionsBuilder is a builder for BackupOptions.
+*/
+type BackupOptionsBuilder struct {
+	roots     []string
+	interval  time.Duration
+	retention int
+}
+
+const (
+	defaultBackupInterval  = time.Hour
+	defaultBackupRetention = 24
+)
+
+/*
+NewBackupOptionsBuilder creates a new BackupOptionsBuilder, backing up the whole namespace every hour and keeping one day of snapshots.
+*/
+func NewBackupOptionsBuilder() BackupOptionsBuilder {
+	return BackupOptionsBuilder{
+		roots:     []string{""},
+		interval:  defaultBackupInterval,
+		retention: defaultBackupRetention,
+	}
+}
+
+/*
+WithRoots sets the paths of the exported subtrees.
+*/
+func (b BackupOptionsBuilder) WithRoots(roots ...string) BackupOptionsBuilder {
+	b.roots = roots
+	return b
+}
+
+/*
+WithInterval sets the delay between two backups.
+*/
+func (b BackupOptionsBuilder) WithInterval(interval time.Duration) BackupOptionsBuilder {
+	b.interval = interval
+	return b
+}
+
+/*
+WithRetention sets the number of snapshots kept for each root.
+*/
+func (b BackupOptionsBuilder) WithRetention(retention int) BackupOptionsBuilder {
+	b.retention = retention
+	return b
+}
+
+/*
+Build builds the BackupOptions.
+*/
+func (b BackupOptionsBuilder) Build() BackupOptions {
+	return BackupOptions{
+		Roots:     b.roots,
+		Interval:  b.interval,
+		Retention: b.retention,
+	}
+}
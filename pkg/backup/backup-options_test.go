@@ -0,0 +1,41 @@
+package backup_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/backup"
+)
+
+func TestDefaultBackupOptionsBuilder(t *testing.T) {
+	opts := backup.NewBackupOptionsBuilder().Build()
+
+	if !slices.Equal(opts.Roots, []string{""}) {
+		t.Errorf("Expected Roots to be the namespace root, got %v", opts.Roots)
+	}
+	if opts.Interval != time.Hour {
+		t.Errorf("Expected Interval to be %v, got %v", time.Hour, opts.Interval)
+	}
+	if opts.Retention != 24 {
+		t.Errorf("Expected Retention to be 24, got %d", opts.Retention)
+	}
+}
+
+func TestBackupOptionsBuilder(t *testing.T) {
+	opts := backup.NewBackupOptionsBuilder().
+		WithRoots("config", "services").
+		WithInterval(time.Minute).
+		WithRetention(3).
+		Build()
+
+	if !slices.Equal(opts.Roots, []string{"config", "services"}) {
+		t.Errorf("Expected Roots to be [config services], got %v", opts.Roots)
+	}
+	if opts.Interval != time.Minute {
+		t.Errorf("Expected Interval to be %v, got %v", time.Minute, opts.Interval)
+	}
+	if opts.Retention != 3 {
+		t.Errorf("Expected Retention to be 3, got %d", opts.Retention)
+	}
+}
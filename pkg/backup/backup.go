@@ -0,0 +1,192 @@
+/*
+Package backup exports subtrees as snapshots and writes them periodically to a pluggable store.
+*/
+package backup
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/morphy76/zk/pkg/backup/backuperr"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+const snapshotTimeFormat = "20060102T150405.000000000Z"
+
+/*
+Stats are the scheduled backup metrics.
+*/
+type Stats struct {
+	// Successes is the number of completed backups.
+	Successes int64
+	// Failures is the number of failed backups.
+	Failures int64
+	// LastSuccess is the time the last completed backup ended.
+	LastSuccess time.Time
+	// LastFailure is the time the last failed backup ended.
+	LastFailure time.Time
+	// LastError is the error of the last failed backup.
+	LastError error
+	// LastDuration is the duration of the last backup.
+	LastDuration time.Duration
+}
+
+/*
+Backup periodically exports subtrees and writes them to a store, applying the retention of each root.
+*/
+type Backup struct {
+	framework core.ZKFramework
+	store     Store
+	options   BackupOptions
+
+	stats   Stats
+	stop    chan bool
+	running bool
+	lock    sync.Mutex
+}
+
+/*
+NewBackup creates a scheduled backup, using the default options.
+*/
+func NewBackup(zkFramework core.ZKFramework, store Store) *Backup {
+	return NewBackupWithOptions(zkFramework, store, NewBackupOptionsBuilder().Build())
+}
+
+/*
+NewBackupWithOptions creates a scheduled backup, specifying the backup options.
+*/
+func NewBackupWithOptions(zkFramework core.ZKFramework, store Store, options BackupOptions) *Backup {
+	return &Backup{
+		framework: zkFramework,
+		store:     store,
+		options:   options,
+	}
+}
+
+/*
+SnapshotPrefix returns the prefix shared by the names of the snapshots of the given root.
+*/
+func SnapshotPrefix(root string) string {
+	return uriEncode(strings.Trim(root, "/"), true) + "@"
+}
+
+/*
+SnapshotName returns the name of the snapshot of the given root created at the given time, names of the same root sort by creation time.
+*/
+func SnapshotName(root string, created time.Time) string {
+	return SnapshotPrefix(root) + created.UTC().Format(snapshotTimeFormat) + ".json"
+}
+
+/*
+Start runs a backup immediately and then at every interval, until stopped.
+*/
+func (b *Backup) Start() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.running {
+		return backuperr.ErrBackupAlreadyStarted
+	}
+	b.running = true
+	b.stop = make(chan bool)
+
+	go b.schedule(b.stop)
+	return nil
+}
+
+/*
+Stop stops the scheduled backups, a running backup is completed.
+*/
+func (b *Backup) Stop() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.running {
+		return
+	}
+	b.running = false
+	close(b.stop)
+}
+
+/*
+Stats returns the backup metrics.
+*/
+func (b *Backup) Stats() Stats {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.stats
+}
+
+/*
+RunOnce exports every root and writes the snapshots to the store, then deletes the snapshots exceeding the retention.
+*/
+func (b *Backup) RunOnce() error {
+	start := time.Now()
+
+	errs := []error{}
+	for _, root := range b.options.Roots {
+		if err := b.backup(root); err != nil {
+			b.framework.Logger().Error("backup failed", "root", root, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	err := errors.Join(errs...)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.stats.LastDuration = time.Since(start)
+	if err != nil {
+		b.stats.Failures++
+		b.stats.LastFailure = time.Now()
+		b.stats.LastError = err
+	} else {
+		b.stats.Successes++
+		b.stats.LastSuccess = time.Now()
+	}
+	return err
+}
+
+func (b *Backup) schedule(stop chan bool) {
+	ticker := time.NewTicker(b.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		b.RunOnce()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *Backup) backup(root string) error {
+	snapshot, err := Export(b.framework, root)
+	if err != nil {
+		return err
+	}
+	data, err := snapshot.Encode()
+	if err != nil {
+		return err
+	}
+	if err := b.store.Put(SnapshotName(root, snapshot.Created), data); err != nil {
+		return err
+	}
+
+	if b.options.Retention <= 0 {
+		return nil
+	}
+	names, err := b.store.List(SnapshotPrefix(root))
+	if err != nil {
+		return err
+	}
+	for len(names) > b.options.Retention {
+		if err := b.store.Delete(names[0]); err != nil && !backuperr.IsSnapshotNotFound(err) {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package backup_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/backup"
+	"github.com/morphy76/zk/pkg/backup/backuperr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestSnapshotName(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	name := backup.SnapshotName("/config/app", created)
+	if name != "config%2Fapp@20240102T030405.000000000Z.json" {
+		t.Errorf("unexpected snapshot name %s", name)
+	}
+	if !strings.HasPrefix(name, backup.SnapshotPrefix("config/app")) {
+		t.Errorf("expected %s to start with the root prefix", name)
+	}
+}
+
+func TestExport(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	if err := operation.CreateWithOptions(zkFramework, root+"/a", operation.NewCreateOptionsBuilder().WithData([]byte("a")).Build()); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	snapshot, err := backup.Export(zkFramework, root)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if len(snapshot.Nodes) != 2 || snapshot.Nodes[1].Path != root+"/a" || string(snapshot.Nodes[1].Data) != "a" {
+		t.Errorf("unexpected snapshot nodes %v", snapshot.Nodes)
+	}
+
+	data, err := snapshot.Encode()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	decoded, err := backup.DecodeSnapshot(data)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if decoded.Root != root || len(decoded.Nodes) != 2 {
+		t.Errorf("unexpected decoded snapshot %v", decoded)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	if err := operation.Create(zkFramework, root); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	store, err := backup.NewDirStore(t.TempDir())
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	t.Run("Retention of the snapshots", func(t *testing.T) {
+		t.Log("Retention of the snapshots")
+		scheduled := backup.NewBackupWithOptions(zkFramework, store, backup.NewBackupOptionsBuilder().WithRoots(root).WithRetention(2).Build())
+
+		for i := 0; i < 3; i++ {
+			if err := scheduled.RunOnce(); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+
+		names, err := store.List(backup.SnapshotPrefix(root))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(names) != 2 {
+			t.Errorf("expected 2 snapshots, got %d", len(names))
+		}
+		if stats := scheduled.Stats(); stats.Successes != 3 || stats.Failures != 0 {
+			t.Errorf("expected 3 successes and no failures, got %+v", stats)
+		}
+	})
+
+	t.Run("Failed backups are counted", func(t *testing.T) {
+		t.Log("Failed backups are counted")
+		scheduled := backup.NewBackupWithOptions(zkFramework, store, backup.NewBackupOptionsBuilder().WithRoots(uuid.New().String()).Build())
+
+		if err := scheduled.RunOnce(); err == nil {
+			t.Error("expected error to be not nil")
+		}
+		if stats := scheduled.Stats(); stats.Failures != 1 || stats.LastError == nil {
+			t.Errorf("expected 1 failure, got %+v", stats)
+		}
+	})
+
+	t.Run("Start twice", func(t *testing.T) {
+		t.Log("Start twice")
+		scheduled := backup.NewBackupWithOptions(zkFramework, store, backup.NewBackupOptionsBuilder().WithRoots(root).Build())
+
+		if err := scheduled.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer scheduled.Stop()
+		if err := scheduled.Start(); !backuperr.IsBackupAlreadyStarted(err) {
+			t.Errorf("expected error %v, got %v", backuperr.ErrBackupAlreadyStarted, err)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+/*
+Package backuperr provides error types for the backup package.
+*/
+package backuperr
+
+import "errors"
+
+/*
+ErrSnapshotNotFound is returned when a snapshot is missing from the store.
+*/
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+/*
+ErrInvalidSnapshotName is returned when a snapshot name is empty or contains a path separator.
+*/
+var ErrInvalidSnapshotName = errors.New("invalid snapshot name")
+
+/*
+ErrStoreRequestFailed is returned when the remote store rejects a request.
+*/
+var ErrStoreRequestFailed = errors.New("store request failed")
+
+/*
+IsSnapshotNotFound checks if the error is ErrSnapshotNotFound.
+*/
+func IsSnapshotNotFound(err error) bool {
+	return err == ErrSnapshotNotFound
+}
+
+/*
+IsInvalidSnapshotName checks if the error is ErrInvalidSnapshotName.
+*/
+func IsInvalidSnapshotName(err error) bool {
+	return err == ErrInvalidSnapshotName
+}
+
+/*
+IsStoreRequestFailed checks if the error is, or wraps, ErrStoreRequestFailed.
+*/
+func IsStoreRequestFailed(err error) bool {
+	return errors.Is(err, ErrStoreRequestFailed)
+}
+
+/*
+ErrBackupAlreadyStarted is returned when a scheduled backup is started twice.
+*/
+var ErrBackupAlreadyStarted = errors.New("backup already started")
+
+/*
+IsBackupAlreadyStarted checks if the error is ErrBackupAlreadyStarted.
+*/
+func IsBackupAlreadyStarted(err error) bool {
+	return err == ErrBackupAlreadyStarted
+}
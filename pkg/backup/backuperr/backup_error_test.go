@@ -0,0 +1,65 @@
+package backuperr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/backup/backuperr"
+)
+
+func TestIsSnapshotNotFound(t *testing.T) {
+	err := backuperr.ErrSnapshotNotFound
+	if !backuperr.IsSnapshotNotFound(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsSnapshotNotFoundFalse(t *testing.T) {
+	err := errors.New("some error")
+	if backuperr.IsSnapshotNotFound(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidSnapshotName(t *testing.T) {
+	err := backuperr.ErrInvalidSnapshotName
+	if !backuperr.IsInvalidSnapshotName(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidSnapshotNameFalse(t *testing.T) {
+	err := errors.New("some error")
+	if backuperr.IsInvalidSnapshotName(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsStoreRequestFailed(t *testing.T) {
+	err := fmt.Errorf("%w: 403 Forbidden", backuperr.ErrStoreRequestFailed)
+	if !backuperr.IsStoreRequestFailed(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsStoreRequestFailedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if backuperr.IsStoreRequestFailed(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsBackupAlreadyStarted(t *testing.T) {
+	err := backuperr.ErrBackupAlreadyStarted
+	if !backuperr.IsBackupAlreadyStarted(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsBackupAlreadyStartedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if backuperr.IsBackupAlreadyStarted(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,116 @@
+package backup
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+ChangeType is the type of a difference between two snapshots.
+*/
+type ChangeType int
+
+const (
+	// NodeAdded is a node missing from the first snapshot.
+	NodeAdded ChangeType = iota
+	// NodeRemoved is a node missing from the second snapshot.
+	NodeRemoved
+	// NodeChanged is a node with different data in the two snapshots.
+	NodeChanged
+)
+
+/*
+Change is a difference between two snapshots.
+*/
+type Change struct {
+	// Type is the type of the difference.
+	Type ChangeType
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// Old is the data of the node in the first snapshot, nil when added.
+	Old []byte
+	// New is the data of the node in the second snapshot, nil when removed.
+	New []byte
+}
+
+/*
+Diff compares two snapshots, returning the changes turning the first into the second, sorted by path.
+*/
+func Diff(from Snapshot, to Snapshot) []Change {
+	fromNodes := make(map[string][]byte, len(from.Nodes))
+	for _, node := range from.Nodes {
+		fromNodes[node.Path] = node.Data
+	}
+
+	changes := []Change{}
+	for _, node := range to.Nodes {
+		old, found := fromNodes[node.Path]
+		switch {
+		case !found:
+			changes = append(changes, Change{Type: NodeAdded, Path: node.Path, New: node.Data})
+		case !bytes.Equal(old, node.Data):
+			changes = append(changes, Change{Type: NodeChanged, Path: node.Path, Old: old, New: node.Data})
+		}
+		delete(fromNodes, node.Path)
+	}
+	for nodePath, old := range fromNodes {
+		changes = append(changes, Change{Type: NodeRemoved, Path: nodePath, Old: old})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+/*
+DiffLive compares the live subtree with the snapshot, returning the changes restoring the snapshot.
+*/
+func DiffLive(zkFramework core.ZKFramework, snapshot Snapshot) ([]Change, error) {
+	live, err := Export(zkFramework, snapshot.Root)
+	if err != nil {
+		return nil, err
+	}
+	return Diff(live, snapshot), nil
+}
+
+/*
+Restore applies the given changes to the live tree, e.g. a selection of the changes returned by DiffLive.
+
+Added nodes are created parents first and removed nodes are deleted children first, the first failing change stops the restore.
+*/
+func Restore(zkFramework core.ZKFramework, changes []Change) error {
+	ordered := append([]Change{}, changes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Type == NodeRemoved && ordered[j].Type == NodeRemoved {
+			return ordered[i].Path > ordered[j].Path
+		}
+		if ordered[i].Type == NodeRemoved || ordered[j].Type == NodeRemoved {
+			return ordered[j].Type == NodeRemoved
+		}
+		return ordered[i].Path < ordered[j].Path
+	})
+
+	for _, change := range ordered {
+		var err error
+		switch change.Type {
+		case NodeAdded:
+			err = operation.CreateWithOptions(zkFramework, change.Path, operation.NewCreateOptionsBuilder().WithData(change.New).Build())
+		case NodeChanged:
+			_, err = operation.Update(zkFramework, change.Path, change.New)
+		case NodeRemoved:
+			err = operation.Delete(zkFramework, change.Path)
+			if coreerr.IsUnknownNode(err) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
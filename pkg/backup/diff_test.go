@@ -0,0 +1,94 @@
+package backup_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/backup"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestDiff(t *testing.T) {
+	from := backup.Snapshot{Nodes: []backup.Node{
+		{Path: "a", Data: []byte("1")},
+		{Path: "a/b", Data: []byte("2")},
+		{Path: "a/c", Data: []byte("3")},
+	}}
+	to := backup.Snapshot{Nodes: []backup.Node{
+		{Path: "a", Data: []byte("1")},
+		{Path: "a/b", Data: []byte("changed")},
+		{Path: "a/d", Data: []byte("4")},
+	}}
+
+	changes := backup.Diff(from, to)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %v", changes)
+	}
+	if changes[0].Type != backup.NodeChanged || changes[0].Path != "a/b" || string(changes[0].Old) != "2" || string(changes[0].New) != "changed" {
+		t.Errorf("unexpected change %+v", changes[0])
+	}
+	if changes[1].Type != backup.NodeRemoved || changes[1].Path != "a/c" {
+		t.Errorf("unexpected change %+v", changes[1])
+	}
+	if changes[2].Type != backup.NodeAdded || changes[2].Path != "a/d" {
+		t.Errorf("unexpected change %+v", changes[2])
+	}
+}
+
+func TestSelectiveRestore(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for _, nodeName := range []string{root + "/a", root + "/b"} {
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte("original")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+
+	snapshot, err := backup.Export(zkFramework, root)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	for _, nodeName := range []string{root + "/a", root + "/b"} {
+		if _, err := operation.Update(zkFramework, nodeName, []byte("modified")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+	if err := operation.Create(zkFramework, root+"/c"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	changes, err := backup.DiffLive(zkFramework, snapshot)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %v", changes)
+	}
+
+	selected := []backup.Change{}
+	for _, change := range changes {
+		if change.Path != root+"/b" {
+			selected = append(selected, change)
+		}
+	}
+	if err := backup.Restore(zkFramework, selected); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	if data, _ := operation.Get(zkFramework, root+"/a"); string(data) != "original" {
+		t.Errorf("expected original, got %s", data)
+	}
+	if data, _ := operation.Get(zkFramework, root+"/b"); string(data) != "modified" {
+		t.Errorf("expected modified, got %s", data)
+	}
+	if exists, _ := operation.Exists(zkFramework, root+"/c"); exists {
+		t.Error("expected the added node to be removed")
+	}
+}
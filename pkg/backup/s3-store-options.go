@@ -0,0 +1,90 @@
+package backup
+
+import "net/http"
+
+/*
+S3StoreOptions is used to configure the S3-compatible store.
+*/
+type S3StoreOptions struct {
+	// Region is the region used to sign the requests.
+	Region string
+	// Prefix is prepended to the snapshot names to build the object keys.
+	Prefix string
+	// AccessKeyID is the access key used to sign the requests.
+	AccessKeyID string
+	// SecretAccessKey is the secret key used to sign the requests.
+	SecretAccessKey string
+	// Client is the HTTP client sending the requests.
+	Client *http.Client
+}
+
+/*
+S3StoreOptionsBuilder is a builder for S3StoreOptions.
+*/
+type S3StoreOptionsBuilder struct {
+	region          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	client          *http.Client
+}
+
+const (
+	defaultS3Region = "us-east-1"
+)
+
+/*
+NewS3StoreOptionsBuilder creates a new S3StoreOptionsBuilder.
+*/
+func NewS3StoreOptionsBuilder() S3StoreOptionsBuilder {
+	return S3StoreOptionsBuilder{
+		region: defaultS3Region,
+		client: http.DefaultClient,
+	}
+}
+
+/*
+WithRegion sets the region used to sign the requests.
+*/
+func (b S3StoreOptionsBuilder) WithRegion(region string) S3StoreOptionsBuilder {
+	b.region = region
+	return b
+}
+
+/*
+WithPrefix sets the prefix prepended to the snapshot names to build the object keys.
+*/
+func (b S3StoreOptionsBuilder) WithPrefix(prefix string) S3StoreOptionsBuilder {
+	b.prefix = prefix
+	return b
+}
+
+/*
+WithCredentials sets the keys used to sign the requests.
+*/
+func (b S3StoreOptionsBuilder) WithCredentials(accessKeyID string, secretAccessKey string) S3StoreOptionsBuilder {
+	b.accessKeyID = accessKeyID
+	b.secretAccessKey = secretAccessKey
+	return b
+}
+
+/*
+WithClient sets the HTTP client sending the requests.
+*/
+func (b S3StoreOptionsBuilder) WithClient(client *http.Client) S3StoreOptionsBuilder {
+	b.client = client
+	return b
+}
+
+/*
+Build builds the S3StoreOptions.
+*/
+func (b S3StoreOptionsBuilder) Build() S3StoreOptions {
+	return S3StoreOptions{
+		Region:          b.region,
+		Prefix:          b.prefix,
+		AccessKeyID:     b.accessKeyID,
+		SecretAccessKey: b.secretAccessKey,
+		Client:          b.client,
+	}
+}
@@ -0,0 +1,227 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/morphy76/zk/pkg/backup/backuperr"
+)
+
+/*
+S3Store is a Store keeping each snapshot as an object of a bucket of an S3-compatible service, using path-style requests signed with AWS Signature Version 4.
+*/
+type S3Store struct {
+	endpoint string
+	bucket   string
+	options  S3StoreOptions
+}
+
+/*
+NewS3Store creates a store in the given bucket of the S3-compatible service at the given endpoint, e.g. https://s3.eu-west-1.amazonaws.com.
+*/
+func NewS3Store(endpoint string, bucket string, options S3StoreOptions) *S3Store {
+	return &S3Store{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		bucket:   bucket,
+		options:  options,
+	}
+}
+
+/*
+Put uploads the snapshot object.
+*/
+func (s *S3Store) Put(name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	_, err := s.do(http.MethodPut, s.options.Prefix+name, nil, data)
+	return err
+}
+
+/*
+Get downloads the snapshot object.
+*/
+func (s *S3Store) Get(name string) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	return s.do(http.MethodGet, s.options.Prefix+name, nil, nil)
+}
+
+/*
+List lists the snapshot objects starting with the given prefix.
+*/
+func (s *S3Store) List(prefix string) ([]string, error) {
+	names := []string{}
+	continuationToken := ""
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", s.options.Prefix+prefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		body, err := s.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, content := range result.Contents {
+			names = append(names, strings.TrimPrefix(content.Key, s.options.Prefix))
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+/*
+Delete deletes the snapshot object.
+*/
+func (s *S3Store) Delete(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	_, err := s.do(http.MethodDelete, s.options.Prefix+name, nil, nil)
+	return err
+}
+
+func (s *S3Store) do(method string, key string, query url.Values, payload []byte) ([]byte, error) {
+	requestURL, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	requestURL.Path = "/" + s.bucket
+	if key != "" {
+		requestURL.Path += "/" + key
+	}
+	requestURL.RawPath = uriEncode(requestURL.Path, false)
+	requestURL.RawQuery = canonicalQuery(query)
+
+	request, err := http.NewRequest(method, requestURL.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(request, requestURL, payload, time.Now().UTC())
+
+	response, err := s.options.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound && key != "" {
+		return nil, backuperr.ErrSnapshotNotFound
+	}
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%w: %s %s: %s", backuperr.ErrStoreRequestFailed, method, requestURL.Path, response.Status)
+	}
+	return body, nil
+}
+
+func (s *S3Store) sign(request *http.Request, requestURL *url.URL, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		requestURL.RawPath,
+		requestURL.RawQuery,
+		"host:" + requestURL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.options.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.options.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.options.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.options.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, uriEncode(key, true)+"="+uriEncode(query.Get(key), true))
+	}
+	return strings.Join(parts, "&")
+}
+
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
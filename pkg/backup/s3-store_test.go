@@ -0,0 +1,114 @@
+package backup_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/backup"
+	"github.com/morphy76/zk/pkg/backup/backuperr"
+)
+
+type fakeS3 struct {
+	objects map[string][]byte
+	lock    sync.Mutex
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") || r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/bucket":
+		prefix := r.URL.Query().Get("prefix")
+		keys := []string{}
+		for key := range f.objects {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, "<ListBucketResult><IsTruncated>false</IsTruncated>")
+		for _, key := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+		}
+		fmt.Fprint(w, "</ListBucketResult>")
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[key] = data
+	case r.Method == http.MethodGet:
+		data, found := f.objects[key]
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	defer server.Close()
+
+	options := backup.NewS3StoreOptionsBuilder().
+		WithPrefix("zk/").
+		WithCredentials("access", "secret").
+		Build()
+	store := backup.NewS3Store(server.URL, "bucket", options)
+
+	for _, name := range []string{"a@1.json", "a@2.json", "b@1.json"} {
+		if err := store.Put(name, []byte(name)); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+
+	names, err := store.List("a@")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if !slices.Equal(names, []string{"a@1.json", "a@2.json"}) {
+		t.Errorf("expected [a@1.json a@2.json], got %v", names)
+	}
+
+	data, err := store.Get("a@2.json")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if string(data) != "a@2.json" {
+		t.Errorf("expected a@2.json, got %s", data)
+	}
+
+	if err := store.Delete("a@2.json"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if _, err := store.Get("a@2.json"); !backuperr.IsSnapshotNotFound(err) {
+		t.Errorf("expected error %v, got %v", backuperr.ErrSnapshotNotFound, err)
+	}
+}
+
+func TestS3StoreRejectedRequest(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{objects: make(map[string][]byte)})
+	defer server.Close()
+
+	options := backup.NewS3StoreOptionsBuilder().WithCredentials("other", "secret").Build()
+	store := backup.NewS3Store(server.URL, "bucket", options)
+
+	if err := store.Put("a@1.json", []byte{}); !backuperr.IsStoreRequestFailed(err) {
+		t.Errorf("expected error %v, got %v", backuperr.ErrStoreRequestFailed, err)
+	}
+}
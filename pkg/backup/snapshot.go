@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+Node is a node exported in a snapshot.
+*/
+type Node struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string `json:"path"`
+	// Data is the data of the node.
+	Data []byte `json:"data"`
+}
+
+/*
+Snapshot is the exported content of a subtree.
+*/
+type Snapshot struct {
+	// Root is the path of the exported subtree, relative to the namespace.
+	Root string `json:"root"`
+	// Created is the time the export started.
+	Created time.Time `json:"created"`
+	// Nodes are the exported nodes, parents before their children.
+	Nodes []Node `json:"nodes"`
+}
+
+/*
+Export walks the subtree at the given path and exports its persistent nodes, ephemeral nodes are skipped.
+*/
+func Export(zkFramework core.ZKFramework, root string) (Snapshot, error) {
+	snapshot := Snapshot{
+		Root:    root,
+		Created: time.Now(),
+		Nodes:   []Node{},
+	}
+	if err := export(zkFramework, root, &snapshot); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+/*
+Encode encodes the snapshot as JSON.
+*/
+func (s Snapshot) Encode() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+/*
+DecodeSnapshot decodes a snapshot encoded as JSON.
+*/
+func DecodeSnapshot(data []byte) (Snapshot, error) {
+	var snapshot Snapshot
+	err := json.Unmarshal(data, &snapshot)
+	return snapshot, err
+}
+
+func export(zkFramework core.ZKFramework, nodeName string, snapshot *Snapshot) error {
+	data, stat, err := operation.GetWithStat(zkFramework, nodeName)
+	if err != nil {
+		return err
+	}
+	if stat.EphemeralOwner != 0 {
+		return nil
+	}
+	snapshot.Nodes = append(snapshot.Nodes, Node{Path: nodeName, Data: data})
+
+	if stat.NumChildren == 0 {
+		return nil
+	}
+	children, err := operation.Ls(zkFramework, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := export(zkFramework, path.Join(nodeName, child), snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/morphy76/zk/pkg/backup/backuperr"
+)
+
+/*
+Store persists encoded snapshots by name.
+*/
+type Store interface {
+	// Put writes the snapshot with the given name, replacing any previous one.
+	Put(name string, data []byte) error
+	// Get reads the snapshot with the given name.
+	Get(name string) ([]byte, error)
+	// List lists the names of the stored snapshots starting with the given prefix, sorted.
+	List(prefix string) ([]string, error)
+	// Delete deletes the snapshot with the given name.
+	Delete(name string) error
+}
+
+/*
+DirStore is a Store keeping each snapshot as a file of a local directory.
+*/
+type DirStore struct {
+	dir string
+}
+
+/*
+NewDirStore creates a store in the given directory, creating the directory if missing.
+*/
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+/*
+Put writes the snapshot file, replacing it atomically.
+*/
+func (s *DirStore) Put(name string, data []byte) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	tmp := filepath.Join(s.dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, name))
+}
+
+/*
+Get reads the snapshot file.
+*/
+func (s *DirStore) Get(name string) ([]byte, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil, backuperr.ErrSnapshotNotFound
+	}
+	return data, err
+}
+
+/*
+List lists the snapshot files starting with the given prefix.
+*/
+func (s *DirStore) List(prefix string) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+/*
+Delete deletes the snapshot file.
+*/
+func (s *DirStore) Delete(name string) error {
+	if err := validateName(name); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return backuperr.ErrSnapshotNotFound
+	}
+	return err
+}
+
+func validateName(name string) error {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return backuperr.ErrInvalidSnapshotName
+	}
+	return nil
+}
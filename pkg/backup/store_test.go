@@ -0,0 +1,48 @@
+package backup_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/backup"
+	"github.com/morphy76/zk/pkg/backup/backuperr"
+)
+
+func TestDirStore(t *testing.T) {
+	store, err := backup.NewDirStore(t.TempDir())
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	for _, name := range []string{"a@1.json", "a@2.json", "b@1.json"} {
+		if err := store.Put(name, []byte(name)); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+
+	names, err := store.List("a@")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if !slices.Equal(names, []string{"a@1.json", "a@2.json"}) {
+		t.Errorf("expected [a@1.json a@2.json], got %v", names)
+	}
+
+	data, err := store.Get("b@1.json")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if string(data) != "b@1.json" {
+		t.Errorf("expected b@1.json, got %s", data)
+	}
+
+	if err := store.Delete("b@1.json"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if _, err := store.Get("b@1.json"); !backuperr.IsSnapshotNotFound(err) {
+		t.Errorf("expected error %v, got %v", backuperr.ErrSnapshotNotFound, err)
+	}
+	if err := store.Put("../escape", []byte{}); !backuperr.IsInvalidSnapshotName(err) {
+		t.Errorf("expected error %v, got %v", backuperr.ErrInvalidSnapshotName, err)
+	}
+}
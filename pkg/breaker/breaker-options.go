@@ -0,0 +1,89 @@
+package breaker
+
+import "time"
+
+/*
+BreakerOptions is used to configure the circuit breaker.
+*/
+type BreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures tripping the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before letting probe operations through.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of successful probe operations closing the breaker again.
+	HalfOpenProbes int
+	// IsFailure decides which operation errors count as failures of the ensemble.
+	IsFailure func(err error) bool
+}
+
+/*
+BreakerOptionsBuilder is a builder for BreakerOptions.
+*/
+type BreakerOptionsBuilder struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenProbes   int
+	isFailure        func(err error) bool
+}
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = 5 * time.Second
+	defaultHalfOpenProbes   = 1
+)
+
+/*
+NewBreakerOptionsBuilder creates a new BreakerOptionsBuilder.
+*/
+func NewBreakerOptionsBuilder() BreakerOptionsBuilder {
+	return BreakerOptionsBuilder{
+		failureThreshold: defaultFailureThreshold,
+		openTimeout:      defaultOpenTimeout,
+		halfOpenProbes:   defaultHalfOpenProbes,
+		isFailure:        IsConnectionFailure,
+	}
+}
+
+/*
+WithFailureThreshold sets the number of consecutive failures tripping the breaker.
+*/
+func (b BreakerOptionsBuilder) WithFailureThreshold(failureThreshold int) BreakerOptionsBuilder {
+	b.failureThreshold = failureThreshold
+	return b
+}
+
+/*
+WithOpenTimeout sets how long the breaker stays open before letting probe operations through.
+*/
+func (b BreakerOptionsBuilder) WithOpenTimeout(openTimeout time.Duration) BreakerOptionsBuilder {
+	b.openTimeout = openTimeout
+	return b
+}
+
+/*
+WithHalfOpenProbes sets the number of successful probe operations closing the breaker again.
+*/
+func (b BreakerOptionsBuilder) WithHalfOpenProbes(halfOpenProbes int) BreakerOptionsBuilder {
+	b.halfOpenProbes = halfOpenProbes
+	return b
+}
+
+/*
+WithIsFailure sets the function deciding which operation errors count as failures of the ensemble.
+*/
+func (b BreakerOptionsBuilder) WithIsFailure(isFailure func(err error) bool) BreakerOptionsBuilder {
+	b.isFailure = isFailure
+	return b
+}
+
+/*
+Build builds the BreakerOptions.
+*/
+func (b BreakerOptionsBuilder) Build() BreakerOptions {
+	return BreakerOptions{
+		FailureThreshold: b.failureThreshold,
+		OpenTimeout:      b.openTimeout,
+		HalfOpenProbes:   b.halfOpenProbes,
+		IsFailure:        b.isFailure,
+	}
+}
@@ -0,0 +1,48 @@
+package breaker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/breaker"
+)
+
+func TestDefaultBreakerOptionsBuilder(t *testing.T) {
+	opts := breaker.NewBreakerOptionsBuilder().Build()
+
+	if opts.FailureThreshold != 5 {
+		t.Errorf("Expected FailureThreshold to be 5, got %d", opts.FailureThreshold)
+	}
+	if opts.OpenTimeout != 5*time.Second {
+		t.Errorf("Expected OpenTimeout to be %v, got %v", 5*time.Second, opts.OpenTimeout)
+	}
+	if opts.HalfOpenProbes != 1 {
+		t.Errorf("Expected HalfOpenProbes to be 1, got %d", opts.HalfOpenProbes)
+	}
+	if opts.IsFailure == nil {
+		t.Errorf("Expected IsFailure to be set")
+	}
+}
+
+func TestBreakerOptionsBuilder(t *testing.T) {
+	opts := breaker.NewBreakerOptionsBuilder().
+		WithFailureThreshold(2).
+		WithOpenTimeout(time.Second).
+		WithHalfOpenProbes(3).
+		WithIsFailure(func(err error) bool { return err != nil }).
+		Build()
+
+	if opts.FailureThreshold != 2 {
+		t.Errorf("Expected FailureThreshold to be 2, got %d", opts.FailureThreshold)
+	}
+	if opts.OpenTimeout != time.Second {
+		t.Errorf("Expected OpenTimeout to be %v, got %v", time.Second, opts.OpenTimeout)
+	}
+	if opts.HalfOpenProbes != 3 {
+		t.Errorf("Expected HalfOpenProbes to be 3, got %d", opts.HalfOpenProbes)
+	}
+	if !opts.IsFailure(errors.New("some error")) {
+		t.Errorf("Expected IsFailure to be the given function")
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Package breaker provides a circuit breaker protecting applications from piling operations onto an unavailable Zookeeper ensemble.
+*/
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/morphy76/zk/pkg/breaker/breakererr"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+State is the state of a circuit breaker.
+*/
+type State int
+
+const (
+	// Closed lets every operation through.
+	Closed State = iota
+	// Open fails every operation fast.
+	Open
+	// HalfOpen lets a limited number of probe operations through to test the ensemble.
+	HalfOpen
+)
+
+/*
+String returns the name of the state.
+*/
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+IsConnectionFailure is the default failure classifier, counting the transient errors as classified by operr.IsTransient; errors about nodes prove the ensemble is reachable.
+*/
+func IsConnectionFailure(err error) bool {
+	return operr.IsTransient(err)
+}
+
+/*
+Breaker is a circuit breaker tripping after consecutive failures, set on a framework with framework.WithCircuitBreaker.
+
+While open, operations fail fast with breakererr.ErrCircuitOpen. After the open timeout the breaker half-opens and lets probe operations through:
+enough successful probes close it, a failed probe opens it again.
+*/
+type Breaker struct {
+	options BreakerOptions
+
+	state     State
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+	lock      sync.Mutex
+}
+
+/*
+NewBreaker creates a new circuit breaker with the default options.
+*/
+func NewBreaker() *Breaker {
+	return NewBreakerWithOptions(NewBreakerOptionsBuilder().Build())
+}
+
+/*
+NewBreakerWithOptions creates a new circuit breaker.
+*/
+func NewBreakerWithOptions(options BreakerOptions) *Breaker {
+	if options.IsFailure == nil {
+		options.IsFailure = IsConnectionFailure
+	}
+	return &Breaker{
+		options: options,
+		state:   Closed,
+	}
+}
+
+/*
+State returns the current state of the breaker.
+*/
+func (b *Breaker) State() State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.halfOpenIfElapsed()
+	return b.state
+}
+
+/*
+Allow returns breakererr.ErrCircuitOpen when the operation must fail fast.
+*/
+func (b *Breaker) Allow() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.halfOpenIfElapsed()
+	switch b.state {
+	case Open:
+		return breakererr.ErrCircuitOpen
+	case HalfOpen:
+		if b.probes >= b.options.HalfOpenProbes {
+			return breakererr.ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+/*
+Done records the outcome of an allowed operation.
+*/
+func (b *Breaker) Done(err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	failed := err != nil && b.options.IsFailure(err)
+	switch b.state {
+	case Closed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.options.FailureThreshold {
+			b.trip()
+		}
+	case HalfOpen:
+		if failed {
+			b.trip()
+			return
+		}
+		b.successes++
+		if b.successes >= b.options.HalfOpenProbes {
+			b.state = Closed
+			b.failures = 0
+		}
+	}
+}
+
+func (b *Breaker) trip() {
+	b.state = Open
+	b.openedAt = time.Now()
+}
+
+func (b *Breaker) halfOpenIfElapsed() {
+	if b.state == Open && time.Since(b.openedAt) >= b.options.OpenTimeout {
+		b.state = HalfOpen
+		b.probes = 0
+		b.successes = 0
+	}
+}
@@ -0,0 +1,86 @@
+package breaker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/breaker"
+	"github.com/morphy76/zk/pkg/breaker/breakererr"
+)
+
+func TestIsConnectionFailure(t *testing.T) {
+	for _, err := range []error{zk.ErrConnectionClosed, zk.ErrNoServer, zk.ErrSessionExpired, context.DeadlineExceeded} {
+		if !breaker.IsConnectionFailure(err) {
+			t.Errorf("expected %v to be a connection failure", err)
+		}
+	}
+	for _, err := range []error{zk.ErrNoNode, zk.ErrNodeExists, zk.ErrBadVersion} {
+		if breaker.IsConnectionFailure(err) {
+			t.Errorf("expected %v not to be a connection failure", err)
+		}
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	opts := breaker.NewBreakerOptionsBuilder().
+		WithFailureThreshold(2).
+		WithOpenTimeout(50 * time.Millisecond).
+		WithHalfOpenProbes(1).
+		Build()
+	circuitBreaker := breaker.NewBreakerWithOptions(opts)
+
+	run := func(err error) error {
+		if allowErr := circuitBreaker.Allow(); allowErr != nil {
+			return allowErr
+		}
+		circuitBreaker.Done(err)
+		return err
+	}
+
+	t.Run("Trip after consecutive failures", func(t *testing.T) {
+		run(zk.ErrConnectionClosed)
+		run(zk.ErrNoNode)
+		run(zk.ErrConnectionClosed)
+		if state := circuitBreaker.State(); state != breaker.Closed {
+			t.Fatalf("expected a non consecutive failure to keep the breaker closed, got %s", state)
+		}
+
+		run(zk.ErrConnectionClosed)
+		if state := circuitBreaker.State(); state != breaker.Open {
+			t.Fatalf("expected the breaker to be open, got %s", state)
+		}
+		if err := run(nil); !breakererr.IsCircuitOpen(err) {
+			t.Errorf("expected error %v, got %v", breakererr.ErrCircuitOpen, err)
+		}
+	})
+
+	t.Run("Failed probe opens again", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+		if state := circuitBreaker.State(); state != breaker.HalfOpen {
+			t.Fatalf("expected the breaker to be half-open, got %s", state)
+		}
+
+		if err := circuitBreaker.Allow(); err != nil {
+			t.Fatalf("expected a probe to be allowed, got %v", err)
+		}
+		if err := circuitBreaker.Allow(); !breakererr.IsCircuitOpen(err) {
+			t.Errorf("expected a single probe at a time, got %v", err)
+		}
+		circuitBreaker.Done(zk.ErrNoServer)
+		if state := circuitBreaker.State(); state != breaker.Open {
+			t.Fatalf("expected the breaker to be open, got %s", state)
+		}
+	})
+
+	t.Run("Successful probe closes", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+		if err := run(zk.ErrNoNode); err != zk.ErrNoNode {
+			t.Fatalf("expected the probe to reach the server, got %v", err)
+		}
+		if state := circuitBreaker.State(); state != breaker.Closed {
+			t.Fatalf("expected the breaker to be closed, got %s", state)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+/*
+Package breakererr provides error types for the breaker package.
+*/
+package breakererr
+
+import "errors"
+
+/*
+ErrCircuitOpen is returned when an operation fails fast because the circuit breaker is open.
+*/
+var ErrCircuitOpen = errors.New("circuit open")
+
+/*
+IsCircuitOpen checks if the error is a circuit open error.
+*/
+func IsCircuitOpen(err error) bool {
+	return err == ErrCircuitOpen
+}
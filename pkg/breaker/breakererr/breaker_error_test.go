@@ -0,0 +1,22 @@
+package breakererr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/breaker/breakererr"
+)
+
+func TestIsCircuitOpen(t *testing.T) {
+	err := breakererr.ErrCircuitOpen
+	if !breakererr.IsCircuitOpen(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsCircuitOpenFalse(t *testing.T) {
+	err := errors.New("some error")
+	if breakererr.IsCircuitOpen(err) {
+		t.Errorf("expected false, got true")
+	}
+}
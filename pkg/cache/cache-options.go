@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 	"syscall"
+	"time"
 )
 
 /*
@@ -20,6 +21,18 @@ type ZKCacheOptions struct {
 	EvictionPolicy EvictionPolicy
 	// EnableCacheSynch is a flag to enable cache synchronization with the ZooKeeper server on node data change.
 	EnableCacheSynch bool
+	// InvalidationNode, when not empty, is the node whose invalidation records keep the cache coherent across processes instead of per-node watches.
+	InvalidationNode string
+	// InvalidationRetention is the number of invalidation records kept under the invalidation node.
+	InvalidationRetention int
+	// EnableStatValidation is a flag to check the stat of a cached node on each Get, fetching its data again only when changed, instead of per-node watches.
+	EnableStatValidation bool
+	// RefreshAfter, when positive, is the age after which a cached node is served stale while being refreshed in background.
+	RefreshAfter time.Duration
+	// MaxStale bounds how long a stale node is served before Get fetches it again synchronously, zero means no bound.
+	MaxStale time.Duration
+	// OperationTimeout, when positive, overrides the operation deadline of the framework for the reads and refreshes of the cache.
+	OperationTimeout time.Duration
 }
 
 /*
@@ -29,10 +42,17 @@ type ZKCacheOptionsBuilder struct {
 	maxSizeInBytes   int
 	evictionPolicy   EvictionPolicy
 	enableCacheSynch bool
+	invalidationNode string
+	invalidationRet  int
+	statValidation   bool
+	refreshAfter     time.Duration
+	maxStale         time.Duration
+	operationTimeout time.Duration
 }
 
 const (
 	defaultCacheMemoryPercentage = 5
+	defaultInvalidationRetention = 1000
 )
 
 /*
@@ -63,6 +83,7 @@ func NewCacheOptionsBuilder() (ZKCacheOptionsBuilder, error) {
 		maxSizeInBytes:   maxSizeInBytes,
 		evictionPolicy:   EvictLeastRecentlyUsed,
 		enableCacheSynch: true,
+		invalidationRet:  defaultInvalidationRetention,
 	}, nil
 }
 
@@ -90,13 +111,67 @@ func (b ZKCacheOptionsBuilder) WithEnableCacheSynch(enableCacheSynch bool) ZKCac
 	return b
 }
 
+/*
+WithInvalidationNode sets the node whose invalidation records keep the cache coherent across processes instead of per-node watches.
+*/
+func (b ZKCacheOptionsBuilder) WithInvalidationNode(invalidationNode string) ZKCacheOptionsBuilder {
+	b.invalidationNode = invalidationNode
+	return b
+}
+
+/*
+WithInvalidationRetention sets the number of invalidation records kept under the invalidation node.
+*/
+func (b ZKCacheOptionsBuilder) WithInvalidationRetention(invalidationRetention int) ZKCacheOptionsBuilder {
+	b.invalidationRet = invalidationRetention
+	return b
+}
+
+/*
+WithEnableStatValidation sets the flag to check the stat of a cached node on each Get, fetching its data again only when changed.
+*/
+func (b ZKCacheOptionsBuilder) WithEnableStatValidation(enableStatValidation bool) ZKCacheOptionsBuilder {
+	b.statValidation = enableStatValidation
+	return b
+}
+
+/*
+WithRefreshAfter sets the age after which a cached node is served stale while being refreshed in background.
+*/
+func (b ZKCacheOptionsBuilder) WithRefreshAfter(refreshAfter time.Duration) ZKCacheOptionsBuilder {
+	b.refreshAfter = refreshAfter
+	return b
+}
+
+/*
+WithMaxStale sets how long a stale node is served before Get fetches it again synchronously.
+*/
+func (b ZKCacheOptionsBuilder) WithMaxStale(maxStale time.Duration) ZKCacheOptionsBuilder {
+	b.maxStale = maxStale
+	return b
+}
+
+/*
+WithOperationTimeout overrides the operation deadline of the framework for the reads and refreshes of the cache.
+*/
+func (b ZKCacheOptionsBuilder) WithOperationTimeout(operationTimeout time.Duration) ZKCacheOptionsBuilder {
+	b.operationTimeout = operationTimeout
+	return b
+}
+
 /*
 Build builds the ZKCacheOptions.
 */
 func (b ZKCacheOptionsBuilder) Build() ZKCacheOptions {
 	return ZKCacheOptions{
-		MaxSizeInBytes:   b.maxSizeInBytes,
-		EvictionPolicy:   b.evictionPolicy,
-		EnableCacheSynch: b.enableCacheSynch,
+		MaxSizeInBytes:        b.maxSizeInBytes,
+		EvictionPolicy:        b.evictionPolicy,
+		EnableCacheSynch:      b.enableCacheSynch,
+		InvalidationNode:      b.invalidationNode,
+		InvalidationRetention: b.invalidationRet,
+		EnableStatValidation:  b.statValidation,
+		RefreshAfter:          b.refreshAfter,
+		MaxStale:              b.maxStale,
+		OperationTimeout:      b.operationTimeout,
 	}
 }
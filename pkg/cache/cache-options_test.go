@@ -3,6 +3,7 @@ package cache_test
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/morphy76/zk/pkg/cache"
 )
@@ -25,6 +26,26 @@ func TestDefaultCacheOptionsBuilder(t *testing.T) {
 	if opts.EvictionPolicy != cache.EvictLeastRecentlyUsed {
 		t.Errorf("Expected EvictionPolicy to be %v, got %v", cache.EvictLeastRecentlyUsed, opts.EvictionPolicy)
 	}
+
+	if opts.InvalidationNode != "" {
+		t.Errorf("Expected InvalidationNode to be empty, got %s", opts.InvalidationNode)
+	}
+
+	if opts.InvalidationRetention <= 0 {
+		t.Errorf("Expected InvalidationRetention to be > 0, got %d", opts.InvalidationRetention)
+	}
+
+	if opts.EnableStatValidation {
+		t.Errorf("Expected EnableStatValidation to be false, got true")
+	}
+
+	if opts.RefreshAfter != 0 || opts.MaxStale != 0 {
+		t.Errorf("Expected stale-while-revalidate to be disabled, got %v and %v", opts.RefreshAfter, opts.MaxStale)
+	}
+
+	if opts.OperationTimeout != 0 {
+		t.Errorf("Expected OperationTimeout to be 0, got %v", opts.OperationTimeout)
+	}
 }
 
 func TestCacheOptionsBuilder(t *testing.T) {
@@ -40,6 +61,12 @@ func TestCacheOptionsBuilder(t *testing.T) {
 		WithEvictionPolicy(evictPolicy).
 		WithEnableCacheSynch(sinch).
 		WithMaxSizeInBytes(maxSize).
+		WithInvalidationNode("invalidations").
+		WithInvalidationRetention(10).
+		WithEnableStatValidation(true).
+		WithRefreshAfter(time.Second).
+		WithMaxStale(time.Minute).
+		WithOperationTimeout(30 * time.Second).
 		Build()
 
 	if opts.EnableCacheSynch != sinch {
@@ -53,4 +80,28 @@ func TestCacheOptionsBuilder(t *testing.T) {
 	if opts.EvictionPolicy != evictPolicy {
 		t.Errorf("Expected EvictionPolicy to be %v, got %v", evictPolicy, opts.EvictionPolicy)
 	}
+
+	if opts.InvalidationNode != "invalidations" {
+		t.Errorf("Expected InvalidationNode to be invalidations, got %s", opts.InvalidationNode)
+	}
+
+	if opts.InvalidationRetention != 10 {
+		t.Errorf("Expected InvalidationRetention to be 10, got %d", opts.InvalidationRetention)
+	}
+
+	if !opts.EnableStatValidation {
+		t.Errorf("Expected EnableStatValidation to be true, got false")
+	}
+
+	if opts.RefreshAfter != time.Second {
+		t.Errorf("Expected RefreshAfter to be %v, got %v", time.Second, opts.RefreshAfter)
+	}
+
+	if opts.MaxStale != time.Minute {
+		t.Errorf("Expected MaxStale to be %v, got %v", time.Minute, opts.MaxStale)
+	}
+
+	if opts.OperationTimeout != 30*time.Second {
+		t.Errorf("Expected OperationTimeout to be %v, got %v", 30*time.Second, opts.OperationTimeout)
+	}
 }
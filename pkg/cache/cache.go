@@ -4,7 +4,6 @@ Package cache provides a simple in-memory cache implementation.
 package cache
 
 import (
-	"log"
 	"math"
 	"path"
 	"sync"
@@ -44,6 +43,23 @@ type Cache struct {
 	evictPathCh    chan string
 	mu             sync.RWMutex
 	synched        bool
+
+	invalidationPath      string
+	invalidationRetention int
+	lastInvalidation      int64
+	stopCh                chan struct{}
+
+	preloads  map[string]*watcher.TreeWatcher
+	preloaded map[string]bool
+
+	statValidated bool
+	mzxids        map[string]int64
+
+	refreshAfter time.Duration
+	maxStale     time.Duration
+	fetched      map[string]time.Time
+	invalidated  map[string]time.Time
+	revalidating map[string]bool
 }
 
 /*
@@ -67,18 +83,40 @@ func NewCacheWithOptions(framework core.ZKFramework, options ZKCacheOptions) (*C
 	if options.MaxSizeInBytes <= 0 {
 		return nil, cacheerr.ErrInvalidCacheSize
 	}
+	if options.OperationTimeout > 0 {
+		framework = framework.UsingOperationTimeout(options.OperationTimeout)
+	}
 
-	return &Cache{
+	c := &Cache{
 		framework:      framework,
 		cache:          make(map[string][]byte),
 		cacheUsage:     make(map[string]int64),
 		sizeInBytes:    0,
 		evictionPolicy: options.EvictionPolicy,
 		maxSizeInBytes: options.MaxSizeInBytes,
-		synched:        options.EnableCacheSynch,
+		synched:        options.EnableCacheSynch && options.InvalidationNode == "" && !options.EnableStatValidation,
 		evictPathCh:    make(chan string),
 		mu:             sync.RWMutex{},
-	}, nil
+		preloads:       make(map[string]*watcher.TreeWatcher),
+		preloaded:      make(map[string]bool),
+		statValidated:  options.EnableStatValidation,
+		mzxids:         make(map[string]int64),
+		refreshAfter:   options.RefreshAfter,
+		maxStale:       options.MaxStale,
+		fetched:        make(map[string]time.Time),
+		invalidated:    make(map[string]time.Time),
+		revalidating:   make(map[string]bool),
+	}
+
+	if options.InvalidationNode != "" {
+		c.invalidationPath = path.Join(framework.Namespace(), options.InvalidationNode)
+		c.invalidationRetention = options.InvalidationRetention
+		if err := c.followInvalidations(options.InvalidationNode); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
 
 /*
@@ -94,6 +132,24 @@ func (c *Cache) Clear() {
 	c.refreshSizeInBytes()
 }
 
+/*
+Close stops following the invalidation node and the preloaded subtrees.
+*/
+func (c *Cache) Close() {
+	c.mu.Lock()
+	preloads := c.preloads
+	c.preloads = make(map[string]*watcher.TreeWatcher)
+	if c.stopCh != nil {
+		close(c.stopCh)
+		c.stopCh = nil
+	}
+	c.mu.Unlock()
+
+	for _, treeWatcher := range preloads {
+		treeWatcher.Stop()
+	}
+}
+
 /*
 Get gets a node at the given path.
 */
@@ -117,6 +173,12 @@ func (c *Cache) Get(nodeName string) ([]byte, error) {
 	actualPath := path.Join(append([]string{c.framework.Namespace()}, nodeName)...)
 
 	cachedData, ok := c.cache[actualPath]
+	if ok && c.statValidated {
+		return c.getValidated(actualPath, cachedData)
+	}
+	if ok && c.refreshAfter > 0 {
+		return c.getRevalidating(actualPath, cachedData)
+	}
 	if ok {
 		c.incrementUsageByPolicy(actualPath)
 		return cachedData, nil
@@ -125,17 +187,16 @@ func (c *Cache) Get(nodeName string) ([]byte, error) {
 	if c.testExceedingResources() {
 		err := c.evictByPolicy()
 		if err != nil {
-			log.Printf("Error evicting cache: %v, warning, possible leak", err)
+			c.framework.Logger().Warn("error evicting cache, possible leak", "error", err)
 		}
 	}
 
-	data, err := operation.Get(c.framework, actualPath)
+	data, stat, err := operation.GetWithStat(c.framework, actualPath)
 	if err != nil {
 		return nil, err
 	}
-	c.cache[actualPath] = data
+	c.store(actualPath, data, stat)
 	c.initCacheUsageByPolicy(actualPath)
-	c.refreshSizeInBytes()
 
 	if !c.synched {
 		return data, nil
@@ -161,6 +222,49 @@ func (c *Cache) Get(nodeName string) ([]byte, error) {
 	return data, nil
 }
 
+func (c *Cache) getValidated(actualPath string, cachedData []byte) ([]byte, error) {
+	stat, err := operation.Stat(c.framework, actualPath)
+	if err == zk.ErrNoNode {
+		c.evict(actualPath)
+		c.refreshSizeInBytes()
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.incrementUsageByPolicy(actualPath)
+	if stat.Mzxid == c.mzxids[actualPath] {
+		return cachedData, nil
+	}
+
+	data, stat, err := operation.GetWithStat(c.framework, actualPath)
+	if err != nil {
+		return nil, err
+	}
+	c.store(actualPath, data, stat)
+	return data, nil
+}
+
+func (c *Cache) store(actualPath string, data []byte, stat *zk.Stat) {
+	c.cache[actualPath] = data
+	c.mzxids[actualPath] = stat.Mzxid
+	c.fetched[actualPath] = time.Now()
+	delete(c.invalidated, actualPath)
+	c.refreshSizeInBytes()
+}
+
+/*
+Query gets a node at the given path through the cache and evaluates the JSONPath expression over its data.
+*/
+func (c *Cache) Query(nodeName string, jsonPath string) (any, error) {
+	data, err := c.Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	return operation.EvaluateJSONPath(data, jsonPath)
+}
+
 /*
 GetSizeInBytes returns the size of the cache in bytes.
 */
@@ -180,11 +284,15 @@ func (c *Cache) refreshSizeInBytes() {
 }
 
 func (c *Cache) evict(zkPath string) {
-	if c.synched {
+	if c.synched && !c.preloaded[zkPath] {
 		c.evictPathCh <- zkPath
 	}
 	delete(c.cache, zkPath)
 	delete(c.cacheUsage, zkPath)
+	delete(c.preloaded, zkPath)
+	delete(c.mzxids, zkPath)
+	delete(c.fetched, zkPath)
+	delete(c.invalidated, zkPath)
 }
 
 func (c *Cache) renew(actualPath string) error {
@@ -193,7 +301,7 @@ func (c *Cache) renew(actualPath string) error {
 
 	data, err := operation.Get(c.framework, actualPath)
 	if err != nil {
-		log.Printf("Error renewing cache for path %s: %v", actualPath, err)
+		c.framework.Logger().Error("error renewing cache", "path", actualPath, "error", err)
 		delete(c.cache, actualPath)
 	}
 	c.cache[actualPath] = data
@@ -203,7 +311,7 @@ func (c *Cache) renew(actualPath string) error {
 }
 
 func (c *Cache) testExceedingResources() bool {
-	log.Printf("Cache size: %d, max size: %d", c.sizeInBytes, c.maxSizeInBytes)
+	c.framework.Logger().Debug("cache size", "size", c.sizeInBytes, "max", c.maxSizeInBytes)
 	return c.sizeInBytes > c.maxSizeInBytes
 }
 
@@ -240,12 +348,15 @@ func (c *Cache) evictLRU() error {
 	oldestPath := ""
 	oldestTime := time.Now().UnixNano()
 	for zkPath, time := range c.cacheUsage {
+		if c.preloaded[zkPath] {
+			continue
+		}
 		if time < oldestTime {
 			oldestTime = time
 			oldestPath = zkPath
 		}
 	}
-	log.Printf("Evicting LRU: %s", oldestPath)
+	c.framework.Logger().Debug("evicting LRU", "path", oldestPath)
 	if oldestPath != "" {
 		c.evict(oldestPath)
 	}
@@ -256,12 +367,15 @@ func (c *Cache) evictLFU() error {
 	leastFrequentPath := ""
 	var leastFrequency int64 = math.MaxInt64
 	for zkPath, frequency := range c.cacheUsage {
+		if c.preloaded[zkPath] {
+			continue
+		}
 		if frequency < leastFrequency {
 			leastFrequency = frequency
 			leastFrequentPath = zkPath
 		}
 	}
-	log.Printf("Evicting LFU: %s", leastFrequentPath)
+	c.framework.Logger().Debug("evicting LFU", "path", leastFrequentPath)
 	if leastFrequentPath != "" {
 		c.evict(leastFrequentPath)
 	}
@@ -269,8 +383,11 @@ func (c *Cache) evictLFU() error {
 }
 
 func (c *Cache) evictRandomly() error {
-	log.Printf("Evicting randomly")
+	c.framework.Logger().Debug("evicting randomly")
 	for zkPath := range c.cache {
+		if c.preloaded[zkPath] {
+			continue
+		}
 		c.evict(zkPath)
 		break
 	}
@@ -566,3 +566,330 @@ func TestZKCache(t *testing.T) {
 		}
 	})
 }
+
+func TestZKCacheQuery(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	zkCache, err := cache.NewCache(zkFramework)
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkCache.Clear()
+
+	nodeName := uuid.New().String()
+	opts := operation.NewCreateOptionsBuilder().
+		WithData([]byte(`{"db":{"host":"localhost"}}`)).
+		Build()
+	if err := operation.CreateWithOptions(zkFramework, nodeName, opts); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	value, err := zkCache.Query(nodeName, "$.db.host")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if value != "localhost" {
+		t.Errorf("Expected value to be localhost, got %v", value)
+	}
+	if !zkCache.IsCached(nodeName) {
+		t.Errorf("Expected node %s to be cached", nodeName)
+	}
+}
+
+func TestCacheCoherence(t *testing.T) {
+	invalidationNode := uuid.New().String() + "/invalidations"
+	nodeName := uuid.New().String()
+
+	newCoherentCache := func() *cache.Cache {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		t.Cleanup(func() { zkFramework.Stop() })
+
+		optsBuilder, err := cache.NewCacheOptionsBuilder()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		zkCache, err := cache.NewCacheWithOptions(zkFramework, optsBuilder.WithInvalidationNode(invalidationNode).WithInvalidationRetention(2).Build())
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		t.Cleanup(zkCache.Close)
+		return zkCache
+	}
+
+	writer := newCoherentCache()
+	reader := newCoherentCache()
+
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+	if err := operation.Create(zkFramework, nodeName); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := reader.Get(nodeName); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if !reader.IsCached(nodeName) {
+			t.Fatalf("expected node %s to be cached", nodeName)
+		}
+
+		if err := writer.Invalidate(nodeName); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for reader.IsCached(nodeName) && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if reader.IsCached(nodeName) {
+			t.Fatalf("expected node %s to be invalidated", nodeName)
+		}
+	}
+
+	records, err := operation.Ls(zkFramework, invalidationNode)
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if len(records) != 2 {
+		t.Errorf("expected 2 retained invalidation records, got %d", len(records))
+	}
+}
+
+func TestCachePreload(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for _, nodeName := range []string{root + "/a/b", root + "/c"} {
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+	}
+
+	zkCache, err := cache.NewCache(zkFramework)
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkCache.Close()
+
+	if err := zkCache.Preload(root); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	for _, nodeName := range []string{root, root + "/a", root + "/a/b", root + "/c"} {
+		if !zkCache.IsCached(nodeName) {
+			t.Errorf("expected node %s to be preloaded", nodeName)
+		}
+	}
+
+	waitFor := func(condition func() bool) bool {
+		deadline := time.Now().Add(5 * time.Second)
+		for !condition() && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return condition()
+	}
+
+	if _, err := operation.Update(zkFramework, root+"/c", []byte("updated")); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if !waitFor(func() bool {
+		data, err := zkCache.Get(root + "/c")
+		return err == nil && string(data) == "updated"
+	}) {
+		t.Errorf("expected node %s to be refreshed", root+"/c")
+	}
+
+	if err := operation.Create(zkFramework, root+"/d"); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if !waitFor(func() bool { return zkCache.IsCached(root + "/d") }) {
+		t.Errorf("expected node %s to be cached", root+"/d")
+	}
+
+	if err := operation.Delete(zkFramework, root+"/a/b"); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if !waitFor(func() bool { return !zkCache.IsCached(root + "/a/b") }) {
+		t.Errorf("expected node %s to be evicted", root+"/a/b")
+	}
+}
+
+func TestCachePreloadNotSynched(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	opts := operation.NewCreateOptionsBuilder().WithData([]byte("data")).Build()
+	for _, nodeName := range []string{root + "/a/b", root + "/c", root + "-x", root + "-y"} {
+		if err := operation.CreateWithOptions(zkFramework, nodeName, opts); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+	}
+
+	optsBuilder, err := cache.NewCacheOptionsBuilder()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	zkCache, err := cache.NewCacheWithOptions(zkFramework, optsBuilder.WithEnableCacheSynch(false).WithMaxSizeInBytes(1).Build())
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkCache.Close()
+
+	if err := zkCache.Preload(root); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if _, err := zkCache.Get(root + "-x"); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if _, err := zkCache.Get(root + "-y"); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	for _, nodeName := range []string{root, root + "/a", root + "/a/b", root + "/c"} {
+		if !zkCache.IsCached(nodeName) {
+			t.Errorf("expected preloaded node %s not to be evicted", nodeName)
+		}
+	}
+	if zkCache.IsCached(root + "-x") {
+		t.Errorf("expected node %s to be evicted", root+"-x")
+	}
+}
+
+func TestCacheStatValidation(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	nodeName := uuid.New().String()
+	opts := operation.NewCreateOptionsBuilder().WithData([]byte("v1")).Build()
+	if err := operation.CreateWithOptions(zkFramework, nodeName, opts); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	optsBuilder, err := cache.NewCacheOptionsBuilder()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	zkCache, err := cache.NewCacheWithOptions(zkFramework, optsBuilder.WithEnableStatValidation(true).Build())
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkCache.Close()
+
+	if data, err := zkCache.Get(nodeName); err != nil || string(data) != "v1" {
+		t.Fatalf("unexpected data %s, error %v", data, err)
+	}
+
+	if _, err := operation.Update(zkFramework, nodeName, []byte("v2")); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if data, err := zkCache.Get(nodeName); err != nil || string(data) != "v2" {
+		t.Errorf("expected the changed node to be fetched again, got %s, error %v", data, err)
+	}
+
+	if err := operation.Delete(zkFramework, nodeName); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if _, err := zkCache.Get(nodeName); err == nil {
+		t.Errorf("expected an error getting a deleted node")
+	}
+	if zkCache.IsCached(nodeName) {
+		t.Errorf("expected the deleted node to be evicted")
+	}
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	newCache := func(refreshAfter time.Duration, maxStale time.Duration) *cache.Cache {
+		optsBuilder, err := cache.NewCacheOptionsBuilder()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		opts := optsBuilder.WithEnableCacheSynch(false).WithRefreshAfter(refreshAfter).WithMaxStale(maxStale).Build()
+		zkCache, err := cache.NewCacheWithOptions(zkFramework, opts)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		return zkCache
+	}
+	newNode := func() string {
+		nodeName := uuid.New().String()
+		opts := operation.NewCreateOptionsBuilder().WithData([]byte("v1")).Build()
+		if err := operation.CreateWithOptions(zkFramework, nodeName, opts); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		return nodeName
+	}
+	get := func(zkCache *cache.Cache, nodeName string) string {
+		data, err := zkCache.Get(nodeName)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		return string(data)
+	}
+
+	t.Run("Serve stale while refreshing", func(t *testing.T) {
+		zkCache := newCache(100*time.Millisecond, time.Hour)
+		nodeName := newNode()
+
+		get(zkCache, nodeName)
+		if _, err := operation.Update(zkFramework, nodeName, []byte("v2")); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if data := get(zkCache, nodeName); data != "v1" {
+			t.Errorf("expected the fresh entry v1, got %s", data)
+		}
+
+		time.Sleep(150 * time.Millisecond)
+		if data := get(zkCache, nodeName); data != "v1" {
+			t.Errorf("expected the stale entry v1, got %s", data)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for get(zkCache, nodeName) != "v2" && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if data := get(zkCache, nodeName); data != "v2" {
+			t.Errorf("expected the refreshed entry v2, got %s", data)
+		}
+	})
+
+	t.Run("Fetch beyond max stale", func(t *testing.T) {
+		zkCache := newCache(10*time.Millisecond, 50*time.Millisecond)
+		nodeName := newNode()
+
+		get(zkCache, nodeName)
+		if _, err := operation.Update(zkFramework, nodeName, []byte("v2")); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+
+		time.Sleep(100 * time.Millisecond)
+		if data := get(zkCache, nodeName); data != "v2" {
+			t.Errorf("expected the entry to be fetched again, got %s", data)
+		}
+	})
+}
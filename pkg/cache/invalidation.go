@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	invalidationRecordPrefix = "record-"
+	sequenceLength           = 10
+)
+
+/*
+Invalidate evicts the node from the cache and records the invalidation under the invalidation node, so that every cache following it evicts the node too.
+
+Writers call Invalidate after changing a cached node; without an invalidation node only the local cache is affected.
+In stale-while-revalidate mode the node is marked stale instead of being evicted.
+*/
+func (c *Cache) Invalidate(nodeName string) error {
+	actualPath := path.Join(append([]string{c.framework.Namespace()}, nodeName)...)
+
+	c.mu.Lock()
+	c.evictCoherent(actualPath)
+	c.mu.Unlock()
+
+	if c.invalidationPath == "" {
+		return nil
+	}
+
+	executor := operation.Executor(c.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), c.framework.OperationTimeout())
+	defer cancel()
+	recordPath := path.Join(c.invalidationPath, invalidationRecordPrefix)
+	if _, err := executor.Create(ctx, recordPath, []byte(actualPath), zk.FlagSequence, nodeacl.DefaultFor(c.framework.ACLProvider(), recordPath)); err != nil {
+		return err
+	}
+	return c.trimInvalidations()
+}
+
+func (c *Cache) followInvalidations(invalidationNode string) error {
+	exists, err := operation.Exists(c.framework, invalidationNode)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := operation.Create(c.framework, invalidationNode); err != nil && err != zk.ErrNodeExists {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.framework.OperationTimeout())
+	defer cancel()
+	children, _, events, err := operation.Executor(c.framework).ChildrenW(ctx, c.invalidationPath)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		c.lastInvalidation = max(c.lastInvalidation, recordSequence(child))
+	}
+
+	c.stopCh = make(chan struct{})
+	go c.invalidationLoop(events, c.stopCh)
+	return nil
+}
+
+func (c *Cache) invalidationLoop(events <-chan zk.Event, stopCh chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-events:
+		}
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), c.framework.OperationTimeout())
+			children, _, nextEvents, err := operation.Executor(c.framework).ChildrenW(ctx, c.invalidationPath)
+			cancel()
+			if err == nil {
+				c.applyInvalidations(children)
+				events = nextEvents
+				break
+			}
+			c.framework.Logger().Error("error following invalidations", "path", c.invalidationPath, "error", err)
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}
+}
+
+func (c *Cache) applyInvalidations(children []string) {
+	slices.SortFunc(children, func(a string, b string) int {
+		return cmp.Compare(recordSequence(a), recordSequence(b))
+	})
+
+	executor := operation.Executor(c.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), c.framework.OperationTimeout())
+	defer cancel()
+	for _, child := range children {
+		sequence := recordSequence(child)
+		if sequence <= c.lastInvalidation {
+			continue
+		}
+		c.lastInvalidation = sequence
+
+		actualPath, _, err := executor.Get(ctx, path.Join(c.invalidationPath, child))
+		if err != nil {
+			if err != zk.ErrNoNode {
+				c.framework.Logger().Error("error reading invalidation record", "record", child, "error", err)
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		c.evictCoherent(string(actualPath))
+		c.mu.Unlock()
+	}
+}
+
+func (c *Cache) trimInvalidations() error {
+	executor := operation.Executor(c.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), c.framework.OperationTimeout())
+	defer cancel()
+	children, _, err := executor.Children(ctx, c.invalidationPath)
+	if err != nil {
+		return err
+	}
+	if len(children) <= c.invalidationRetention {
+		return nil
+	}
+
+	slices.SortFunc(children, func(a string, b string) int {
+		return cmp.Compare(recordSequence(a), recordSequence(b))
+	})
+	for _, child := range children[:len(children)-c.invalidationRetention] {
+		if err := executor.Delete(ctx, path.Join(c.invalidationPath, child), -1); err != nil && err != zk.ErrNoNode {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) evictCoherent(actualPath string) {
+	if _, ok := c.cache[actualPath]; !ok {
+		return
+	}
+	if c.refreshAfter > 0 {
+		c.markStale(actualPath)
+		return
+	}
+	c.evict(actualPath)
+	c.refreshSizeInBytes()
+}
+
+func recordSequence(name string) int64 {
+	if len(name) < sequenceLength {
+		return 0
+	}
+	sequence, err := strconv.ParseInt(name[len(name)-sequenceLength:], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return sequence
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"errors"
+	"path"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/watcher"
+)
+
+/*
+Preload populates the cache with every node of the subtree at the given path in a single pass, instead of one miss per node.
+
+When the cache is synched, the subtree stays watched as a whole: created and updated nodes are refreshed and deleted nodes are evicted until Close;
+otherwise the subtree is read once, without setting any watch.
+Preloaded nodes count towards the maximum size of the cache but are never evicted to make room for them.
+*/
+func (c *Cache) Preload(root string) error {
+	actualRoot := path.Join(c.framework.Namespace(), root)
+
+	c.mu.RLock()
+	_, preloading := c.preloads[actualRoot]
+	c.mu.RUnlock()
+	if preloading {
+		return nil
+	}
+
+	if !c.synched {
+		return c.loadTree(root)
+	}
+
+	treeWatcher := watcher.NewTreeWatcher(c.framework, root, c.onPreloadEvent)
+	if err := treeWatcher.Start(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.preloads[actualRoot] = treeWatcher
+	return nil
+}
+
+func (c *Cache) onPreloadEvent(event watcher.TreeEvent) {
+	actualPath := path.Join(c.framework.Namespace(), event.Path)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch event.Type {
+	case watcher.TreeNodeAdded, watcher.TreeNodeUpdated:
+		c.preloadNode(actualPath, event.Data)
+	case watcher.TreeNodeRemoved:
+		delete(c.cache, actualPath)
+		delete(c.cacheUsage, actualPath)
+		delete(c.preloaded, actualPath)
+		c.refreshSizeInBytes()
+	}
+}
+
+/*
+loadTree reads the subtree at the given path depth-first into the cache, nodes deleted meanwhile are skipped.
+*/
+func (c *Cache) loadTree(nodeName string) error {
+	data, err := operation.Get(c.framework, nodeName)
+	if errors.Is(err, zk.ErrNoNode) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.preloadNode(path.Join(c.framework.Namespace(), nodeName), data)
+	c.mu.Unlock()
+
+	children, err := operation.Ls(c.framework, nodeName)
+	if errors.Is(err, zk.ErrNoNode) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := c.loadTree(path.Join(nodeName, child)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) preloadNode(actualPath string, data []byte) {
+	if _, cached := c.cache[actualPath]; !cached {
+		c.preloaded[actualPath] = true
+		c.initCacheUsageByPolicy(actualPath)
+	}
+	c.cache[actualPath] = data
+	c.fetched[actualPath] = time.Now()
+	delete(c.invalidated, actualPath)
+	c.refreshSizeInBytes()
+
+	if c.testExceedingResources() {
+		c.framework.Logger().Warn("cache size exceeded by preloaded node", "path", actualPath)
+	}
+}
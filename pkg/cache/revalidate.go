@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+getRevalidating serves a fresh node as is, a stale node within the max-stale bound while refreshing it in background and a node beyond the bound by fetching it again.
+*/
+func (c *Cache) getRevalidating(actualPath string, cachedData []byte) ([]byte, error) {
+	c.incrementUsageByPolicy(actualPath)
+
+	staleSince, stale := c.staleSince(actualPath)
+	if !stale {
+		return cachedData, nil
+	}
+	if c.maxStale <= 0 || time.Since(staleSince) <= c.maxStale {
+		c.revalidate(actualPath)
+		return cachedData, nil
+	}
+
+	data, stat, err := operation.GetWithStat(c.framework, actualPath)
+	if err != nil {
+		return nil, err
+	}
+	c.store(actualPath, data, stat)
+	return data, nil
+}
+
+func (c *Cache) staleSince(actualPath string) (time.Time, bool) {
+	if invalidated, ok := c.invalidated[actualPath]; ok {
+		return invalidated, true
+	}
+	expiry := c.fetched[actualPath].Add(c.refreshAfter)
+	return expiry, time.Now().After(expiry)
+}
+
+func (c *Cache) markStale(actualPath string) {
+	if _, ok := c.invalidated[actualPath]; !ok {
+		c.invalidated[actualPath] = time.Now()
+	}
+}
+
+func (c *Cache) revalidate(actualPath string) {
+	if c.revalidating[actualPath] {
+		return
+	}
+	c.revalidating[actualPath] = true
+
+	go func() {
+		data, stat, err := operation.GetWithStat(c.framework, actualPath)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.revalidating, actualPath)
+		if err != nil {
+			c.framework.Logger().Error("error revalidating cache", "path", actualPath, "error", err)
+			return
+		}
+		if _, cached := c.cache[actualPath]; cached {
+			c.store(actualPath, data, stat)
+		}
+	}()
+}
@@ -0,0 +1,287 @@
+/*
+Package cdc converts the changes of a subtree into an ordered, resumable change stream.
+*/
+package cdc
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/cdc/cdcerr"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/watcher"
+)
+
+/*
+ChangeType is the type of a change of the stream.
+*/
+type ChangeType int
+
+const (
+	// Created is a node created after the cursor.
+	Created ChangeType = iota
+	// Updated is a node whose data changed after the cursor.
+	Updated
+	// Deleted is a node deleted after the cursor.
+	Deleted
+)
+
+/*
+Change is a change of the stream.
+*/
+type Change struct {
+	// Type is the type of the change.
+	Type ChangeType
+	// Path is the path of the changed node, relative to the namespace.
+	Path string
+	// Data is the data of the node, nil when deleted.
+	Data []byte
+	// Zxid is the transaction ID of the change, deletions carry the latest transaction ID observed by the stream.
+	Zxid int64
+}
+
+type cursor struct {
+	Zxid  int64    `json:"zxid"`
+	Paths []string `json:"paths"`
+}
+
+/*
+Stream is the change stream of a subtree, resuming after the last change committed by its consumer.
+
+The cursor is persisted in the cursor node, which must be outside of the watched subtree and holds the committed zxid together with the paths known at that point, so that deletions happening while the consumer is down are streamed on resume.
+*/
+type Stream struct {
+	framework  core.ZKFramework
+	cursorNode string
+	watcher    *watcher.TreeWatcher
+
+	out     chan Change
+	stop    chan bool
+	queue   []Change
+	running bool
+	lock    sync.Mutex
+	ready   *sync.Cond
+
+	initializing bool
+	initial      []Change
+	seen         map[string]bool
+	resumeZxid   int64
+	resumePaths  map[string]bool
+	lastZxid     int64
+
+	committedZxid  int64
+	committedPaths map[string]bool
+}
+
+/*
+NewStream creates the change stream of the subtree at the given path, persisting its cursor at the given node.
+*/
+func NewStream(zkFramework core.ZKFramework, root string, cursorNode string) *Stream {
+	s := &Stream{
+		framework:  zkFramework,
+		cursorNode: cursorNode,
+	}
+	s.ready = sync.NewCond(&s.lock)
+	s.watcher = watcher.NewTreeWatcher(zkFramework, root, s.onEvent)
+	return s
+}
+
+/*
+Start reads the cursor and starts streaming the changes following it, the initial changes are sorted by zxid.
+
+The returned channel is closed when the stream is stopped.
+*/
+func (s *Stream) Start() (<-chan Change, error) {
+	s.lock.Lock()
+	if s.running {
+		s.lock.Unlock()
+		return nil, cdcerr.ErrStreamAlreadyStarted
+	}
+
+	c, err := s.loadCursor()
+	if err != nil {
+		s.lock.Unlock()
+		return nil, err
+	}
+
+	s.resumeZxid = c.Zxid
+	s.lastZxid = c.Zxid
+	s.committedZxid = c.Zxid
+	s.resumePaths = make(map[string]bool, len(c.Paths))
+	s.committedPaths = make(map[string]bool, len(c.Paths))
+	for _, nodePath := range c.Paths {
+		s.resumePaths[nodePath] = true
+		s.committedPaths[nodePath] = true
+	}
+	s.initializing = true
+	s.initial = []Change{}
+	s.seen = make(map[string]bool)
+	s.queue = []Change{}
+	s.out = make(chan Change)
+	s.stop = make(chan bool)
+	s.running = true
+	s.lock.Unlock()
+
+	if err := s.watcher.Start(); err != nil {
+		s.lock.Lock()
+		s.running = false
+		s.lock.Unlock()
+		return nil, err
+	}
+
+	s.lock.Lock()
+	for nodePath := range s.resumePaths {
+		if !s.seen[nodePath] {
+			s.initial = append(s.initial, Change{Type: Deleted, Path: nodePath, Zxid: s.lastZxid})
+		}
+	}
+	sort.SliceStable(s.initial, func(i, j int) bool {
+		return s.initial[i].Zxid < s.initial[j].Zxid
+	})
+	s.enqueue(s.initial...)
+	s.initializing = false
+	s.initial = nil
+	s.lock.Unlock()
+
+	go s.pump(s.out, s.stop)
+	return s.out, nil
+}
+
+/*
+Stop stops streaming the changes, the changes not yet received are discarded.
+*/
+func (s *Stream) Stop() {
+	s.lock.Lock()
+	if !s.running {
+		s.lock.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stop)
+	s.ready.Broadcast()
+	s.lock.Unlock()
+
+	s.watcher.Stop()
+}
+
+/*
+Commit persists the cursor after the given change, a restarted stream resumes after it.
+*/
+func (s *Stream) Commit(change Change) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.running {
+		return cdcerr.ErrStreamNotStarted
+	}
+
+	if change.Type == Deleted {
+		delete(s.committedPaths, change.Path)
+	} else {
+		s.committedPaths[change.Path] = true
+	}
+	if change.Zxid > s.committedZxid {
+		s.committedZxid = change.Zxid
+	}
+
+	paths := make([]string, 0, len(s.committedPaths))
+	for nodePath := range s.committedPaths {
+		paths = append(paths, nodePath)
+	}
+	sort.Strings(paths)
+
+	data, err := json.Marshal(cursor{Zxid: s.committedZxid, Paths: paths})
+	if err != nil {
+		return err
+	}
+	_, err = operation.Update(s.framework, s.cursorNode, data)
+	if coreerr.IsUnknownNode(err) {
+		return operation.CreateWithOptions(s.framework, s.cursorNode, operation.NewCreateOptionsBuilder().WithData(data).Build())
+	}
+	return err
+}
+
+func (s *Stream) loadCursor() (cursor, error) {
+	var c cursor
+	data, err := operation.Get(s.framework, s.cursorNode)
+	if errors.Is(err, zk.ErrNoNode) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+func (s *Stream) onEvent(event watcher.TreeEvent) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.running {
+		return
+	}
+
+	change := Change{Path: event.Path, Data: event.Data, Zxid: s.lastZxid}
+	switch event.Type {
+	case watcher.TreeNodeAdded:
+		change.Type = Created
+		change.Zxid = event.Stat.Mzxid
+	case watcher.TreeNodeUpdated:
+		change.Type = Updated
+		change.Zxid = event.Stat.Mzxid
+	case watcher.TreeNodeRemoved:
+		change.Type = Deleted
+	}
+	if change.Zxid > s.lastZxid {
+		s.lastZxid = change.Zxid
+	}
+
+	if !s.initializing {
+		s.enqueue(change)
+		return
+	}
+
+	s.seen[event.Path] = true
+	if change.Type == Created && s.resumePaths[event.Path] {
+		if change.Zxid <= s.resumeZxid {
+			return
+		}
+		change.Type = Updated
+	}
+	s.initial = append(s.initial, change)
+}
+
+func (s *Stream) enqueue(changes ...Change) {
+	s.queue = append(s.queue, changes...)
+	s.ready.Signal()
+}
+
+func (s *Stream) pump(out chan Change, stop chan bool) {
+	defer close(out)
+
+	for {
+		s.lock.Lock()
+		for len(s.queue) == 0 && s.running {
+			s.ready.Wait()
+		}
+		if !s.running {
+			s.lock.Unlock()
+			return
+		}
+		change := s.queue[0]
+		s.queue = s.queue[1:]
+		s.lock.Unlock()
+
+		select {
+		case out <- change:
+		case <-stop:
+			return
+		}
+	}
+}
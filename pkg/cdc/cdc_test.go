@@ -0,0 +1,136 @@
+package cdc_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/cdc"
+	"github.com/morphy76/zk/pkg/cdc/cdcerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestStream(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	cursorNode := uuid.New().String()
+	for _, nodeName := range []string{root + "/a", root + "/b"} {
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+
+	receive := func(changes <-chan cdc.Change, count int) map[string]cdc.ChangeType {
+		received := make(map[string]cdc.ChangeType)
+		for len(received) < count {
+			select {
+			case change := <-changes:
+				received[change.Path] = change.Type
+			case <-time.After(5 * time.Second):
+				t.Fatalf("expected %d changes, got %v", count, received)
+			}
+		}
+		return received
+	}
+
+	stream := cdc.NewStream(zkFramework, root, cursorNode)
+	changes, err := stream.Start()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if _, err := stream.Start(); !cdcerr.IsStreamAlreadyStarted(err) {
+		t.Errorf("expected error %v, got %v", cdcerr.ErrStreamAlreadyStarted, err)
+	}
+
+	var last cdc.Change
+	for i := 0; i < 3; i++ {
+		select {
+		case last = <-changes:
+			if last.Type != cdc.Created {
+				t.Errorf("expected a created change, got %+v", last)
+			}
+			if err := stream.Commit(last); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the initial changes")
+		}
+	}
+	stream.Stop()
+
+	if _, err := operation.Update(zkFramework, root+"/a", []byte("changed")); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := operation.Delete(zkFramework, root+"/b"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := operation.Create(zkFramework, root+"/c"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	resumed := cdc.NewStream(zkFramework, root, cursorNode)
+	changes, err = resumed.Start()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer resumed.Stop()
+
+	received := receive(changes, 3)
+	if received[root+"/a"] != cdc.Updated {
+		t.Errorf("expected %s/a to be updated, got %v", root, received)
+	}
+	if received[root+"/b"] != cdc.Deleted {
+		t.Errorf("expected %s/b to be deleted, got %v", root, received)
+	}
+	if received[root+"/c"] != cdc.Created {
+		t.Errorf("expected %s/c to be created, got %v", root, received)
+	}
+}
+
+func TestCommitNotStarted(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	stream := cdc.NewStream(zkFramework, uuid.New().String(), uuid.New().String())
+	if err := stream.Commit(cdc.Change{}); !cdcerr.IsStreamNotStarted(err) {
+		t.Errorf("expected error %v, got %v", cdcerr.ErrStreamNotStarted, err)
+	}
+}
@@ -0,0 +1,30 @@
+/*
+Package cdcerr provides error types for the cdc package.
+*/
+package cdcerr
+
+import "errors"
+
+/*
+ErrStreamAlreadyStarted is returned when a change stream is started twice.
+*/
+var ErrStreamAlreadyStarted = errors.New("change stream already started")
+
+/*
+ErrStreamNotStarted is returned when committing a change of a stream not started.
+*/
+var ErrStreamNotStarted = errors.New("change stream not started")
+
+/*
+IsStreamAlreadyStarted checks if the error is ErrStreamAlreadyStarted.
+*/
+func IsStreamAlreadyStarted(err error) bool {
+	return err == ErrStreamAlreadyStarted
+}
+
+/*
+IsStreamNotStarted checks if the error is ErrStreamNotStarted.
+*/
+func IsStreamNotStarted(err error) bool {
+	return err == ErrStreamNotStarted
+}
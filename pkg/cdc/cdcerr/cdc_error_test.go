@@ -0,0 +1,36 @@
+package cdcerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/cdc/cdcerr"
+)
+
+func TestIsStreamAlreadyStarted(t *testing.T) {
+	err := cdcerr.ErrStreamAlreadyStarted
+	if !cdcerr.IsStreamAlreadyStarted(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsStreamAlreadyStartedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if cdcerr.IsStreamAlreadyStarted(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsStreamNotStarted(t *testing.T) {
+	err := cdcerr.ErrStreamNotStarted
+	if !cdcerr.IsStreamNotStarted(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsStreamNotStartedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if cdcerr.IsStreamNotStarted(err) {
+		t.Errorf("expected false, got true")
+	}
+}
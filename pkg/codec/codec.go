@@ -0,0 +1,68 @@
+/*
+Package codec provides encoders and decoders of node data.
+*/
+package codec
+
+import "encoding/json"
+
+/*
+Codec converts values of type T to and from node data.
+*/
+type Codec[T any] interface {
+	Encode(value T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+/*
+JSONCodec is a Codec marshalling values as JSON documents.
+*/
+type JSONCodec[T any] struct{}
+
+/*
+NewJSONCodec creates a new JSONCodec.
+*/
+func NewJSONCodec[T any]() JSONCodec[T] {
+	return JSONCodec[T]{}
+}
+
+/*
+Encode marshals the value as a JSON document.
+*/
+func (c JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+/*
+Decode unmarshals the JSON document into a value.
+*/
+func (c JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+/*
+BytesCodec is a Codec passing node data through unchanged.
+*/
+type BytesCodec struct{}
+
+/*
+NewBytesCodec creates a new BytesCodec.
+*/
+func NewBytesCodec() BytesCodec {
+	return BytesCodec{}
+}
+
+/*
+Encode returns the data unchanged.
+*/
+func (c BytesCodec) Encode(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+/*
+Decode returns the data unchanged.
+*/
+func (c BytesCodec) Decode(data []byte) ([]byte, error) {
+	return data, nil
+}
@@ -0,0 +1,49 @@
+package codec_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/codec"
+)
+
+type sample struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestJSONCodec(t *testing.T) {
+	c := codec.NewJSONCodec[sample]()
+	value := sample{Name: "a", Count: 2}
+
+	data, err := c.Encode(value)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if string(data) != `{"name":"a","count":2}` {
+		t.Errorf("unexpected encoding %s", data)
+	}
+
+	decoded, err := c.Decode(data)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if decoded != value {
+		t.Errorf("expected %v, got %v", value, decoded)
+	}
+}
+
+func TestJSONCodecInvalidData(t *testing.T) {
+	c := codec.NewJSONCodec[sample]()
+	if _, err := c.Decode([]byte("not json")); err == nil {
+		t.Errorf("expected error to be not nil")
+	}
+}
+
+func TestBytesCodec(t *testing.T) {
+	c := codec.NewBytesCodec()
+	data, _ := c.Encode([]byte("data"))
+	decoded, _ := c.Decode(data)
+	if string(decoded) != "data" {
+		t.Errorf("expected data, got %s", decoded)
+	}
+}
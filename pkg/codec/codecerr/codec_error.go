@@ -0,0 +1,42 @@
+/*
+Package codecerr provides error types for the codec package.
+*/
+package codecerr
+
+import "errors"
+
+/*
+ErrUnknownKey is returned when the key provider does not know the requested key ID.
+*/
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+/*
+ErrInvalidCiphertext is returned when encrypted data is malformed or cannot be authenticated.
+*/
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+/*
+ErrInvalidKeyID is returned when a key ID is too long to be stored in the header of the encrypted data.
+*/
+var ErrInvalidKeyID = errors.New("invalid encryption key ID")
+
+/*
+IsUnknownKey checks if the error is ErrUnknownKey.
+*/
+func IsUnknownKey(err error) bool {
+	return err == ErrUnknownKey
+}
+
+/*
+IsInvalidCiphertext checks if the error is ErrInvalidCiphertext.
+*/
+func IsInvalidCiphertext(err error) bool {
+	return err == ErrInvalidCiphertext
+}
+
+/*
+IsInvalidKeyID checks if the error is ErrInvalidKeyID.
+*/
+func IsInvalidKeyID(err error) bool {
+	return err == ErrInvalidKeyID
+}
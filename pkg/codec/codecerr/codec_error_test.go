@@ -0,0 +1,50 @@
+package codecerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/codec/codecerr"
+)
+
+func TestIsUnknownKey(t *testing.T) {
+	err := codecerr.ErrUnknownKey
+	if !codecerr.IsUnknownKey(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsUnknownKeyFalse(t *testing.T) {
+	err := errors.New("some error")
+	if codecerr.IsUnknownKey(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidCiphertext(t *testing.T) {
+	err := codecerr.ErrInvalidCiphertext
+	if !codecerr.IsInvalidCiphertext(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidCiphertextFalse(t *testing.T) {
+	err := errors.New("some error")
+	if codecerr.IsInvalidCiphertext(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidKeyID(t *testing.T) {
+	err := codecerr.ErrInvalidKeyID
+	if !codecerr.IsInvalidKeyID(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidKeyIDFalse(t *testing.T) {
+	err := errors.New("some error")
+	if codecerr.IsInvalidKeyID(err) {
+		t.Errorf("expected false, got true")
+	}
+}
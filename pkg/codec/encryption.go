@@ -0,0 +1,194 @@
+package codec
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/codec/codecerr"
+)
+
+var encryptionHeader = []byte("ZKE1")
+
+// maxKeyIDLength is the length of the longest key ID the header can carry, its length being stored in a single byte.
+const maxKeyIDLength = 255
+
+/*
+KeyProvider supplies the AES keys used by the encryption codec, it can be backed by a KMS.
+*/
+type KeyProvider interface {
+	// CurrentKey returns the ID and the value of the key used to encrypt new data.
+	CurrentKey() (string, []byte, error)
+	// Key returns the value of the key with the given ID, used to decrypt existing data.
+	Key(keyID string) ([]byte, error)
+}
+
+/*
+StaticKeyProvider is an in-memory KeyProvider, keys can be added to rotate the current one.
+*/
+type StaticKeyProvider struct {
+	keys      map[string][]byte
+	currentID string
+	mu        sync.RWMutex
+}
+
+/*
+NewStaticKeyProvider creates a new StaticKeyProvider using the given key as current key,
+failing with codecerr.ErrInvalidKeyID when the key ID is longer than 255 bytes.
+*/
+func NewStaticKeyProvider(keyID string, key []byte) (*StaticKeyProvider, error) {
+	if err := validateKeyID(keyID); err != nil {
+		return nil, err
+	}
+	return &StaticKeyProvider{
+		keys:      map[string][]byte{keyID: key},
+		currentID: keyID,
+	}, nil
+}
+
+/*
+Rotate adds a key and makes it the current one, previous keys remain available for decryption;
+it fails with codecerr.ErrInvalidKeyID when the key ID is longer than 255 bytes.
+*/
+func (p *StaticKeyProvider) Rotate(keyID string, key []byte) error {
+	if err := validateKeyID(keyID); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys[keyID] = key
+	p.currentID = keyID
+	return nil
+}
+
+/*
+CurrentKey returns the ID and the value of the current key.
+*/
+func (p *StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.currentID, p.keys[p.currentID], nil
+}
+
+/*
+Key returns the value of the key with the given ID.
+*/
+func (p *StaticKeyProvider) Key(keyID string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, codecerr.ErrUnknownKey
+	}
+	return key, nil
+}
+
+/*
+EncryptionCodec is a Codec encrypting with AES-GCM the data produced by another codec.
+
+The encrypted data starts with a header carrying the key ID, so data written before a key rotation stays readable.
+*/
+type EncryptionCodec[T any] struct {
+	inner       Codec[T]
+	keyProvider KeyProvider
+}
+
+/*
+NewEncryptionCodec creates a new EncryptionCodec wrapping the given codec.
+*/
+func NewEncryptionCodec[T any](inner Codec[T], keyProvider KeyProvider) EncryptionCodec[T] {
+	return EncryptionCodec[T]{
+		inner:       inner,
+		keyProvider: keyProvider,
+	}
+}
+
+/*
+Encode encodes the value with the wrapped codec and encrypts the result with the current key.
+*/
+func (c EncryptionCodec[T]) Encode(value T) ([]byte, error) {
+	plaintext, err := c.inner.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, key, err := c.keyProvider.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateKeyID(keyID); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := append(append(append([]byte{}, encryptionHeader...), byte(len(keyID))), keyID...)
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, header), nil
+}
+
+/*
+Decode decrypts the data with the key referenced by its header and decodes the result with the wrapped codec.
+*/
+func (c EncryptionCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+
+	if len(data) < len(encryptionHeader)+1 || !bytes.HasPrefix(data, encryptionHeader) {
+		return value, codecerr.ErrInvalidCiphertext
+	}
+	keyIDLength := int(data[len(encryptionHeader)])
+	headerLength := len(encryptionHeader) + 1 + keyIDLength
+	if len(data) < headerLength {
+		return value, codecerr.ErrInvalidCiphertext
+	}
+	header := data[:headerLength]
+	keyID := string(data[len(encryptionHeader)+1 : headerLength])
+
+	key, err := c.keyProvider.Key(keyID)
+	if err != nil {
+		return value, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return value, err
+	}
+
+	body := data[headerLength:]
+	if len(body) < aead.NonceSize() {
+		return value, codecerr.ErrInvalidCiphertext
+	}
+	nonce, ciphertext := body[:aead.NonceSize()], body[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return value, codecerr.ErrInvalidCiphertext
+	}
+	return c.inner.Decode(plaintext)
+}
+
+func validateKeyID(keyID string) error {
+	if len(keyID) > maxKeyIDLength {
+		return codecerr.ErrInvalidKeyID
+	}
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
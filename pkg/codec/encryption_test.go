@@ -0,0 +1,100 @@
+package codec_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/codec"
+	"github.com/morphy76/zk/pkg/codec/codecerr"
+)
+
+var (
+	firstKey  = bytes.Repeat([]byte{1}, 32)
+	secondKey = bytes.Repeat([]byte{2}, 32)
+)
+
+func staticKeys(t *testing.T, keyID string, key []byte) *codec.StaticKeyProvider {
+	keys, err := codec.NewStaticKeyProvider(keyID, key)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return keys
+}
+
+func TestEncryptionCodec(t *testing.T) {
+	keys := staticKeys(t, "k1", firstKey)
+	c := codec.NewEncryptionCodec(codec.NewJSONCodec[sample](), keys)
+	value := sample{Name: "secret", Count: 1}
+
+	data, err := c.Encode(value)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Errorf("expected data to be encrypted, got %s", data)
+	}
+
+	decoded, err := c.Decode(data)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if decoded != value {
+		t.Errorf("expected %v, got %v", value, decoded)
+	}
+}
+
+func TestEncryptionCodecKeyRotation(t *testing.T) {
+	keys := staticKeys(t, "k1", firstKey)
+	c := codec.NewEncryptionCodec(codec.NewBytesCodec(), keys)
+
+	oldData, _ := c.Encode([]byte("old"))
+	if err := keys.Rotate("k2", secondKey); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	newData, _ := c.Encode([]byte("new"))
+
+	if decoded, err := c.Decode(oldData); err != nil || string(decoded) != "old" {
+		t.Errorf("expected old, got %s (%v)", decoded, err)
+	}
+	if decoded, err := c.Decode(newData); err != nil || string(decoded) != "new" {
+		t.Errorf("expected new, got %s (%v)", decoded, err)
+	}
+}
+
+func TestEncryptionCodecUnknownKey(t *testing.T) {
+	data, _ := codec.NewEncryptionCodec(codec.NewBytesCodec(), staticKeys(t, "k1", firstKey)).Encode([]byte("data"))
+
+	c := codec.NewEncryptionCodec(codec.NewBytesCodec(), staticKeys(t, "k2", secondKey))
+	if _, err := c.Decode(data); !codecerr.IsUnknownKey(err) {
+		t.Errorf("expected error %v, got %v", codecerr.ErrUnknownKey, err)
+	}
+}
+
+func TestEncryptionCodecTamperedData(t *testing.T) {
+	c := codec.NewEncryptionCodec(codec.NewBytesCodec(), staticKeys(t, "k1", firstKey))
+	data, _ := c.Encode([]byte("data"))
+	data[len(data)-1] ^= 0xff
+
+	if _, err := c.Decode(data); !codecerr.IsInvalidCiphertext(err) {
+		t.Errorf("expected error %v, got %v", codecerr.ErrInvalidCiphertext, err)
+	}
+	if _, err := c.Decode([]byte("plain")); !codecerr.IsInvalidCiphertext(err) {
+		t.Errorf("expected error %v, got %v", codecerr.ErrInvalidCiphertext, err)
+	}
+}
+
+func TestStaticKeyProviderInvalidKeyID(t *testing.T) {
+	longID := strings.Repeat("k", 256)
+	if _, err := codec.NewStaticKeyProvider(longID, firstKey); !codecerr.IsInvalidKeyID(err) {
+		t.Errorf("expected error %v, got %v", codecerr.ErrInvalidKeyID, err)
+	}
+
+	keys := staticKeys(t, strings.Repeat("k", 255), firstKey)
+	if err := keys.Rotate(longID, secondKey); !codecerr.IsInvalidKeyID(err) {
+		t.Errorf("expected error %v, got %v", codecerr.ErrInvalidKeyID, err)
+	}
+	if keyID, _, _ := keys.CurrentKey(); keyID == longID {
+		t.Errorf("expected the invalid key not to become the current one")
+	}
+}
@@ -4,6 +4,8 @@ Package core provides the core interfaces for the Zookeeper framework.
 package core
 
 import (
+	"context"
+	"log/slog"
 	"time"
 
 	"github.com/go-zookeeper/zk"
@@ -15,14 +17,192 @@ ZKFramework represents a Zookeeper client with higher level capabilities, wrappi
 type ZKFramework interface {
 	StatusChangeHandler
 	ShutdownHandler
+	SessionHandler
 	Namespace() string
+	UsingNamespace(namespace string) ZKFramework
+	OperationTimeout() time.Duration
+	UsingOperationTimeout(timeout time.Duration) ZKFramework
+	// Deprecated: use Executor, running the requests within a context on the current connection.
 	Cn() *zk.Conn
+	Executor() Executor
 	URL() string
+	UpdateServers(hosts []string) error
+	NegotiatedSessionTimeout() time.Duration
+	ConnectedServer() string
+	SessionID() int64
+	ConnectionStats() ConnectionStats
+	HealthCheck(ctx context.Context) (Health, error)
 	Started() bool
 	Connected() bool
+	Failed() bool
+	ConnectionState() ConnectionState
+	Events() <-chan FrameworkEvent
 	Start() error
 	WaitConnection(timeout time.Duration) error
+	StartAndWait(timeout time.Duration) error
 	Stop() error
+	StopGracefully(ctx context.Context) error
+	EnableAdminMode(superPassword string) error
+	AddAuth(scheme string, credentials []byte) error
+	AdminMode() bool
+	CircuitBreaker() CircuitBreaker
+	ACLProvider() ACLProvider
+	Extension(key any, create func() any) any
+	Logger() *slog.Logger
+}
+
+/*
+Executor runs the requests on the current connection of a framework, on absolute paths, returning the error of the context when it is done first.
+*/
+type Executor interface {
+	Get(ctx context.Context, actualPath string) ([]byte, *zk.Stat, error)
+	GetW(ctx context.Context, actualPath string) ([]byte, *zk.Stat, <-chan zk.Event, error)
+	Set(ctx context.Context, actualPath string, data []byte, version int32) (*zk.Stat, error)
+	Create(ctx context.Context, actualPath string, data []byte, flags int32, acl []zk.ACL) (string, error)
+	Delete(ctx context.Context, actualPath string, version int32) error
+	Children(ctx context.Context, actualPath string) ([]string, *zk.Stat, error)
+	ChildrenW(ctx context.Context, actualPath string) ([]string, *zk.Stat, <-chan zk.Event, error)
+	Exists(ctx context.Context, actualPath string) (bool, *zk.Stat, error)
+	ExistsW(ctx context.Context, actualPath string) (bool, *zk.Stat, <-chan zk.Event, error)
+	GetACL(ctx context.Context, actualPath string) ([]zk.ACL, *zk.Stat, error)
+	SetACL(ctx context.Context, actualPath string, acl []zk.ACL, version int32) (*zk.Stat, error)
+	Multi(ctx context.Context, ops ...any) ([]zk.MultiResponse, error)
+	Sync(ctx context.Context, actualPath string) (string, error)
+}
+
+/*
+ConnectionStats describes the connection of a framework to the Zookeeper ensemble.
+*/
+type ConnectionStats struct {
+	// State is the state of the connection.
+	State zk.State
+	// Server is the address of the ensemble member serving the session, empty when not connected.
+	Server string
+	// Servers are the configured servers of the ensemble.
+	Servers []string
+	// HostSelection is the name of the strategy choosing the server to connect to.
+	HostSelection string
+	// NegotiatedSessionTimeout is the session timeout granted by the server, zero before the first connection.
+	NegotiatedSessionTimeout time.Duration
+}
+
+/*
+Health is the report of a health check of a framework, usable in readiness probes.
+*/
+type Health struct {
+	// Healthy is true when the round trip to the server succeeded.
+	Healthy bool
+	// State is the state of the connection.
+	State zk.State
+	// Server is the address of the ensemble member serving the session, empty when not connected.
+	Server string
+	// Latency is the duration of the round trip to the server.
+	Latency time.Duration
+	// Servers are the reports of the four letter words sent to each server of the ensemble, when enabled.
+	Servers []ServerHealth
+}
+
+/*
+ServerHealth is the report of the four letter words sent to a server of the ensemble.
+*/
+type ServerHealth struct {
+	// Server is the address of the server.
+	Server string
+	// OK is true when the server answered imok to ruok.
+	OK bool
+	// Stats are the stats answered to srvr, nil when the server did not answer.
+	Stats *zk.ServerStats
+}
+
+/*
+ConnectionLost describes the reconnection attempts after which a framework gave up reconnecting to the Zookeeper ensemble.
+*/
+type ConnectionLost struct {
+	// Attempts is the number of reconnection attempts made.
+	Attempts int
+	// Elapsed is the time spent reconnecting.
+	Elapsed time.Duration
+	// Servers are the configured servers of the ensemble.
+	Servers []string
+}
+
+/*
+StateFailed is the terminal state of a framework which gave up reconnecting to the Zookeeper server, as decided by its retry policy.
+*/
+const StateFailed zk.State = -1000
+
+/*
+ConnectionState is the state of the connection derived by the framework from the Zookeeper states, modelled after the one of Apache Curator.
+*/
+type ConnectionState int
+
+const (
+	// ConnectionStateNone is the state of a framework not yet connected since it started.
+	ConnectionStateNone ConnectionState = iota
+	// ConnectionStateConnected is the first connection of the framework.
+	ConnectionStateConnected
+	// ConnectionStateSuspended is the loss of the connection, the session may still be alive.
+	ConnectionStateSuspended
+	// ConnectionStateReconnected is the connection established again after being suspended, lost or read-only.
+	ConnectionStateReconnected
+	// ConnectionStateLost is the loss of the session, expired or given up by the framework.
+	ConnectionStateLost
+	// ConnectionStateReadOnly is the connection to a server in read-only mode.
+	ConnectionStateReadOnly
+)
+
+var connectionStateNames = map[ConnectionState]string{
+	ConnectionStateNone:        "NONE",
+	ConnectionStateConnected:   "CONNECTED",
+	ConnectionStateSuspended:   "SUSPENDED",
+	ConnectionStateReconnected: "RECONNECTED",
+	ConnectionStateLost:        "LOST",
+	ConnectionStateReadOnly:    "READ_ONLY",
+}
+
+/*
+String returns the name of the connection state.
+*/
+func (s ConnectionState) String() string {
+	if name, found := connectionStateNames[s]; found {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+/*
+IsConnected returns whether the operations can reach the Zookeeper server in the connection state.
+*/
+func (s ConnectionState) IsConnected() bool {
+	return s == ConnectionStateConnected || s == ConnectionStateReconnected || s == ConnectionStateReadOnly
+}
+
+/*
+ACLProvider decides the ACL of the nodes created through a framework without an explicit ACL, e.g. to secure subtrees differently.
+*/
+type ACLProvider interface {
+	// GetDefaultACL returns the ACL of the nodes outside any specific subtree.
+	GetDefaultACL() []zk.ACL
+	// GetACLForPath returns the ACL of the node at the given absolute path.
+	GetACLForPath(actualPath string) []zk.ACL
+}
+
+/*
+CircuitBreaker guards the operations run through a framework, failing them fast while the Zookeeper ensemble looks unavailable.
+*/
+type CircuitBreaker interface {
+	// Allow returns an error when the operation must fail fast instead of reaching the server.
+	Allow() error
+	// Done records the outcome of an allowed operation.
+	Done(err error)
+}
+
+/*
+Subscription is the registration of a listener, returned when subscribing it.
+*/
+type Subscription interface {
+	// Cancel removes the listener, the following calls returning the result of the first one.
+	Cancel() error
 }
 
 /*
@@ -30,7 +210,9 @@ StatusChangeHandler is an interface for listening to Zookeeper connection status
 */
 type StatusChangeHandler interface {
 	AddStatusChangeListener(listener StatusChangeListener) error
+	SubscribeStatusChange(listener StatusChangeListener) (Subscription, error)
 	RemoveStatusChangeListener(listener StatusChangeListener) error
+	RemoveStatusChangeListenerByID(id string) error
 	NotifyStatusChange()
 }
 
@@ -39,10 +221,38 @@ ShutdownHandler is an interface for shutting down the Zookeeper client.
 */
 type ShutdownHandler interface {
 	AddShutdownListener(listener ShutdownListener) error
+	SubscribeShutdown(listener ShutdownListener) (Subscription, error)
 	RemoveShutdownListener(listener ShutdownListener) error
+	RemoveShutdownListenerByID(id string) error
 	NotifyShutdown()
 }
 
+/*
+SessionHandler is an interface for listening to the loss of the Zookeeper session.
+*/
+type SessionHandler interface {
+	AddSessionListener(listener SessionListener) error
+	SubscribeSession(listener SessionListener) (Subscription, error)
+	RemoveSessionListener(listener SessionListener) error
+	RemoveSessionListenerByID(id string) error
+}
+
+const (
+	// PriorityInternal is the priority of the framework recipes that must react before the application, e.g. re-arming watches.
+	PriorityInternal = 100
+	// PriorityDefault is the priority of the listeners not implementing PrioritizedListener.
+	PriorityDefault = 0
+)
+
+/*
+PrioritizedListener is implemented by the status change, shutdown and session listeners notified with a priority other than PriorityDefault.
+
+Listeners with a higher priority are notified first, listeners with the same priority are notified in registration order.
+*/
+type PrioritizedListener interface {
+	Priority() int
+}
+
 /*
 StatusChangeListener is an interface for listening to Zookeeper connection status changes.
 */
@@ -52,6 +262,90 @@ type StatusChangeListener interface {
 	Stop()
 }
 
+/*
+ContextStatusChangeListener is implemented by the status change listeners honouring the listener deadline, the context is cancelled when it is exceeded.
+*/
+type ContextStatusChangeListener interface {
+	OnStatusChangeWithContext(ctx context.Context, zkFramework ZKFramework, previous zk.State, current zk.State) error
+}
+
+/*
+FrameworkEvent is an event of a framework received from the channels returned by Events, the consumers switching on its type.
+*/
+type FrameworkEvent interface {
+	// Name returns the name of the kind of event.
+	Name() string
+}
+
+/*
+StateChanged is received when the Zookeeper state of the connection changes.
+*/
+type StateChanged struct {
+	// Previous is the previous Zookeeper state.
+	Previous zk.State
+	// Current is the current Zookeeper state.
+	Current zk.State
+	// ConnectionState is the connection state derived from the current Zookeeper state.
+	ConnectionState ConnectionState
+}
+
+/*
+Name returns state-changed.
+*/
+func (e StateChanged) Name() string {
+	return "state-changed"
+}
+
+/*
+SessionExpired is received when the session is lost, the ephemeral nodes and the watches of the session being gone.
+*/
+type SessionExpired struct {
+	// SessionID is the ID of the expired session.
+	SessionID int64
+}
+
+/*
+Name returns session-expired.
+*/
+func (e SessionExpired) Name() string {
+	return "session-expired"
+}
+
+/*
+Reconnected is received when the connection is established again after being suspended, lost or read-only.
+*/
+type Reconnected struct {
+	// SessionID is the ID of the session, different from the previous one when it was lost.
+	SessionID int64
+}
+
+/*
+Name returns reconnected.
+*/
+func (e Reconnected) Name() string {
+	return "reconnected"
+}
+
+/*
+ShuttingDown is the last event received before the channel is closed, when the framework is stopped.
+*/
+type ShuttingDown struct{}
+
+/*
+Name returns shutting-down.
+*/
+func (e ShuttingDown) Name() string {
+	return "shutting-down"
+}
+
+/*
+ConnectionStateListener is implemented by the status change listeners notified of the changes of the connection state derived by the framework,
+after being notified of the Zookeeper state causing them.
+*/
+type ConnectionStateListener interface {
+	OnConnectionStateChange(zkFramework ZKFramework, state ConnectionState) error
+}
+
 /*
 ShutdownListener is an interface for listening to Zookeeper client shutdown events.
 */
@@ -60,3 +354,23 @@ type ShutdownListener interface {
 	OnShutdown(zkFramework ZKFramework) error
 	Stop()
 }
+
+/*
+SessionListener is an interface for listening to the loss of the Zookeeper session, as opposed to the loss of the connection:
+the ephemeral nodes and the watches of an expired session are gone and must be rebuilt on the new one.
+
+OnSessionExpired is called when the session expires or is replaced by a new one, OnSessionReestablished once a new session is established.
+*/
+type SessionListener interface {
+	UUID() string
+	OnSessionExpired(zkFramework ZKFramework) error
+	OnSessionReestablished(zkFramework ZKFramework) error
+	Stop()
+}
+
+/*
+ContextShutdownListener is implemented by the shutdown listeners honouring the listener deadline, the context is cancelled when it is exceeded.
+*/
+type ContextShutdownListener interface {
+	OnShutdownWithContext(ctx context.Context, zkFramework ZKFramework) error
+}
@@ -0,0 +1,213 @@
+/*
+Package deadletter keeps the notifications and events whose delivery failed, so they can be inspected and replayed instead of being lost.
+*/
+package deadletter
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/morphy76/zk/pkg/deadletter/deadlettererr"
+)
+
+/*
+Letter is a failed delivery captured by a dead-letter buffer.
+*/
+type Letter struct {
+	// ID identifies the letter in the buffer.
+	ID uint64
+	// Source identifies the failed recipient, e.g. the name of a sink or the UUID of a listener.
+	Source string
+	// Payload is what failed to be delivered, e.g. a batch of events or a status change.
+	Payload any
+	// Err is the error of the last failed delivery.
+	Err error
+	// Attempts is the number of failed deliveries, replays included.
+	Attempts int
+	// Time is the time the letter was captured.
+	Time time.Time
+
+	replay func() error
+}
+
+/*
+Buffer is a bounded dead-letter buffer, the oldest letters are discarded when it is full.
+*/
+type Buffer struct {
+	capacity  int
+	letters   []*Letter
+	nextID    uint64
+	discarded int64
+	logger    *slog.Logger
+	lock      sync.Mutex
+}
+
+const defaultCapacity = 1000
+
+/*
+NewBuffer creates a dead-letter buffer holding up to the given number of letters, a non-positive capacity meaning 1000.
+*/
+func NewBuffer(capacity int) *Buffer {
+	return NewBufferWithLogger(capacity, slog.Default())
+}
+
+/*
+NewBufferWithLogger creates a dead-letter buffer holding up to the given number of letters, logging the discarded letters to the given logger.
+*/
+func NewBufferWithLogger(capacity int, logger *slog.Logger) *Buffer {
+	if capacity <= 0 {
+		capacity = defaultCapacity
+	}
+	return &Buffer{
+		capacity: capacity,
+		letters:  []*Letter{},
+		logger:   logger,
+	}
+}
+
+/*
+Add captures a failed delivery, replay is the function delivering the payload again.
+*/
+func (b *Buffer) Add(source string, payload any, err error, attempts int, replay func() error) Letter {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.nextID++
+	letter := &Letter{
+		ID:       b.nextID,
+		Source:   source,
+		Payload:  payload,
+		Err:      err,
+		Attempts: attempts,
+		Time:     time.Now(),
+		replay:   replay,
+	}
+	if len(b.letters) == b.capacity {
+		b.logger.Warn("dead letters buffer full, discarding letter", "letter", b.letters[0].ID, "source", b.letters[0].Source)
+		b.letters = b.letters[1:]
+		b.discarded++
+	}
+	b.letters = append(b.letters, letter)
+	return *letter
+}
+
+/*
+Letters returns the letters in the buffer, oldest first.
+*/
+func (b *Buffer) Letters() []Letter {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	letters := make([]Letter, len(b.letters))
+	for i, letter := range b.letters {
+		letters[i] = *letter
+	}
+	return letters
+}
+
+/*
+Len returns the number of letters in the buffer.
+*/
+func (b *Buffer) Len() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.letters)
+}
+
+/*
+Discarded returns the number of letters discarded because the buffer was full.
+*/
+func (b *Buffer) Discarded() int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.discarded
+}
+
+/*
+Replay delivers the letter again, removing it from the buffer when the delivery succeeds.
+*/
+func (b *Buffer) Replay(id uint64) error {
+	b.lock.Lock()
+	letter := b.find(id)
+	b.lock.Unlock()
+	if letter == nil {
+		return deadlettererr.ErrUnknownLetter
+	}
+
+	err := letter.replay()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if err != nil {
+		letter.Err = err
+		letter.Attempts++
+		return err
+	}
+	b.remove(id)
+	return nil
+}
+
+/*
+ReplayAll replays every letter in the buffer, oldest first, returning the number of letters delivered and the last error.
+*/
+func (b *Buffer) ReplayAll() (int, error) {
+	var lastErr error
+	replayed := 0
+	for _, letter := range b.Letters() {
+		err := b.Replay(letter.ID)
+		if deadlettererr.IsUnknownLetter(err) {
+			continue
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		replayed++
+	}
+	return replayed, lastErr
+}
+
+/*
+Discard removes the letter from the buffer without delivering it.
+*/
+func (b *Buffer) Discard(id uint64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.find(id) == nil {
+		return deadlettererr.ErrUnknownLetter
+	}
+	b.remove(id)
+	return nil
+}
+
+/*
+Clear removes every letter from the buffer.
+*/
+func (b *Buffer) Clear() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.letters = []*Letter{}
+}
+
+func (b *Buffer) find(id uint64) *Letter {
+	for _, letter := range b.letters {
+		if letter.ID == id {
+			return letter
+		}
+	}
+	return nil
+}
+
+func (b *Buffer) remove(id uint64) {
+	for i, letter := range b.letters {
+		if letter.ID == id {
+			b.letters = append(b.letters[:i], b.letters[i+1:]...)
+			return
+		}
+	}
+}
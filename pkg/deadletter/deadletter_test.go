@@ -0,0 +1,116 @@
+package deadletter_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/deadletter"
+	"github.com/morphy76/zk/pkg/deadletter/deadlettererr"
+)
+
+const unexpectedErrorFmt = "unexpected error %v"
+
+func TestBuffer(t *testing.T) {
+	buffer := deadletter.NewBuffer(2)
+	failing := errors.New("failing")
+
+	delivered := []string{}
+	deliver := func(payload string, fail *bool) func() error {
+		return func() error {
+			if *fail {
+				return failing
+			}
+			delivered = append(delivered, payload)
+			return nil
+		}
+	}
+
+	failA, failB, failC := false, true, false
+	buffer.Add("sink", "a", failing, 3, deliver("a", &failA))
+	second := buffer.Add("sink", "b", failing, 3, deliver("b", &failB))
+	third := buffer.Add("listener", "c", failing, 1, deliver("c", &failC))
+
+	t.Run("Bounded capacity", func(t *testing.T) {
+		letters := buffer.Letters()
+		if len(letters) != 2 || letters[0].ID != second.ID || letters[1].ID != third.ID {
+			t.Fatalf("expected the oldest letter to be discarded, got %+v", letters)
+		}
+		if buffer.Discarded() != 1 {
+			t.Errorf("expected 1 discarded letter, got %d", buffer.Discarded())
+		}
+		if letters[0].Source != "sink" || letters[0].Payload != "b" || letters[0].Attempts != 3 || letters[0].Err != failing {
+			t.Errorf("unexpected letter %+v", letters[0])
+		}
+	})
+
+	t.Run("Failed replay is kept", func(t *testing.T) {
+		if err := buffer.Replay(second.ID); err != failing {
+			t.Errorf("expected error %v, got %v", failing, err)
+		}
+		if letters := buffer.Letters(); len(letters) != 2 || letters[0].Attempts != 4 {
+			t.Errorf("expected the letter to be kept with one more attempt, got %+v", letters)
+		}
+	})
+
+	t.Run("Replay all", func(t *testing.T) {
+		failB = false
+		replayed, err := buffer.ReplayAll()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if replayed != 2 || buffer.Len() != 0 {
+			t.Errorf("expected 2 replayed letters and an empty buffer, got %d and %d", replayed, buffer.Len())
+		}
+		if len(delivered) != 2 || delivered[0] != "b" || delivered[1] != "c" {
+			t.Errorf("expected b and c to be delivered in order, got %v", delivered)
+		}
+	})
+
+	t.Run("Unknown letter", func(t *testing.T) {
+		if err := buffer.Replay(second.ID); !deadlettererr.IsUnknownLetter(err) {
+			t.Errorf("expected error %v, got %v", deadlettererr.ErrUnknownLetter, err)
+		}
+		if err := buffer.Discard(second.ID); !deadlettererr.IsUnknownLetter(err) {
+			t.Errorf("expected error %v, got %v", deadlettererr.ErrUnknownLetter, err)
+		}
+	})
+
+	t.Run("Discard and clear", func(t *testing.T) {
+		letter := buffer.Add("sink", "d", failing, 1, func() error { return nil })
+		buffer.Add("sink", "e", failing, 1, func() error { return nil })
+		if err := buffer.Discard(letter.ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if buffer.Len() != 1 {
+			t.Errorf("expected 1 letter, got %d", buffer.Len())
+		}
+		buffer.Clear()
+		if buffer.Len() != 0 {
+			t.Errorf("expected an empty buffer, got %d", buffer.Len())
+		}
+	})
+}
+
+func TestDefaultCapacity(t *testing.T) {
+	buffer := deadletter.NewBuffer(0)
+	for i := 0; i < 1001; i++ {
+		buffer.Add("sink", i, nil, 1, func() error { return nil })
+	}
+	if buffer.Len() != 1000 {
+		t.Errorf("expected 1000 letters, got %d", buffer.Len())
+	}
+}
+
+func TestBufferWithLogger(t *testing.T) {
+	var output bytes.Buffer
+	buffer := deadletter.NewBufferWithLogger(1, slog.New(slog.NewTextHandler(&output, nil)))
+	buffer.Add("sink", 1, nil, 1, func() error { return nil })
+	buffer.Add("sink", 2, nil, 1, func() error { return nil })
+
+	if !strings.Contains(output.String(), "discarding letter") {
+		t.Errorf("expected the discarded letter to be logged, got %q", output.String())
+	}
+}
@@ -0,0 +1,18 @@
+/*
+Package deadlettererr provides error types for the deadletter package.
+*/
+package deadlettererr
+
+import "errors"
+
+/*
+ErrUnknownLetter is returned when a letter is not, or no longer, in the dead-letter buffer.
+*/
+var ErrUnknownLetter = errors.New("unknown dead letter")
+
+/*
+IsUnknownLetter checks if the error is ErrUnknownLetter.
+*/
+func IsUnknownLetter(err error) bool {
+	return err == ErrUnknownLetter
+}
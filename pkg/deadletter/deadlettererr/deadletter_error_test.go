@@ -0,0 +1,21 @@
+package deadlettererr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/deadletter/deadlettererr"
+)
+
+func TestIsUnknownLetter(t *testing.T) {
+	if !deadlettererr.IsUnknownLetter(deadlettererr.ErrUnknownLetter) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsUnknownLetterFalse(t *testing.T) {
+	err := errors.New("some error")
+	if deadlettererr.IsUnknownLetter(err) {
+		t.Errorf("expected false, got true")
+	}
+}
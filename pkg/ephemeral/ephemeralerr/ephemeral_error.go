@@ -0,0 +1,18 @@
+/*
+Package ephemeralerr provides error types for the ephemeral package.
+*/
+package ephemeralerr
+
+import "errors"
+
+/*
+ErrNotEphemeral is returned when registering a node whose create mode is not a plain ephemeral one.
+*/
+var ErrNotEphemeral = errors.New("only plain ephemeral nodes can be registered")
+
+/*
+IsNotEphemeral checks if the error is ErrNotEphemeral.
+*/
+func IsNotEphemeral(err error) bool {
+	return err == ErrNotEphemeral
+}
@@ -0,0 +1,22 @@
+package ephemeralerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/ephemeral/ephemeralerr"
+)
+
+func TestIsNotEphemeral(t *testing.T) {
+	err := ephemeralerr.ErrNotEphemeral
+	if !ephemeralerr.IsNotEphemeral(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsNotEphemeralFalse(t *testing.T) {
+	err := errors.New("some error")
+	if ephemeralerr.IsNotEphemeral(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Package ephemeral keeps ephemeral nodes, e.g. service registrations, registered across sessions by creating them again when a new session is established.
+*/
+package ephemeral
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/ephemeral/ephemeralerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+Registry creates the registered ephemeral nodes and creates them again each time the session of the framework is lost and a new one is established.
+
+The registry listens to the session of the framework from its creation until it is closed or the framework is stopped.
+*/
+type Registry struct {
+	framework core.ZKFramework
+	id        string
+	nodes     map[string]operation.CreateOptions
+	sessions  map[int64]bool
+	lock      sync.Mutex
+}
+
+/*
+NewRegistry creates a registry of the ephemeral nodes of the given framework.
+*/
+func NewRegistry(zkFramework core.ZKFramework) (*Registry, error) {
+	registry := &Registry{
+		framework: zkFramework,
+		id:        uuid.New().String(),
+		nodes:     map[string]operation.CreateOptions{},
+		sessions:  map[int64]bool{},
+	}
+	if err := zkFramework.AddSessionListener(registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+/*
+Register creates the ephemeral node with the given options and keeps it registered, the mode of the options must be zk.FlagEphemeral.
+
+A node already created by the current session, e.g. registered twice, is kept as it is.
+*/
+func (r *Registry) Register(nodeName string, options operation.CreateOptions) error {
+	if options.Mode != zk.FlagEphemeral {
+		return ephemeralerr.ErrNotEphemeral
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.create(nodeName, options); err != nil {
+		return err
+	}
+	r.nodes[nodeName] = options
+	return nil
+}
+
+/*
+Unregister deletes the node and stops keeping it registered.
+*/
+func (r *Registry) Unregister(nodeName string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.nodes, nodeName)
+	if err := operation.Delete(r.framework, nodeName); err != nil && !coreerr.IsUnknownNode(err) {
+		return err
+	}
+	return nil
+}
+
+/*
+Nodes returns the registered nodes, sorted by path.
+*/
+func (r *Registry) Nodes() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return slices.Sorted(maps.Keys(r.nodes))
+}
+
+/*
+Close stops listening to the session of the framework, the registered nodes are left to the current session.
+*/
+func (r *Registry) Close() error {
+	return r.framework.RemoveSessionListener(r)
+}
+
+/*
+UUID returns the ID of the registry as a session listener.
+*/
+func (r *Registry) UUID() string {
+	return r.id
+}
+
+/*
+Priority makes the registry recreate the nodes before the application is notified of the new session.
+*/
+func (r *Registry) Priority() int {
+	return core.PriorityInternal
+}
+
+/*
+OnSessionExpired logs the loss of the registered nodes, they are created again once a new session is established.
+*/
+func (r *Registry) OnSessionExpired(zkFramework core.ZKFramework) error {
+	zkFramework.Logger().Warn("session lost, ephemeral nodes to register again", "nodes", len(r.Nodes()))
+	return nil
+}
+
+/*
+OnSessionReestablished creates the registered nodes again.
+*/
+func (r *Registry) OnSessionReestablished(zkFramework core.ZKFramework) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	errs := []error{}
+	for nodeName, options := range r.nodes {
+		if err := r.create(nodeName, options); err != nil {
+			zkFramework.Logger().Error("registering ephemeral node again failed", "node", nodeName, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+/*
+Stop is called when the framework is stopped, the registered nodes are gone with the session.
+*/
+func (r *Registry) Stop() {
+}
+
+/*
+create creates the node, replacing the one left by the previous session of the registry when it did not expire yet; the lock must be held.
+
+A node owned by another session is left untouched, failing with zk.ErrNodeExists.
+*/
+func (r *Registry) create(nodeName string, options operation.CreateOptions) error {
+	for {
+		session := r.framework.SessionID()
+		err := operation.CreateWithOptions(r.framework, nodeName, options)
+		if err == nil {
+			r.sessions[session] = true
+			return nil
+		}
+		if !errors.Is(err, zk.ErrNodeExists) {
+			return err
+		}
+
+		stat, err := operation.Stat(r.framework, nodeName)
+		if errors.Is(err, zk.ErrNoNode) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if stat.EphemeralOwner == session {
+			return nil
+		}
+		if !r.sessions[stat.EphemeralOwner] {
+			return zk.ErrNodeExists
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.framework.OperationTimeout())
+		err = operation.Executor(r.framework).Delete(ctx, path.Join(r.framework.Namespace(), nodeName), stat.Version)
+		cancel()
+		if err != nil && !errors.Is(err, zk.ErrNoNode) && !errors.Is(err, zk.ErrBadVersion) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package ephemeral_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/ephemeral"
+	"github.com/morphy76/zk/pkg/ephemeral/ephemeralerr"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestRegistry(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	ephemeralOptions := operation.NewCreateOptionsBuilder().WithMode(zk.FlagEphemeral).Build()
+
+	t.Run("Register and unregister an ephemeral node", func(t *testing.T) {
+		t.Log("Register and unregister an ephemeral node")
+		registry, err := ephemeral.NewRegistry(zkFramework)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer registry.Close()
+
+		nodeName := uuid.New().String()
+		if err := registry.Register(nodeName, ephemeralOptions); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := registry.Register(nodeName, ephemeralOptions); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if nodes := registry.Nodes(); len(nodes) != 1 || nodes[0] != nodeName {
+			t.Errorf("expected nodes [%s], got %v", nodeName, nodes)
+		}
+
+		if err := registry.Unregister(nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if exists, err := operation.Exists(zkFramework, nodeName); err != nil || exists {
+			t.Errorf("expected the node to be deleted, got %v, %v", exists, err)
+		}
+		if nodes := registry.Nodes(); len(nodes) != 0 {
+			t.Errorf("expected no nodes, got %v", nodes)
+		}
+	})
+
+	t.Run("Register a persistent node", func(t *testing.T) {
+		t.Log("Register a persistent node")
+		registry, err := ephemeral.NewRegistry(zkFramework)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer registry.Close()
+
+		err = registry.Register(uuid.New().String(), operation.NewCreateOptionsBuilder().Build())
+		if !ephemeralerr.IsNotEphemeral(err) {
+			t.Errorf("expected error %v, got %v", ephemeralerr.ErrNotEphemeral, err)
+		}
+	})
+
+	t.Run("Leave the nodes of other sessions untouched", func(t *testing.T) {
+		t.Log("Leave the nodes of other sessions untouched")
+		other, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer other.Stop()
+
+		nodeName := uuid.New().String()
+		if err := operation.CreateWithOptions(other, nodeName, ephemeralOptions); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		registry, err := ephemeral.NewRegistry(zkFramework)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer registry.Close()
+		if err := registry.Register(nodeName, ephemeralOptions); err != zk.ErrNodeExists {
+			t.Errorf("expected error %v, got %v", zk.ErrNodeExists, err)
+		}
+	})
+}
+
+func TestRegistryAfterSessionLoss(t *testing.T) {
+	first, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer first.Close()
+	second, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer second.Close()
+
+	zkFramework, err := framework.CreateFrameworkWithOptions(first.Addr(), framework.WithSessionTimeout(4*time.Second))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+	if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	registry, err := ephemeral.NewRegistry(zkFramework)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	nodeName := uuid.New().String()
+	if err := registry.Register(nodeName, operation.NewCreateOptionsBuilder().WithMode(zk.FlagEphemeral).Build()); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	before := zkFramework.SessionID()
+
+	if err := zkFramework.UpdateServers([]string{second.Addr()}); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	first.Close()
+
+	observer, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer observer.Stop()
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		stat, err := operation.Stat(observer, nodeName)
+		if err == nil && stat.EphemeralOwner != before && stat.EphemeralOwner == zkFramework.SessionID() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the node to be registered again by the new session, got %v, %v", stat, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,298 @@
+/*
+Package eventbus publishes framework-owned, typed events on a unified bus, subscribed by topic, decoupling the users from the events of the underlying Zookeeper client.
+*/
+package eventbus
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const rearmInterval = time.Second
+
+/*
+Subscription receives the events published on the subscribed topics.
+*/
+type Subscription struct {
+	// C is the channel the events are delivered to, closed when unsubscribed.
+	C <-chan Event
+
+	ch      chan Event
+	topics  map[Topic]bool
+	dropped atomic.Int64
+}
+
+/*
+Dropped returns the number of events dropped because the subscription buffer was full.
+*/
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+func (s *Subscription) accepts(topic Topic) bool {
+	return len(s.topics) == 0 || s.topics[topic]
+}
+
+/*
+Bus publishes the connection state changes of a framework and the changes of the watched nodes to its subscriptions.
+
+Events are delivered without blocking the publisher: an event is dropped for a subscription whose buffer is full.
+*/
+type Bus struct {
+	id        string
+	framework core.ZKFramework
+
+	subscriptions map[*Subscription]bool
+	watches       map[string]chan struct{}
+	reconnected   chan struct{}
+	closed        bool
+	lock          sync.Mutex
+}
+
+/*
+NewBus creates a bus publishing the events of the given framework.
+*/
+func NewBus(zkFramework core.ZKFramework) (*Bus, error) {
+	b := &Bus{
+		id:            uuid.New().String(),
+		framework:     zkFramework,
+		subscriptions: make(map[*Subscription]bool),
+		watches:       make(map[string]chan struct{}),
+		reconnected:   make(chan struct{}),
+	}
+	if err := zkFramework.AddStatusChangeListener(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+/*
+UUID returns the identifier of the bus as a status change listener.
+*/
+func (b *Bus) UUID() string {
+	return b.id
+}
+
+/*
+Priority notifies the bus before the application listeners, so that the lost watches are armed again first.
+*/
+func (b *Bus) Priority() int {
+	return core.PriorityInternal
+}
+
+/*
+Subscribe subscribes to the given topics, all of them when none is given, buffering up to the given number of events.
+*/
+func (b *Bus) Subscribe(buffer int, topics ...Topic) *Subscription {
+	ch := make(chan Event, buffer)
+	s := &Subscription{
+		C:      ch,
+		ch:     ch,
+		topics: make(map[Topic]bool, len(topics)),
+	}
+	for _, topic := range topics {
+		s.topics[topic] = true
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		close(ch)
+		return s
+	}
+	b.subscriptions[s] = true
+	return s
+}
+
+/*
+Unsubscribe stops delivering events to the subscription and closes its channel.
+*/
+func (b *Bus) Unsubscribe(s *Subscription) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !b.subscriptions[s] {
+		return
+	}
+	delete(b.subscriptions, s)
+	close(s.ch)
+}
+
+/*
+Publish delivers the event to every subscription of its topic.
+*/
+func (b *Bus) Publish(event Event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for s := range b.subscriptions {
+		if !s.accepts(event.Topic()) {
+			continue
+		}
+		select {
+		case s.ch <- event:
+		default:
+			s.dropped.Add(1)
+			b.framework.Logger().Warn("event bus dropping event, subscription buffer full", "bus", b.id, "topic", event.Topic())
+		}
+	}
+}
+
+/*
+Watch publishes the creation, the data changes and the deletion of the node at the given path, which may not exist yet.
+
+The watch is armed again after each change and after each reconnection, changes happening in between are coalesced.
+*/
+func (b *Bus) Watch(nodeName string) error {
+	actualPath := path.Join(b.framework.Namespace(), nodeName)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, watching := b.watches[actualPath]; watching || b.closed {
+		return nil
+	}
+
+	stat, events, err := b.arm(actualPath)
+	if err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	b.watches[actualPath] = stop
+	go b.watch(actualPath, stat, events, stop)
+	return nil
+}
+
+/*
+Unwatch stops publishing the changes of the node at the given path.
+*/
+func (b *Bus) Unwatch(nodeName string) {
+	actualPath := path.Join(b.framework.Namespace(), nodeName)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if stop, watching := b.watches[actualPath]; watching {
+		close(stop)
+		delete(b.watches, actualPath)
+	}
+}
+
+/*
+OnStatusChange publishes the connection state change and arms the lost watches again once connected.
+*/
+func (b *Bus) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	b.Publish(ConnectionStateChanged{Previous: previous, Current: current})
+
+	if current == zk.StateHasSession {
+		b.lock.Lock()
+		close(b.reconnected)
+		b.reconnected = make(chan struct{})
+		b.lock.Unlock()
+	}
+	return nil
+}
+
+/*
+Stop stops every watch and closes every subscription.
+*/
+func (b *Bus) Stop() {
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		return
+	}
+	b.closed = true
+	for actualPath, stop := range b.watches {
+		close(stop)
+		delete(b.watches, actualPath)
+	}
+	for s := range b.subscriptions {
+		delete(b.subscriptions, s)
+		close(s.ch)
+	}
+	b.lock.Unlock()
+
+	b.framework.RemoveStatusChangeListener(b)
+}
+
+func (b *Bus) arm(actualPath string) (*zk.Stat, <-chan zk.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.framework.OperationTimeout())
+	defer cancel()
+	exists, stat, events, err := operation.Executor(b.framework).ExistsW(ctx, actualPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, events, nil
+	}
+	return stat, events, nil
+}
+
+func (b *Bus) watch(actualPath string, stat *zk.Stat, events <-chan zk.Event, stop chan struct{}) {
+	nodeName := strings.TrimPrefix(strings.TrimPrefix(actualPath, b.framework.Namespace()), "/")
+
+	for {
+		select {
+		case <-stop:
+			return
+		case e := <-events:
+			if e.Type == zk.EventNotWatching {
+				b.Publish(WatchLost{Path: nodeName, Err: e.Err})
+				if !b.waitReconnection(stop) {
+					return
+				}
+			}
+		}
+
+		next, nextEvents, err := b.arm(actualPath)
+		for err != nil {
+			b.Publish(WatchLost{Path: nodeName, Err: err})
+			if !b.waitReconnection(stop) {
+				return
+			}
+			next, nextEvents, err = b.arm(actualPath)
+		}
+
+		b.publishChanges(nodeName, stat, next)
+		stat, events = next, nextEvents
+	}
+}
+
+func (b *Bus) waitReconnection(stop chan struct{}) bool {
+	b.lock.Lock()
+	reconnected := b.reconnected
+	b.lock.Unlock()
+
+	select {
+	case <-stop:
+		return false
+	case <-reconnected:
+	case <-time.After(rearmInterval):
+	}
+	return true
+}
+
+func (b *Bus) publishChanges(nodeName string, previous *zk.Stat, current *zk.Stat) {
+	switch {
+	case previous == nil && current != nil:
+		b.Publish(NodeCreated{Path: nodeName, Stat: *current})
+	case previous != nil && current == nil:
+		b.Publish(NodeDeleted{Path: nodeName, Stat: *previous})
+	case previous != nil && current != nil && previous.Czxid != current.Czxid:
+		b.Publish(NodeDeleted{Path: nodeName, Stat: *previous})
+		b.Publish(NodeCreated{Path: nodeName, Stat: *current})
+	case previous != nil && current != nil && previous.Mzxid != current.Mzxid:
+		b.Publish(NodeDataChanged{Path: nodeName, OldStat: *previous, NewStat: *current})
+	}
+}
@@ -0,0 +1,143 @@
+package eventbus_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/eventbus"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestSubscriptions(t *testing.T) {
+	zkFramework, err := framework.CreateFramework(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	bus, err := eventbus.NewBus(zkFramework)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer bus.Stop()
+	if bus.Priority() != core.PriorityInternal {
+		t.Errorf("expected the bus to be notified before the application listeners")
+	}
+
+	all := bus.Subscribe(10)
+	connection := bus.Subscribe(1, eventbus.TopicConnectionStateChanged)
+
+	bus.Publish(eventbus.NodeCreated{Path: "a"})
+	bus.Publish(eventbus.ConnectionStateChanged{Previous: zk.StateDisconnected, Current: zk.StateHasSession})
+	bus.Publish(eventbus.ConnectionStateChanged{Previous: zk.StateHasSession, Current: zk.StateDisconnected})
+
+	if len(all.C) != 3 {
+		t.Errorf("expected 3 events, got %d", len(all.C))
+	}
+	event := <-connection.C
+	if changed, ok := event.(eventbus.ConnectionStateChanged); !ok || changed.Current != zk.StateHasSession {
+		t.Errorf("expected a connection state change, got %+v", event)
+	}
+	if connection.Dropped() != 1 {
+		t.Errorf("expected 1 dropped event, got %d", connection.Dropped())
+	}
+
+	bus.Unsubscribe(connection)
+	if _, open := <-connection.C; open {
+		t.Errorf("expected the subscription channel to be closed")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	bus, err := eventbus.NewBus(zkFramework)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer bus.Stop()
+
+	nodes := bus.Subscribe(10, eventbus.TopicNodeCreated, eventbus.TopicNodeDataChanged, eventbus.TopicNodeDeleted)
+	nodeName := uuid.New().String()
+	if err := bus.Watch(nodeName); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	next := func() eventbus.Event {
+		select {
+		case event := <-nodes.C:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected an event")
+			return nil
+		}
+	}
+
+	if err := operation.Create(zkFramework, nodeName); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if created, ok := next().(eventbus.NodeCreated); !ok || created.Path != nodeName {
+		t.Errorf("expected node %s to be created, got %+v", nodeName, created)
+	}
+
+	if _, err := operation.Update(zkFramework, nodeName, []byte("data")); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	changed, ok := next().(eventbus.NodeDataChanged)
+	if !ok || changed.OldStat.Version != 0 || changed.NewStat.Version != 1 {
+		t.Errorf("expected the data of node %s to change from version 0 to 1, got %+v", nodeName, changed)
+	}
+
+	if err := operation.Delete(zkFramework, nodeName); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if deleted, ok := next().(eventbus.NodeDeleted); !ok || deleted.Stat.Version != 1 {
+		t.Errorf("expected node %s to be deleted, got %+v", nodeName, deleted)
+	}
+
+	bus.Unwatch(nodeName)
+	if err := operation.Create(zkFramework, nodeName); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	select {
+	case event := <-nodes.C:
+		t.Errorf("expected no event after unwatching, got %+v", event)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,118 @@
+package eventbus
+
+import (
+	"github.com/go-zookeeper/zk"
+)
+
+/*
+Topic identifies a kind of event published on the bus.
+*/
+type Topic string
+
+const (
+	// TopicNodeCreated is the topic of NodeCreated events.
+	TopicNodeCreated Topic = "node.created"
+	// TopicNodeDataChanged is the topic of NodeDataChanged events.
+	TopicNodeDataChanged Topic = "node.data-changed"
+	// TopicNodeDeleted is the topic of NodeDeleted events.
+	TopicNodeDeleted Topic = "node.deleted"
+	// TopicConnectionStateChanged is the topic of ConnectionStateChanged events.
+	TopicConnectionStateChanged Topic = "connection.state-changed"
+	// TopicWatchLost is the topic of WatchLost events.
+	TopicWatchLost Topic = "watch.lost"
+)
+
+/*
+Event is an event published on the bus.
+*/
+type Event interface {
+	// Topic returns the topic the event is published on.
+	Topic() Topic
+}
+
+/*
+NodeCreated is published when a watched node is created.
+*/
+type NodeCreated struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// Stat is the stat of the created node.
+	Stat zk.Stat
+}
+
+/*
+Topic returns TopicNodeCreated.
+*/
+func (e NodeCreated) Topic() Topic {
+	return TopicNodeCreated
+}
+
+/*
+NodeDataChanged is published when the data of a watched node changes.
+*/
+type NodeDataChanged struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// OldStat is the stat of the node before the change.
+	OldStat zk.Stat
+	// NewStat is the stat of the node after the change.
+	NewStat zk.Stat
+}
+
+/*
+Topic returns TopicNodeDataChanged.
+*/
+func (e NodeDataChanged) Topic() Topic {
+	return TopicNodeDataChanged
+}
+
+/*
+NodeDeleted is published when a watched node is deleted.
+*/
+type NodeDeleted struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// Stat is the last known stat of the node.
+	Stat zk.Stat
+}
+
+/*
+Topic returns TopicNodeDeleted.
+*/
+func (e NodeDeleted) Topic() Topic {
+	return TopicNodeDeleted
+}
+
+/*
+ConnectionStateChanged is published when the state of the connection to the Zookeeper server changes.
+*/
+type ConnectionStateChanged struct {
+	// Previous is the previous state of the connection.
+	Previous zk.State
+	// Current is the current state of the connection.
+	Current zk.State
+}
+
+/*
+Topic returns TopicConnectionStateChanged.
+*/
+func (e ConnectionStateChanged) Topic() Topic {
+	return TopicConnectionStateChanged
+}
+
+/*
+WatchLost is published when the watch of a node is lost, e.g. on disconnection; it is armed again, and the missed changes published, once connected.
+*/
+type WatchLost struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// Err is the reason the watch was lost.
+	Err error
+}
+
+/*
+Topic returns TopicWatchLost.
+*/
+func (e WatchLost) Topic() Topic {
+	return TopicWatchLost
+}
@@ -0,0 +1,352 @@
+package eventlog
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/eventlog/eventlogerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/recipes/base"
+)
+
+const (
+	membersNode   = "members"
+	offsetsNode   = "offsets"
+	memberPrefix  = "member-"
+	retryInterval = time.Second
+)
+
+/*
+Message is an entry delivered to a consumer, it must be acknowledged before the next entry of its partition is delivered.
+*/
+type Message struct {
+	Entry
+	// Partition is the root of the log the entry was read from.
+	Partition string
+
+	ack func() error
+}
+
+/*
+Ack commits the position of the message as the offset of its partition, releasing the next entry of the partition.
+*/
+func (m Message) Ack() error {
+	return m.ack()
+}
+
+/*
+Consumer is a member of a consumer group reading a set of logs, the partitions, each of them assigned to a single member of the group.
+
+The members join the <group>/members node and the partitions are spread among them by sequence number, rebalancing when a member joins or leaves.
+The offset of each partition is committed in the <group>/offsets node when a message is acknowledged: a partition changing owner, or a restarted member,
+resumes from the committed offset, so that unacknowledged messages are delivered again (at-least-once delivery).
+*/
+type Consumer struct {
+	framework  core.ZKFramework
+	group      string
+	partitions map[string]*Log
+	messages   chan Message
+
+	participant base.Participant
+	assigned    map[string]context.CancelFunc
+	stop        context.CancelFunc
+	running     bool
+	lock        sync.Mutex
+	workers     sync.WaitGroup
+}
+
+/*
+NewConsumer creates a consumer of the given group, reading the given logs.
+*/
+func NewConsumer(zkFramework core.ZKFramework, group string, partitions ...*Log) *Consumer {
+	byRoot := map[string]*Log{}
+	for _, partition := range partitions {
+		byRoot[partition.root] = partition
+	}
+	return &Consumer{
+		framework:  zkFramework,
+		group:      group,
+		partitions: byRoot,
+		messages:   make(chan Message),
+		assigned:   map[string]context.CancelFunc{},
+	}
+}
+
+/*
+Messages returns the channel delivering the messages of the assigned partitions, it is not closed when the consumer stops.
+*/
+func (c *Consumer) Messages() <-chan Message {
+	return c.messages
+}
+
+/*
+Assigned returns the partitions currently assigned to the consumer, sorted by root.
+*/
+func (c *Consumer) Assigned() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return slices.Sorted(maps.Keys(c.assigned))
+}
+
+/*
+Committed returns the committed offset of a partition for the group, Beginning when nothing has been committed yet.
+*/
+func (c *Consumer) Committed(partition string) (Position, error) {
+	position, _, err := c.readOffset(partition)
+	return position, err
+}
+
+/*
+Start joins the group and starts consuming the assigned partitions, until stopped.
+*/
+func (c *Consumer) Start() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.running {
+		return eventlogerr.ErrConsumerAlreadyStarted
+	}
+	participant, err := base.Join(c.framework, path.Join(c.group, membersNode), memberPrefix, nil)
+	if err != nil {
+		return err
+	}
+	c.participant = participant
+	c.running = true
+
+	ctx, stop := context.WithCancel(context.Background())
+	c.stop = stop
+	c.workers.Add(1)
+	go c.rebalance(ctx)
+	return nil
+}
+
+/*
+Stop stops consuming and leaves the group, handing the partitions over to the other members.
+*/
+func (c *Consumer) Stop() {
+	c.lock.Lock()
+	if !c.running {
+		c.lock.Unlock()
+		return
+	}
+	c.running = false
+	c.stop()
+	c.lock.Unlock()
+
+	c.workers.Wait()
+	if err := base.Leave(c.framework, c.participant); err != nil {
+		c.framework.Logger().Error("leaving consumer group failed", "group", c.group, "error", err)
+	}
+}
+
+/*
+rebalance assigns the partitions to the consumer each time the members of the group change.
+
+The previous owner of a partition may still be delivering it for a short while, duplicates being allowed by the at-least-once delivery.
+*/
+func (c *Consumer) rebalance(ctx context.Context) {
+	defer c.workers.Done()
+	defer c.assign(ctx, []string{})
+
+	for {
+		events, err := c.rebalanceOnce(ctx)
+		if err != nil {
+			c.framework.Logger().Error("rebalancing consumer group failed", "group", c.group, "error", err)
+			select {
+			case <-time.After(retryInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case <-events:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Consumer) rebalanceOnce(ctx context.Context) (<-chan zk.Event, error) {
+	parent := path.Join(c.group, membersNode)
+	requestCtx, cancel := context.WithTimeout(ctx, c.framework.OperationTimeout())
+	children, _, events, err := operation.Executor(c.framework).ChildrenW(requestCtx, path.Join(c.framework.Namespace(), parent))
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	members := []base.Participant{}
+	for _, child := range children {
+		if member, ok := base.ParseName(parent, child); ok && member.Prefix == memberPrefix {
+			members = append(members, member)
+		}
+	}
+	slices.SortFunc(members, func(a, b base.Participant) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+
+	self := slices.IndexFunc(members, func(member base.Participant) bool { return member.Name == c.participant.Name })
+	if self < 0 {
+		c.framework.Logger().Warn("consumer left group, joining again", "consumer", c.participant.Name, "group", c.group)
+		c.assign(ctx, []string{})
+		participant, err := base.Join(c.framework, parent, memberPrefix, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.participant = participant
+		return c.rebalanceOnce(ctx)
+	}
+
+	wanted := []string{}
+	for i, partition := range slices.Sorted(maps.Keys(c.partitions)) {
+		if i%len(members) == self {
+			wanted = append(wanted, partition)
+		}
+	}
+	c.assign(ctx, wanted)
+	return events, nil
+}
+
+/*
+assign starts consuming the wanted partitions and stops consuming the other ones.
+*/
+func (c *Consumer) assign(ctx context.Context, wanted []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for partition, revoke := range c.assigned {
+		if !slices.Contains(wanted, partition) {
+			c.framework.Logger().Info("partition revoked from consumer", "partition", partition, "consumer", c.participant.Name)
+			revoke()
+			delete(c.assigned, partition)
+		}
+	}
+	for _, partition := range wanted {
+		if _, ok := c.assigned[partition]; ok {
+			continue
+		}
+		c.framework.Logger().Info("partition assigned to consumer", "partition", partition, "consumer", c.participant.Name)
+		partitionCtx, revoke := context.WithCancel(ctx)
+		c.assigned[partition] = revoke
+		c.workers.Add(1)
+		go c.consume(partitionCtx, partition)
+	}
+}
+
+/*
+consume delivers the entries of a partition following its committed offset, one at a time, until the partition is revoked.
+*/
+func (c *Consumer) consume(ctx context.Context, partition string) {
+	defer c.workers.Done()
+
+	position, err := c.Committed(partition)
+	for err != nil {
+		c.framework.Logger().Error("reading the offset of partition failed", "partition", partition, "error", err)
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+			return
+		}
+		position, err = c.Committed(partition)
+	}
+
+	reader := c.partitions[partition].NewReaderAfter(position)
+	for {
+		entry, err := reader.Next(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.framework.Logger().Error("reading partition failed", "partition", partition, "error", err)
+			select {
+			case <-time.After(retryInterval):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		acked := make(chan bool)
+		var once sync.Once
+		message := Message{Entry: entry, Partition: partition, ack: func() error {
+			if err := c.commit(partition, entry.Position); err != nil {
+				return err
+			}
+			once.Do(func() { close(acked) })
+			return nil
+		}}
+
+		select {
+		case c.messages <- message:
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-acked:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Consumer) offsetNode(partition string) string {
+	return path.Join(c.group, offsetsNode, url.PathEscape(partition))
+}
+
+func (c *Consumer) readOffset(partition string) (Position, int32, error) {
+	data, stat, err := operation.GetWithStat(c.framework, c.offsetNode(partition))
+	if errors.Is(err, zk.ErrNoNode) {
+		return Beginning, -1, nil
+	}
+	if err != nil {
+		return Position{}, 0, err
+	}
+
+	position := Position{}
+	if err := json.Unmarshal(data, &position); err != nil {
+		return Position{}, 0, err
+	}
+	return position, stat.Version, nil
+}
+
+/*
+commit stores the position as the offset of the partition, an offset already beyond the position is left as is.
+*/
+func (c *Consumer) commit(partition string, position Position) error {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return err
+	}
+
+	for {
+		committed, version, err := c.readOffset(partition)
+		if err != nil {
+			return err
+		}
+		if committed.Compare(position) >= 0 {
+			return nil
+		}
+
+		if version < 0 {
+			err = operation.CreateWithOptions(c.framework, c.offsetNode(partition), operation.NewCreateOptionsBuilder().WithData(data).Build())
+		} else {
+			_, err = operation.UpdateWithVersion(c.framework, c.offsetNode(partition), data, version)
+		}
+		if errors.Is(err, zk.ErrNodeExists) || errors.Is(err, zk.ErrBadVersion) {
+			continue
+		}
+		return err
+	}
+}
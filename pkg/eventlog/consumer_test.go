@@ -0,0 +1,118 @@
+package eventlog_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/eventlog"
+	"github.com/morphy76/zk/pkg/eventlog/eventlogerr"
+)
+
+func receive(t *testing.T, consumer *eventlog.Consumer) eventlog.Message {
+	select {
+	case message := <-consumer.Messages():
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected a message")
+		return eventlog.Message{}
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Deliver at least once", func(t *testing.T) {
+		t.Log("Deliver at least once")
+		group := uuid.New().String()
+		partition := eventlog.NewLog(zkFramework, uuid.New().String())
+		for _, data := range []string{"a", "b", "c"} {
+			if _, err := partition.Append([]byte(data)); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+
+		consumer := eventlog.NewConsumer(zkFramework, group, partition)
+		if err := consumer.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := consumer.Start(); !eventlogerr.IsConsumerAlreadyStarted(err) {
+			t.Errorf("expected %v, got %v", eventlogerr.ErrConsumerAlreadyStarted, err)
+		}
+
+		message := receive(t, consumer)
+		if string(message.Data) != "a" {
+			t.Errorf("expected a, got %s", message.Data)
+		}
+		if err := message.Ack(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if message := receive(t, consumer); string(message.Data) != "b" {
+			t.Errorf("expected b, got %s", message.Data)
+		}
+		consumer.Stop()
+
+		committed, err := consumer.Committed(message.Partition)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if committed != message.Position {
+			t.Errorf("expected the offset %v, got %v", message.Position, committed)
+		}
+
+		consumer = eventlog.NewConsumer(zkFramework, group, partition)
+		if err := consumer.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer consumer.Stop()
+		if message := receive(t, consumer); string(message.Data) != "b" {
+			t.Errorf("expected the unacknowledged b again, got %s", message.Data)
+		}
+	})
+
+	t.Run("Rebalance partitions among members", func(t *testing.T) {
+		t.Log("Rebalance partitions among members")
+		group := uuid.New().String()
+		partitions := []*eventlog.Log{}
+		for range 4 {
+			partitions = append(partitions, eventlog.NewLog(zkFramework, uuid.New().String()))
+		}
+
+		first := eventlog.NewConsumer(zkFramework, group, partitions...)
+		if err := first.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer first.Stop()
+		second := eventlog.NewConsumer(zkFramework, group, partitions...)
+		if err := second.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		balanced := func(expected int) bool {
+			for range 50 {
+				if len(first.Assigned()) == expected && len(first.Assigned())+len(second.Assigned()) == 4 {
+					return true
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+			return false
+		}
+		if !balanced(2) {
+			t.Errorf("expected 2 partitions each, got %v and %v", first.Assigned(), second.Assigned())
+		}
+		if slices.ContainsFunc(first.Assigned(), func(partition string) bool { return slices.Contains(second.Assigned(), partition) }) {
+			t.Errorf("expected disjoint assignments, got %v and %v", first.Assigned(), second.Assigned())
+		}
+
+		second.Stop()
+		if !balanced(4) {
+			t.Errorf("expected all the partitions to be assigned to the first member, got %v", first.Assigned())
+		}
+	})
+}
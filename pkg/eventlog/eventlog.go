@@ -0,0 +1,382 @@
+/*
+Package eventlog provides a small durable append-only log stored as sequential nodes, for coordination use cases.
+*/
+package eventlog
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	segmentPrefix = "segment-"
+	entryPrefix   = "entry-"
+	sealedMarker  = "sealed"
+)
+
+/*
+Position locates an entry in the log, positions are ordered by segment and then by entry.
+*/
+type Position struct {
+	// Segment is the sequence number of the segment holding the entry.
+	Segment int64 `json:"segment"`
+	// Entry is the sequence number of the entry in its segment.
+	Entry int64 `json:"entry"`
+}
+
+/*
+Beginning is the position preceding every entry of a log.
+*/
+var Beginning = Position{Segment: -1, Entry: -1}
+
+/*
+Compare returns -1, 0 or 1 when the position precedes, equals or follows the other one.
+*/
+func (p Position) Compare(other Position) int {
+	if p.Segment != other.Segment {
+		return cmp.Compare(p.Segment, other.Segment)
+	}
+	return cmp.Compare(p.Entry, other.Entry)
+}
+
+/*
+Entry is an entry read from the log.
+*/
+type Entry struct {
+	Position Position
+	Data     []byte
+}
+
+/*
+Log is an append-only log stored under a root node, as sequential entry nodes grouped in sequential segment nodes.
+
+Once a segment holds SegmentSize entries it is sealed, with a multi operation creating the next segment: appends to a sealed segment fail and are retried on the next one,
+so the order of the entries across segments is the order of the appends. Retention and truncation delete whole sealed segments, oldest first.
+*/
+type Log struct {
+	framework core.ZKFramework
+	root      string
+	options   LogOptions
+
+	current int64
+	lock    sync.Mutex
+}
+
+/*
+NewLog creates a log rooted at the given node, using the default options.
+*/
+func NewLog(zkFramework core.ZKFramework, root string) *Log {
+	return NewLogWithOptions(zkFramework, root, NewLogOptionsBuilder().Build())
+}
+
+/*
+NewLogWithOptions creates a log rooted at the given node, specifying the log options.
+*/
+func NewLogWithOptions(zkFramework core.ZKFramework, root string, options LogOptions) *Log {
+	if options.SegmentSize <= 0 {
+		options.SegmentSize = defaultSegmentSize
+	}
+	return &Log{
+		framework: zkFramework,
+		root:      root,
+		options:   options,
+		current:   -1,
+	}
+}
+
+/*
+Append appends an entry to the log, returning its position.
+*/
+func (l *Log) Append(data []byte) (Position, error) {
+	for {
+		segment, err := l.currentSegment()
+		if err != nil {
+			return Position{}, err
+		}
+
+		segmentPath := l.actualPath(segmentName(segment))
+		entryPath := path.Join(segmentPath, entryPrefix)
+		ctx, cancel := l.withDeadline(context.Background())
+		responses, err := operation.Executor(l.framework).Multi(ctx,
+			&zk.CheckVersionRequest{Path: segmentPath, Version: 0},
+			&zk.CreateRequest{Path: entryPath, Data: data, Acl: l.aclFor(entryPath), Flags: zk.FlagSequence},
+		)
+		cancel()
+		if err := multiError(responses, err); err != nil {
+			if errors.Is(err, zk.ErrBadVersion) || errors.Is(err, zk.ErrNoNode) {
+				l.resetCurrent(segment)
+				continue
+			}
+			return Position{}, err
+		}
+
+		entry, _ := operation.SequenceOf(responses[1].String)
+		if entry >= l.options.SegmentSize-1 {
+			if err := l.roll(segment); err != nil {
+				l.framework.Logger().Error("rolling log segment failed", "log", l.root, "segment", segment, "error", err)
+			}
+		}
+		return Position{Segment: segment, Entry: entry}, nil
+	}
+}
+
+/*
+NewReader creates a reader of the entries from the beginning of the log.
+*/
+func (l *Log) NewReader() *Reader {
+	return l.NewReaderAfter(Beginning)
+}
+
+/*
+NewReaderAfter creates a reader of the entries following the given position.
+*/
+func (l *Log) NewReaderAfter(position Position) *Reader {
+	return &Reader{log: l, position: position}
+}
+
+/*
+TruncateBefore deletes the sealed segments preceding the segment of the given position, returning the number of deleted segments.
+*/
+func (l *Log) TruncateBefore(position Position) (int, error) {
+	segments, err := l.segments()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, segment := range segments[:max(len(segments)-1, 0)] {
+		if segment >= position.Segment {
+			break
+		}
+		if err := l.deleteSegment(segment); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+/*
+ApplyRetention deletes the oldest sealed segments exceeding the retention, returning the number of deleted segments; it runs each time a segment is sealed.
+*/
+func (l *Log) ApplyRetention() (int, error) {
+	segments, err := l.segments()
+	if err != nil || len(segments) < 2 {
+		return 0, err
+	}
+
+	keep := len(segments)
+	if l.options.RetentionSegments > 0 {
+		keep = min(keep, l.options.RetentionSegments)
+	}
+	if l.options.RetentionBytes > 0 {
+		size := int64(0)
+		for i := len(segments) - 1; i >= len(segments)-keep; i-- {
+			segmentSize, err := l.segmentBytes(segments[i])
+			if err != nil {
+				return 0, err
+			}
+			size += segmentSize
+			if size > l.options.RetentionBytes {
+				keep = len(segments) - i - 1
+				break
+			}
+		}
+	}
+
+	count := 0
+	for _, segment := range segments[:len(segments)-max(keep, 1)] {
+		if err := l.deleteSegment(segment); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (l *Log) currentSegment() (int64, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.current >= 0 {
+		return l.current, nil
+	}
+
+	segments, err := l.segments()
+	if err != nil {
+		return 0, err
+	}
+	if len(segments) == 0 {
+		if err := l.initialize(); err != nil {
+			return 0, err
+		}
+		if segments, err = l.segments(); err != nil {
+			return 0, err
+		}
+	}
+	l.current = segments[len(segments)-1]
+	return l.current, nil
+}
+
+func (l *Log) resetCurrent(segment int64) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.current == segment {
+		l.current = -1
+	}
+}
+
+/*
+initialize creates the first segment, a concurrent initialization makes the multi operation fail on the version of the root.
+*/
+func (l *Log) initialize() error {
+	actualRoot := l.actualPath("")
+	executor := operation.Executor(l.framework)
+	ctx, cancel := l.withDeadline(context.Background())
+	defer cancel()
+
+	_, stat, err := executor.Get(ctx, actualRoot)
+	if errors.Is(err, zk.ErrNoNode) {
+		if err := operation.Create(l.framework, l.root); err != nil && !errors.Is(err, zk.ErrNodeExists) {
+			return err
+		}
+		_, stat, err = executor.Get(ctx, actualRoot)
+	}
+	if err != nil {
+		return err
+	}
+
+	firstPath := path.Join(actualRoot, segmentPrefix)
+	responses, err := executor.Multi(ctx,
+		&zk.SetDataRequest{Path: actualRoot, Data: []byte{}, Version: stat.Version},
+		&zk.CreateRequest{Path: firstPath, Data: []byte{}, Acl: l.aclFor(firstPath), Flags: zk.FlagSequence},
+	)
+	if err := multiError(responses, err); err != nil && !errors.Is(err, zk.ErrBadVersion) {
+		return err
+	}
+	return nil
+}
+
+/*
+roll seals the segment and creates the next one, a segment already sealed by a concurrent appender is left as is.
+*/
+func (l *Log) roll(segment int64) error {
+	ctx, cancel := l.withDeadline(context.Background())
+	defer cancel()
+	nextPath := path.Join(l.actualPath(""), segmentPrefix)
+	responses, err := operation.Executor(l.framework).Multi(ctx,
+		&zk.SetDataRequest{Path: l.actualPath(segmentName(segment)), Data: []byte(sealedMarker), Version: 0},
+		&zk.CreateRequest{Path: nextPath, Data: []byte{}, Acl: l.aclFor(nextPath), Flags: zk.FlagSequence},
+	)
+	if err := multiError(responses, err); err != nil {
+		if errors.Is(err, zk.ErrBadVersion) {
+			return nil
+		}
+		return err
+	}
+
+	l.resetCurrent(segment)
+	_, err = l.ApplyRetention()
+	return err
+}
+
+func (l *Log) segments() ([]int64, error) {
+	children, err := operation.Ls(l.framework, l.root)
+	if errors.Is(err, zk.ErrNoNode) {
+		return []int64{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	segments := []int64{}
+	for _, child := range children {
+		if sequence, ok := operation.SequenceOf(child); ok && child == segmentName(sequence) {
+			segments = append(segments, sequence)
+		}
+	}
+	slices.Sort(segments)
+	return segments, nil
+}
+
+func (l *Log) segmentBytes(segment int64) (int64, error) {
+	entries, err := operation.Ls(l.framework, l.root, segmentName(segment))
+	if err != nil {
+		return 0, err
+	}
+	size := int64(0)
+	for _, entry := range entries {
+		stat, err := operation.Stat(l.framework, path.Join(l.root, segmentName(segment), entry))
+		if err != nil && !errors.Is(err, zk.ErrNoNode) {
+			return 0, err
+		}
+		if err == nil {
+			size += int64(stat.DataLength)
+		}
+	}
+	return size, nil
+}
+
+func (l *Log) deleteSegment(segment int64) error {
+	l.framework.Logger().Debug("deleting log segment", "log", l.root, "segment", segment)
+	segmentNode := path.Join(l.root, segmentName(segment))
+	entries, err := operation.Ls(l.framework, segmentNode)
+	if errors.Is(err, zk.ErrNoNode) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := operation.Delete(l.framework, path.Join(segmentNode, entry)); err != nil && !coreerr.IsUnknownNode(err) {
+			return err
+		}
+	}
+	if err := operation.Delete(l.framework, segmentNode); err != nil && !coreerr.IsUnknownNode(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *Log) actualPath(nodeName string) string {
+	return path.Join(l.framework.Namespace(), l.root, nodeName)
+}
+
+func (l *Log) aclFor(actualPath string) []zk.ACL {
+	return nodeacl.DefaultFor(l.framework.ACLProvider(), actualPath)
+}
+
+func (l *Log) withDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, l.framework.OperationTimeout())
+}
+
+func segmentName(segment int64) string {
+	return segmentPrefix + sequenceSuffix(segment)
+}
+
+func entryName(entry int64) string {
+	return entryPrefix + sequenceSuffix(entry)
+}
+
+func sequenceSuffix(sequence int64) string {
+	return fmt.Sprintf("%010d", sequence)
+}
+
+func multiError(responses []zk.MultiResponse, err error) error {
+	for _, response := range responses {
+		if response.Error != nil {
+			return response.Error
+		}
+	}
+	return err
+}
@@ -0,0 +1,181 @@
+package eventlog_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/eventlog"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestLog(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Append and read across segments", func(t *testing.T) {
+		t.Log("Append and read across segments")
+		root := uuid.New().String()
+		opts := eventlog.NewLogOptionsBuilder().WithSegmentSize(3).Build()
+		eventLog := eventlog.NewLogWithOptions(zkFramework, root, opts)
+
+		positions := []eventlog.Position{}
+		for i := range 7 {
+			position, err := eventLog.Append([]byte(fmt.Sprintf("event %d", i)))
+			if err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+			if len(positions) > 0 && position.Compare(positions[len(positions)-1]) <= 0 {
+				t.Errorf("expected %v to follow %v", position, positions[len(positions)-1])
+			}
+			positions = append(positions, position)
+		}
+		if positions[0].Segment == positions[6].Segment {
+			t.Errorf("expected the entries to span several segments, got %v", positions)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		reader := eventLog.NewReader()
+		for i := range 7 {
+			entry, err := reader.Next(ctx)
+			if err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+			if string(entry.Data) != fmt.Sprintf("event %d", i) || entry.Position != positions[i] {
+				t.Errorf("expected event %d at %v, got %s at %v", i, positions[i], entry.Data, entry.Position)
+			}
+		}
+
+		reader = eventLog.NewReaderAfter(positions[4])
+		if entry, err := reader.Next(ctx); err != nil || string(entry.Data) != "event 5" {
+			t.Errorf("expected event 5, got %s, %v", entry.Data, err)
+		}
+	})
+
+	t.Run("Wait for the next entry", func(t *testing.T) {
+		t.Log("Wait for the next entry")
+		root := uuid.New().String()
+		eventLog := eventlog.NewLog(zkFramework, root)
+
+		received := make(chan eventlog.Entry)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			entry, err := eventLog.NewReader().Next(ctx)
+			if err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+			received <- entry
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		if _, err := eventLog.Append([]byte("late")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if entry := <-received; string(entry.Data) != "late" {
+			t.Errorf("expected the late entry, got %s", entry.Data)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		reader := eventLog.NewReader()
+		reader.Next(ctx)
+		if _, err := reader.Next(ctx); err != context.DeadlineExceeded {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("Truncate and retain segments", func(t *testing.T) {
+		t.Log("Truncate and retain segments")
+		root := uuid.New().String()
+		opts := eventlog.NewLogOptionsBuilder().WithSegmentSize(2).WithRetentionSegments(3).Build()
+		eventLog := eventlog.NewLogWithOptions(zkFramework, root, opts)
+
+		var last eventlog.Position
+		for i := range 10 {
+			if last, err = eventLog.Append([]byte(fmt.Sprintf("event %d", i))); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+		segments, err := operation.Ls(zkFramework, root)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(segments) != 3 {
+			t.Errorf("expected 3 retained segments, got %v", segments)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if entry, err := eventLog.NewReader().Next(ctx); err != nil || string(entry.Data) != "event 6" {
+			t.Errorf("expected the oldest retained entry, got %s, %v", entry.Data, err)
+		}
+
+		count, err := eventLog.TruncateBefore(last)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if count != 1 {
+			t.Errorf("expected 1 truncated segment, got %d", count)
+		}
+		if entry, err := eventLog.NewReader().Next(ctx); err != nil || string(entry.Data) != "event 8" {
+			t.Errorf("expected the first entry of the last sealed segment, got %s, %v", entry.Data, err)
+		}
+	})
+
+	t.Run("Retain segments by size", func(t *testing.T) {
+		t.Log("Retain segments by size")
+		root := uuid.New().String()
+		opts := eventlog.NewLogOptionsBuilder().WithSegmentSize(2).WithRetentionBytes(25).Build()
+		eventLog := eventlog.NewLogWithOptions(zkFramework, root, opts)
+
+		for range 8 {
+			if _, err := eventLog.Append([]byte("0123456789")); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+		segments, err := operation.Ls(zkFramework, root)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(segments) != 2 {
+			t.Errorf("expected 2 retained segments, got %v", segments)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+/*
+Package eventlogerr provides error types for the eventlog package.
+*/
+package eventlogerr
+
+import "errors"
+
+/*
+ErrConsumerAlreadyStarted is returned when a consumer is started twice.
+*/
+var ErrConsumerAlreadyStarted = errors.New("consumer already started")
+
+/*
+IsConsumerAlreadyStarted checks if the error is ErrConsumerAlreadyStarted.
+*/
+func IsConsumerAlreadyStarted(err error) bool {
+	return err == ErrConsumerAlreadyStarted
+}
@@ -0,0 +1,22 @@
+package eventlogerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/eventlog/eventlogerr"
+)
+
+func TestIsConsumerAlreadyStarted(t *testing.T) {
+	err := eventlogerr.ErrConsumerAlreadyStarted
+	if !eventlogerr.IsConsumerAlreadyStarted(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsConsumerAlreadyStartedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if eventlogerr.IsConsumerAlreadyStarted(err) {
+		t.Errorf("expected false, got true")
+	}
+}
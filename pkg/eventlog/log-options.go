@@ -0,0 +1,68 @@
+package eventlog
+
+/*
+LogOptions is used to configure the log.
+*/
+type LogOptions struct {
+	// SegmentSize is the number of entries after which a segment is sealed and a new one is started.
+	SegmentSize int64
+	// RetentionSegments is the maximum number of kept segments, zero means no limit.
+	RetentionSegments int
+	// RetentionBytes is the maximum size of the data of the kept segments, zero means no limit.
+	RetentionBytes int64
+}
+
+/*
+LogOptionsBuilder is a builder for LogOptions.
+*/
+type LogOptionsBuilder struct {
+	segmentSize       int64
+	retentionSegments int
+	retentionBytes    int64
+}
+
+const defaultSegmentSize = 1000
+
+/*
+NewLogOptionsBuilder creates a new LogOptionsBuilder, sealing segments of 1000 entries and keeping all of them.
+*/
+func NewLogOptionsBuilder() LogOptionsBuilder {
+	return LogOptionsBuilder{
+		segmentSize: defaultSegmentSize,
+	}
+}
+
+/*
+WithSegmentSize sets the number of entries after which a segment is sealed.
+*/
+func (b LogOptionsBuilder) WithSegmentSize(segmentSize int64) LogOptionsBuilder {
+	b.segmentSize = segmentSize
+	return b
+}
+
+/*
+WithRetentionSegments sets the maximum number of kept segments.
+*/
+func (b LogOptionsBuilder) WithRetentionSegments(retentionSegments int) LogOptionsBuilder {
+	b.retentionSegments = retentionSegments
+	return b
+}
+
+/*
+WithRetentionBytes sets the maximum size of the data of the kept segments.
+*/
+func (b LogOptionsBuilder) WithRetentionBytes(retentionBytes int64) LogOptionsBuilder {
+	b.retentionBytes = retentionBytes
+	return b
+}
+
+/*
+Build builds the LogOptions.
+*/
+func (b LogOptionsBuilder) Build() LogOptions {
+	return LogOptions{
+		SegmentSize:       b.segmentSize,
+		RetentionSegments: b.retentionSegments,
+		RetentionBytes:    b.retentionBytes,
+	}
+}
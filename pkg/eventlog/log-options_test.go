@@ -0,0 +1,39 @@
+package eventlog_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/eventlog"
+)
+
+func TestDefaultLogOptionsBuilder(t *testing.T) {
+	opts := eventlog.NewLogOptionsBuilder().Build()
+
+	if opts.SegmentSize != 1000 {
+		t.Errorf("Expected SegmentSize to be 1000, got %d", opts.SegmentSize)
+	}
+	if opts.RetentionSegments != 0 {
+		t.Errorf("Expected RetentionSegments to be 0, got %d", opts.RetentionSegments)
+	}
+	if opts.RetentionBytes != 0 {
+		t.Errorf("Expected RetentionBytes to be 0, got %d", opts.RetentionBytes)
+	}
+}
+
+func TestLogOptionsBuilder(t *testing.T) {
+	opts := eventlog.NewLogOptionsBuilder().
+		WithSegmentSize(10).
+		WithRetentionSegments(3).
+		WithRetentionBytes(1024).
+		Build()
+
+	if opts.SegmentSize != 10 {
+		t.Errorf("Expected SegmentSize to be 10, got %d", opts.SegmentSize)
+	}
+	if opts.RetentionSegments != 3 {
+		t.Errorf("Expected RetentionSegments to be 3, got %d", opts.RetentionSegments)
+	}
+	if opts.RetentionBytes != 1024 {
+		t.Errorf("Expected RetentionBytes to be 1024, got %d", opts.RetentionBytes)
+	}
+}
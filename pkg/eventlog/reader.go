@@ -0,0 +1,157 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+Reader reads the entries of a log in order, tracking the position of the last read entry.
+
+A reader is not safe for concurrent use; entries of truncated segments are skipped.
+*/
+type Reader struct {
+	log      *Log
+	position Position
+}
+
+/*
+Position returns the position of the last read entry, Beginning when no entry has been read yet.
+*/
+func (r *Reader) Position() Position {
+	return r.position
+}
+
+/*
+Next returns the entry following the position of the reader, waiting for it to be appended until the context is done.
+*/
+func (r *Reader) Next(ctx context.Context) (Entry, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return Entry{}, err
+		}
+
+		segment, err := r.segment()
+		if err != nil {
+			return Entry{}, err
+		}
+		if segment < 0 {
+			if err := r.waitSegments(ctx); err != nil {
+				return Entry{}, err
+			}
+			continue
+		}
+
+		segmentPath := r.log.actualPath(segmentName(segment))
+		requestCtx, cancel := r.log.withDeadline(ctx)
+		children, _, childEvents, err := operation.Executor(r.log.framework).ChildrenW(requestCtx, segmentPath)
+		cancel()
+		if errors.Is(err, zk.ErrNoNode) {
+			continue
+		}
+		if err != nil {
+			return Entry{}, err
+		}
+
+		if entry, found, err := r.read(segment, children); err != nil || found {
+			return entry, err
+		}
+
+		requestCtx, cancel = r.log.withDeadline(ctx)
+		data, _, segmentEvents, err := operation.Executor(r.log.framework).GetW(requestCtx, segmentPath)
+		cancel()
+		if errors.Is(err, zk.ErrNoNode) {
+			continue
+		}
+		if err != nil {
+			return Entry{}, err
+		}
+		if string(data) == sealedMarker {
+			r.position = Position{Segment: segment + 1, Entry: -1}
+			continue
+		}
+
+		select {
+		case <-childEvents:
+		case <-segmentEvents:
+		case <-ctx.Done():
+			return Entry{}, ctx.Err()
+		}
+	}
+}
+
+/*
+segment returns the first existing segment not preceding the position of the reader, -1 when there is no such segment yet.
+*/
+func (r *Reader) segment() (int64, error) {
+	segments, err := r.log.segments()
+	if err != nil {
+		return 0, err
+	}
+	for _, segment := range segments {
+		if segment >= r.position.Segment {
+			if segment > r.position.Segment {
+				r.position = Position{Segment: segment, Entry: -1}
+			}
+			return segment, nil
+		}
+	}
+	return -1, nil
+}
+
+/*
+read reads the first entry of the segment following the position of the reader, skipping the entries deleted meanwhile.
+*/
+func (r *Reader) read(segment int64, children []string) (Entry, bool, error) {
+	entries := []int64{}
+	for _, child := range children {
+		if sequence, ok := operation.SequenceOf(child); ok && child == entryName(sequence) && sequence > r.position.Entry {
+			entries = append(entries, sequence)
+		}
+	}
+	slices.Sort(entries)
+
+	executor := operation.Executor(r.log.framework)
+	for _, sequence := range entries {
+		ctx, cancel := r.log.withDeadline(context.Background())
+		data, _, err := executor.Get(ctx, r.log.actualPath(segmentName(segment)+"/"+entryName(sequence)))
+		cancel()
+		if errors.Is(err, zk.ErrNoNode) {
+			continue
+		}
+		if err != nil {
+			return Entry{}, false, err
+		}
+		r.position = Position{Segment: segment, Entry: sequence}
+		return Entry{Position: r.position, Data: data}, true, nil
+	}
+	return Entry{}, false, nil
+}
+
+func (r *Reader) waitSegments(ctx context.Context) error {
+	executor := operation.Executor(r.log.framework)
+	requestCtx, cancel := r.log.withDeadline(ctx)
+	defer cancel()
+
+	_, _, events, err := executor.ChildrenW(requestCtx, r.log.actualPath(""))
+	if errors.Is(err, zk.ErrNoNode) {
+		exists, _, existsEvents, err := executor.ExistsW(requestCtx, r.log.actualPath(""))
+		if err != nil || exists {
+			return err
+		}
+		events = existsEvents
+	} else if err != nil {
+		return err
+	}
+
+	select {
+	case <-events:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
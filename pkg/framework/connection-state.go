@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+ConnectionState returns the connection state derived from the Zookeeper states since the framework started.
+*/
+func (c *zKFrameworkImpl) ConnectionState() core.ConnectionState {
+	c.statusChangeLock.RLock()
+	defer c.statusChangeLock.RUnlock()
+	return c.connectionState
+}
+
+/*
+trackConnectionState derives the connection state from the Zookeeper state, queueing its changes to be notified, the status change lock must be held.
+*/
+func (c *zKFrameworkImpl) trackConnectionState(state zk.State) {
+	next := c.connectionState
+	switch state {
+	case zk.StateExpired, core.StateFailed:
+		next = core.ConnectionStateLost
+	case zk.StateConnectedReadOnly:
+		next = core.ConnectionStateReadOnly
+	case zk.StateHasSession:
+		if c.connectionState == core.ConnectionStateNone {
+			next = core.ConnectionStateConnected
+		} else if !c.connectionState.IsConnected() || c.connectionState == core.ConnectionStateReadOnly {
+			next = core.ConnectionStateReconnected
+		}
+	case zk.StateDisconnected, zk.StateConnecting:
+		if c.connectionState.IsConnected() {
+			next = core.ConnectionStateSuspended
+		}
+	}
+
+	if next == c.connectionState {
+		return
+	}
+	c.logger.Info("connection state change", "previous", c.connectionState, "current", next)
+	c.connectionState = next
+	c.pendingConnectionStates = append(c.pendingConnectionStates, next)
+}
+
+/*
+notifyStatusChange notifies the status change listeners and then the connection state listeners of the queued changes, in order.
+*/
+func (c *zKFrameworkImpl) notifyStatusChange() {
+	c.NotifyStatusChange()
+
+	c.connectionStateNotification.Lock()
+	defer c.connectionStateNotification.Unlock()
+
+	c.statusChangeLock.Lock()
+	pending := c.pendingConnectionStates
+	c.pendingConnectionStates = nil
+	c.statusChangeLock.Unlock()
+
+	for _, connectionState := range pending {
+		c.notifyConnectionState(connectionState)
+	}
+}
+
+func (c *zKFrameworkImpl) notifyConnectionState(connectionState core.ConnectionState) {
+	for _, listener := range c.statusChangeListeners.snapshot() {
+		connectionStateListener, ok := listener.(core.ConnectionStateListener)
+		if !ok {
+			continue
+		}
+		err := c.callWithDeadline(listener.UUID(), func(_ context.Context) error {
+			return connectionStateListener.OnConnectionStateChange(c, connectionState)
+		})
+		if err != nil {
+			c.logger.Error("error notifying connection state listener", "listener", listener.UUID(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package framework
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+HTTPConnectDialer returns a dialer tunnelling the connections to the Zookeeper servers through an HTTP proxy, with the CONNECT method,
+e.g. to cross a firewalled jump host; it is set with WithDialer.
+
+The optional header, e.g. Proxy-Authorization, is sent with each CONNECT request.
+*/
+func HTTPConnectDialer(proxyAddress string, header http.Header) zk.Dialer {
+	return func(network string, address string, timeout time.Duration) (net.Conn, error) {
+		start := time.Now()
+		cn, err := net.DialTimeout(network, proxyAddress, timeout)
+		if err != nil {
+			return nil, err
+		}
+		if err := cn.SetDeadline(start.Add(timeout)); err != nil {
+			cn.Close()
+			return nil, err
+		}
+
+		request := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Host: address},
+			Host:   address,
+			Header: header,
+		}
+		if request.Header == nil {
+			request.Header = http.Header{}
+		}
+		if err := request.Write(cn); err != nil {
+			cn.Close()
+			return nil, err
+		}
+
+		reader := bufio.NewReader(cn)
+		response, err := http.ReadResponse(reader, request)
+		if err != nil {
+			cn.Close()
+			return nil, err
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			cn.Close()
+			return nil, fmt.Errorf("%w: %s to %s", frwkerr.ErrProxyRefused, response.Status, address)
+		}
+
+		if err := cn.SetDeadline(time.Time{}); err != nil {
+			cn.Close()
+			return nil, err
+		}
+		if reader.Buffered() > 0 {
+			return &bufferedConn{Conn: cn, reader: reader}, nil
+		}
+		return cn, nil
+	}
+}
+
+/*
+UnixSocketDialer returns a dialer connecting to the Zookeeper servers through the unix socket at the given path, e.g. exposed by a sidecar;
+it is set with WithDialer and the server addresses are only used to identify the servers.
+*/
+func UnixSocketDialer(socketPath string) zk.Dialer {
+	return func(_ string, _ string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout("unix", socketPath, timeout)
+	}
+}
+
+/*
+bufferedConn reads first the bytes the proxy sent after its response, buffered while reading it.
+*/
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,104 @@
+package framework_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+startConnectProxy starts an HTTP proxy answering each CONNECT with the given status, then echoing the tunnelled bytes.
+*/
+func startConnectProxy(t *testing.T, status int) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			cn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer cn.Close()
+				reader := bufio.NewReader(cn)
+				request, err := http.ReadRequest(reader)
+				if err != nil || request.Method != http.MethodConnect || request.Header.Get("Proxy-Authorization") != "Basic secret" {
+					return
+				}
+				response := &http.Response{StatusCode: status, ProtoMajor: 1, ProtoMinor: 1}
+				response.Write(cn)
+				io.Copy(cn, reader)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestDialers(t *testing.T) {
+	header := http.Header{"Proxy-Authorization": []string{"Basic secret"}}
+
+	t.Run("Tunnel through an HTTP proxy", func(t *testing.T) {
+		t.Log("Tunnel through an HTTP proxy")
+		dialer := framework.HTTPConnectDialer(startConnectProxy(t, http.StatusOK), header)
+		cn, err := dialer("tcp", "zookeeper:2181", time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		defer cn.Close()
+
+		if _, err := cn.Write([]byte("ruok")); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		echo := make([]byte, 4)
+		if _, err := io.ReadFull(cn, echo); err != nil || string(echo) != "ruok" {
+			t.Errorf("expected the tunnel to carry the bytes, got %q, %v", echo, err)
+		}
+	})
+
+	t.Run("HTTP proxy refusing the tunnel", func(t *testing.T) {
+		t.Log("HTTP proxy refusing the tunnel")
+		dialer := framework.HTTPConnectDialer(startConnectProxy(t, http.StatusProxyAuthRequired), header)
+		if _, err := dialer("tcp", "zookeeper:2181", time.Second); !frwkerr.IsProxyRefused(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrProxyRefused, err)
+		}
+	})
+
+	t.Run("Connect through a unix socket", func(t *testing.T) {
+		t.Log("Connect through a unix socket")
+		socketPath := filepath.Join(t.TempDir(), "zookeeper.sock")
+		listener, err := net.Listen("unix", socketPath)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		defer listener.Close()
+		accepted := make(chan bool, 1)
+		go func() {
+			if cn, err := listener.Accept(); err == nil {
+				cn.Close()
+				accepted <- true
+			}
+		}()
+
+		cn, err := framework.UnixSocketDialer(socketPath)("tcp", "zookeeper:2181", time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		defer cn.Close()
+		select {
+		case <-accepted:
+		case <-time.After(time.Second):
+			t.Errorf("expected the connection to reach the unix socket")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package framework
+
+import (
+	"context"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+)
+
+/*
+recordDialedAddress remembers the IP address a server was dialed at, to detect when its hostname no longer resolves to it.
+*/
+func (c *zKFrameworkImpl) recordDialedAddress(server string, cn net.Conn) {
+	if tcpAddr, ok := cn.RemoteAddr().(*net.TCPAddr); ok {
+		c.dialedAddresses.Store(server, tcpAddr.IP.String())
+	}
+}
+
+/*
+refreshDNSPeriodically resolves the hostname of the connected server at every interval while connected,
+invalidating the connection when the hostname no longer resolves to the address it was dialed at, e.g. after a Kubernetes service moved.
+
+The other servers are resolved again each time they are dialed. When the dialed address is unknown, e.g. with a custom dialer,
+the connection is invalidated when the resolved addresses change.
+*/
+func (c *zKFrameworkImpl) refreshDNSPeriodically(cn *zk.Conn, shutdown chan bool) {
+	ticker := time.NewTicker(c.dnsRefreshInterval)
+	defer ticker.Stop()
+
+	resolved := map[string][]string{}
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-ticker.C:
+		}
+
+		server := cn.Server()
+		host, _, err := net.SplitHostPort(server)
+		if err != nil || net.ParseIP(host) != nil || !c.Connected() {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.dnsRefreshInterval)
+		addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+		cancel()
+		if err != nil {
+			c.logger.Warn("error resolving the Zookeeper server", "server", server, "error", err)
+			continue
+		}
+		slices.Sort(addresses)
+		previous, found := resolved[host]
+		resolved[host] = addresses
+
+		if dialed, known := c.dialedAddresses.Load(server); known {
+			if slices.Contains(addresses, dialed.(string)) {
+				continue
+			}
+		} else if !found || slices.Equal(previous, addresses) {
+			continue
+		}
+
+		select {
+		case <-shutdown:
+			return
+		default:
+		}
+		c.logger.Warn("Zookeeper server resolved to new addresses, invalidating the connection", "server", server, "addresses", addresses)
+		c.handleStatusChange(zk.StateDisconnected)
+		return
+	}
+}
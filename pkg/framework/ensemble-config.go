@@ -0,0 +1,131 @@
+package framework
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+EnsembleConfigNode is the node holding the dynamic configuration of a Zookeeper 3.5+ ensemble.
+*/
+const EnsembleConfigNode = "/zookeeper/config"
+
+const ensembleConfigRetryInterval = time.Second
+
+/*
+ServerResolver returns the current servers of the ensemble, e.g. from a service discovery.
+*/
+type ServerResolver func(ctx context.Context) ([]string, error)
+
+/*
+ParseEnsembleConfig returns the client addresses of the members of the ensemble, as listed in the dynamic configuration node.
+
+Each server.N line is in the form host:quorumPort:electionPort[:role];[clientHost:]clientPort, a wildcard client host being replaced by the host of the server.
+Observers are included, the members without a client port are skipped.
+*/
+func ParseEnsembleConfig(data []byte) ([]string, error) {
+	servers := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || !strings.HasPrefix(key, "server.") {
+			continue
+		}
+
+		quorum, client, ok := strings.Cut(value, ";")
+		if !ok || client == "" {
+			continue
+		}
+		host, _, _ := strings.Cut(quorum, ":")
+		clientHost, clientPort, found := strings.Cut(client, ":")
+		if !found {
+			clientHost, clientPort = "", client
+		}
+		if clientHost == "" || clientHost == "0.0.0.0" || clientHost == "::" {
+			clientHost = host
+		}
+		if clientHost == "" || clientPort == "" {
+			return nil, frwkerr.ErrInvalidConnectionURL
+		}
+		servers = append(servers, net.JoinHostPort(clientHost, clientPort))
+	}
+	if len(servers) == 0 {
+		return nil, frwkerr.ErrInvalidConnectionURL
+	}
+	return servers, nil
+}
+
+/*
+watchEnsembleConfig follows the dynamic configuration of the ensemble while connected, updating the servers when the members change.
+*/
+func (c *zKFrameworkImpl) watchEnsembleConfig(cn *zk.Conn, shutdown chan bool) {
+	for {
+		data, _, events, err := cn.GetW(EnsembleConfigNode)
+		if err != nil {
+			c.logger.Warn("error reading the ensemble configuration", "error", err)
+			select {
+			case <-shutdown:
+				return
+			case <-time.After(ensembleConfigRetryInterval):
+			}
+			continue
+		}
+
+		if servers, err := ParseEnsembleConfig(data); err != nil {
+			c.logger.Warn("invalid ensemble configuration", "error", err)
+		} else {
+			c.updateServersIfChanged(servers)
+		}
+
+		select {
+		case <-shutdown:
+			return
+		case <-events:
+		}
+	}
+}
+
+/*
+resolveServersPeriodically asks the resolver for the servers at every interval while connected, updating them when they change.
+*/
+func (c *zKFrameworkImpl) resolveServersPeriodically(shutdown chan bool) {
+	ticker := time.NewTicker(c.serverResolveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.serverResolveInterval)
+		servers, err := c.serverResolver(ctx)
+		cancel()
+		if err != nil {
+			c.logger.Warn("error resolving the ensemble servers", "error", err)
+			continue
+		}
+		c.updateServersIfChanged(servers)
+	}
+}
+
+func (c *zKFrameworkImpl) updateServersIfChanged(servers []string) {
+	c.statusChangeLock.RLock()
+	current := slices.Clone(c.servers)
+	c.statusChangeLock.RUnlock()
+
+	slices.Sort(current)
+	sorted := slices.Sorted(slices.Values(servers))
+	if slices.Equal(current, sorted) {
+		return
+	}
+	if err := c.UpdateServers(servers); err != nil {
+		c.logger.Warn("error updating the ensemble servers", "servers", servers, "error", err)
+	}
+}
@@ -0,0 +1,72 @@
+package framework_test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+func TestEnsembleConfig(t *testing.T) {
+
+	t.Run("Parse the dynamic configuration of an ensemble", func(t *testing.T) {
+		t.Log("Parse the dynamic configuration of an ensemble")
+		config := []byte("server.1=zoo1:2888:3888:participant;0.0.0.0:2181\n" +
+			"server.2=zoo2:2888:3888:participant;zoo2.example.com:2182\n" +
+			"server.3=zoo3:2888:3888:observer;2183\n" +
+			"server.4=zoo4:2888:3888:participant\n" +
+			"version=100000000\n")
+
+		servers, err := framework.ParseEnsembleConfig(config)
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		expected := []string{"zoo1:2181", "zoo2.example.com:2182", "zoo3:2183"}
+		if !slices.Equal(servers, expected) {
+			t.Errorf("expected servers %v, got %v", expected, servers)
+		}
+	})
+
+	t.Run("Parse an empty configuration", func(t *testing.T) {
+		t.Log("Parse an empty configuration")
+		if _, err := framework.ParseEnsembleConfig([]byte("version=0\n")); !frwkerr.IsInvalidConnectionURL(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrInvalidConnectionURL, err)
+		}
+	})
+
+	t.Run("Update the servers from a resolver", func(t *testing.T) {
+		t.Log("Update the servers from a resolver")
+		url := os.Getenv(zkHostEnv)
+		resolved := []string{url, "127.0.0.1:1"}
+		zkFramework, err := framework.CreateFrameworkWithOptions(url,
+			framework.WithServerResolver(func(ctx context.Context) ([]string, error) {
+				return resolved, nil
+			}, 50*time.Millisecond),
+		)
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for !slices.Equal(zkFramework.ConnectionStats().Servers, resolved) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected servers %v, got %v", resolved, zkFramework.ConnectionStats().Servers)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		if !zkFramework.Connected() {
+			t.Errorf("expected the session to be kept")
+		}
+	})
+}
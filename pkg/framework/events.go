@@ -0,0 +1,53 @@
+package framework
+
+import (
+	"github.com/morphy76/zk/pkg/core"
+)
+
+const eventsBuffer = 64
+
+/*
+Events returns a channel receiving the events of the framework, as an alternative to the listeners, until it is stopped:
+the channel receives ShuttingDown and is then closed.
+
+Each call returns a new channel buffering up to 64 events, the events being dropped while the buffer is full.
+*/
+func (c *zKFrameworkImpl) Events() <-chan core.FrameworkEvent {
+	c.eventsLock.Lock()
+	defer c.eventsLock.Unlock()
+
+	events := make(chan core.FrameworkEvent, eventsBuffer)
+	c.eventChannels = append(c.eventChannels, events)
+	return events
+}
+
+/*
+publish delivers the event to every channel returned by Events without blocking.
+*/
+func (c *zKFrameworkImpl) publish(event core.FrameworkEvent) {
+	c.eventsLock.Lock()
+	defer c.eventsLock.Unlock()
+
+	for _, events := range c.eventChannels {
+		select {
+		case events <- event:
+		default:
+			c.logger.Warn("framework event dropped, channel full", "event", event.Name())
+		}
+	}
+}
+
+/*
+closeEvents publishes ShuttingDown and closes every channel returned by Events.
+*/
+func (c *zKFrameworkImpl) closeEvents() {
+	c.publish(core.ShuttingDown{})
+
+	c.eventsLock.Lock()
+	defer c.eventsLock.Unlock()
+
+	for _, events := range c.eventChannels {
+		close(events)
+	}
+	c.eventChannels = nil
+}
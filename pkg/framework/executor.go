@@ -0,0 +1,212 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+Executor returns the executor running the requests on the current connection, the one opened again after a reconnection included.
+*/
+func (c *zKFrameworkImpl) Executor() core.Executor {
+	return connExecutor{framework: c}
+}
+
+/*
+WithinOperation marks the context of the requests run by an operation which already began, see BeginOperation, and was allowed by the circuit breaker,
+so that the executor does not guard each of its requests again.
+*/
+func WithinOperation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operationKey{}, true)
+}
+
+type operationKey struct{}
+
+/*
+connExecutor runs each request on the connection current at the time of the call, waiting for its response until the context is done.
+
+Unless run within an operation, each request is an operation itself: it is rejected while the framework is stopping gracefully or failing fast by the circuit breaker.
+*/
+type connExecutor struct {
+	framework *zKFrameworkImpl
+}
+
+type response[T any] struct {
+	value T
+	err   error
+}
+
+/*
+conn returns the current connection, read under the status change lock since a reconnection replaces it.
+*/
+func (e connExecutor) conn() (*zk.Conn, error) {
+	e.framework.statusChangeLock.RLock()
+	defer e.framework.statusChangeLock.RUnlock()
+
+	if !e.framework.started || e.framework.cn == nil {
+		return nil, frwkerr.ErrFrameworkNotYetStarted
+	}
+	return e.framework.cn, nil
+}
+
+func call[T any](ctx context.Context, e connExecutor, request func(cn *zk.Conn) (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	cn, err := e.conn()
+	if err != nil {
+		return zero, err
+	}
+	end, err := e.guard(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	done := make(chan response[T], 1)
+	go func() {
+		value, err := request(cn)
+		end(err)
+		done <- response[T]{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+/*
+guard begins the request as an operation, unless the context is within one, returning the function to call with the outcome of the request.
+*/
+func (e connExecutor) guard(ctx context.Context) (func(error), error) {
+	if ctx.Value(operationKey{}) != nil {
+		return func(error) {}, nil
+	}
+
+	end, err := e.framework.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	breaker := e.framework.circuitBreaker
+	if breaker == nil {
+		return func(error) { end() }, nil
+	}
+	if err := breaker.Allow(); err != nil {
+		end()
+		return nil, err
+	}
+	return func(err error) {
+		end()
+		breaker.Done(err)
+	}, nil
+}
+
+type withStat[T any] struct {
+	value T
+	stat  *zk.Stat
+}
+
+type withWatch[T any] struct {
+	value  T
+	stat   *zk.Stat
+	events <-chan zk.Event
+}
+
+func (e connExecutor) Get(ctx context.Context, actualPath string) ([]byte, *zk.Stat, error) {
+	r, err := call(ctx, e, func(cn *zk.Conn) (withStat[[]byte], error) {
+		data, stat, err := cn.Get(actualPath)
+		return withStat[[]byte]{value: data, stat: stat}, err
+	})
+	return r.value, r.stat, err
+}
+
+func (e connExecutor) GetW(ctx context.Context, actualPath string) ([]byte, *zk.Stat, <-chan zk.Event, error) {
+	r, err := call(ctx, e, func(cn *zk.Conn) (withWatch[[]byte], error) {
+		data, stat, events, err := cn.GetW(actualPath)
+		return withWatch[[]byte]{value: data, stat: stat, events: events}, err
+	})
+	return r.value, r.stat, r.events, err
+}
+
+func (e connExecutor) Set(ctx context.Context, actualPath string, data []byte, version int32) (*zk.Stat, error) {
+	return call(ctx, e, func(cn *zk.Conn) (*zk.Stat, error) {
+		return cn.Set(actualPath, data, version)
+	})
+}
+
+func (e connExecutor) Create(ctx context.Context, actualPath string, data []byte, flags int32, acl []zk.ACL) (string, error) {
+	return call(ctx, e, func(cn *zk.Conn) (string, error) {
+		return cn.Create(actualPath, data, flags, acl)
+	})
+}
+
+func (e connExecutor) Delete(ctx context.Context, actualPath string, version int32) error {
+	_, err := call(ctx, e, func(cn *zk.Conn) (struct{}, error) {
+		return struct{}{}, cn.Delete(actualPath, version)
+	})
+	return err
+}
+
+func (e connExecutor) Children(ctx context.Context, actualPath string) ([]string, *zk.Stat, error) {
+	r, err := call(ctx, e, func(cn *zk.Conn) (withStat[[]string], error) {
+		children, stat, err := cn.Children(actualPath)
+		return withStat[[]string]{value: children, stat: stat}, err
+	})
+	return r.value, r.stat, err
+}
+
+func (e connExecutor) ChildrenW(ctx context.Context, actualPath string) ([]string, *zk.Stat, <-chan zk.Event, error) {
+	r, err := call(ctx, e, func(cn *zk.Conn) (withWatch[[]string], error) {
+		children, stat, events, err := cn.ChildrenW(actualPath)
+		return withWatch[[]string]{value: children, stat: stat, events: events}, err
+	})
+	return r.value, r.stat, r.events, err
+}
+
+func (e connExecutor) Exists(ctx context.Context, actualPath string) (bool, *zk.Stat, error) {
+	r, err := call(ctx, e, func(cn *zk.Conn) (withStat[bool], error) {
+		exists, stat, err := cn.Exists(actualPath)
+		return withStat[bool]{value: exists, stat: stat}, err
+	})
+	return r.value, r.stat, err
+}
+
+func (e connExecutor) ExistsW(ctx context.Context, actualPath string) (bool, *zk.Stat, <-chan zk.Event, error) {
+	r, err := call(ctx, e, func(cn *zk.Conn) (withWatch[bool], error) {
+		exists, stat, events, err := cn.ExistsW(actualPath)
+		return withWatch[bool]{value: exists, stat: stat, events: events}, err
+	})
+	return r.value, r.stat, r.events, err
+}
+
+func (e connExecutor) GetACL(ctx context.Context, actualPath string) ([]zk.ACL, *zk.Stat, error) {
+	r, err := call(ctx, e, func(cn *zk.Conn) (withStat[[]zk.ACL], error) {
+		acl, stat, err := cn.GetACL(actualPath)
+		return withStat[[]zk.ACL]{value: acl, stat: stat}, err
+	})
+	return r.value, r.stat, err
+}
+
+func (e connExecutor) SetACL(ctx context.Context, actualPath string, acl []zk.ACL, version int32) (*zk.Stat, error) {
+	return call(ctx, e, func(cn *zk.Conn) (*zk.Stat, error) {
+		return cn.SetACL(actualPath, acl, version)
+	})
+}
+
+func (e connExecutor) Multi(ctx context.Context, ops ...any) ([]zk.MultiResponse, error) {
+	return call(ctx, e, func(cn *zk.Conn) ([]zk.MultiResponse, error) {
+		return cn.Multi(ops...)
+	})
+}
+
+func (e connExecutor) Sync(ctx context.Context, actualPath string) (string, error) {
+	return call(ctx, e, func(cn *zk.Conn) (string, error) {
+		return cn.Sync(actualPath)
+	})
+}
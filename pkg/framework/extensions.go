@@ -0,0 +1,14 @@
+package framework
+
+/*
+Extension returns the value attached to the framework with the given key by a package extending it, attaching the one returned by create when none is yet.
+
+The views of the framework share its values, which are dropped together with the framework.
+*/
+func (c *zKFrameworkImpl) Extension(key any, create func() any) any {
+	if value, found := c.extensions.Load(key); found {
+		return value
+	}
+	value, _ := c.extensions.LoadOrStore(key, create())
+	return value
+}
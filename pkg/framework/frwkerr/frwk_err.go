@@ -3,10 +3,14 @@ Package frwkerr provides error types for the framework package.
 */
 package frwkerr
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 /*
-ErrInvalidConnectionURL is returned when the connection URL is invalid. A connection url is invalid when it is empty.
+ErrInvalidConnectionURL is returned when the connection URL is invalid. A connection url is invalid when it is empty or lists an empty server.
 */
 var ErrInvalidConnectionURL = errors.New("invalid connection URL")
 
@@ -52,3 +56,163 @@ IsFrameworkNotYetStarted checks if the error is a not yet started error.
 func IsFrameworkNotYetStarted(err error) bool {
 	return err == ErrFrameworkNotYetStarted
 }
+
+/*
+ErrAdminModeAlreadyEnabled is returned when the admin mode is enabled twice.
+*/
+var ErrAdminModeAlreadyEnabled = errors.New("admin mode already enabled")
+
+/*
+IsAdminModeAlreadyEnabled checks if the error is an admin mode already enabled error.
+*/
+func IsAdminModeAlreadyEnabled(err error) bool {
+	return err == ErrAdminModeAlreadyEnabled
+}
+
+/*
+ErrFrameworkAlreadyRegistered is returned when a framework name is registered twice in a manager.
+*/
+var ErrFrameworkAlreadyRegistered = errors.New("framework already registered")
+
+/*
+ErrFrameworkNotRegistered is returned when no framework is registered with the given name in a manager.
+*/
+var ErrFrameworkNotRegistered = errors.New("framework not registered")
+
+/*
+IsFrameworkAlreadyRegistered checks if the error is a framework already registered error.
+*/
+func IsFrameworkAlreadyRegistered(err error) bool {
+	return err == ErrFrameworkAlreadyRegistered
+}
+
+/*
+IsFrameworkNotRegistered checks if the error is a framework not registered error.
+*/
+func IsFrameworkNotRegistered(err error) bool {
+	return err == ErrFrameworkNotRegistered
+}
+
+/*
+ErrListenerDeadlineExceeded is returned when a listener callback runs longer than the listener deadline.
+*/
+var ErrListenerDeadlineExceeded = errors.New("listener deadline exceeded")
+
+/*
+IsListenerDeadlineExceeded checks if the error is, or wraps, a listener deadline exceeded error.
+*/
+func IsListenerDeadlineExceeded(err error) bool {
+	return errors.Is(err, ErrListenerDeadlineExceeded)
+}
+
+/*
+ErrFrameworkFailed is returned when the framework gave up reconnecting to the Zookeeper server and is in the terminal failed state.
+*/
+var ErrFrameworkFailed = errors.New("framework failed, reconnection attempts exhausted")
+
+/*
+IsFrameworkFailed checks if the error is a framework failed error.
+*/
+func IsFrameworkFailed(err error) bool {
+	return err == ErrFrameworkFailed
+}
+
+/*
+ErrInvalidCertificate is returned when a PEM file holds no valid certificate.
+*/
+var ErrInvalidCertificate = errors.New("invalid certificate")
+
+/*
+IsInvalidCertificate checks if the error is, or wraps, an invalid certificate error.
+*/
+func IsInvalidCertificate(err error) bool {
+	return errors.Is(err, ErrInvalidCertificate)
+}
+
+/*
+ErrProxyRefused is returned when a proxy refuses to open a tunnel to a Zookeeper server.
+*/
+var ErrProxyRefused = errors.New("proxy refused the connection")
+
+/*
+IsProxyRefused checks if the error is, or wraps, a proxy refused error.
+*/
+func IsProxyRefused(err error) bool {
+	return errors.Is(err, ErrProxyRefused)
+}
+
+/*
+ErrFrameworkStopping is returned when an operation is attempted while the framework is stopping gracefully.
+*/
+var ErrFrameworkStopping = errors.New("framework stopping")
+
+/*
+IsFrameworkStopping checks if the error is a framework stopping error.
+*/
+func IsFrameworkStopping(err error) bool {
+	return err == ErrFrameworkStopping
+}
+
+/*
+ErrStopDeadlineExceeded is returned when a framework is stopped before its operations in flight or its shutdown listeners completed.
+*/
+var ErrStopDeadlineExceeded = errors.New("stop deadline exceeded")
+
+/*
+IsStopDeadlineExceeded checks if the error is, or wraps, a stop deadline exceeded error.
+*/
+func IsStopDeadlineExceeded(err error) bool {
+	return errors.Is(err, ErrStopDeadlineExceeded)
+}
+
+/*
+StartupPhase is a phase of the startup of a framework.
+*/
+type StartupPhase string
+
+const (
+	// PhaseStart is the connection to the Zookeeper server being opened.
+	PhaseStart StartupPhase = "start"
+	// PhaseConnection is the wait for the session to be established.
+	PhaseConnection StartupPhase = "connection"
+	// PhaseNamespace is the creation of the missing nodes of the namespace.
+	PhaseNamespace StartupPhase = "namespace"
+)
+
+/*
+ErrStartupFailed is wrapped by the StartupError returned when a framework fails to start and wait for the connection.
+*/
+var ErrStartupFailed = errors.New("framework startup failed")
+
+/*
+StartupError is returned when a framework fails to start and wait for the connection, telling the failed phase.
+*/
+type StartupError struct {
+	// Phase is the phase which failed.
+	Phase StartupPhase
+	// Elapsed is the time spent starting up until the failure.
+	Elapsed time.Duration
+	// Err is the error of the failed phase.
+	Err error
+}
+
+/*
+Error returns the description of the failed phase.
+*/
+func (e *StartupError) Error() string {
+	return fmt.Sprintf("%s: %s phase after %s: %v", ErrStartupFailed, e.Phase, e.Elapsed, e.Err)
+}
+
+/*
+Unwrap returns ErrStartupFailed along with the error of the failed phase.
+*/
+func (e *StartupError) Unwrap() []error {
+	return []error{ErrStartupFailed, e.Err}
+}
+
+/*
+IsStartupFailed checks if the error is, or wraps, a StartupError; use errors.As to access the failed phase.
+*/
+func IsStartupFailed(err error) bool {
+	return errors.Is(err, ErrStartupFailed)
+}
@@ -2,6 +2,7 @@ package frwkerr_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/morphy76/zk/pkg/framework/frwkerr"
@@ -62,3 +63,151 @@ func TestIsFrameworkNotYetStartedFalse(t *testing.T) {
 		t.Errorf("expected false, got true")
 	}
 }
+
+func TestIsAdminModeAlreadyEnabled(t *testing.T) {
+	err := frwkerr.ErrAdminModeAlreadyEnabled
+	if !frwkerr.IsAdminModeAlreadyEnabled(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsAdminModeAlreadyEnabledFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsAdminModeAlreadyEnabled(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsFrameworkAlreadyRegistered(t *testing.T) {
+	err := frwkerr.ErrFrameworkAlreadyRegistered
+	if !frwkerr.IsFrameworkAlreadyRegistered(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsFrameworkAlreadyRegisteredFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsFrameworkAlreadyRegistered(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsFrameworkNotRegistered(t *testing.T) {
+	err := frwkerr.ErrFrameworkNotRegistered
+	if !frwkerr.IsFrameworkNotRegistered(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsFrameworkNotRegisteredFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsFrameworkNotRegistered(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsListenerDeadlineExceeded(t *testing.T) {
+	err := fmt.Errorf("%w: listener id", frwkerr.ErrListenerDeadlineExceeded)
+	if !frwkerr.IsListenerDeadlineExceeded(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsListenerDeadlineExceededFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsListenerDeadlineExceeded(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsFrameworkFailed(t *testing.T) {
+	err := frwkerr.ErrFrameworkFailed
+	if !frwkerr.IsFrameworkFailed(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsFrameworkFailedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsFrameworkFailed(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidCertificate(t *testing.T) {
+	err := fmt.Errorf("%w: ca.pem", frwkerr.ErrInvalidCertificate)
+	if !frwkerr.IsInvalidCertificate(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidCertificateFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsInvalidCertificate(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsProxyRefused(t *testing.T) {
+	err := fmt.Errorf("%w: 407 Proxy Authentication Required", frwkerr.ErrProxyRefused)
+	if !frwkerr.IsProxyRefused(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsProxyRefusedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsProxyRefused(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsStartupFailed(t *testing.T) {
+	err := error(&frwkerr.StartupError{Phase: frwkerr.PhaseConnection, Err: frwkerr.ErrConnectionTimeout})
+	if !frwkerr.IsStartupFailed(err) {
+		t.Errorf("expected true, got false")
+	}
+	if !errors.Is(err, frwkerr.ErrConnectionTimeout) {
+		t.Errorf("expected the error of the phase to be wrapped")
+	}
+
+	var startupErr *frwkerr.StartupError
+	if !errors.As(fmt.Errorf("bootstrap: %w", err), &startupErr) || startupErr.Phase != frwkerr.PhaseConnection {
+		t.Errorf("expected the failed phase to be accessible")
+	}
+}
+
+func TestIsStartupFailedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsStartupFailed(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsFrameworkStopping(t *testing.T) {
+	err := frwkerr.ErrFrameworkStopping
+	if !frwkerr.IsFrameworkStopping(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsFrameworkStoppingFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsFrameworkStopping(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsStopDeadlineExceeded(t *testing.T) {
+	err := fmt.Errorf("%w: operations in flight", frwkerr.ErrStopDeadlineExceeded)
+	if !frwkerr.IsStopDeadlineExceeded(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsStopDeadlineExceededFalse(t *testing.T) {
+	err := errors.New("some error")
+	if frwkerr.IsStopDeadlineExceeded(err) {
+		t.Errorf("expected false, got true")
+	}
+}
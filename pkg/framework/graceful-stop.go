@@ -0,0 +1,104 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+type operationsKey struct{}
+
+/*
+BeginOperation registers an operation running through the framework, or through any of its views, returning the function to call once it completes;
+it fails with frwkerr.ErrFrameworkStopping while the framework is stopping gracefully.
+
+The operations of the frameworks not created by this package are not tracked.
+*/
+func BeginOperation(zkFramework core.ZKFramework) (func(), error) {
+	if c, ok := zkFramework.Extension(operationsKey{}, func() any { return nil }).(*zKFrameworkImpl); ok {
+		return c.beginOperation()
+	}
+	return func() {}, nil
+}
+
+func (c *zKFrameworkImpl) beginOperation() (func(), error) {
+	c.operationsLock.Lock()
+	defer c.operationsLock.Unlock()
+
+	if c.draining {
+		return nil, frwkerr.ErrFrameworkStopping
+	}
+	c.operations++
+	return sync.OnceFunc(c.endOperation), nil
+}
+
+/*
+endOperation unregisters an operation, closing the drained channel once the last operation in flight completed while draining.
+*/
+func (c *zKFrameworkImpl) endOperation() {
+	c.operationsLock.Lock()
+	defer c.operationsLock.Unlock()
+
+	c.operations--
+	if c.operations == 0 && c.drained != nil {
+		close(c.drained)
+		c.drained = nil
+	}
+}
+
+/*
+StopGracefully stops the framework once the operations in flight completed and the shutdown listeners, e.g. the watchers, were torn down,
+rejecting the new operations from then on; while being torn down, the shutdown listeners can still run requests through the executor
+within the context they are given, see WithinOperation.
+
+The framework is stopped anyway when the context is done first, the returned error wrapping frwkerr.ErrStopDeadlineExceeded and the error of the context.
+*/
+func (c *zKFrameworkImpl) StopGracefully(ctx context.Context) error {
+	if !c.Started() {
+		return frwkerr.ErrFrameworkNotYetStarted
+	}
+
+	c.operationsLock.Lock()
+	c.draining = true
+	drained := c.drained
+	if drained == nil {
+		drained = make(chan struct{})
+		if c.operations == 0 {
+			close(drained)
+		} else {
+			c.drained = drained
+		}
+	}
+	c.operationsLock.Unlock()
+	c.logger.Info("draining the operations before closing the connection", "url", c.url)
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		if err := c.Stop(); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: operations in flight: %w", frwkerr.ErrStopDeadlineExceeded, ctx.Err())
+	}
+
+	tornDown := make(chan struct{})
+	go func() {
+		c.NotifyShutdown()
+		c.clearAllListeners()
+		close(tornDown)
+	}()
+	var deadlineErr error
+	select {
+	case <-tornDown:
+	case <-ctx.Done():
+		deadlineErr = fmt.Errorf("%w: shutdown listeners running: %w", frwkerr.ErrStopDeadlineExceeded, ctx.Err())
+	}
+
+	if err := c.stop(false); err != nil {
+		return err
+	}
+	return deadlineErr
+}
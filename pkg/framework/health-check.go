@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+HealthCheck verifies the liveness of the connection with a lightweight round trip to the server, an exists on the root node,
+and, when enabled with WithHealthFourLetterWords, sends ruok and srvr to each server of the ensemble; both are bounded by the context.
+
+The report is returned together with the error failing the round trip, e.g. ErrFrameworkNotYetStarted, ErrFrameworkFailed or the error of the context.
+*/
+func (c *zKFrameworkImpl) HealthCheck(ctx context.Context) (core.Health, error) {
+	stats := c.ConnectionStats()
+	health := core.Health{
+		State:  stats.State,
+		Server: stats.Server,
+	}
+
+	c.statusChangeLock.RLock()
+	started, failed, cn := c.started, c.state == core.StateFailed, c.cn
+	c.statusChangeLock.RUnlock()
+
+	if !started {
+		return health, frwkerr.ErrFrameworkNotYetStarted
+	}
+	if c.fourLetterWordsTimeout > 0 {
+		servers := make(chan []core.ServerHealth, 1)
+		go func() {
+			servers <- fourLetterWords(stats.Servers, c.fourLetterWordsTimeout)
+		}()
+		select {
+		case health.Servers = <-servers:
+		case <-ctx.Done():
+			return health, ctx.Err()
+		}
+	}
+	if failed {
+		return health, frwkerr.ErrFrameworkFailed
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := cn.Exists("/")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		health.Latency = time.Since(start)
+		health.Healthy = err == nil
+		return health, err
+	case <-ctx.Done():
+		return health, ctx.Err()
+	}
+}
+
+/*
+fourLetterWords sends ruok and srvr to each server.
+*/
+func fourLetterWords(servers []string, timeout time.Duration) []core.ServerHealth {
+	servers = slices.Clone(servers)
+	ok := zk.FLWRuok(servers, timeout)
+	stats, _ := zk.FLWSrvr(servers, timeout)
+
+	reports := make([]core.ServerHealth, len(servers))
+	for i, server := range servers {
+		reports[i] = core.ServerHealth{Server: server, OK: ok[i]}
+		if i < len(stats) && stats[i].Error == nil {
+			reports[i].Stats = stats[i]
+		}
+	}
+	return reports
+}
@@ -0,0 +1,58 @@
+package framework
+
+import (
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+)
+
+/*
+updatableHostProvider is a zk.HostProvider whose server list can be replaced while the connection is open, choosing the servers with a host selection strategy.
+
+The strategy is called without holding the lock, as it may take a while, e.g. probing the servers.
+*/
+type updatableHostProvider struct {
+	selection HostSelection
+	mu        sync.Mutex
+}
+
+func newUpdatableHostProvider() *updatableHostProvider {
+	return &updatableHostProvider{
+		selection: ShuffledRoundRobin(),
+	}
+}
+
+/*
+useDialer makes the strategy dial the servers with the given dialer, when it dials them to choose among them.
+*/
+func (p *updatableHostProvider) useDialer(dial zk.Dialer) {
+	if selection, ok := p.current().(dialingHostSelection); ok {
+		selection.useDialer(dial)
+	}
+}
+
+func (p *updatableHostProvider) Init(servers []string) error {
+	return p.current().Init(servers)
+}
+
+func (p *updatableHostProvider) Len() int {
+	return p.current().Len()
+}
+
+func (p *updatableHostProvider) Next() (string, bool) {
+	return p.current().Next()
+}
+
+func (p *updatableHostProvider) Connected() {
+	p.current().Connected()
+}
+
+func (p *updatableHostProvider) Name() string {
+	return p.current().Name()
+}
+
+func (p *updatableHostProvider) current() HostSelection {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.selection
+}
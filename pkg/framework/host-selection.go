@@ -0,0 +1,272 @@
+package framework
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+)
+
+/*
+HostSelection is a strategy choosing the server of the ensemble to connect to, on connect and on each reconnect.
+
+Next returns true, together with the server, when every server has been tried since the last successful connection,
+making the client pause before trying again.
+*/
+type HostSelection interface {
+	zk.HostProvider
+	// Name identifies the strategy in the connection stats.
+	Name() string
+}
+
+const (
+	// HostSelectionShuffledRoundRobin is the name of the shuffled round robin strategy.
+	HostSelectionShuffledRoundRobin = "shuffled-round-robin"
+	// HostSelectionStickyUntilFailure is the name of the sticky until failure strategy.
+	HostSelectionStickyUntilFailure = "sticky-until-failure"
+	// HostSelectionNearestByLatency is the name of the nearest by latency strategy.
+	HostSelectionNearestByLatency = "nearest-by-latency"
+)
+
+/*
+ShuffledRoundRobin tries the servers in a random order, moving to the next server on each reconnect; it is the default strategy.
+*/
+func ShuffledRoundRobin() HostSelection {
+	return &shuffledRoundRobin{DNSHostProvider: zk.NewDNSHostProvider()}
+}
+
+type shuffledRoundRobin struct {
+	*zk.DNSHostProvider
+}
+
+func (s *shuffledRoundRobin) Name() string {
+	return HostSelectionShuffledRoundRobin
+}
+
+/*
+StickyUntilFailure tries the servers in a random order, reconnecting to the last server the client was connected to
+before moving to the next one, e.g. to keep the sessions on a local server.
+*/
+func StickyUntilFailure() HostSelection {
+	return &stickyUntilFailure{current: -1, last: -1}
+}
+
+type stickyUntilFailure struct {
+	servers []string
+	current int
+	last    int
+	stick   bool
+	lock    sync.Mutex
+}
+
+func (s *stickyUntilFailure) Name() string {
+	return HostSelectionStickyUntilFailure
+}
+
+func (s *stickyUntilFailure) Init(servers []string) error {
+	resolved, err := resolveServers(servers)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.servers = resolved
+	s.current = -1
+	s.last = -1
+	s.stick = false
+	return nil
+}
+
+func (s *stickyUntilFailure) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.servers)
+}
+
+func (s *stickyUntilFailure) Next() (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stick {
+		s.stick = false
+		s.current = s.last
+		return s.servers[s.current], false
+	}
+
+	s.current = (s.current + 1) % len(s.servers)
+	retryStart := s.current == s.last
+	if s.last == -1 {
+		s.last = 0
+	}
+	return s.servers[s.current], retryStart
+}
+
+func (s *stickyUntilFailure) Connected() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.last = s.current
+	s.stick = true
+}
+
+/*
+NearestByLatency tries the servers by increasing connection latency, probed with a TCP connection bounded by the given timeout;
+the servers are probed again on each reconnect and each time all of them have been tried.
+*/
+func NearestByLatency(probeTimeout time.Duration) HostSelection {
+	return &nearestByLatency{probeTimeout: probeTimeout, dial: net.DialTimeout}
+}
+
+/*
+dialingHostSelection is a host selection dialing the servers to choose among them, with the dialer of the framework.
+*/
+type dialingHostSelection interface {
+	useDialer(dial zk.Dialer)
+}
+
+type nearestByLatency struct {
+	probeTimeout time.Duration
+	dial         zk.Dialer
+	servers      []string
+	generation   int
+	tried        int
+	probe        bool
+	lock         sync.Mutex
+}
+
+func (s *nearestByLatency) Name() string {
+	return HostSelectionNearestByLatency
+}
+
+func (s *nearestByLatency) Init(servers []string) error {
+	resolved, err := resolveServers(servers)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	dial := s.dial
+	s.lock.Unlock()
+	sorted := sortByLatency(resolved, s.probeTimeout, dial)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.servers = sorted
+	s.generation++
+	s.tried = 0
+	s.probe = false
+	return nil
+}
+
+func (s *nearestByLatency) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.servers)
+}
+
+/*
+Next probes the servers again when needed, without holding the lock: servers replaced by Init meanwhile are kept, as they were just probed.
+*/
+func (s *nearestByLatency) Next() (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	retryStart := s.tried == len(s.servers)
+	if s.probe || retryStart {
+		servers, generation, dial := slices.Clone(s.servers), s.generation, s.dial
+		s.lock.Unlock()
+		sorted := sortByLatency(servers, s.probeTimeout, dial)
+		s.lock.Lock()
+
+		if generation == s.generation {
+			s.servers = sorted
+		}
+		s.tried = 0
+		s.probe = false
+	}
+
+	server := s.servers[s.tried]
+	s.tried++
+	return server, retryStart
+}
+
+func (s *nearestByLatency) Connected() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.probe = true
+}
+
+func (s *nearestByLatency) useDialer(dial zk.Dialer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dial = dial
+}
+
+/*
+sortByLatency sorts the servers by the latency of a connection opened with the given dialer, the unreachable ones last.
+*/
+func sortByLatency(servers []string, timeout time.Duration, dial zk.Dialer) []string {
+	type probed struct {
+		server  string
+		latency time.Duration
+	}
+
+	results := make([]probed, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			latency := time.Duration(1<<63 - 1)
+			if cn, err := dial("tcp", server, timeout); err == nil {
+				latency = time.Since(start)
+				cn.Close()
+			}
+			results[i] = probed{server: server, latency: latency}
+		}()
+	}
+	wg.Wait()
+
+	slices.SortStableFunc(results, func(a, b probed) int {
+		return cmp.Compare(a.latency, b.latency)
+	})
+	sorted := make([]string, len(results))
+	for i, result := range results {
+		sorted[i] = result.server
+	}
+	return sorted
+}
+
+/*
+resolveServers resolves the host names of the servers to their addresses, in a random order.
+*/
+func resolveServers(servers []string) ([]string, error) {
+	resolved := []string{}
+	for _, server := range zk.FormatServers(servers) {
+		host, port, err := net.SplitHostPort(server)
+		if err != nil {
+			return nil, err
+		}
+		addresses, err := net.LookupHost(host)
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range addresses {
+			resolved = append(resolved, net.JoinHostPort(address, port))
+		}
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no hosts found for addresses %q", servers)
+	}
+
+	rand.Shuffle(len(resolved), func(i, j int) {
+		resolved[i], resolved[j] = resolved[j], resolved[i]
+	})
+	return resolved, nil
+}
@@ -0,0 +1,161 @@
+package framework_test
+
+import (
+	"net"
+	"os"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+func TestHostSelection(t *testing.T) {
+
+	t.Run("Stick to the last connected server", func(t *testing.T) {
+		t.Log("Stick to the last connected server")
+		selection := framework.StickyUntilFailure()
+		if err := selection.Init([]string{"127.0.0.1:2181", "127.0.0.2:2181", "127.0.0.3:2181"}); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+
+		first, _ := selection.Next()
+		selection.Next()
+		connected, _ := selection.Next()
+		selection.Connected()
+
+		if server, retryStart := selection.Next(); server != connected || retryStart {
+			t.Errorf("expected to reconnect to %s, got %s, %v", connected, server, retryStart)
+		}
+		tried := []string{}
+		for range 3 {
+			server, retryStart := selection.Next()
+			tried = append(tried, server)
+			if retryStart != (server == connected) {
+				t.Errorf("expected to retry from the start only after all the servers, got %s, %v", server, retryStart)
+			}
+		}
+		if tried[0] != first {
+			t.Errorf("expected to move on to %s after a failure, got %s", first, tried[0])
+		}
+		if selection.Name() != framework.HostSelectionStickyUntilFailure {
+			t.Errorf("expected %s, got %s", framework.HostSelectionStickyUntilFailure, selection.Name())
+		}
+	})
+
+	t.Run("Prefer the nearest server", func(t *testing.T) {
+		t.Log("Prefer the nearest server")
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		defer listener.Close()
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		closed.Close()
+
+		selection := framework.NearestByLatency(100 * time.Millisecond)
+		if err := selection.Init([]string{closed.Addr().String(), listener.Addr().String()}); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if server, retryStart := selection.Next(); server != listener.Addr().String() || retryStart {
+			t.Errorf("expected the reachable server first, got %s, %v", server, retryStart)
+		}
+		if server, _ := selection.Next(); server != closed.Addr().String() {
+			t.Errorf("expected the unreachable server last, got %s", server)
+		}
+		if _, retryStart := selection.Next(); !retryStart {
+			t.Errorf("expected to retry from the start after all the servers")
+		}
+	})
+
+	t.Run("Probe the servers with the framework dialer", func(t *testing.T) {
+		t.Log("Probe the servers with the framework dialer")
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		defer listener.Close()
+
+		var dialed atomic.Int32
+		dialer := func(network string, address string, timeout time.Duration) (net.Conn, error) {
+			dialed.Add(1)
+			return net.DialTimeout(network, address, timeout)
+		}
+		zkFramework, err := framework.CreateFrameworkWithOptions(listener.Addr().String(),
+			framework.WithHostSelection(framework.NearestByLatency(100*time.Millisecond)),
+			framework.WithDialer(dialer),
+		)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if err := zkFramework.UpdateServers([]string{listener.Addr().String()}); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if dialed.Load() == 0 {
+			t.Errorf("expected the servers to be probed with the framework dialer")
+		}
+	})
+
+	t.Run("Expose the strategy in the connection stats", func(t *testing.T) {
+		t.Log("Expose the strategy in the connection stats")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url, framework.WithHostSelection(framework.StickyUntilFailure()))
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+
+		stats := zkFramework.ConnectionStats()
+		if stats.HostSelection != framework.HostSelectionStickyUntilFailure {
+			t.Errorf("expected %s, got %s", framework.HostSelectionStickyUntilFailure, stats.HostSelection)
+		}
+		if stats.Server == "" || !slices.Equal(stats.Servers, []string{url}) {
+			t.Errorf("expected the connected server among %s, got %v", url, stats)
+		}
+	})
+
+	t.Run("Connect to an ensemble", func(t *testing.T) {
+		t.Log("Connect to an ensemble")
+		url := os.Getenv(zkHostEnv)
+		if _, err := framework.CreateFrameworkWithOptions(url + ", "); !frwkerr.IsInvalidConnectionURL(err) {
+			t.Errorf("expected %v, got %v", frwkerr.ErrInvalidConnectionURL, err)
+		}
+		if _, err := framework.CreateFrameworkForEnsemble([]string{}); !frwkerr.IsInvalidConnectionURL(err) {
+			t.Errorf("expected %v, got %v", frwkerr.ErrInvalidConnectionURL, err)
+		}
+
+		unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		unreachable.Close()
+
+		zkFramework, err := framework.CreateFrameworkForEnsemble([]string{unreachable.Addr().String(), url})
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+
+		stats := zkFramework.ConnectionStats()
+		if zkFramework.URL() != unreachable.Addr().String()+","+url || len(stats.Servers) != 2 {
+			t.Errorf("expected both servers to be configured, got %s and %v", zkFramework.URL(), stats.Servers)
+		}
+	})
+}
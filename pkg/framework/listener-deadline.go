@@ -0,0 +1,74 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+func (c *zKFrameworkImpl) notifyStatusChangeListener(listener core.StatusChangeListener, previous zk.State, current zk.State) error {
+	return c.callWithDeadline(listener.UUID(), func(ctx context.Context) error {
+		if contextListener, ok := listener.(core.ContextStatusChangeListener); ok {
+			return contextListener.OnStatusChangeWithContext(ctx, c, previous, current)
+		}
+		return listener.OnStatusChange(c, previous, current)
+	})
+}
+
+func (c *zKFrameworkImpl) notifyShutdownListener(listener core.ShutdownListener) error {
+	return c.callWithDeadline(listener.UUID(), func(ctx context.Context) error {
+		if contextListener, ok := listener.(core.ContextShutdownListener); ok {
+			return contextListener.OnShutdownWithContext(WithinOperation(ctx), c)
+		}
+		return listener.OnShutdown(c)
+	})
+}
+
+/*
+callWithDeadline runs the callback of a listener, no longer awaiting it when the listener deadline is exceeded.
+*/
+func (c *zKFrameworkImpl) callWithDeadline(id string, callback func(ctx context.Context) error) error {
+	if c.listenerDeadline <= 0 {
+		return callback(context.Background())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.listenerDeadline)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- callback(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: listener %s after %s", frwkerr.ErrListenerDeadlineExceeded, id, c.listenerDeadline)
+	}
+}
+
+/*
+exceededDeadline counts a listener exceeding the deadline, returning whether it must be quarantined.
+*/
+func (c *zKFrameworkImpl) exceededDeadline(timeouts map[string]int, id string) bool {
+	c.timeoutsLock.Lock()
+	defer c.timeoutsLock.Unlock()
+
+	timeouts[id]++
+	if c.quarantineAfter <= 0 || timeouts[id] < c.quarantineAfter {
+		return false
+	}
+	delete(timeouts, id)
+	return true
+}
+
+func (c *zKFrameworkImpl) reportDeadlineExceeded(id string, quarantined bool) {
+	c.logger.Error("listener deadline exceeded", "listener", id, "quarantined", quarantined)
+	for _, callback := range c.onDeadlineExceeded {
+		callback(c, id, quarantined)
+	}
+}
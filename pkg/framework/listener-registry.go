@@ -0,0 +1,124 @@
+package framework
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+)
+
+type registrable interface {
+	UUID() string
+}
+
+/*
+listenerRegistry holds the listeners of a kind by UUID, in registration order.
+
+The notifications run on a snapshot of the listeners, so that a listener can add or remove listeners, itself included, while being notified.
+*/
+type listenerRegistry[L registrable] struct {
+	listeners map[string]L
+	order     []string
+	lock      sync.RWMutex
+}
+
+func newListenerRegistry[L registrable]() *listenerRegistry[L] {
+	return &listenerRegistry[L]{
+		listeners: make(map[string]L),
+	}
+}
+
+func (r *listenerRegistry[L]) add(listener L) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.listeners[listener.UUID()]; found {
+		return coreerr.ErrListenerAlreadyExists
+	}
+
+	r.listeners[listener.UUID()] = listener
+	r.order = append(r.order, listener.UUID())
+	return nil
+}
+
+func (r *listenerRegistry[L]) remove(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, found := r.listeners[id]; !found {
+		return coreerr.ErrListenerNotFound
+	}
+
+	delete(r.listeners, id)
+	r.order = slices.DeleteFunc(r.order, func(registered string) bool { return registered == id })
+	return nil
+}
+
+/*
+subscribe adds the listener, returning the subscription removing it.
+*/
+func (r *listenerRegistry[L]) subscribe(listener L) (core.Subscription, error) {
+	if err := r.add(listener); err != nil {
+		return nil, err
+	}
+	id := listener.UUID()
+	return &subscription{cancel: func() error { return r.remove(id) }}, nil
+}
+
+/*
+snapshot returns the listeners sorted by descending priority, keeping the registration order of the listeners with the same priority.
+*/
+func (r *listenerRegistry[L]) snapshot() []L {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	sorted := slices.Clone(r.order)
+	slices.SortStableFunc(sorted, func(a string, b string) int {
+		return priorityOf(r.listeners[b]) - priorityOf(r.listeners[a])
+	})
+	listeners := make([]L, len(sorted))
+	for i, id := range sorted {
+		listeners[i] = r.listeners[id]
+	}
+	return listeners
+}
+
+/*
+clear removes every listener, returning the removed ones.
+*/
+func (r *listenerRegistry[L]) clear() []L {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	removed := make([]L, 0, len(r.order))
+	for _, id := range r.order {
+		removed = append(removed, r.listeners[id])
+	}
+	r.listeners = make(map[string]L)
+	r.order = nil
+	return removed
+}
+
+func priorityOf(listener any) int {
+	if prioritizedListener, ok := listener.(core.PrioritizedListener); ok {
+		return prioritizedListener.Priority()
+	}
+	return core.PriorityDefault
+}
+
+/*
+subscription removes a listener once, the following cancellations being no-ops.
+*/
+type subscription struct {
+	cancel func() error
+	once   sync.Once
+	err    error
+}
+
+func (s *subscription) Cancel() error {
+	s.once.Do(func() {
+		s.err = s.cancel()
+	})
+	return s.err
+}
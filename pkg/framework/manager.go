@@ -0,0 +1,107 @@
+package framework
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+Manager owns a set of named frameworks, e.g. one per cluster or namespace, starting each of them on first use.
+*/
+type Manager struct {
+	frameworks map[string]core.ZKFramework
+	lock       sync.Mutex
+}
+
+/*
+NewManager creates an empty framework manager.
+*/
+func NewManager() *Manager {
+	return &Manager{
+		frameworks: make(map[string]core.ZKFramework),
+	}
+}
+
+/*
+Register creates a framework with the given connection URL and options, registering it under the given name without starting it.
+*/
+func (m *Manager) Register(name string, url string, options ...Option) error {
+	zkFramework, err := CreateFrameworkWithOptions(url, options...)
+	if err != nil {
+		return err
+	}
+	return m.Add(name, zkFramework)
+}
+
+/*
+Add registers an existing framework under the given name.
+*/
+func (m *Manager) Add(name string, zkFramework core.ZKFramework) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, found := m.frameworks[name]; found {
+		return frwkerr.ErrFrameworkAlreadyRegistered
+	}
+	m.frameworks[name] = zkFramework
+	return nil
+}
+
+/*
+Get returns the framework registered under the given name, starting it if it is not yet started.
+*/
+func (m *Manager) Get(name string) (core.ZKFramework, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	zkFramework, found := m.frameworks[name]
+	if !found {
+		return nil, frwkerr.ErrFrameworkNotRegistered
+	}
+
+	if !zkFramework.Started() {
+		if err := zkFramework.Start(); err != nil {
+			return nil, err
+		}
+	}
+	return zkFramework, nil
+}
+
+/*
+Names returns the sorted names of the registered frameworks.
+*/
+func (m *Manager) Names() []string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	names := make([]string, 0, len(m.frameworks))
+	for name := range m.frameworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+Shutdown stops all the started frameworks and unregisters every framework.
+*/
+func (m *Manager) Shutdown() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	errs := []error{}
+	for _, zkFramework := range m.frameworks {
+		if !zkFramework.Started() {
+			continue
+		}
+		if err := zkFramework.Stop(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	m.frameworks = make(map[string]core.ZKFramework)
+	return errors.Join(errs...)
+}
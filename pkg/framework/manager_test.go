@@ -0,0 +1,101 @@
+package framework_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+func TestManager(t *testing.T) {
+
+	t.Run("Lazily start a registered framework", func(t *testing.T) {
+		t.Log("Lazily start a registered framework")
+		url := os.Getenv(zkHostEnv)
+		manager := framework.NewManager()
+		defer manager.Shutdown()
+
+		if err := manager.Register("primary", url, framework.WithNamespace("primary")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := manager.Register("secondary", url, framework.WithNamespace("secondary")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if names := manager.Names(); len(names) != 2 || names[0] != "primary" || names[1] != "secondary" {
+			t.Errorf("expected [primary secondary], got %v", names)
+		}
+
+		zkFramework, err := manager.Get("primary")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if !zkFramework.Started() {
+			t.Error("expected the framework to be started")
+		}
+		if zkFramework.Namespace() != "/primary" {
+			t.Errorf("expected /primary namespace, got %s", zkFramework.Namespace())
+		}
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		again, err := manager.Get("primary")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if again != zkFramework {
+			t.Error("expected the same framework instance")
+		}
+	})
+
+	t.Run("Register a name twice", func(t *testing.T) {
+		t.Log("Register a name twice")
+		url := os.Getenv(zkHostEnv)
+		manager := framework.NewManager()
+
+		if err := manager.Register("primary", url); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := manager.Register("primary", url); !frwkerr.IsFrameworkAlreadyRegistered(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkAlreadyRegistered, err)
+		}
+	})
+
+	t.Run("Get an unknown framework", func(t *testing.T) {
+		t.Log("Get an unknown framework")
+		manager := framework.NewManager()
+
+		if _, err := manager.Get("unknown"); !frwkerr.IsFrameworkNotRegistered(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkNotRegistered, err)
+		}
+	})
+
+	t.Run("Shutdown stops the started frameworks", func(t *testing.T) {
+		t.Log("Shutdown stops the started frameworks")
+		url := os.Getenv(zkHostEnv)
+		manager := framework.NewManager()
+
+		if err := manager.Register("started", url); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := manager.Register("idle", url); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		zkFramework, err := manager.Get("started")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := manager.Shutdown(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.Started() {
+			t.Error("expected the framework to be stopped")
+		}
+		if len(manager.Names()) != 0 {
+			t.Errorf("expected no registered frameworks, got %v", manager.Names())
+		}
+	})
+}
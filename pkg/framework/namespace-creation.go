@@ -0,0 +1,38 @@
+package framework
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/acl"
+)
+
+/*
+createNamespace creates the missing nodes of the namespace as container nodes, so that the operations on a fresh ensemble find the namespace;
+the nodes get the ACL of the ACL provider, if any, otherwise the world ACL.
+*/
+func (c *zKFrameworkImpl) createNamespace(cn *zk.Conn) error {
+	current := ""
+	for _, part := range strings.Split(strings.Trim(c.namespace, "/"), "/") {
+		if part == "" {
+			return nil
+		}
+		current += "/" + part
+
+		if _, err := cn.Create(current, []byte{}, zk.FlagContainer, acl.DefaultFor(c.aclProvider, current)); err != nil && !errors.Is(err, zk.ErrNodeExists) {
+			return err
+		}
+	}
+	c.logger.Debug("namespace created", "namespace", c.namespace)
+	return nil
+}
+
+/*
+bootstrapNamespace creates the namespace for a new session, logging the failure.
+*/
+func (c *zKFrameworkImpl) bootstrapNamespace(cn *zk.Conn) {
+	if err := c.createNamespace(cn); err != nil {
+		c.logger.Error("error creating the namespace", "namespace", c.namespace, "error", err)
+	}
+}
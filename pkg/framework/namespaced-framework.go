@@ -0,0 +1,114 @@
+package framework
+
+import (
+	"path"
+	"time"
+
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+namespacedFramework is a view of a framework scoped to a child namespace, sharing its connection and listeners;
+the listeners added through the view are notified with the view.
+*/
+type namespacedFramework struct {
+	core.ZKFramework
+	namespace string
+}
+
+func (n *namespacedFramework) Namespace() string {
+	return n.namespace
+}
+
+func (n *namespacedFramework) UsingNamespace(namespace string) core.ZKFramework {
+	return usingNamespace(n.ZKFramework, n.namespace, namespace)
+}
+
+func (n *namespacedFramework) UsingOperationTimeout(timeout time.Duration) core.ZKFramework {
+	return &timeoutFramework{
+		ZKFramework: n,
+		timeout:     timeout,
+	}
+}
+
+func (n *namespacedFramework) AddStatusChangeListener(listener core.StatusChangeListener) error {
+	return n.ZKFramework.AddStatusChangeListener(viewStatusChangeListener{StatusChangeListener: listener, view: n})
+}
+
+func (n *namespacedFramework) SubscribeStatusChange(listener core.StatusChangeListener) (core.Subscription, error) {
+	return n.ZKFramework.SubscribeStatusChange(viewStatusChangeListener{StatusChangeListener: listener, view: n})
+}
+
+func (n *namespacedFramework) AddShutdownListener(listener core.ShutdownListener) error {
+	return n.ZKFramework.AddShutdownListener(viewShutdownListener{ShutdownListener: listener, view: n})
+}
+
+func (n *namespacedFramework) SubscribeShutdown(listener core.ShutdownListener) (core.Subscription, error) {
+	return n.ZKFramework.SubscribeShutdown(viewShutdownListener{ShutdownListener: listener, view: n})
+}
+
+func (n *namespacedFramework) AddSessionListener(listener core.SessionListener) error {
+	return n.ZKFramework.AddSessionListener(viewSessionListener{SessionListener: listener, view: n})
+}
+
+func (n *namespacedFramework) SubscribeSession(listener core.SessionListener) (core.Subscription, error) {
+	return n.ZKFramework.SubscribeSession(viewSessionListener{SessionListener: listener, view: n})
+}
+
+/*
+timeoutFramework is a view of a framework running the operations with a different deadline, sharing its connection and listeners;
+the listeners added through the view are notified with the view.
+*/
+type timeoutFramework struct {
+	core.ZKFramework
+	timeout time.Duration
+}
+
+func (t *timeoutFramework) OperationTimeout() time.Duration {
+	return t.timeout
+}
+
+func (t *timeoutFramework) UsingOperationTimeout(timeout time.Duration) core.ZKFramework {
+	return &timeoutFramework{
+		ZKFramework: t.ZKFramework,
+		timeout:     timeout,
+	}
+}
+
+func (t *timeoutFramework) UsingNamespace(namespace string) core.ZKFramework {
+	return &timeoutFramework{
+		ZKFramework: t.ZKFramework.UsingNamespace(namespace),
+		timeout:     t.timeout,
+	}
+}
+
+func (t *timeoutFramework) AddStatusChangeListener(listener core.StatusChangeListener) error {
+	return t.ZKFramework.AddStatusChangeListener(viewStatusChangeListener{StatusChangeListener: listener, view: t})
+}
+
+func (t *timeoutFramework) SubscribeStatusChange(listener core.StatusChangeListener) (core.Subscription, error) {
+	return t.ZKFramework.SubscribeStatusChange(viewStatusChangeListener{StatusChangeListener: listener, view: t})
+}
+
+func (t *timeoutFramework) AddShutdownListener(listener core.ShutdownListener) error {
+	return t.ZKFramework.AddShutdownListener(viewShutdownListener{ShutdownListener: listener, view: t})
+}
+
+func (t *timeoutFramework) SubscribeShutdown(listener core.ShutdownListener) (core.Subscription, error) {
+	return t.ZKFramework.SubscribeShutdown(viewShutdownListener{ShutdownListener: listener, view: t})
+}
+
+func (t *timeoutFramework) AddSessionListener(listener core.SessionListener) error {
+	return t.ZKFramework.AddSessionListener(viewSessionListener{SessionListener: listener, view: t})
+}
+
+func (t *timeoutFramework) SubscribeSession(listener core.SessionListener) (core.Subscription, error) {
+	return t.ZKFramework.SubscribeSession(viewSessionListener{SessionListener: listener, view: t})
+}
+
+func usingNamespace(parent core.ZKFramework, parentNamespace string, namespace string) core.ZKFramework {
+	return &namespacedFramework{
+		ZKFramework: parent,
+		namespace:   path.Join(parentNamespace, namespace),
+	}
+}
@@ -0,0 +1,342 @@
+package framework
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/deadletter"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+const authenticatedLogFormat = "authenticated: id=%d, timeout=%d"
+
+/*
+Option configures a framework created with CreateFrameworkWithOptions.
+*/
+type Option func(*zKFrameworkImpl)
+
+/*
+WithNamespace sets the namespace prefixed to the paths of all the operations, the parts are joined as a path.
+*/
+func WithNamespace(namespace ...string) Option {
+	return func(c *zKFrameworkImpl) {
+		c.namespace = "/" + strings.TrimPrefix(path.Join(namespace...), "/")
+	}
+}
+
+/*
+WithNamespaceCreation creates the missing nodes of the namespace as container nodes once a session is established, for each new session.
+*/
+func WithNamespaceCreation() Option {
+	return func(c *zKFrameworkImpl) {
+		c.namespaceCreation = true
+	}
+}
+
+/*
+WithSessionTimeout sets the session timeout requested to the Zookeeper server.
+*/
+func WithSessionTimeout(sessionTimeout time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.sessionTimeout = sessionTimeout
+	}
+}
+
+/*
+WithOperationTimeout sets the default deadline of the operations run through the framework.
+*/
+func WithOperationTimeout(operationTimeout time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.operationTimeout = operationTimeout
+	}
+}
+
+/*
+WithRetryPolicy sets the policy deciding the delay between reconnection attempts.
+*/
+func WithRetryPolicy(retryPolicy retry.Policy) Option {
+	return func(c *zKFrameworkImpl) {
+		c.retryPolicy = retryPolicy
+	}
+}
+
+/*
+WithMaxReconnectionAttempts bounds the reconnection attempts, whatever the retry policy, after which the framework transitions to the terminal failed state.
+*/
+func WithMaxReconnectionAttempts(attempts int) Option {
+	return func(c *zKFrameworkImpl) {
+		c.maxReconnectionAttempts = attempts
+	}
+}
+
+/*
+WithMaxReconnectionElapsed bounds the time spent reconnecting, whatever the retry policy, after which the framework transitions to the terminal failed state.
+*/
+func WithMaxReconnectionElapsed(elapsed time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.maxReconnectionElapsed = elapsed
+	}
+}
+
+/*
+WithHealthFourLetterWords makes the health checks also send ruok and srvr to each server of the ensemble, bounded by the given timeout.
+
+The four letter words are sent over plain TCP connections and must be whitelisted on the servers (4lw.commands.whitelist).
+*/
+func WithHealthFourLetterWords(timeout time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.fourLetterWordsTimeout = timeout
+	}
+}
+
+/*
+WithLogger sets the logger used by the framework and by the underlying Zookeeper connection.
+*/
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *zKFrameworkImpl) {
+		c.logger = logger
+	}
+}
+
+/*
+WithAuth adds credentials applied to the session each time the framework connects, e.g. scheme digest with user:password.
+*/
+func WithAuth(scheme string, auth []byte) Option {
+	return func(c *zKFrameworkImpl) {
+		c.auth = append(c.auth, authCredentials{scheme: scheme, auth: auth})
+	}
+}
+
+/*
+WithTLS connects to the secure client port of the Zookeeper servers using the given TLS configuration.
+
+The TLS handshake runs over the connections opened by the dialer, so that it can be combined with WithDialer or WithContextDialer.
+*/
+func WithTLS(tlsConfig *tls.Config) Option {
+	return func(c *zKFrameworkImpl) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+/*
+WithDialer sets the function opening the network connections to the Zookeeper servers, net.DialTimeout by default.
+*/
+func WithDialer(dialer zk.Dialer) Option {
+	return func(c *zKFrameworkImpl) {
+		c.dialer = dialer
+	}
+}
+
+/*
+WithContextDialer sets a context-aware function opening the network connections to the Zookeeper servers, bounded by the connection timeout,
+e.g. the DialContext method of a net.Dialer tuning the keepalive, or of a SOCKS proxy dialer.
+*/
+func WithContextDialer(dial func(ctx context.Context, network string, address string) (net.Conn, error)) Option {
+	return func(c *zKFrameworkImpl) {
+		c.dialer = func(network string, address string, timeout time.Duration) (net.Conn, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			return dial(ctx, network, address)
+		}
+	}
+}
+
+/*
+WithHostSelection sets the strategy choosing the server of the ensemble to connect to, ShuffledRoundRobin by default.
+*/
+func WithHostSelection(selection HostSelection) Option {
+	return func(c *zKFrameworkImpl) {
+		c.hostProvider.selection = selection
+	}
+}
+
+/*
+WithACLProvider sets the provider of the ACL of the nodes created through the framework without an explicit one, instead of the world ACL,
+e.g. an acl.SubtreeProvider applying a policy to each subtree.
+*/
+func WithACLProvider(aclProvider core.ACLProvider) Option {
+	return func(c *zKFrameworkImpl) {
+		c.aclProvider = aclProvider
+	}
+}
+
+/*
+WithExtension runs the given function on the framework once created, letting the packages extending the framework, e.g. operation, provide their own options.
+*/
+func WithExtension(extend func(core.ZKFramework)) Option {
+	return func(c *zKFrameworkImpl) {
+		c.extenders = append(c.extenders, extend)
+	}
+}
+
+/*
+WithCircuitBreaker sets the circuit breaker guarding the operations run through the framework.
+*/
+func WithCircuitBreaker(circuitBreaker core.CircuitBreaker) Option {
+	return func(c *zKFrameworkImpl) {
+		c.circuitBreaker = circuitBreaker
+	}
+}
+
+/*
+WithSessionWatchdog probes the connection with a lightweight request at the given interval while connected, invalidating it when no response arrives within the threshold.
+
+It catches half-open TCP connections, which the underlying Zookeeper client only detects when its own read timeout expires.
+*/
+func WithSessionWatchdog(interval time.Duration, threshold time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.watchdogInterval = interval
+		c.watchdogThreshold = threshold
+	}
+}
+
+/*
+WithEnsembleConfigWatch follows the dynamic configuration of a Zookeeper 3.5+ ensemble, updating the servers as members are added or removed.
+*/
+func WithEnsembleConfigWatch() Option {
+	return func(c *zKFrameworkImpl) {
+		c.ensembleConfigWatch = true
+	}
+}
+
+/*
+WithServerResolver asks the resolver for the servers of the ensemble at the given interval, updating them when they change.
+*/
+func WithServerResolver(resolver ServerResolver, interval time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.serverResolver = resolver
+		c.serverResolveInterval = interval
+	}
+}
+
+/*
+WithDNSRefresh resolves again the hostname of the connected server at the given interval,
+reconnecting when it no longer resolves to the address of the connection, e.g. when the IPs of a Kubernetes service change.
+*/
+func WithDNSRefresh(interval time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.dnsRefreshInterval = interval
+	}
+}
+
+/*
+WithDeadLetters sets the buffer capturing the status changes a listener failed to handle, replaying a letter notifies the listener again.
+*/
+func WithDeadLetters(deadLetters *deadletter.Buffer) Option {
+	return func(c *zKFrameworkImpl) {
+		c.deadLetters = deadLetters
+	}
+}
+
+/*
+WithListenerDeadline sets the maximum execution time of each listener callback, the callbacks exceeding it are reported and no longer awaited.
+
+Listeners implementing core.ContextStatusChangeListener or core.ContextShutdownListener are notified with a context cancelled when the deadline is exceeded.
+*/
+func WithListenerDeadline(deadline time.Duration) Option {
+	return func(c *zKFrameworkImpl) {
+		c.listenerDeadline = deadline
+	}
+}
+
+/*
+WithListenerQuarantine removes the listeners exceeding the listener deadline the given number of times, they are no longer notified unless added again.
+*/
+func WithListenerQuarantine(after int) Option {
+	return func(c *zKFrameworkImpl) {
+		c.quarantineAfter = after
+	}
+}
+
+/*
+WithOnListenerDeadlineExceeded registers a callback invoked each time a listener exceeds the listener deadline, reporting whether it was quarantined.
+*/
+func WithOnListenerDeadlineExceeded(callback func(zkFramework core.ZKFramework, listenerID string, quarantined bool)) Option {
+	return func(c *zKFrameworkImpl) {
+		c.onDeadlineExceeded = append(c.onDeadlineExceeded, callback)
+	}
+}
+
+/*
+WithOnConnected registers a callback invoked each time the connection to the Zookeeper server is established.
+*/
+func WithOnConnected(callback func(zkFramework core.ZKFramework)) Option {
+	return func(c *zKFrameworkImpl) {
+		c.onConnected = append(c.onConnected, callback)
+	}
+}
+
+/*
+WithOnDisconnected registers a callback invoked each time the connection to the Zookeeper server is lost.
+*/
+func WithOnDisconnected(callback func(zkFramework core.ZKFramework)) Option {
+	return func(c *zKFrameworkImpl) {
+		c.onDisconnected = append(c.onDisconnected, callback)
+	}
+}
+
+/*
+WithOnSessionExpired registers a callback invoked each time the Zookeeper session expires.
+*/
+func WithOnSessionExpired(callback func(zkFramework core.ZKFramework)) Option {
+	return func(c *zKFrameworkImpl) {
+		c.onSessionExpired = append(c.onSessionExpired, callback)
+	}
+}
+
+/*
+WithOnFailed registers a callback invoked when the framework gives up reconnecting to the Zookeeper server,
+reporting the attempts made, e.g. to crash the process or to fail over deliberately.
+*/
+func WithOnFailed(callback func(zkFramework core.ZKFramework, lost core.ConnectionLost)) Option {
+	return func(c *zKFrameworkImpl) {
+		c.onFailed = append(c.onFailed, callback)
+	}
+}
+
+func (c *zKFrameworkImpl) runLifecycleCallbacks(previous zk.State, current zk.State) {
+	callbacks := []func(core.ZKFramework){}
+	switch {
+	case current == core.StateFailed:
+		// the failed callbacks are run by fail, with the reconnection attempts
+		return
+	case current == zk.StateExpired:
+		callbacks = c.onSessionExpired
+	case !isConnectedState(previous) && isConnectedState(current):
+		callbacks = c.onConnected
+	case isConnectedState(previous) && !isConnectedState(current):
+		callbacks = c.onDisconnected
+	}
+
+	for _, callback := range callbacks {
+		callback(c)
+	}
+}
+
+/*
+zkLogger adapts a slog.Logger to the logger of the underlying Zookeeper connection.
+
+The connection only reports the negotiated session timeout in its log, so the authentication message is parsed to capture it.
+*/
+type zkLogger struct {
+	logger           *slog.Logger
+	onSessionTimeout func(timeout int64)
+}
+
+func (l zkLogger) Printf(format string, args ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, args...))
+
+	if format == authenticatedLogFormat && len(args) == 2 && l.onSessionTimeout != nil {
+		if timeoutMs, ok := args[1].(int32); ok {
+			l.onSessionTimeout(int64(time.Duration(timeoutMs) * time.Millisecond))
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package framework_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	"github.com/morphy76/zk/internal/test_util/mocks"
+	"github.com/morphy76/zk/pkg/breaker"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/deadletter"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+func TestCreateFrameworkWithOptions(t *testing.T) {
+
+	t.Run("Create a ZK framework with options and empty URL", func(t *testing.T) {
+		t.Log("Create a ZK framework with options and empty URL")
+		_, err := framework.CreateFrameworkWithOptions("")
+		if !frwkerr.IsInvalidConnectionURL(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrInvalidConnectionURL, err)
+		}
+	})
+
+	t.Run("Lifecycle callbacks", func(t *testing.T) {
+		t.Log("Lifecycle callbacks")
+		connected := make(chan bool, 1)
+		disconnected := make(chan bool, 1)
+
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url,
+			framework.WithOnConnected(func(core.ZKFramework) { connected <- true }),
+			framework.WithOnDisconnected(func(core.ZKFramework) { disconnected <- true }),
+			framework.WithOnSessionExpired(func(core.ZKFramework) {}),
+		)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.Namespace() != "/" {
+			t.Errorf("expected root namespace, got %s", zkFramework.Namespace())
+		}
+
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		select {
+		case <-connected:
+		case <-time.After(10 * time.Second):
+			t.Error("expected the connected callback to be invoked")
+		}
+		select {
+		case <-disconnected:
+			t.Error("expected the disconnected callback not to be invoked")
+		default:
+		}
+	})
+	t.Run("Namespace, session, retry, logger and auth options", func(t *testing.T) {
+		t.Log("Namespace, session, retry, logger and auth options")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url,
+			framework.WithNamespace("options", "test"),
+			framework.WithSessionTimeout(5*time.Second),
+			framework.WithRetryPolicy(retry.NewExponentialBackoff(50*time.Millisecond, time.Second)),
+			framework.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			framework.WithAuth("digest", []byte("user:password")),
+		)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.Namespace() != "/options/test" {
+			t.Errorf("expected namespace /options/test, got %s", zkFramework.Namespace())
+		}
+
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	})
+
+	t.Run("Circuit breaker option", func(t *testing.T) {
+		t.Log("Circuit breaker option")
+		circuitBreaker := breaker.NewBreaker()
+		zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), framework.WithCircuitBreaker(circuitBreaker))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.CircuitBreaker() != circuitBreaker {
+			t.Errorf("expected the circuit breaker to be set")
+		}
+		if zkFramework.UsingNamespace("child").CircuitBreaker() != circuitBreaker {
+			t.Errorf("expected the circuit breaker to be shared by namespaced views")
+		}
+	})
+
+	t.Run("Dead letters option", func(t *testing.T) {
+		t.Log("Dead letters option")
+		deadLetters := deadletter.NewBuffer(10)
+		zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), framework.WithDeadLetters(deadLetters))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		failing := errors.New("failing")
+		mockedListener := &mocks.MockedStatusChangeListener{
+			ID:  uuid.New().String(),
+			Err: failing,
+		}
+		if err := zkFramework.AddStatusChangeListener(mockedListener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		zkFramework.NotifyStatusChange()
+
+		letters := deadLetters.Letters()
+		if len(letters) != 1 || letters[0].Source != mockedListener.ID || letters[0].Err != failing {
+			t.Fatalf("expected 1 dead letter from the listener, got %+v", letters)
+		}
+		if _, ok := letters[0].Payload.(framework.StatusChange); !ok {
+			t.Errorf("expected a status change payload, got %+v", letters[0].Payload)
+		}
+
+		mockedListener.Err = nil
+		if err := deadLetters.Replay(letters[0].ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if mockedListener.Interactions != 2 || deadLetters.Len() != 0 {
+			t.Errorf("expected the listener to be notified again on replay")
+		}
+	})
+
+	t.Run("Listener deadline and quarantine options", func(t *testing.T) {
+		t.Log("Listener deadline and quarantine options")
+		reports := make(chan bool, 2)
+		zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv),
+			framework.WithListenerDeadline(20*time.Millisecond),
+			framework.WithListenerQuarantine(2),
+			framework.WithOnListenerDeadlineExceeded(func(zkFramework core.ZKFramework, listenerID string, quarantined bool) {
+				reports <- quarantined
+			}),
+		)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		slowListener := &mocks.MockedSlowListener{
+			ID:        uuid.New().String(),
+			Cancelled: make(chan bool, 2),
+		}
+		if err := zkFramework.AddStatusChangeListener(slowListener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		for _, expected := range []bool{false, true} {
+			zkFramework.NotifyStatusChange()
+			if quarantined := <-reports; quarantined != expected {
+				t.Errorf("expected quarantined to be %v, got %v", expected, quarantined)
+			}
+			select {
+			case <-slowListener.Cancelled:
+			case <-time.After(time.Second):
+				t.Errorf("expected the listener context to be cancelled")
+			}
+		}
+
+		if err := zkFramework.RemoveStatusChangeListenerByID(slowListener.ID); !coreerr.IsListenerNotFound(err) {
+			t.Errorf("expected the quarantined listener to be removed, got %v", err)
+		}
+	})
+
+	t.Run("Operation timeout option", func(t *testing.T) {
+		t.Log("Operation timeout option")
+		zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv),
+			framework.WithNamespace("timeouts"),
+			framework.WithOperationTimeout(time.Minute),
+		)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.OperationTimeout() != time.Minute {
+			t.Errorf("expected operation timeout %s, got %s", time.Minute, zkFramework.OperationTimeout())
+		}
+
+		view := zkFramework.UsingOperationTimeout(time.Second).UsingNamespace("child")
+		if view.OperationTimeout() != time.Second || view.Namespace() != "/timeouts/child" {
+			t.Errorf("expected a view on /timeouts/child with operation timeout %s, got %s and %s", time.Second, view.Namespace(), view.OperationTimeout())
+		}
+		view = zkFramework.UsingNamespace("child").UsingOperationTimeout(time.Second)
+		if view.OperationTimeout() != time.Second || view.Namespace() != "/timeouts/child" {
+			t.Errorf("expected a view on /timeouts/child with operation timeout %s, got %s and %s", time.Second, view.Namespace(), view.OperationTimeout())
+		}
+		if zkFramework.UsingNamespace("child").OperationTimeout() != time.Minute {
+			t.Errorf("expected namespaced views to keep the operation timeout")
+		}
+	})
+
+	t.Run("Custom dialer option", func(t *testing.T) {
+		t.Log("Custom dialer option")
+		dials := atomic.Int32{}
+		dialer := &net.Dialer{KeepAlive: 5 * time.Second}
+		zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv),
+			framework.WithContextDialer(func(ctx context.Context, network string, address string) (net.Conn, error) {
+				dials.Add(1)
+				return dialer.DialContext(ctx, network, address)
+			}),
+		)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if dials.Load() == 0 {
+			t.Errorf("expected the connection to be opened by the custom dialer")
+		}
+
+		failing, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv),
+			framework.WithDialer(func(string, string, time.Duration) (net.Conn, error) {
+				return nil, errors.New("proxy unavailable")
+			}),
+		)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := failing.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer failing.Stop()
+		if err := failing.WaitConnection(500 * time.Millisecond); err == nil {
+			t.Errorf("expected no connection through the failing dialer")
+		}
+	})
+
+	t.Run("Add credentials to a running framework", func(t *testing.T) {
+		t.Log("Add credentials to a running framework")
+		zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.AddAuth("digest", []byte("reader:secret")); !frwkerr.IsFrameworkNotYetStarted(err) {
+			t.Errorf("expected %v, got %v", frwkerr.ErrFrameworkNotYetStarted, err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		nodePath := "/" + uuid.New().String()
+		protected := zk.DigestACL(zk.PermAll, "reader", "secret")
+		if _, err := zkFramework.Cn().Create(nodePath, []byte("protected"), 0, protected); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, _, err := zkFramework.Cn().Get(nodePath); !errors.Is(err, zk.ErrNoAuth) {
+			t.Errorf("expected %v, got %v", zk.ErrNoAuth, err)
+		}
+
+		if err := zkFramework.AddAuth("digest", []byte("reader:secret")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if data, _, err := zkFramework.Cn().Get(nodePath); err != nil || string(data) != "protected" {
+			t.Errorf("expected to read the protected node, got %s, %v", data, err)
+		}
+	})
+
+	t.Run("Logger shared with the operations", func(t *testing.T) {
+		t.Log("Logger shared with the operations")
+		output := &bytes.Buffer{}
+		logger := slog.New(slog.NewTextHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url, framework.WithLogger(logger))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.Logger() != logger || zkFramework.UsingNamespace("scoped").Logger() != logger {
+			t.Errorf("expected the configured logger")
+		}
+
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if _, err := operation.Exists(zkFramework, uuid.New().String()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if !strings.Contains(output.String(), "checking if node exists") {
+			t.Errorf("expected the operation to be logged, got %s", output.String())
+		}
+	})
+
+	t.Run("Create the namespace once connected", func(t *testing.T) {
+		t.Log("Create the namespace once connected")
+		ns := uuid.New().String()
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url, framework.WithNamespace(ns, "nested"), framework.WithNamespaceCreation())
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			exists, _, err := zkFramework.Cn().Exists(zkFramework.Namespace())
+			if err != nil {
+				t.Fatalf(unexpectedErrorFmt, err)
+			}
+			if exists {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the namespace %s to be created", zkFramework.Namespace())
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if _, err := operation.Ls(zkFramework); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	})
+
+	t.Run("Refresh the DNS while the addresses are unchanged", func(t *testing.T) {
+		t.Log("Refresh the DNS while the addresses are unchanged")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url, framework.WithDNSRefresh(100*time.Millisecond))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := zkFramework.StartAndWait(10 * time.Second); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+		sessionID := zkFramework.SessionID()
+
+		time.Sleep(time.Second)
+		if !zkFramework.Connected() || zkFramework.SessionID() != sessionID {
+			t.Errorf("expected the connection to be kept while the server resolves to the same addresses")
+		}
+	})
+
+	t.Run("Share the extensions with the views", func(t *testing.T) {
+		t.Log("Share the extensions with the views")
+		type extensionKey struct{}
+		extended := 0
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url, framework.WithExtension(func(zkFramework core.ZKFramework) {
+			extended++
+			zkFramework.Extension(extensionKey{}, func() any { return "value" })
+		}))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if extended != 1 {
+			t.Errorf("expected the extension to run once, got %d", extended)
+		}
+
+		view := zkFramework.UsingNamespace("view").UsingOperationTimeout(time.Second)
+		if value := view.Extension(extensionKey{}, func() any { return "other" }); value != "value" {
+			t.Errorf("expected the value of the framework, got %v", value)
+		}
+	})
+}
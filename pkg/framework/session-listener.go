@@ -0,0 +1,98 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+AddSessionListener adds a listener for the loss of the Zookeeper session.
+*/
+func (c *zKFrameworkImpl) AddSessionListener(sessionListener core.SessionListener) error {
+	return c.sessionListeners.add(sessionListener)
+}
+
+/*
+SubscribeSession adds a listener for the loss of the Zookeeper session, returning the subscription removing it.
+*/
+func (c *zKFrameworkImpl) SubscribeSession(sessionListener core.SessionListener) (core.Subscription, error) {
+	return c.sessionListeners.subscribe(sessionListener)
+}
+
+/*
+RemoveSessionListener removes a listener for the loss of the Zookeeper session.
+*/
+func (c *zKFrameworkImpl) RemoveSessionListener(sessionListener core.SessionListener) error {
+	return c.RemoveSessionListenerByID(sessionListener.UUID())
+}
+
+/*
+RemoveSessionListenerByID removes the listener for the loss of the Zookeeper session with the given UUID.
+*/
+func (c *zKFrameworkImpl) RemoveSessionListenerByID(id string) error {
+	return c.sessionListeners.remove(id)
+}
+
+/*
+trackSession detects the loss of the session from the connection states, the status change lock must be held.
+
+The session is lost when it expires or when a session with another ID is established, e.g. after the framework replaced the connection.
+*/
+func (c *zKFrameworkImpl) trackSession(state zk.State) {
+	if state == zk.StateExpired && !c.sessionLost {
+		c.sessionLost = true
+		c.publish(core.SessionExpired{SessionID: c.sessionID})
+		go c.notifySession(true, false)
+		return
+	}
+	if state != zk.StateHasSession || c.cn == nil {
+		return
+	}
+
+	id := c.cn.SessionID()
+	if c.sessionID != 0 && id != c.sessionID {
+		if !c.sessionLost {
+			c.publish(core.SessionExpired{SessionID: c.sessionID})
+		}
+		go c.notifySession(!c.sessionLost, true)
+	}
+	if c.namespaceCreation && id != c.sessionID {
+		go c.bootstrapNamespace(c.cn)
+	}
+	c.sessionID = id
+	c.sessionLost = false
+}
+
+/*
+notifySession notifies the session listeners of the expiry of the session and then of the new session, as requested.
+*/
+func (c *zKFrameworkImpl) notifySession(expired bool, reestablished bool) {
+	c.sessionNotification.Lock()
+	defer c.sessionNotification.Unlock()
+
+	if expired {
+		c.logger.Warn("Zookeeper session expired", "url", c.url)
+		c.notifySessionListeners(func(listener core.SessionListener) error {
+			return listener.OnSessionExpired(c)
+		})
+	}
+	if reestablished {
+		c.logger.Info("Zookeeper session reestablished", "url", c.url)
+		c.notifySessionListeners(func(listener core.SessionListener) error {
+			return listener.OnSessionReestablished(c)
+		})
+	}
+}
+
+func (c *zKFrameworkImpl) notifySessionListeners(notify func(listener core.SessionListener) error) {
+	for _, listener := range c.sessionListeners.snapshot() {
+		err := c.callWithDeadline(listener.UUID(), func(_ context.Context) error {
+			return notify(listener)
+		})
+		if err != nil {
+			c.logger.Error("error notifying session listener", "listener", listener.UUID(), "error", err)
+		}
+	}
+}
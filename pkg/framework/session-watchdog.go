@@ -0,0 +1,56 @@
+package framework
+
+import (
+	"time"
+
+	"github.com/go-zookeeper/zk"
+)
+
+/*
+sessionWatchdog probes the connection while connected, forcing its invalidation when the server stops responding.
+*/
+func (c *zKFrameworkImpl) sessionWatchdog(cn *zk.Conn, shutdown chan bool) {
+	ticker := time.NewTicker(c.watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-ticker.C:
+		}
+
+		if !c.Connected() || c.probe(cn, shutdown) {
+			continue
+		}
+
+		select {
+		case <-shutdown:
+			return
+		default:
+		}
+		c.logger.Warn("no response from Zookeeper server, invalidating the connection", "url", c.url, "threshold", c.watchdogThreshold)
+		c.handleStatusChange(zk.StateDisconnected)
+		return
+	}
+}
+
+/*
+probe issues a lightweight request, returning whether any response arrived within the threshold.
+*/
+func (c *zKFrameworkImpl) probe(cn *zk.Conn, shutdown chan bool) bool {
+	done := make(chan bool, 1)
+	go func() {
+		cn.Exists("/")
+		done <- true
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-shutdown:
+		return true
+	case <-time.After(c.watchdogThreshold):
+		return false
+	}
+}
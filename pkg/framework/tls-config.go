@@ -0,0 +1,40 @@
+package framework
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+LoadTLSConfig loads a TLS configuration for WithTLS from PEM files: the certificate authorities trusted to verify the servers
+and, for mutual TLS, the client certificate and its private key; empty file names are skipped.
+*/
+func LoadTLSConfig(caFile string, certFile string, keyFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", frwkerr.ErrInvalidCertificate, caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", frwkerr.ErrInvalidCertificate, certFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,83 @@
+package framework_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+func writeSelfSignedPair(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "zk-client"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestLoadTLSConfig(t *testing.T) {
+
+	t.Run("Load a mutual TLS configuration", func(t *testing.T) {
+		t.Log("Load a mutual TLS configuration")
+		certFile, keyFile := writeSelfSignedPair(t, t.TempDir())
+
+		tlsConfig, err := framework.LoadTLSConfig(certFile, certFile, keyFile)
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 {
+			t.Errorf("expected the CA and the client certificate to be loaded, got %v", tlsConfig)
+		}
+	})
+
+	t.Run("Reject invalid certificates", func(t *testing.T) {
+		t.Log("Reject invalid certificates")
+		dir := t.TempDir()
+		invalid := filepath.Join(dir, "invalid.pem")
+		if err := os.WriteFile(invalid, []byte("not a certificate"), 0600); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if _, err := framework.LoadTLSConfig(invalid, "", ""); !frwkerr.IsInvalidCertificate(err) {
+			t.Errorf("expected %v, got %v", frwkerr.ErrInvalidCertificate, err)
+		}
+		if _, err := framework.LoadTLSConfig("", invalid, invalid); !frwkerr.IsInvalidCertificate(err) {
+			t.Errorf("expected %v, got %v", frwkerr.ErrInvalidCertificate, err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package framework
+
+import (
+	"context"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+viewStatusChangeListener passes to a status change listener added through a view the view itself, instead of the framework it derives from,
+so that the operations run by the listener keep the namespace and the deadline of the view.
+*/
+type viewStatusChangeListener struct {
+	core.StatusChangeListener
+	view core.ZKFramework
+}
+
+func (l viewStatusChangeListener) Priority() int {
+	return priorityOf(l.StatusChangeListener)
+}
+
+func (l viewStatusChangeListener) OnStatusChange(_ core.ZKFramework, previous zk.State, current zk.State) error {
+	return l.StatusChangeListener.OnStatusChange(l.view, previous, current)
+}
+
+func (l viewStatusChangeListener) OnStatusChangeWithContext(ctx context.Context, _ core.ZKFramework, previous zk.State, current zk.State) error {
+	if contextListener, ok := l.StatusChangeListener.(core.ContextStatusChangeListener); ok {
+		return contextListener.OnStatusChangeWithContext(ctx, l.view, previous, current)
+	}
+	return l.StatusChangeListener.OnStatusChange(l.view, previous, current)
+}
+
+func (l viewStatusChangeListener) OnConnectionStateChange(_ core.ZKFramework, state core.ConnectionState) error {
+	if connectionStateListener, ok := l.StatusChangeListener.(core.ConnectionStateListener); ok {
+		return connectionStateListener.OnConnectionStateChange(l.view, state)
+	}
+	return nil
+}
+
+/*
+viewShutdownListener passes to a shutdown listener added through a view the view itself.
+*/
+type viewShutdownListener struct {
+	core.ShutdownListener
+	view core.ZKFramework
+}
+
+func (l viewShutdownListener) Priority() int {
+	return priorityOf(l.ShutdownListener)
+}
+
+func (l viewShutdownListener) OnShutdown(_ core.ZKFramework) error {
+	return l.ShutdownListener.OnShutdown(l.view)
+}
+
+func (l viewShutdownListener) OnShutdownWithContext(ctx context.Context, _ core.ZKFramework) error {
+	if contextListener, ok := l.ShutdownListener.(core.ContextShutdownListener); ok {
+		return contextListener.OnShutdownWithContext(ctx, l.view)
+	}
+	return l.ShutdownListener.OnShutdown(l.view)
+}
+
+/*
+viewSessionListener passes to a session listener added through a view the view itself.
+*/
+type viewSessionListener struct {
+	core.SessionListener
+	view core.ZKFramework
+}
+
+func (l viewSessionListener) Priority() int {
+	return priorityOf(l.SessionListener)
+}
+
+func (l viewSessionListener) OnSessionExpired(_ core.ZKFramework) error {
+	return l.SessionListener.OnSessionExpired(l.view)
+}
+
+func (l viewSessionListener) OnSessionReestablished(_ core.ZKFramework) error {
+	return l.SessionListener.OnSessionReestablished(l.view)
+}
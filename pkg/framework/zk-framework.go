@@ -4,23 +4,34 @@ Package framework provides a higher level Zookeeper client with more capabilitie
 package framework
 
 import (
-	"log"
-	"path"
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/acl"
 	"github.com/morphy76/zk/pkg/core"
-	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/deadletter"
 	"github.com/morphy76/zk/pkg/framework/frwkerr"
+	"github.com/morphy76/zk/pkg/retry"
 )
 
 const (
-	defaultReconnectionTimeoutMs = 100
+	defaultReconnectionTimeout = 100 * time.Millisecond
+	defaultSessionTimeout      = 10 * time.Second
+	defaultOperationTimeout    = 10 * time.Second
 )
 
+type authCredentials struct {
+	scheme string
+	auth   []byte
+}
+
 type zKFrameworkImpl struct {
 	namespace     string
 	url           string
@@ -28,26 +39,124 @@ type zKFrameworkImpl struct {
 	previousState zk.State
 	started       bool
 
-	cn                    *zk.Conn
-	events                <-chan zk.Event
-	reconnectionTimeoutMs uint64
+	adminMode     bool
+	superPassword string
+
+	servers                 []string
+	hostProvider            *updatableHostProvider
+	sessionTimeout          time.Duration
+	operationTimeout        time.Duration
+	dialer                  zk.Dialer
+	tlsConfig               *tls.Config
+	auth                    []authCredentials
+	authLock                sync.Mutex
+	cn                      *zk.Conn
+	events                  <-chan zk.Event
+	retryPolicy             retry.Policy
+	reconnectionAttempt     int
+	reconnectionStart       time.Time
+	reconnectionCycle       int
+	maxReconnectionAttempts int
+	maxReconnectionElapsed  time.Duration
+	watchdogInterval        time.Duration
+	watchdogThreshold       time.Duration
+
+	fourLetterWordsTimeout time.Duration
+	ensembleConfigWatch    bool
+	namespaceCreation      bool
+	serverResolver         ServerResolver
+	serverResolveInterval  time.Duration
+	dnsRefreshInterval     time.Duration
+	dialedAddresses        sync.Map
+
+	negotiatedSessionTimeout atomic.Int64
+
+	logger         *slog.Logger
+	circuitBreaker core.CircuitBreaker
+	aclProvider    core.ACLProvider
+	deadLetters    *deadletter.Buffer
+
+	extensions sync.Map
+	extenders  []func(core.ZKFramework)
 
 	shutdown          chan bool
-	shutdownConsumers atomic.Int32
-	shutdownListeners map[string]core.ShutdownListener
-	shutdownLock      sync.RWMutex
+	shutdownListeners *listenerRegistry[core.ShutdownListener]
 
 	statusChange          chan zk.State
 	statusChangeConsumers atomic.Int32
 	statusChangeLock      sync.RWMutex
-	statusChangeListeners map[string]core.StatusChangeListener
+	statusChangeListeners *listenerRegistry[core.StatusChangeListener]
+
+	sessionID           int64
+	sessionLost         bool
+	sessionListeners    *listenerRegistry[core.SessionListener]
+	sessionNotification sync.Mutex
+
+	connectionState             core.ConnectionState
+	pendingConnectionStates     []core.ConnectionState
+	connectionStateNotification sync.Mutex
+
+	onConnected      []func(core.ZKFramework)
+	onDisconnected   []func(core.ZKFramework)
+	onSessionExpired []func(core.ZKFramework)
+	onFailed         []func(core.ZKFramework, core.ConnectionLost)
+
+	listenerDeadline     time.Duration
+	quarantineAfter      int
+	onDeadlineExceeded   []func(core.ZKFramework, string, bool)
+	statusChangeTimeouts map[string]int
+	shutdownTimeouts     map[string]int
+	timeoutsLock         sync.Mutex
+
+	eventChannels []chan core.FrameworkEvent
+	eventsLock    sync.Mutex
+
+	operations     int
+	operationsLock sync.Mutex
+	draining       bool
+	drained        chan struct{}
 }
 
 func (c *zKFrameworkImpl) Namespace() string {
 	return c.namespace
 }
 
+/*
+UsingNamespace returns a view of the framework scoped to the given child namespace.
+
+The view shares the connection, the lifecycle and the listeners of the framework, only the paths of the operations are prefixed.
+*/
+func (c *zKFrameworkImpl) UsingNamespace(namespace string) core.ZKFramework {
+	return usingNamespace(c, c.namespace, namespace)
+}
+
+/*
+OperationTimeout returns the deadline of the operations run through the framework.
+*/
+func (c *zKFrameworkImpl) OperationTimeout() time.Duration {
+	return c.operationTimeout
+}
+
+/*
+UsingOperationTimeout returns a view of the framework running the operations with the given deadline, e.g. longer for background refreshes than for interactive calls.
+
+The view shares the connection, the lifecycle and the listeners of the framework.
+*/
+func (c *zKFrameworkImpl) UsingOperationTimeout(timeout time.Duration) core.ZKFramework {
+	return &timeoutFramework{
+		ZKFramework: c,
+		timeout:     timeout,
+	}
+}
+
+/*
+Cn returns the current connection to the Zookeeper server.
+
+Deprecated: use Executor, running the requests within a context on the current connection.
+*/
 func (c *zKFrameworkImpl) Cn() *zk.Conn {
+	c.statusChangeLock.RLock()
+	defer c.statusChangeLock.RUnlock()
 	return c.cn
 }
 
@@ -58,10 +167,111 @@ func (c *zKFrameworkImpl) URL() string {
 	return c.url
 }
 
+/*
+UpdateServers replaces the list of Zookeeper servers.
+
+The session is kept: the current connection stays open and the new list is used the next time the client has to connect to a server.
+*/
+func (c *zKFrameworkImpl) UpdateServers(hosts []string) error {
+	if len(hosts) == 0 {
+		return frwkerr.ErrInvalidConnectionURL
+	}
+	for _, host := range hosts {
+		if host == "" {
+			return frwkerr.ErrInvalidConnectionURL
+		}
+	}
+
+	c.logger.Info("updating Zookeeper servers", "servers", hosts)
+
+	if err := c.hostProvider.Init(hosts); err != nil {
+		return err
+	}
+
+	c.statusChangeLock.Lock()
+	defer c.statusChangeLock.Unlock()
+	c.servers = hosts
+	c.url = strings.Join(hosts, ",")
+	return nil
+}
+
+/*
+NegotiatedSessionTimeout returns the session timeout agreed with the Zookeeper server, zero until the first session is established.
+*/
+func (c *zKFrameworkImpl) NegotiatedSessionTimeout() time.Duration {
+	return time.Duration(c.negotiatedSessionTimeout.Load())
+}
+
+/*
+CircuitBreaker returns the circuit breaker guarding the operations, nil when none is configured.
+*/
+func (c *zKFrameworkImpl) CircuitBreaker() core.CircuitBreaker {
+	return c.circuitBreaker
+}
+
+/*
+ACLProvider returns the provider of the ACL of the nodes created without an explicit one, nil when none is configured.
+*/
+func (c *zKFrameworkImpl) ACLProvider() core.ACLProvider {
+	return c.aclProvider
+}
+
+/*
+Logger returns the logger of the framework, set with WithLogger, used by the operations, the watchers and the caches built on the framework.
+*/
+func (c *zKFrameworkImpl) Logger() *slog.Logger {
+	return c.logger
+}
+
+/*
+ConnectedServer returns the address of the ensemble member serving the session, empty when not connected.
+*/
+func (c *zKFrameworkImpl) ConnectedServer() string {
+	cn := c.Cn()
+	if !c.Connected() || cn == nil {
+		return ""
+	}
+	return cn.Server()
+}
+
+/*
+SessionID returns the ID of the current Zookeeper session, zero when no session is established; it is the ephemeral owner of the ephemeral nodes of the session.
+*/
+func (c *zKFrameworkImpl) SessionID() int64 {
+	c.statusChangeLock.RLock()
+	started, cn := c.started, c.cn
+	c.statusChangeLock.RUnlock()
+
+	if !started || cn == nil {
+		return 0
+	}
+	return cn.SessionID()
+}
+
+/*
+ConnectionStats returns the stats of the connection to the Zookeeper ensemble.
+*/
+func (c *zKFrameworkImpl) ConnectionStats() core.ConnectionStats {
+	c.statusChangeLock.RLock()
+	state := c.state
+	servers := slices.Clone(c.servers)
+	c.statusChangeLock.RUnlock()
+
+	return core.ConnectionStats{
+		State:                    state,
+		Server:                   c.ConnectedServer(),
+		Servers:                  servers,
+		HostSelection:            c.hostProvider.Name(),
+		NegotiatedSessionTimeout: c.NegotiatedSessionTimeout(),
+	}
+}
+
 /*
 Started returns whether the Zookeeper client is started.
 */
 func (c *zKFrameworkImpl) Started() bool {
+	c.statusChangeLock.RLock()
+	defer c.statusChangeLock.RUnlock()
 	return c.started
 }
 
@@ -74,15 +284,27 @@ func (c *zKFrameworkImpl) Connected() bool {
 	return isConnectedState(c.state)
 }
 
+/*
+Failed returns whether the framework gave up reconnecting to the Zookeeper server, the operations failing with frwkerr.ErrFrameworkFailed until it is stopped and started again.
+*/
+func (c *zKFrameworkImpl) Failed() bool {
+	c.statusChangeLock.RLock()
+	defer c.statusChangeLock.RUnlock()
+	return c.state == core.StateFailed
+}
+
 /*
 Start connects to the Zookeeper server and starts watching connection events.
 */
 func (c *zKFrameworkImpl) Start() error {
+	c.statusChangeLock.Lock()
+	defer c.statusChangeLock.Unlock()
+
 	if c.started {
 		return frwkerr.ErrFrameworkAlreadyStarted
 	}
 
-	log.Printf("connecting to Zookeeper server at %s", c.url)
+	c.logger.Info("connecting to Zookeeper server", "url", c.url)
 
 	c.started = true
 
@@ -93,20 +315,22 @@ func (c *zKFrameworkImpl) Start() error {
 WaitConnection waits for the connection to the Zookeeper server to be established.
 */
 func (c *zKFrameworkImpl) WaitConnection(timeout time.Duration) error {
-	if !c.started {
+	if !c.Started() {
 		return frwkerr.ErrFrameworkNotYetStarted
 	}
 
 	if c.Connected() {
 		return nil
 	}
+	if c.Failed() {
+		return frwkerr.ErrFrameworkFailed
+	}
 
-	log.Printf("waiting for connection to Zookeeper server at %s", c.url)
+	c.logger.Info("waiting for connection to Zookeeper server", "url", c.url)
 
-	c.shutdownConsumers.Add(1)
-	defer func() {
-		c.shutdownConsumers.Add(-1)
-	}()
+	c.statusChangeLock.RLock()
+	shutdown := c.shutdown
+	c.statusChangeLock.RUnlock()
 
 	c.statusChangeConsumers.Add(1)
 	defer func() {
@@ -117,10 +341,13 @@ func (c *zKFrameworkImpl) WaitConnection(timeout time.Duration) error {
 		select {
 		case <-c.statusChange:
 			if c.Connected() {
-				log.Printf("connected to Zookeeper server at %s", c.url)
+				c.logger.Info("connected to Zookeeper server", "url", c.url)
 				return nil
 			}
-		case <-c.shutdown:
+			if c.Failed() {
+				return frwkerr.ErrFrameworkFailed
+			}
+		case <-shutdown:
 			return nil
 		case <-time.After(timeout):
 			return frwkerr.ErrConnectionTimeout
@@ -128,10 +355,58 @@ func (c *zKFrameworkImpl) WaitConnection(timeout time.Duration) error {
 	}
 }
 
+/*
+StartAndWait starts the framework, waits for the connection and creates the missing nodes of the namespace, all within the timeout.
+
+The returned error is a frwkerr.StartupError telling the failed phase and wrapping its error, e.g. frwkerr.ErrConnectionTimeout.
+*/
+func (c *zKFrameworkImpl) StartAndWait(timeout time.Duration) error {
+	begin := time.Now()
+	failed := func(phase frwkerr.StartupPhase, err error) error {
+		return &frwkerr.StartupError{Phase: phase, Elapsed: time.Since(begin), Err: err}
+	}
+
+	if err := c.Start(); err != nil {
+		return failed(frwkerr.PhaseStart, err)
+	}
+	if err := c.WaitConnection(timeout); err != nil {
+		return failed(frwkerr.PhaseConnection, err)
+	}
+
+	remaining := timeout - time.Since(begin)
+	if remaining <= 0 {
+		return failed(frwkerr.PhaseNamespace, frwkerr.ErrConnectionTimeout)
+	}
+
+	done := make(chan error, 1)
+	cn := c.Cn()
+	go func() {
+		done <- c.createNamespace(cn)
+	}()
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			return failed(frwkerr.PhaseNamespace, err)
+		}
+	case <-timer.C:
+		return failed(frwkerr.PhaseNamespace, frwkerr.ErrConnectionTimeout)
+	}
+	return nil
+}
+
 /*
 Stop closes the connection to the Zookeeper server.
 */
 func (c *zKFrameworkImpl) Stop() error {
+	return c.stop(true)
+}
+
+/*
+stop closes the connection, notifying the shutdown listeners unless they were already.
+*/
+func (c *zKFrameworkImpl) stop(notifyShutdown bool) error {
 	c.statusChangeLock.Lock()
 	defer c.statusChangeLock.Unlock()
 
@@ -140,153 +415,257 @@ func (c *zKFrameworkImpl) Stop() error {
 	}
 	defer c.cn.Close()
 
-	log.Printf("closing connection to Zookeeper server at %s", c.url)
+	c.logger.Info("closing connection to Zookeeper server", "url", c.url)
 
 	c.stopBgTasks()
-	go func() {
-		c.NotifyShutdown()
-		c.clearAllListeners()
-	}()
+	c.closeEvents()
+	if notifyShutdown {
+		go func() {
+			c.NotifyShutdown()
+			c.clearAllListeners()
+		}()
+	}
 
 	c.started = false
+	c.reconnectionCycle++
 	c.state = zk.StateDisconnected
+	c.sessionID = 0
+	c.sessionLost = false
+	c.connectionState = core.ConnectionStateNone
+	c.pendingConnectionStates = nil
+
+	c.authLock.Lock()
+	c.adminMode = false
+	c.superPassword = ""
+	c.authLock.Unlock()
+
+	c.operationsLock.Lock()
+	c.draining = false
+	c.drained = nil
+	c.operationsLock.Unlock()
 
 	return nil
 }
 
 /*
-AddStatusChangeListener adds a listener for Zookeeper connection status changes.
+EnableAdminMode authenticates the session as the Zookeeper super user, bypassing every ACL check.
+
+The server must be configured with the matching superDigest (see acl.SuperDigest). Once enabled the admin mode lasts until the framework is stopped, the credentials are re-applied after each reconnection.
 */
-func (c *zKFrameworkImpl) AddStatusChangeListener(statusChangeListener core.StatusChangeListener) error {
-	c.statusChangeLock.Lock()
-	defer c.statusChangeLock.Unlock()
+func (c *zKFrameworkImpl) EnableAdminMode(superPassword string) error {
+	if !c.Started() {
+		return frwkerr.ErrFrameworkNotYetStarted
+	}
 
-	if found := c.statusChangeListeners[statusChangeListener.UUID()]; found != nil {
-		return coreerr.ErrListenerAlreadyExists
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+	if c.adminMode {
+		return frwkerr.ErrAdminModeAlreadyEnabled
 	}
 
-	c.statusChangeListeners[statusChangeListener.UUID()] = statusChangeListener
+	c.logger.Warn("enabling admin mode on Zookeeper server", "url", c.url)
+
+	if err := c.Cn().AddAuth(acl.SchemeDigest, []byte(acl.SuperUser+":"+superPassword)); err != nil {
+		return err
+	}
+	c.adminMode = true
+	c.superPassword = superPassword
 	return nil
 }
 
 /*
-RemoveStatusChangeListener removes a listener for Zookeeper connection status changes.
-*/
-func (c *zKFrameworkImpl) RemoveStatusChangeListener(statusChangeListener core.StatusChangeListener) error {
-	c.statusChangeLock.Lock()
-	defer c.statusChangeLock.Unlock()
+AddAuth adds credentials to the session, e.g. scheme digest with user:password, so that the operations on the nodes protected by matching ACLs succeed.
 
-	if found := c.statusChangeListeners[statusChangeListener.UUID()]; found == nil {
-		return coreerr.ErrListenerNotFound
+Like the credentials set with WithAuth, they are re-applied after each reconnection for the lifetime of the framework.
+*/
+func (c *zKFrameworkImpl) AddAuth(scheme string, credentials []byte) error {
+	if !c.Started() {
+		return frwkerr.ErrFrameworkNotYetStarted
 	}
 
-	delete(c.statusChangeListeners, statusChangeListener.UUID())
+	if err := c.Cn().AddAuth(scheme, credentials); err != nil {
+		return err
+	}
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+	c.auth = append(c.auth, authCredentials{scheme: scheme, auth: credentials})
 	return nil
 }
 
+/*
+AdminMode returns whether the framework is authenticated as the Zookeeper super user.
+*/
+func (c *zKFrameworkImpl) AdminMode() bool {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+	return c.adminMode
+}
+
+/*
+AddStatusChangeListener adds a listener for Zookeeper connection status changes.
+*/
+func (c *zKFrameworkImpl) AddStatusChangeListener(statusChangeListener core.StatusChangeListener) error {
+	return c.statusChangeListeners.add(statusChangeListener)
+}
+
+/*
+SubscribeStatusChange adds a listener for Zookeeper connection status changes, returning the subscription removing it.
+*/
+func (c *zKFrameworkImpl) SubscribeStatusChange(statusChangeListener core.StatusChangeListener) (core.Subscription, error) {
+	return c.statusChangeListeners.subscribe(statusChangeListener)
+}
+
+/*
+RemoveStatusChangeListener removes a listener for Zookeeper connection status changes.
+*/
+func (c *zKFrameworkImpl) RemoveStatusChangeListener(statusChangeListener core.StatusChangeListener) error {
+	return c.RemoveStatusChangeListenerByID(statusChangeListener.UUID())
+}
+
+/*
+RemoveStatusChangeListenerByID removes the listener for Zookeeper connection status changes with the given UUID.
+*/
+func (c *zKFrameworkImpl) RemoveStatusChangeListenerByID(id string) error {
+	return c.statusChangeListeners.remove(id)
+}
+
 /*
 NotifyStatusChange notifies all listeners of a Zookeeper connection status change.
 */
 func (c *zKFrameworkImpl) NotifyStatusChange() {
-	c.statusChangeLock.RLock()
-	defer c.statusChangeLock.RUnlock()
+	previous, current := c.previousState, c.state
+
+	exceeded := []string{}
+	quarantined := map[string]bool{}
+	for _, listener := range c.statusChangeListeners.snapshot() {
+		id := listener.UUID()
+		err := c.notifyStatusChangeListener(listener, previous, current)
+		if frwkerr.IsListenerDeadlineExceeded(err) {
+			exceeded = append(exceeded, id)
+			quarantined[id] = c.exceededDeadline(c.statusChangeTimeouts, id)
+		}
+		if err != nil {
+			c.logger.Error("error notifying status change listener", "error", err)
+			c.deadLetter(listener, previous, current, err)
+		}
+	}
 
-	for _, listener := range c.statusChangeListeners {
-		if err := listener.OnStatusChange(c, c.previousState, c.state); err != nil {
-			log.Printf("error notifying status change listener: %s", err)
+	for _, id := range exceeded {
+		if quarantined[id] {
+			c.RemoveStatusChangeListenerByID(id)
 		}
+		c.reportDeadlineExceeded(id, quarantined[id])
 	}
+
+	c.runLifecycleCallbacks(previous, current)
 }
 
 /*
-AddShutdownListener adds a listener for Zookeeper client shutdown events.
+StatusChange is the payload of the dead letters of the status change listeners.
 */
-func (c *zKFrameworkImpl) AddShutdownListener(shutdownListener core.ShutdownListener) error {
-	c.shutdownLock.Lock()
-	defer c.shutdownLock.Unlock()
+type StatusChange struct {
+	// Previous is the previous connection state.
+	Previous zk.State
+	// Current is the connection state notified to the listener.
+	Current zk.State
+}
 
-	if found := c.shutdownListeners[shutdownListener.UUID()]; found != nil {
-		return coreerr.ErrListenerAlreadyExists
+func (c *zKFrameworkImpl) deadLetter(listener core.StatusChangeListener, previous zk.State, current zk.State, err error) {
+	if c.deadLetters == nil {
+		return
 	}
+	c.deadLetters.Add(listener.UUID(), StatusChange{Previous: previous, Current: current}, err, 1, func() error {
+		return listener.OnStatusChange(c, previous, current)
+	})
+}
 
-	c.shutdownListeners[shutdownListener.UUID()] = shutdownListener
-	return nil
+/*
+AddShutdownListener adds a listener for Zookeeper client shutdown events.
+*/
+func (c *zKFrameworkImpl) AddShutdownListener(shutdownListener core.ShutdownListener) error {
+	return c.shutdownListeners.add(shutdownListener)
+}
+
+/*
+SubscribeShutdown adds a listener for Zookeeper client shutdown events, returning the subscription removing it.
+*/
+func (c *zKFrameworkImpl) SubscribeShutdown(shutdownListener core.ShutdownListener) (core.Subscription, error) {
+	return c.shutdownListeners.subscribe(shutdownListener)
 }
 
 /*
 RemoveShutdownListener removes a listener for Zookeeper client shutdown events.
 */
 func (c *zKFrameworkImpl) RemoveShutdownListener(shutdownListener core.ShutdownListener) error {
-	c.shutdownLock.Lock()
-	defer c.shutdownLock.Unlock()
-
-	if found := c.shutdownListeners[shutdownListener.UUID()]; found == nil {
-		return coreerr.ErrListenerNotFound
-	}
+	return c.RemoveShutdownListenerByID(shutdownListener.UUID())
+}
 
-	delete(c.shutdownListeners, shutdownListener.UUID())
-	return nil
+/*
+RemoveShutdownListenerByID removes the listener for Zookeeper client shutdown events with the given UUID.
+*/
+func (c *zKFrameworkImpl) RemoveShutdownListenerByID(id string) error {
+	return c.shutdownListeners.remove(id)
 }
 
 /*
 NotifyShutdown notifies all listeners of a Zookeeper client shutdown event.
 */
 func (c *zKFrameworkImpl) NotifyShutdown() {
-	c.shutdownLock.RLock()
-	defer c.shutdownLock.RUnlock()
+	exceeded := []string{}
+	quarantined := map[string]bool{}
+	for _, listener := range c.shutdownListeners.snapshot() {
+		id := listener.UUID()
+		err := c.notifyShutdownListener(listener)
+		if frwkerr.IsListenerDeadlineExceeded(err) {
+			exceeded = append(exceeded, id)
+			quarantined[id] = c.exceededDeadline(c.shutdownTimeouts, id)
+		}
+		if err != nil {
+			c.logger.Error("error notifying shutdown listener", "error", err)
+		}
+	}
 
-	for _, listener := range c.shutdownListeners {
-		if err := listener.OnShutdown(c); err != nil {
-			log.Printf("error notifying shutdown listener: %s", err)
+	for _, id := range exceeded {
+		if quarantined[id] {
+			c.RemoveShutdownListenerByID(id)
 		}
+		c.reportDeadlineExceeded(id, quarantined[id])
 	}
 }
 
 func (c *zKFrameworkImpl) clearAllListeners() {
-	c.statusChangeLock.Lock()
-	defer c.statusChangeLock.Unlock()
-
-	for _, listener := range c.statusChangeListeners {
+	for _, listener := range c.statusChangeListeners.clear() {
 		listener.Stop()
 	}
-	c.statusChangeListeners = make(map[string]core.StatusChangeListener)
-
-	c.shutdownLock.Lock()
-	defer c.shutdownLock.Unlock()
-
-	for _, listener := range c.shutdownListeners {
+	for _, listener := range c.shutdownListeners.clear() {
+		listener.Stop()
+	}
+	for _, listener := range c.sessionListeners.clear() {
 		listener.Stop()
 	}
-	c.shutdownListeners = make(map[string]core.ShutdownListener)
 }
 
-func (c *zKFrameworkImpl) watchEvents() {
-	log.Printf("watching events from Zookeeper server at %s", c.url)
-
-	c.shutdownConsumers.Add(1)
-	defer func() {
-		c.shutdownConsumers.Add(-1)
-	}()
+func (c *zKFrameworkImpl) watchEvents(events <-chan zk.Event, shutdown chan bool) {
+	c.logger.Debug("watching events from Zookeeper server", "url", c.url)
 
 	for {
 		select {
-		case <-c.shutdown:
+		case <-shutdown:
 			return
-		case event := <-c.events:
+		case event := <-events:
 			for i := 0; i < int(c.statusChangeConsumers.Load()); i++ {
-				c.statusChange <- event.State
+				select {
+				case c.statusChange <- event.State:
+				case <-shutdown:
+					return
+				}
 			}
 		}
 	}
 }
 
-func (c *zKFrameworkImpl) connectionWatcher() {
-	log.Printf("watching connection to Zookeeper server at %s", c.url)
-
-	c.shutdownConsumers.Add(1)
-	defer func() {
-		c.shutdownConsumers.Add(-1)
-	}()
+func (c *zKFrameworkImpl) connectionWatcher(shutdown chan bool) {
+	c.logger.Debug("watching connection to Zookeeper server", "url", c.url)
 
 	c.statusChangeConsumers.Add(1)
 	defer func() {
@@ -295,7 +674,7 @@ func (c *zKFrameworkImpl) connectionWatcher() {
 
 	for {
 		select {
-		case <-c.shutdown:
+		case <-shutdown:
 			return
 		case state := <-c.statusChange:
 			c.handleStatusChange(state)
@@ -313,50 +692,201 @@ func (c *zKFrameworkImpl) handleStatusChange(state zk.State) {
 
 	c.previousState = c.state
 	c.state = state
-	go c.NotifyStatusChange()
-	log.Printf("status change from %s to %s", c.previousState, c.state)
+	previousConnectionState := c.connectionState
+	c.trackConnectionState(state)
+	go c.notifyStatusChange()
+	c.logger.Info("status change", "previous", c.previousState, "current", c.state)
+	c.publish(core.StateChanged{Previous: c.previousState, Current: c.state, ConnectionState: c.connectionState})
+	c.trackSession(state)
+	if c.connectionState == core.ConnectionStateReconnected && previousConnectionState != c.connectionState {
+		c.publish(core.Reconnected{SessionID: c.sessionID})
+	}
 
 	if !c.previouslyConnected() && isConnectedState(c.state) {
-		c.reconnectionTimeoutMs = defaultReconnectionTimeoutMs
+		c.reconnectionAttempt = 0
 	}
 	if c.started && c.previouslyConnected() && !isConnectedState(c.state) {
-		log.Printf("connection to Zookeeper server at %s lost, trying to reconnect", c.url)
+		c.logger.Warn("connection to Zookeeper server lost, trying to reconnect", "url", c.url)
 		c.invalidateCn()
 	}
 }
 
 func (c *zKFrameworkImpl) tryConnect() error {
-	cn, events, err := zk.Connect([]string{c.url}, 10*time.Second)
+	cn, events, err := zk.Connect(c.servers, c.sessionTimeout,
+		zk.WithHostProvider(c.hostProvider),
+		zk.WithDialer(c.dial),
+		zk.WithLogger(zkLogger{logger: c.logger, onSessionTimeout: c.negotiatedSessionTimeout.Store}),
+	)
 	if err != nil {
 		return err
 	}
 	c.cn = cn
 	c.events = events
-	go c.watchEvents()
-	go c.connectionWatcher()
+	go c.applyAuth(cn)
+	go c.watchEvents(events, c.shutdown)
+	go c.connectionWatcher(c.shutdown)
+	if c.watchdogInterval > 0 {
+		go c.sessionWatchdog(cn, c.shutdown)
+	}
+	if c.ensembleConfigWatch {
+		go c.watchEnsembleConfig(cn, c.shutdown)
+	}
+	if c.serverResolver != nil && c.serverResolveInterval > 0 {
+		go c.resolveServersPeriodically(c.shutdown)
+	}
+	if c.dnsRefreshInterval > 0 {
+		go c.refreshDNSPeriodically(cn, c.shutdown)
+	}
 
 	return nil
 }
 
+/*
+dial opens a connection to a Zookeeper server with the configured dialer, running the TLS handshake when TLS is enabled.
+*/
+func (c *zKFrameworkImpl) dial(network string, address string, timeout time.Duration) (net.Conn, error) {
+	start := time.Now()
+	cn, err := c.dialer(network, address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	c.recordDialedAddress(address, cn)
+	if c.tlsConfig == nil {
+		return cn, nil
+	}
+
+	tlsConfig := c.tlsConfig
+	if tlsConfig.ServerName == "" && !tlsConfig.InsecureSkipVerify {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName, _, _ = net.SplitHostPort(address)
+	}
+	tlsCn := tls.Client(cn, tlsConfig)
+	if err := tlsCn.SetDeadline(start.Add(timeout)); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	if err := tlsCn.Handshake(); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	if err := tlsCn.SetDeadline(time.Time{}); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	return tlsCn, nil
+}
+
+func (c *zKFrameworkImpl) applyAuth(cn *zk.Conn) {
+	c.authLock.Lock()
+	credentials := append([]authCredentials{}, c.auth...)
+	if c.adminMode {
+		credentials = append(credentials, authCredentials{scheme: acl.SchemeDigest, auth: []byte(acl.SuperUser + ":" + c.superPassword)})
+	}
+	c.authLock.Unlock()
+
+	for _, credential := range credentials {
+		if err := cn.AddAuth(credential.scheme, credential.auth); err != nil {
+			c.logger.Error("error applying credentials", "scheme", credential.scheme, "error", err)
+		}
+	}
+}
+
 func (c *zKFrameworkImpl) invalidateCn() {
 	c.stopBgTasks()
-	<-time.After(time.Duration(c.reconnectionTimeoutMs) * time.Millisecond)
-	c.reconnectionTimeoutMs *= 2
 
+	if c.reconnectionAttempt == 0 {
+		c.reconnectionStart = time.Now()
+	}
+	c.reconnectionAttempt++
+	elapsed := time.Since(c.reconnectionStart)
+	delay, ok := c.retryPolicy.NextDelay(c.reconnectionAttempt, elapsed)
+	if c.maxReconnectionAttempts > 0 && c.reconnectionAttempt > c.maxReconnectionAttempts {
+		ok = false
+	}
+	if c.maxReconnectionElapsed > 0 && elapsed+delay > c.maxReconnectionElapsed {
+		ok = false
+	}
+	if !ok {
+		c.logger.Error("giving up reconnecting to Zookeeper server", "url", c.url, "attempts", c.reconnectionAttempt)
+		c.fail()
+		return
+	}
+
+	c.reconnectionCycle++
+	go c.reconnectAfter(delay, c.reconnectionCycle)
+}
+
+/*
+reconnectAfter opens a new connection once the retry delay elapsed, unless the framework was stopped or another reconnection cycle started meanwhile.
+*/
+func (c *zKFrameworkImpl) reconnectAfter(delay time.Duration, cycle int) {
+	<-time.After(delay)
+
+	c.statusChangeLock.Lock()
+	defer c.statusChangeLock.Unlock()
+
+	if !c.started || cycle != c.reconnectionCycle || c.state == core.StateFailed {
+		return
+	}
 	if c.cn != nil {
 		c.cn.Close()
 	}
 	c.tryConnect()
+
+	go c.awaitReconnection(cycle)
+}
+
+/*
+awaitReconnection fails the reconnection attempt when no session is established within the session timeout, the client would otherwise keep dialing forever on its own.
+*/
+func (c *zKFrameworkImpl) awaitReconnection(cycle int) {
+	<-time.After(c.sessionTimeout)
+
+	c.statusChangeLock.Lock()
+	defer c.statusChangeLock.Unlock()
+
+	if !c.started || cycle != c.reconnectionCycle || c.reconnectionAttempt == 0 || c.state == core.StateFailed {
+		return
+	}
+	c.logger.Warn("reconnection attempt failed", "url", c.url, "attempts", c.reconnectionAttempt)
+	c.invalidateCn()
+}
+
+/*
+fail transitions to the terminal failed state, the status change lock must be held.
+*/
+func (c *zKFrameworkImpl) fail() {
+	if c.cn != nil {
+		c.cn.Close()
+	}
+	c.previousState = c.state
+	c.state = core.StateFailed
+	c.trackConnectionState(core.StateFailed)
+	go c.notifyStatusChange()
+	c.publish(core.StateChanged{Previous: c.previousState, Current: c.state, ConnectionState: c.connectionState})
+
+	lost := core.ConnectionLost{
+		Attempts: c.reconnectionAttempt - 1,
+		Elapsed:  time.Since(c.reconnectionStart),
+		Servers:  slices.Clone(c.servers),
+	}
+	go func() {
+		for _, callback := range c.onFailed {
+			callback(c, lost)
+		}
+	}()
 }
 
 func (c *zKFrameworkImpl) previouslyConnected() bool {
 	return isConnectedState(c.previousState)
 }
 
+/*
+stopBgTasks stops the background tasks of the current connection, the status change lock must be held.
+*/
 func (c *zKFrameworkImpl) stopBgTasks() {
-	for i := 0; i < int(c.shutdownConsumers.Load()); i++ {
-		c.shutdown <- true
-	}
+	close(c.shutdown)
+	c.shutdown = make(chan bool)
 }
 
 func isConnectedState(state zk.State) bool {
@@ -368,31 +898,67 @@ func isConnectedState(state zk.State) bool {
 }
 
 /*
-CreateFramework creates a new Zookeeper client with the given connection URL and namespace.
+CreateFramework creates a new Zookeeper client with the given connection URL and namespace, the URL being a comma-separated list of servers for an ensemble.
 */
 func CreateFramework(url string, namespace ...string) (core.ZKFramework, error) {
-	if url == "" {
+	return CreateFrameworkWithOptions(url, WithNamespace(namespace...))
+}
+
+/*
+CreateFrameworkForEnsemble creates a new Zookeeper client connecting to one of the given servers, failing over to the other ones, configured by the given options.
+*/
+func CreateFrameworkForEnsemble(servers []string, options ...Option) (core.ZKFramework, error) {
+	if len(servers) == 0 {
 		return nil, frwkerr.ErrInvalidConnectionURL
 	}
+	return CreateFrameworkWithOptions(strings.Join(servers, ","), options...)
+}
 
-	useNamespace := "/" + strings.TrimPrefix(path.Join(namespace...), "/")
+/*
+CreateFrameworkWithOptions creates a new Zookeeper client with the given connection URL, a comma-separated list of servers for an ensemble, configured by the given options.
+*/
+func CreateFrameworkWithOptions(url string, options ...Option) (core.ZKFramework, error) {
+	servers := strings.Split(url, ",")
+	for i, server := range servers {
+		servers[i] = strings.TrimSpace(server)
+		if servers[i] == "" {
+			return nil, frwkerr.ErrInvalidConnectionURL
+		}
+	}
 
-	return &zKFrameworkImpl{
-		// TODO more connection options
-		namespace: useNamespace,
-		url:       url,
+	zkFramework := &zKFrameworkImpl{
+		namespace: "/",
+		url:       strings.Join(servers, ","),
+		servers:   servers,
 		state:     zk.StateDisconnected,
 		started:   false,
 
-		shutdownConsumers:     atomic.Int32{},
+		hostProvider:     newUpdatableHostProvider(),
+		sessionTimeout:   defaultSessionTimeout,
+		operationTimeout: defaultOperationTimeout,
+		dialer:           net.DialTimeout,
+		retryPolicy:      retry.NewExponentialBackoff(defaultReconnectionTimeout, 0),
+		logger:           slog.Default(),
+
 		statusChangeConsumers: atomic.Int32{},
-		reconnectionTimeoutMs: defaultReconnectionTimeoutMs,
 
 		shutdown:              make(chan bool),
-		shutdownListeners:     make(map[string]core.ShutdownListener),
-		shutdownLock:          sync.RWMutex{},
+		shutdownListeners:     newListenerRegistry[core.ShutdownListener](),
 		statusChange:          make(chan zk.State),
-		statusChangeListeners: make(map[string]core.StatusChangeListener),
+		statusChangeListeners: newListenerRegistry[core.StatusChangeListener](),
 		statusChangeLock:      sync.RWMutex{},
-	}, nil
+		sessionListeners:      newListenerRegistry[core.SessionListener](),
+
+		statusChangeTimeouts: make(map[string]int),
+		shutdownTimeouts:     make(map[string]int),
+	}
+	for _, option := range options {
+		option(zkFramework)
+	}
+	zkFramework.extensions.Store(operationsKey{}, zkFramework)
+	zkFramework.hostProvider.useDialer(zkFramework.dial)
+	for _, extend := range zkFramework.extenders {
+		extend(zkFramework)
+	}
+	return zkFramework, nil
 }
@@ -1,17 +1,25 @@
 package framework_test
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/go-zookeeper/zk"
 	"github.com/google/uuid"
 	testutil "github.com/morphy76/zk/internal/test_util"
 	"github.com/morphy76/zk/internal/test_util/mocks"
+	"github.com/morphy76/zk/pkg/core"
 	"github.com/morphy76/zk/pkg/core/coreerr"
 	"github.com/morphy76/zk/pkg/framework"
 	"github.com/morphy76/zk/pkg/framework/frwkerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/retry"
 )
 
 const (
@@ -20,6 +28,49 @@ const (
 	expectedClientToBeConnected = "expected client to be connected"
 )
 
+type subscribingListener struct {
+	mocks.MockedStatusChangeListener
+	subscribe core.StatusChangeListener
+	err       error
+}
+
+func (l *subscribingListener) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	_, l.err = zkFramework.SubscribeStatusChange(l.subscribe)
+	return nil
+}
+
+type recordingListener struct {
+	mocks.MockedStatusChangeListener
+	notified core.ZKFramework
+}
+
+func (l *recordingListener) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	l.notified = zkFramework
+	return l.MockedStatusChangeListener.OnStatusChange(zkFramework, previous, current)
+}
+
+type connectionStateListener struct {
+	mocks.MockedStatusChangeListener
+	states chan core.ConnectionState
+}
+
+func (l *connectionStateListener) OnConnectionStateChange(zkFramework core.ZKFramework, state core.ConnectionState) error {
+	l.states <- state
+	return nil
+}
+
+type teardownListener struct {
+	mocks.MockedShutdownListener
+	beginErr   error
+	requestErr error
+}
+
+func (l *teardownListener) OnShutdownWithContext(ctx context.Context, zkFramework core.ZKFramework) error {
+	_, l.beginErr = framework.BeginOperation(zkFramework)
+	_, _, l.requestErr = zkFramework.Executor().Exists(ctx, "/")
+	return l.MockedShutdownListener.OnShutdown(zkFramework)
+}
+
 func TestMain(m *testing.M) {
 	zkC, ctx, err := testutil.StartTestServer()
 	if err != nil {
@@ -482,4 +533,847 @@ func TestZKFramework(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Enable admin mode on a non-started framework", func(t *testing.T) {
+		t.Log("Enable admin mode on a non-started framework")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := zkFramework.EnableAdminMode(uuid.New().String()); !frwkerr.IsFrameworkNotYetStarted(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkNotYetStarted, err)
+		}
+		if zkFramework.AdminMode() {
+			t.Error("expected admin mode to be disabled")
+		}
+	})
+
+	t.Run("Update the servers of a connected framework", func(t *testing.T) {
+		t.Log("Update the servers of a connected framework")
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		url := os.Getenv(zkHostEnv)
+		if err := zkFramework.UpdateServers([]string{url, url}); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.URL() != url+","+url {
+			t.Errorf("expected URL %s,%s, got %s", url, url, zkFramework.URL())
+		}
+		if !zkFramework.Connected() {
+			t.Error(expectedClientToBeConnected)
+		}
+	})
+
+	t.Run("Update the servers with an empty list", func(t *testing.T) {
+		t.Log("Update the servers with an empty list")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := zkFramework.UpdateServers([]string{}); !frwkerr.IsInvalidConnectionURL(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrInvalidConnectionURL, err)
+		}
+	})
+
+	t.Run("Scope the framework to a child namespace", func(t *testing.T) {
+		t.Log("Scope the framework to a child namespace")
+		ns := uuid.New().String()
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url, ns)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		scoped := zkFramework.UsingNamespace("/sub/path")
+		if scoped.Namespace() != "/"+ns+"/sub/path" {
+			t.Errorf("expected /%s/sub/path namespace, got %s", ns, scoped.Namespace())
+		}
+		nested := scoped.UsingNamespace("child")
+		if nested.Namespace() != "/"+ns+"/sub/path/child" {
+			t.Errorf("expected /%s/sub/path/child namespace, got %s", ns, nested.Namespace())
+		}
+		if zkFramework.Namespace() != "/"+ns {
+			t.Errorf("expected /%s namespace, got %s", ns, zkFramework.Namespace())
+		}
+		if scoped.URL() != url {
+			t.Errorf("expected URL %s, got %s", url, scoped.URL())
+		}
+	})
+
+	t.Run("Negotiated session timeout and connected server", func(t *testing.T) {
+		t.Log("Negotiated session timeout and connected server")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if zkFramework.ConnectedServer() != "" {
+			t.Errorf("expected no connected server, got %s", zkFramework.ConnectedServer())
+		}
+		if zkFramework.NegotiatedSessionTimeout() != 0 {
+			t.Errorf("expected no negotiated session timeout, got %s", zkFramework.NegotiatedSessionTimeout())
+		}
+		if zkFramework.SessionID() != 0 {
+			t.Errorf("expected no session ID, got %d", zkFramework.SessionID())
+		}
+
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if zkFramework.ConnectedServer() != url {
+			t.Errorf("expected connected server %s, got %s", url, zkFramework.ConnectedServer())
+		}
+		if zkFramework.NegotiatedSessionTimeout() <= 0 {
+			t.Errorf("expected a negotiated session timeout, got %s", zkFramework.NegotiatedSessionTimeout())
+		}
+		if zkFramework.SessionID() == 0 {
+			t.Errorf("expected a session ID")
+		}
+	})
+
+	t.Run("Remove listeners by ID", func(t *testing.T) {
+		t.Log("Remove listeners by ID")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		statusChangeListener := &mocks.MockedStatusChangeListener{
+			ID: uuid.New().String(),
+		}
+		if err := zkFramework.AddStatusChangeListener(statusChangeListener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		shutdownListener := &mocks.MockedShutdownListener{
+			ID: uuid.New().String(),
+		}
+		if err := zkFramework.AddShutdownListener(shutdownListener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := zkFramework.RemoveStatusChangeListenerByID(statusChangeListener.ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.RemoveShutdownListenerByID(shutdownListener.ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		zkFramework.NotifyStatusChange()
+		zkFramework.NotifyShutdown()
+		if statusChangeListener.Interactions != 0 || shutdownListener.Interactions != 0 {
+			t.Errorf("expected the removed listeners not to be notified")
+		}
+
+		if err := zkFramework.RemoveStatusChangeListenerByID(statusChangeListener.ID); !coreerr.IsListenerNotFound(err) {
+			t.Errorf("expected error %v, got %v", coreerr.ErrListenerNotFound, err)
+		}
+		if err := zkFramework.RemoveShutdownListenerByID(shutdownListener.ID); !coreerr.IsListenerNotFound(err) {
+			t.Errorf("expected error %v, got %v", coreerr.ErrListenerNotFound, err)
+		}
+	})
+
+	t.Run("Notify listeners by priority", func(t *testing.T) {
+		t.Log("Notify listeners by priority")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		notified := []string{}
+		listeners := []*mocks.MockedPrioritizedListener{
+			{ID: "default-1", Rank: core.PriorityDefault, Notified: &notified},
+			{ID: "internal", Rank: core.PriorityInternal, Notified: &notified},
+			{ID: "default-2", Rank: core.PriorityDefault, Notified: &notified},
+			{ID: "early", Rank: 50, Notified: &notified},
+		}
+		for _, listener := range listeners {
+			if err := zkFramework.AddStatusChangeListener(listener); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+			if err := zkFramework.AddShutdownListener(listener); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+
+		expected := []string{"internal", "early", "default-1", "default-2"}
+		zkFramework.NotifyStatusChange()
+		if !slices.Equal(notified, expected) {
+			t.Errorf("expected status change notifications %v, got %v", expected, notified)
+		}
+
+		notified = notified[:0]
+		zkFramework.NotifyShutdown()
+		if !slices.Equal(notified, expected) {
+			t.Errorf("expected shutdown notifications %v, got %v", expected, notified)
+		}
+	})
+
+	t.Run("Subscribe and cancel listeners", func(t *testing.T) {
+		t.Log("Subscribe and cancel listeners")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		statusChangeListener := &mocks.MockedStatusChangeListener{ID: uuid.New().String()}
+		statusChange, err := zkFramework.SubscribeStatusChange(statusChangeListener)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := zkFramework.SubscribeStatusChange(statusChangeListener); !coreerr.IsListenerAlreadyExists(err) {
+			t.Errorf("expected error %v, got %v", coreerr.ErrListenerAlreadyExists, err)
+		}
+		shutdownListener := &mocks.MockedShutdownListener{ID: uuid.New().String()}
+		shutdown, err := zkFramework.SubscribeShutdown(shutdownListener)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		session, err := zkFramework.SubscribeSession(&mocks.MockedSessionListener{ID: uuid.New().String()})
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		for _, subscription := range []core.Subscription{statusChange, shutdown, session} {
+			if err := subscription.Cancel(); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+			if err := subscription.Cancel(); err != nil {
+				t.Errorf("expected a second cancellation to be a no-op, got %v", err)
+			}
+		}
+
+		zkFramework.NotifyStatusChange()
+		zkFramework.NotifyShutdown()
+		if statusChangeListener.Interactions != 0 || shutdownListener.Interactions != 0 {
+			t.Errorf("expected the cancelled listeners not to be notified")
+		}
+	})
+
+	t.Run("Subscribe a listener while notifying", func(t *testing.T) {
+		t.Log("Subscribe a listener while notifying")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		subscribed := &mocks.MockedStatusChangeListener{ID: uuid.New().String()}
+		listener := &subscribingListener{
+			MockedStatusChangeListener: mocks.MockedStatusChangeListener{ID: uuid.New().String()},
+			subscribe:                  subscribed,
+		}
+		if err := zkFramework.AddStatusChangeListener(listener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		done := make(chan bool)
+		go func() {
+			zkFramework.NotifyStatusChange()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the notification not to deadlock")
+		}
+		if listener.err != nil {
+			t.Errorf(unexpectedErrorFmt, listener.err)
+		}
+
+		zkFramework.NotifyStatusChange()
+		if subscribed.Interactions != 1 {
+			t.Errorf("expected the subscribed listener to be notified once, got %d", subscribed.Interactions)
+		}
+	})
+
+	t.Run("Notify the listeners added through a view with the view", func(t *testing.T) {
+		t.Log("Notify the listeners added through a view with the view")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		scoped := zkFramework.UsingNamespace("sub/path").UsingOperationTimeout(time.Second)
+		listener := &recordingListener{
+			MockedStatusChangeListener: mocks.MockedStatusChangeListener{ID: uuid.New().String()},
+		}
+		if err := scoped.AddStatusChangeListener(listener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		zkFramework.NotifyStatusChange()
+		if listener.notified == nil {
+			t.Fatal("expected the listener to be notified")
+		}
+		if listener.notified.Namespace() != scoped.Namespace() {
+			t.Errorf("expected %s namespace, got %s", scoped.Namespace(), listener.notified.Namespace())
+		}
+		if listener.notified.OperationTimeout() != time.Second {
+			t.Errorf("expected 1s operation timeout, got %s", listener.notified.OperationTimeout())
+		}
+
+		if err := scoped.RemoveStatusChangeListener(listener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		zkFramework.NotifyStatusChange()
+		if listener.Interactions != 1 {
+			t.Errorf("expected the removed listener not to be notified, got %d interactions", listener.Interactions)
+		}
+	})
+
+	t.Run("Start and wait for the connection and the namespace", func(t *testing.T) {
+		t.Log("Start and wait for the connection and the namespace")
+		ns := uuid.New().String()
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url, ns, "nested")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := zkFramework.StartAndWait(10 * time.Second); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		if !zkFramework.Connected() {
+			t.Errorf(expectedClientToBeConnected)
+		}
+		if _, err := operation.Ls(zkFramework); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		err = zkFramework.StartAndWait(time.Second)
+		var startupErr *frwkerr.StartupError
+		if !errors.As(err, &startupErr) || startupErr.Phase != frwkerr.PhaseStart || !errors.Is(err, frwkerr.ErrFrameworkAlreadyStarted) {
+			t.Errorf("expected the start phase to fail, got %v", err)
+		}
+	})
+
+	t.Run("Start and wait for an unreachable server", func(t *testing.T) {
+		t.Log("Start and wait for an unreachable server")
+		zkFramework, err := framework.CreateFramework("127.0.0.1:1")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		err = zkFramework.StartAndWait(500 * time.Millisecond)
+		var startupErr *frwkerr.StartupError
+		if !errors.As(err, &startupErr) || startupErr.Phase != frwkerr.PhaseConnection || !errors.Is(err, frwkerr.ErrConnectionTimeout) {
+			t.Errorf("expected the connection phase to time out, got %v", err)
+		}
+	})
+
+	t.Run("Stop gracefully once the operations completed", func(t *testing.T) {
+		t.Log("Stop gracefully once the operations completed")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		shutdownListener := &mocks.MockedShutdownListener{ID: uuid.New().String()}
+		if err := zkFramework.AddShutdownListener(shutdownListener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		end, err := framework.BeginOperation(zkFramework)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		stopped := make(chan error, 1)
+		go func() {
+			stopped <- zkFramework.StopGracefully(context.Background())
+		}()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			started, err := framework.BeginOperation(zkFramework)
+			if frwkerr.IsFrameworkStopping(err) {
+				break
+			}
+			if err == nil {
+				started()
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the new operations to be rejected, got %v", err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		select {
+		case err := <-stopped:
+			t.Fatalf("expected the stop to wait for the operation, got %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		end()
+		select {
+		case err := <-stopped:
+			if err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the framework to stop once the operation completed")
+		}
+		if zkFramework.Started() {
+			t.Errorf("expected the framework to be stopped")
+		}
+		if shutdownListener.Interactions != 1 {
+			t.Errorf("expected the shutdown listener to be notified once, got %d", shutdownListener.Interactions)
+		}
+	})
+
+	t.Run("Stop gracefully past the deadline", func(t *testing.T) {
+		t.Log("Stop gracefully past the deadline")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		end, err := framework.BeginOperation(zkFramework.UsingNamespace("view"))
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer end()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if err := zkFramework.StopGracefully(ctx); !frwkerr.IsStopDeadlineExceeded(err) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the stop deadline to be exceeded, got %v", err)
+		}
+		if zkFramework.Started() {
+			t.Errorf("expected the framework to be stopped anyway")
+		}
+	})
+
+	t.Run("Stop gracefully rejecting the operations while tearing down", func(t *testing.T) {
+		t.Log("Stop gracefully rejecting the operations while tearing down")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		shutdownListener := &teardownListener{MockedShutdownListener: mocks.MockedShutdownListener{ID: uuid.New().String()}}
+		if err := zkFramework.AddShutdownListener(shutdownListener); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+
+		if err := zkFramework.StopGracefully(context.Background()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if !frwkerr.IsFrameworkStopping(shutdownListener.beginErr) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkStopping, shutdownListener.beginErr)
+		}
+		if shutdownListener.requestErr != nil {
+			t.Errorf("expected the listener requests to be run, got %v", shutdownListener.requestErr)
+		}
+	})
+
+	t.Run("Receive the events of the framework", func(t *testing.T) {
+		t.Log("Receive the events of the framework")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		events := zkFramework.Events()
+
+		if err := zkFramework.StartAndWait(10 * time.Second); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		connected := false
+		for !connected {
+			select {
+			case event := <-events:
+				if changed, ok := event.(core.StateChanged); ok && changed.Current == zk.StateHasSession {
+					connected = changed.ConnectionState == core.ConnectionStateConnected
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected a state changed event once connected")
+			}
+		}
+
+		if err := zkFramework.Stop(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		var last core.FrameworkEvent
+		for event := range events {
+			last = event
+		}
+		if _, ok := last.(core.ShuttingDown); !ok {
+			t.Errorf("expected the last event to be shutting down, got %v", last)
+		}
+	})
+
+	t.Run("Run requests through the executor", func(t *testing.T) {
+		t.Log("Run requests through the executor")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.StartAndWait(10 * time.Second); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		executor := zkFramework.Executor()
+		ctx := context.Background()
+		nodePath := "/executor-" + uuid.New().String()
+		if _, err := executor.Create(ctx, nodePath, []byte("data"), 0, zk.WorldACL(zk.PermAll)); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		data, _, err := executor.Get(ctx, nodePath)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if string(data) != "data" {
+			t.Errorf("expected data, got %s", data)
+		}
+		if err := executor.Delete(ctx, nodePath, -1); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		exists, _, err := executor.Exists(ctx, nodePath)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if exists {
+			t.Error("expected the node to be deleted")
+		}
+	})
+
+	t.Run("Return the error of a cancelled context from the executor", func(t *testing.T) {
+		t.Log("Return the error of a cancelled context from the executor")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := zkFramework.Executor().Exists(ctx, "/"); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("Reject the executor requests before the framework is started", func(t *testing.T) {
+		t.Log("Reject the executor requests before the framework is started")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFramework(url)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if _, _, err := zkFramework.Executor().Get(context.Background(), "/"); !frwkerr.IsFrameworkNotYetStarted(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkNotYetStarted, err)
+		}
+	})
+
+	t.Run("Health check", func(t *testing.T) {
+		t.Log("Health check")
+		url := os.Getenv(zkHostEnv)
+		zkFramework, err := framework.CreateFrameworkWithOptions(url, framework.WithHealthFourLetterWords(time.Second))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := zkFramework.HealthCheck(context.Background()); !frwkerr.IsFrameworkNotYetStarted(err) {
+			t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkNotYetStarted, err)
+		}
+
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+		if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		health, err := zkFramework.HealthCheck(context.Background())
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if !health.Healthy || health.Server != url || health.Latency <= 0 {
+			t.Errorf("expected a healthy report, got %+v", health)
+		}
+		if len(health.Servers) != 1 || !health.Servers[0].OK || health.Servers[0].Stats == nil {
+			t.Errorf("expected the four letter words to be answered, got %+v", health.Servers)
+		}
+	})
+
+	t.Run("Bound the health check by the context", func(t *testing.T) {
+		t.Log("Bound the health check by the context")
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer listener.Close()
+
+		zkFramework, err := framework.CreateFrameworkWithOptions(listener.Addr().String(), framework.WithHealthFourLetterWords(10*time.Second))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := zkFramework.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := zkFramework.HealthCheck(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected the health check to return with the context, took %v", elapsed)
+		}
+	})
+}
+
+func TestTerminalFailure(t *testing.T) {
+	proxy, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	failed := make(chan bool, 1)
+	zkFramework, err := framework.CreateFrameworkWithOptions(proxy.Addr(),
+		framework.WithSessionTimeout(4*time.Second),
+		framework.WithRetryPolicy(retry.NewMaxAttempts(retry.NewExponentialBackoff(10*time.Millisecond, 0), 2)),
+		framework.WithOnFailed(func(core.ZKFramework, core.ConnectionLost) { failed <- true }),
+	)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+	if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	proxy.Close()
+	select {
+	case <-failed:
+	case <-time.After(30 * time.Second):
+		t.Fatal("expected the framework to give up reconnecting")
+	}
+
+	if !zkFramework.Failed() {
+		t.Errorf("expected the framework to be failed")
+	}
+	if err := zkFramework.WaitConnection(time.Second); !frwkerr.IsFrameworkFailed(err) {
+		t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkFailed, err)
+	}
+	if _, err := operation.Exists(zkFramework, uuid.New().String()); !frwkerr.IsFrameworkFailed(err) {
+		t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkFailed, err)
+	}
+}
+
+func TestSessionWatchdog(t *testing.T) {
+	proxy, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer proxy.Close()
+
+	disconnected := make(chan bool, 1)
+	zkFramework, err := framework.CreateFrameworkWithOptions(proxy.Addr(),
+		framework.WithSessionWatchdog(100*time.Millisecond, 300*time.Millisecond),
+		framework.WithOnDisconnected(func(core.ZKFramework) { disconnected <- true }),
+	)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+	if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	proxy.Blackout()
+	select {
+	case <-disconnected:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected the half-open connection to be invalidated before the client read timeout")
+	}
+}
+
+func TestSessionListener(t *testing.T) {
+	first, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer first.Close()
+	second, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer second.Close()
+
+	zkFramework, err := framework.CreateFrameworkWithOptions(first.Addr(), framework.WithSessionTimeout(4*time.Second))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	listener := &mocks.MockedSessionListener{
+		ID:            uuid.New().String(),
+		Expired:       make(chan bool, 1),
+		Reestablished: make(chan bool, 1),
+	}
+	if err := zkFramework.AddSessionListener(listener); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.AddSessionListener(listener); !coreerr.IsListenerAlreadyExists(err) {
+		t.Errorf("expected error %v, got %v", coreerr.ErrListenerAlreadyExists, err)
+	}
+	if err := zkFramework.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+	if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.UpdateServers([]string{second.Addr()}); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	first.Close()
+	select {
+	case <-listener.Expired:
+	case <-time.After(20 * time.Second):
+		t.Fatal("expected the session to be reported as expired")
+	}
+	select {
+	case <-listener.Reestablished:
+	case <-time.After(20 * time.Second):
+		t.Fatal("expected the session to be reported as reestablished")
+	}
+
+	if err := zkFramework.RemoveSessionListenerByID(listener.ID); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.RemoveSessionListener(listener); !coreerr.IsListenerNotFound(err) {
+		t.Errorf("expected error %v, got %v", coreerr.ErrListenerNotFound, err)
+	}
+}
+
+func TestMaxReconnectionAttempts(t *testing.T) {
+	proxy, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	lost := make(chan core.ConnectionLost, 1)
+	zkFramework, err := framework.CreateFrameworkWithOptions(proxy.Addr(),
+		framework.WithSessionTimeout(4*time.Second),
+		framework.WithRetryPolicy(retry.NewRetryForever(10*time.Millisecond)),
+		framework.WithMaxReconnectionAttempts(2),
+		framework.WithMaxReconnectionElapsed(time.Minute),
+		framework.WithOnFailed(func(_ core.ZKFramework, connectionLost core.ConnectionLost) { lost <- connectionLost }),
+	)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+	if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	proxy.Close()
+	select {
+	case connectionLost := <-lost:
+		if connectionLost.Attempts != 2 {
+			t.Errorf("expected 2 reconnection attempts, got %d", connectionLost.Attempts)
+		}
+		if len(connectionLost.Servers) != 1 || connectionLost.Servers[0] != proxy.Addr() {
+			t.Errorf("expected servers [%s], got %v", proxy.Addr(), connectionLost.Servers)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("expected the framework to give up reconnecting")
+	}
+
+	if !zkFramework.Failed() {
+		t.Errorf("expected the framework to be failed")
+	}
+}
+
+func TestConnectionState(t *testing.T) {
+	proxy, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	zkFramework, err := framework.CreateFrameworkWithOptions(proxy.Addr(),
+		framework.WithSessionTimeout(4*time.Second),
+		framework.WithRetryPolicy(retry.NewRetryForever(10*time.Millisecond)),
+		framework.WithMaxReconnectionAttempts(2),
+	)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if zkFramework.ConnectionState() != core.ConnectionStateNone {
+		t.Errorf("expected NONE connection state, got %s", zkFramework.ConnectionState())
+	}
+
+	listener := &connectionStateListener{
+		MockedStatusChangeListener: mocks.MockedStatusChangeListener{ID: uuid.New().String()},
+		states:                     make(chan core.ConnectionState, 10),
+	}
+	if err := zkFramework.AddStatusChangeListener(listener); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.StartAndWait(10 * time.Second); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	proxy.Close()
+	for _, expected := range []core.ConnectionState{core.ConnectionStateConnected, core.ConnectionStateSuspended, core.ConnectionStateLost} {
+		select {
+		case state := <-listener.states:
+			if state != expected {
+				t.Errorf("expected %s connection state, got %s", expected, state)
+			}
+		case <-time.After(30 * time.Second):
+			t.Fatalf("expected the %s connection state", expected)
+		}
+	}
+	if zkFramework.ConnectionState() != core.ConnectionStateLost {
+		t.Errorf("expected LOST connection state, got %s", zkFramework.ConnectionState())
+	}
 }
@@ -0,0 +1,115 @@
+package manifest
+
+import (
+	"bytes"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+Result reports the outcome of applying a manifest.
+*/
+type Result struct {
+	// Created are the paths of the created nodes.
+	Created []string
+	// Updated are the paths of the nodes whose data or ACL was changed.
+	Updated []string
+	// Unchanged are the paths of the nodes already matching the manifest.
+	Unchanged []string
+}
+
+/*
+Apply creates or updates the nodes described by the manifest, parents first.
+
+Apply is idempotent: nodes matching the manifest are left untouched, the mode of existing nodes is never changed and missing parents are created as container nodes.
+The first failing node stops the apply, the returned result reports the nodes processed so far.
+*/
+func Apply(zkFramework core.ZKFramework, manifest Manifest) (Result, error) {
+	if err := manifest.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Created:   []string{},
+		Updated:   []string{},
+		Unchanged: []string{},
+	}
+	for _, node := range manifest.sorted() {
+		created, updated, err := applyNode(zkFramework, node)
+		if err != nil {
+			return result, err
+		}
+		switch {
+		case created:
+			result.Created = append(result.Created, node.Path)
+		case updated:
+			result.Updated = append(result.Updated, node.Path)
+		default:
+			result.Unchanged = append(result.Unchanged, node.Path)
+		}
+	}
+	return result, nil
+}
+
+func applyNode(zkFramework core.ZKFramework, node NodeSpec) (bool, bool, error) {
+	flags, _ := node.flags()
+	nodeACL, _ := node.zkACL()
+
+	exists, err := operation.Exists(zkFramework, node.Path)
+	if err != nil {
+		return false, false, err
+	}
+	if !exists {
+		options := operation.NewCreateOptionsBuilder().
+			WithData([]byte(node.Data)).
+			WithMode(flags).
+			WithACL(nodeACL).
+			Build()
+		return true, false, operation.CreateWithOptions(zkFramework, node.Path, options)
+	}
+
+	updated := false
+	data, err := operation.Get(zkFramework, node.Path)
+	if err != nil {
+		return false, false, err
+	}
+	if !bytes.Equal(data, []byte(node.Data)) {
+		if _, err := operation.Update(zkFramework, node.Path, []byte(node.Data)); err != nil {
+			return false, false, err
+		}
+		updated = true
+	}
+
+	if nodeACL != nil {
+		current, err := operation.GetACL(zkFramework, node.Path)
+		if err != nil {
+			return false, false, err
+		}
+		if !sameACL(current, nodeACL) {
+			if err := operation.SetACL(zkFramework, node.Path, nodeACL); err != nil {
+				return false, false, err
+			}
+			updated = true
+		}
+	}
+	return false, updated, nil
+}
+
+func sameACL(current []zk.ACL, desired []zk.ACL) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	remaining := make(map[zk.ACL]int, len(desired))
+	for _, entry := range desired {
+		remaining[entry]++
+	}
+	for _, entry := range current {
+		if remaining[entry] == 0 {
+			return false
+		}
+		remaining[entry]--
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package manifest_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/manifest"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestApply(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	desired := manifest.Manifest{
+		Nodes: []manifest.NodeSpec{
+			{Path: root + "/config/db", Data: "postgres"},
+			{Path: root + "/config", Data: "v1"},
+			{Path: root + "/readonly", ACL: []manifest.ACL{{Scheme: "world", ID: "anyone", Perms: "r"}}},
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		result, err := manifest.Apply(zkFramework, desired)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if len(result.Created) != 3 || len(result.Updated) != 0 || len(result.Unchanged) != 0 {
+			t.Errorf("unexpected result %+v", result)
+		}
+		if result.Created[0] != root+"/config" {
+			t.Errorf("expected parents first, got %v", result.Created)
+		}
+
+		data, err := operation.Get(zkFramework, root+"/config/db")
+		if err != nil || string(data) != "postgres" {
+			t.Errorf("unexpected data %s, error %v", data, err)
+		}
+		nodeACL, err := operation.GetACL(zkFramework, root+"/readonly")
+		if err != nil || len(nodeACL) != 1 || nodeACL[0].Perms != zk.PermRead {
+			t.Errorf("unexpected ACL %v, error %v", nodeACL, err)
+		}
+	})
+
+	t.Run("Idempotent", func(t *testing.T) {
+		result, err := manifest.Apply(zkFramework, desired)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if len(result.Unchanged) != 3 {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		desired.Nodes[1].Data = "v2"
+		result, err := manifest.Apply(zkFramework, desired)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if len(result.Updated) != 1 || result.Updated[0] != root+"/config" {
+			t.Errorf("unexpected result %+v", result)
+		}
+
+		data, err := operation.Get(zkFramework, root+"/config")
+		if err != nil || string(data) != "v2" {
+			t.Errorf("unexpected data %s, error %v", data, err)
+		}
+	})
+}
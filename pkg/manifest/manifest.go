@@ -0,0 +1,186 @@
+/*
+Package manifest provides the declarative provisioning of Zookeeper nodes from YAML or JSON manifests.
+*/
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/manifest/manifesterr"
+	"gopkg.in/yaml.v3"
+)
+
+/*
+Mode is the creation mode of a node described in a manifest.
+*/
+type Mode string
+
+const (
+	// Persistent is a node surviving the session creating it, the default mode.
+	Persistent Mode = "persistent"
+	// Ephemeral is a node deleted when the session creating it ends.
+	Ephemeral Mode = "ephemeral"
+	// Container is a node deleted by the server once its last child is deleted.
+	Container Mode = "container"
+)
+
+/*
+ACL is an ACL entry of a node described in a manifest.
+*/
+type ACL struct {
+	// Scheme is the scheme of the entry, e.g. world, auth, digest or ip.
+	Scheme string `json:"scheme" yaml:"scheme"`
+	// ID is the identity granted by the entry, e.g. anyone for the world scheme.
+	ID string `json:"id" yaml:"id"`
+	// Perms are the granted permissions as letters: r(ead), w(rite), c(reate), d(elete), a(dmin).
+	Perms string `json:"perms" yaml:"perms"`
+}
+
+/*
+NodeSpec is the desired state of a node.
+*/
+type NodeSpec struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string `json:"path" yaml:"path"`
+	// Data is the content of the node.
+	Data string `json:"data,omitempty" yaml:"data,omitempty"`
+	// Mode is the creation mode of the node, only used when the node is created.
+	Mode Mode `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// ACL is the ACL of the node, nil leaves the ACL to the framework defaults.
+	ACL []ACL `json:"acl,omitempty" yaml:"acl,omitempty"`
+}
+
+/*
+Manifest describes the desired nodes of a tree.
+*/
+type Manifest struct {
+	// Nodes are the desired nodes.
+	Nodes []NodeSpec `json:"nodes" yaml:"nodes"`
+}
+
+/*
+Parse parses a YAML or JSON manifest and validates it.
+*/
+func Parse(data []byte) (Manifest, error) {
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %v", manifesterr.ErrInvalidManifest, err)
+	}
+	if err := manifest.Validate(); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+/*
+Load reads and parses the YAML or JSON manifest stored in the given file.
+*/
+func Load(file string) (Manifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return Parse(data)
+}
+
+/*
+Validate checks that every node has a unique, non empty path, a known mode and a valid ACL.
+*/
+func (m Manifest) Validate() error {
+	seen := make(map[string]bool, len(m.Nodes))
+	for _, node := range m.Nodes {
+		nodePath := cleanPath(node.Path)
+		if nodePath == "" {
+			return fmt.Errorf("%w: node without path", manifesterr.ErrInvalidManifest)
+		}
+		if seen[nodePath] {
+			return fmt.Errorf("%w: duplicated node %s", manifesterr.ErrInvalidManifest, nodePath)
+		}
+		seen[nodePath] = true
+
+		if _, err := node.flags(); err != nil {
+			return err
+		}
+		if _, err := node.zkACL(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+sorted returns the nodes with clean paths, parents before their children.
+*/
+func (m Manifest) sorted() []NodeSpec {
+	nodes := make([]NodeSpec, 0, len(m.Nodes))
+	for _, node := range m.Nodes {
+		node.Path = cleanPath(node.Path)
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Path < nodes[j].Path
+	})
+	return nodes
+}
+
+func (n NodeSpec) flags() (int32, error) {
+	switch n.Mode {
+	case "", Persistent:
+		return 0, nil
+	case Ephemeral:
+		return zk.FlagEphemeral, nil
+	case Container:
+		return zk.FlagContainer, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown mode %s of node %s", manifesterr.ErrInvalidManifest, n.Mode, n.Path)
+	}
+}
+
+func (n NodeSpec) zkACL() ([]zk.ACL, error) {
+	if n.ACL == nil {
+		return nil, nil
+	}
+
+	acl := make([]zk.ACL, 0, len(n.ACL))
+	for _, entry := range n.ACL {
+		perms, err := parsePerms(entry.Perms)
+		if err != nil {
+			return nil, fmt.Errorf("%w: node %s: %v", manifesterr.ErrInvalidManifest, n.Path, err)
+		}
+		if entry.Scheme == "" || entry.ID == "" {
+			return nil, fmt.Errorf("%w: node %s: ACL entry without scheme or id", manifesterr.ErrInvalidManifest, n.Path)
+		}
+		acl = append(acl, zk.ACL{Scheme: entry.Scheme, ID: entry.ID, Perms: perms})
+	}
+	return acl, nil
+}
+
+func parsePerms(letters string) (int32, error) {
+	var perms int32
+	for _, letter := range strings.ToLower(letters) {
+		switch letter {
+		case 'r':
+			perms |= zk.PermRead
+		case 'w':
+			perms |= zk.PermWrite
+		case 'c':
+			perms |= zk.PermCreate
+		case 'd':
+			perms |= zk.PermDelete
+		case 'a':
+			perms |= zk.PermAdmin
+		default:
+			return 0, fmt.Errorf("unknown permission %q", letter)
+		}
+	}
+	return perms, nil
+}
+
+func cleanPath(nodePath string) string {
+	return strings.Trim(path.Clean("/"+nodePath), "/")
+}
@@ -0,0 +1,76 @@
+package manifest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/manifest"
+	"github.com/morphy76/zk/pkg/manifest/manifesterr"
+)
+
+func TestParse(t *testing.T) {
+	yamlManifest := `
+nodes:
+  - path: /app/config
+    data: '{"debug": true}'
+    acl:
+      - scheme: world
+        id: anyone
+        perms: rw
+  - path: app/lock
+    mode: ephemeral
+`
+	jsonManifest := `{"nodes": [{"path": "/app/config", "data": "{\"debug\": true}", "acl": [{"scheme": "world", "id": "anyone", "perms": "rw"}]}, {"path": "app/lock", "mode": "ephemeral"}]}`
+
+	for name, content := range map[string]string{"yaml": yamlManifest, "json": jsonManifest} {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := manifest.Parse([]byte(content))
+			if err != nil {
+				t.Fatalf(unexpectedErrorFmt, err)
+			}
+			if len(parsed.Nodes) != 2 {
+				t.Fatalf("expected 2 nodes, got %d", len(parsed.Nodes))
+			}
+			if parsed.Nodes[0].Data != `{"debug": true}` || parsed.Nodes[0].ACL[0].Perms != "rw" {
+				t.Errorf("unexpected node %+v", parsed.Nodes[0])
+			}
+			if parsed.Nodes[1].Mode != manifest.Ephemeral {
+				t.Errorf("expected mode %s, got %s", manifest.Ephemeral, parsed.Nodes[1].Mode)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"syntax":     "nodes: [",
+		"no path":    "nodes: [{data: x}]",
+		"duplicated": "nodes: [{path: /a}, {path: a/}]",
+		"mode":       "nodes: [{path: a, mode: sequential}]",
+		"perms":      "nodes: [{path: a, acl: [{scheme: world, id: anyone, perms: rx}]}]",
+		"scheme":     "nodes: [{path: a, acl: [{id: anyone, perms: r}]}]",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := manifest.Parse([]byte(content))
+			if !manifesterr.IsInvalidManifest(err) {
+				t.Errorf("expected error %v, got %v", manifesterr.ErrInvalidManifest, err)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(file, []byte("nodes: [{path: a, acl: [{scheme: world, id: anyone, perms: rwcda}]}]"), 0o600); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	loaded, err := manifest.Load(file)
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if len(loaded.Nodes) != 1 || loaded.Nodes[0].Path != "a" {
+		t.Errorf("unexpected manifest %+v", loaded)
+	}
+}
@@ -0,0 +1,18 @@
+/*
+Package manifesterr provides error types for the manifest package.
+*/
+package manifesterr
+
+import "errors"
+
+/*
+ErrInvalidManifest is returned when a manifest cannot be parsed or describes invalid nodes.
+*/
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+/*
+IsInvalidManifest checks if the error is, or wraps, ErrInvalidManifest.
+*/
+func IsInvalidManifest(err error) bool {
+	return errors.Is(err, ErrInvalidManifest)
+}
@@ -0,0 +1,23 @@
+package manifesterr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/manifest/manifesterr"
+)
+
+func TestIsInvalidManifest(t *testing.T) {
+	err := fmt.Errorf("%w: missing path", manifesterr.ErrInvalidManifest)
+	if !manifesterr.IsInvalidManifest(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidManifestFalse(t *testing.T) {
+	err := errors.New("some error")
+	if manifesterr.IsInvalidManifest(err) {
+		t.Errorf("expected false, got true")
+	}
+}
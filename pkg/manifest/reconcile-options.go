@@ -0,0 +1,52 @@
+package manifest
+
+/*
+ReconcileOptions represents the options for a reconciliation.
+*/
+type ReconcileOptions struct {
+	// Prune is a flag to delete the nodes under the root which are not described by the manifest.
+	Prune bool
+	// DryRun is a flag to only report the drift without changing the tree.
+	DryRun bool
+}
+
+/*
+ReconcileOptionsBuilder is a builder for ReconcileOptions.
+*/
+type ReconcileOptionsBuilder struct {
+	prune  bool
+	dryRun bool
+}
+
+/*
+NewReconcileOptionsBuilder creates a new ReconcileOptionsBuilder.
+*/
+func NewReconcileOptionsBuilder() ReconcileOptionsBuilder {
+	return ReconcileOptionsBuilder{}
+}
+
+/*
+WithPrune sets the flag to delete the nodes under the root which are not described by the manifest.
+*/
+func (b ReconcileOptionsBuilder) WithPrune(prune bool) ReconcileOptionsBuilder {
+	b.prune = prune
+	return b
+}
+
+/*
+WithDryRun sets the flag to only report the drift without changing the tree.
+*/
+func (b ReconcileOptionsBuilder) WithDryRun(dryRun bool) ReconcileOptionsBuilder {
+	b.dryRun = dryRun
+	return b
+}
+
+/*
+Build builds the ReconcileOptions.
+*/
+func (b ReconcileOptionsBuilder) Build() ReconcileOptions {
+	return ReconcileOptions{
+		Prune:  b.prune,
+		DryRun: b.dryRun,
+	}
+}
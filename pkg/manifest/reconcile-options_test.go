@@ -0,0 +1,32 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/manifest"
+)
+
+func TestDefaultReconcileOptionsBuilder(t *testing.T) {
+	opts := manifest.NewReconcileOptionsBuilder().Build()
+
+	if opts.Prune {
+		t.Errorf("Expected Prune to be false, got true")
+	}
+	if opts.DryRun {
+		t.Errorf("Expected DryRun to be false, got true")
+	}
+}
+
+func TestReconcileOptionsBuilder(t *testing.T) {
+	opts := manifest.NewReconcileOptionsBuilder().
+		WithPrune(true).
+		WithDryRun(true).
+		Build()
+
+	if !opts.Prune {
+		t.Errorf("Expected Prune to be true, got false")
+	}
+	if !opts.DryRun {
+		t.Errorf("Expected DryRun to be true, got false")
+	}
+}
@@ -0,0 +1,186 @@
+package manifest
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+DriftType is the type of a difference between the manifest and the live tree.
+*/
+type DriftType int
+
+const (
+	// NodeMissing is a node described by the manifest missing from the live tree.
+	NodeMissing DriftType = iota
+	// NodeModified is a node whose live data or ACL differs from the manifest.
+	NodeModified
+	// NodeUnmanaged is a live node not described by the manifest.
+	NodeUnmanaged
+)
+
+/*
+Drift is a difference between the manifest and the live tree.
+*/
+type Drift struct {
+	// Type is the type of the difference.
+	Type DriftType
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// Live is the live data of the node, nil when missing.
+	Live []byte
+	// Desired is the data described by the manifest, nil when unmanaged.
+	Desired []byte
+}
+
+/*
+Report reports the outcome of a reconciliation.
+*/
+type Report struct {
+	// Drift are the differences found before reconciling, sorted by path.
+	Drift []Drift
+	// Applied is the outcome of applying the manifest, empty on dry runs.
+	Applied Result
+	// Pruned are the paths of the deleted unmanaged nodes, children first.
+	Pruned []string
+}
+
+type liveNode struct {
+	data      []byte
+	ephemeral bool
+}
+
+/*
+Reconcile compares the subtree at the given root with the manifest nodes under it, reports the drift and applies the manifest.
+
+With pruning, the unmanaged nodes are deleted children first; the root, the ancestors of managed nodes and the ephemeral nodes are never pruned.
+Manifest nodes outside the root are ignored.
+*/
+func Reconcile(zkFramework core.ZKFramework, root string, manifest Manifest, options ReconcileOptions) (Report, error) {
+	if err := manifest.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	root = cleanPath(root)
+	managed := Manifest{Nodes: []NodeSpec{}}
+	for _, node := range manifest.sorted() {
+		if isUnder(node.Path, root) {
+			managed.Nodes = append(managed.Nodes, node)
+		}
+	}
+
+	live := make(map[string]liveNode)
+	if err := collectLive(zkFramework, root, live); err != nil {
+		return Report{}, err
+	}
+
+	drift, err := computeDrift(zkFramework, root, managed, live)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{Drift: drift, Pruned: []string{}}
+	if options.DryRun {
+		return report, nil
+	}
+
+	if report.Applied, err = Apply(zkFramework, managed); err != nil {
+		return report, err
+	}
+	if options.Prune {
+		report.Pruned, err = prune(zkFramework, drift)
+	}
+	return report, err
+}
+
+func computeDrift(zkFramework core.ZKFramework, root string, managed Manifest, live map[string]liveNode) ([]Drift, error) {
+	drift := []Drift{}
+	ancestors := make(map[string]bool)
+	for _, node := range managed.Nodes {
+		for parent := path.Dir(node.Path); parent != "." && parent != root; parent = path.Dir(parent) {
+			ancestors[parent] = true
+		}
+
+		current, found := live[node.Path]
+		if !found {
+			drift = append(drift, Drift{Type: NodeMissing, Path: node.Path, Desired: []byte(node.Data)})
+			continue
+		}
+		delete(live, node.Path)
+
+		modified := !bytes.Equal(current.data, []byte(node.Data))
+		if nodeACL, _ := node.zkACL(); !modified && nodeACL != nil {
+			currentACL, err := operation.GetACL(zkFramework, node.Path)
+			if err != nil {
+				return nil, err
+			}
+			modified = !sameACL(currentACL, nodeACL)
+		}
+		if modified {
+			drift = append(drift, Drift{Type: NodeModified, Path: node.Path, Live: current.data, Desired: []byte(node.Data)})
+		}
+	}
+
+	for nodePath, current := range live {
+		if nodePath == root || ancestors[nodePath] || current.ephemeral {
+			continue
+		}
+		drift = append(drift, Drift{Type: NodeUnmanaged, Path: nodePath, Live: current.data})
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Path < drift[j].Path
+	})
+	return drift, nil
+}
+
+func prune(zkFramework core.ZKFramework, drift []Drift) ([]string, error) {
+	pruned := []string{}
+	for i := len(drift) - 1; i >= 0; i-- {
+		if drift[i].Type != NodeUnmanaged {
+			continue
+		}
+		err := operation.Delete(zkFramework, drift[i].Path)
+		if err != nil && !coreerr.IsUnknownNode(err) {
+			return pruned, err
+		}
+		pruned = append(pruned, drift[i].Path)
+	}
+	return pruned, nil
+}
+
+func collectLive(zkFramework core.ZKFramework, nodeName string, live map[string]liveNode) error {
+	exists, err := operation.Exists(zkFramework, nodeName)
+	if err != nil || !exists {
+		return err
+	}
+
+	data, stat, err := operation.GetWithStat(zkFramework, nodeName)
+	if err != nil {
+		return err
+	}
+	live[nodeName] = liveNode{data: data, ephemeral: stat.EphemeralOwner != 0}
+
+	if stat.NumChildren == 0 {
+		return nil
+	}
+	children, err := operation.Ls(zkFramework, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := collectLive(zkFramework, path.Join(nodeName, child), live); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isUnder(nodePath string, root string) bool {
+	return root == "" || nodePath == root || strings.HasPrefix(nodePath, root+"/")
+}
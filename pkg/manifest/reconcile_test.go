@@ -0,0 +1,83 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/manifest"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestReconcile(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for _, nodeName := range []string{root + "/managed/stale", root + "/unmanaged/child"} {
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+	}
+	desired := manifest.Manifest{
+		Nodes: []manifest.NodeSpec{
+			{Path: root + "/managed/stale", Data: "fresh"},
+			{Path: root + "/managed/new", Data: "new"},
+			{Path: "elsewhere", Data: "ignored"},
+		},
+	}
+
+	t.Run("DryRun", func(t *testing.T) {
+		options := manifest.NewReconcileOptionsBuilder().WithPrune(true).WithDryRun(true).Build()
+		report, err := manifest.Reconcile(zkFramework, root, desired, options)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+
+		expected := []manifest.Drift{
+			{Type: manifest.NodeMissing, Path: root + "/managed/new"},
+			{Type: manifest.NodeModified, Path: root + "/managed/stale"},
+			{Type: manifest.NodeUnmanaged, Path: root + "/unmanaged"},
+			{Type: manifest.NodeUnmanaged, Path: root + "/unmanaged/child"},
+		}
+		if len(report.Drift) != len(expected) {
+			t.Fatalf("expected drift %v, got %v", expected, report.Drift)
+		}
+		for i, drift := range report.Drift {
+			if drift.Type != expected[i].Type || drift.Path != expected[i].Path {
+				t.Errorf("expected drift %v, got %v", expected[i], drift)
+			}
+		}
+		if exists, _ := operation.Exists(zkFramework, root+"/managed/new"); exists {
+			t.Errorf("expected the dry run to leave the tree untouched")
+		}
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		options := manifest.NewReconcileOptionsBuilder().WithPrune(true).Build()
+		report, err := manifest.Reconcile(zkFramework, root, desired, options)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if len(report.Applied.Created) != 1 || len(report.Applied.Updated) != 1 {
+			t.Errorf("unexpected apply result %+v", report.Applied)
+		}
+		if len(report.Pruned) != 2 || report.Pruned[0] != root+"/unmanaged/child" {
+			t.Errorf("expected children to be pruned first, got %v", report.Pruned)
+		}
+		if exists, _ := operation.Exists(zkFramework, "elsewhere"); exists {
+			t.Errorf("expected nodes outside the root to be ignored")
+		}
+
+		report, err = manifest.Reconcile(zkFramework, root, desired, options)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if len(report.Drift) != 0 {
+			t.Errorf("expected no drift, got %v", report.Drift)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+/*
+Package migrationerr provides error types for the migration package.
+*/
+package migrationerr
+
+import "errors"
+
+/*
+ErrLockTimeout is returned when another runner holds the migration lock longer than the lock timeout.
+*/
+var ErrLockTimeout = errors.New("migration lock timeout")
+
+/*
+ErrDuplicateVersion is returned when two migration steps share the same version.
+*/
+var ErrDuplicateVersion = errors.New("duplicate migration version")
+
+/*
+ErrIrreversible is returned when rolling back a migration step without a down function.
+*/
+var ErrIrreversible = errors.New("irreversible migration")
+
+/*
+ErrUnknownMigration is returned when rolling back an applied migration which is not among the steps of the migrator.
+*/
+var ErrUnknownMigration = errors.New("unknown migration")
+
+/*
+IsLockTimeout checks if the error is ErrLockTimeout.
+*/
+func IsLockTimeout(err error) bool {
+	return err == ErrLockTimeout
+}
+
+/*
+IsDuplicateVersion checks if the error is, or wraps, ErrDuplicateVersion.
+*/
+func IsDuplicateVersion(err error) bool {
+	return errors.Is(err, ErrDuplicateVersion)
+}
+
+/*
+IsIrreversible checks if the error is, or wraps, ErrIrreversible.
+*/
+func IsIrreversible(err error) bool {
+	return errors.Is(err, ErrIrreversible)
+}
+
+/*
+IsUnknownMigration checks if the error is, or wraps, ErrUnknownMigration.
+*/
+func IsUnknownMigration(err error) bool {
+	return errors.Is(err, ErrUnknownMigration)
+}
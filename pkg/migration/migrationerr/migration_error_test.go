@@ -0,0 +1,65 @@
+package migrationerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/migration/migrationerr"
+)
+
+func TestIsLockTimeout(t *testing.T) {
+	err := migrationerr.ErrLockTimeout
+	if !migrationerr.IsLockTimeout(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsLockTimeoutFalse(t *testing.T) {
+	err := errors.New("some error")
+	if migrationerr.IsLockTimeout(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsDuplicateVersion(t *testing.T) {
+	err := fmt.Errorf("%w: 3", migrationerr.ErrDuplicateVersion)
+	if !migrationerr.IsDuplicateVersion(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsDuplicateVersionFalse(t *testing.T) {
+	err := errors.New("some error")
+	if migrationerr.IsDuplicateVersion(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsIrreversible(t *testing.T) {
+	err := fmt.Errorf("%w: 3", migrationerr.ErrIrreversible)
+	if !migrationerr.IsIrreversible(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsIrreversibleFalse(t *testing.T) {
+	err := errors.New("some error")
+	if migrationerr.IsIrreversible(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsUnknownMigration(t *testing.T) {
+	err := fmt.Errorf("%w: 3", migrationerr.ErrUnknownMigration)
+	if !migrationerr.IsUnknownMigration(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsUnknownMigrationFalse(t *testing.T) {
+	err := errors.New("some error")
+	if migrationerr.IsUnknownMigration(err) {
+		t.Errorf("expected false, got true")
+	}
+}
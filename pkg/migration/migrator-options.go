@@ -0,0 +1,46 @@
+package migration
+
+import "time"
+
+/*
+MigratorOptions is used to configure the migrator.
+*/
+type MigratorOptions struct {
+	// LockTimeout is the maximum time waited for the migration lock held by another runner.
+	LockTimeout time.Duration
+}
+
+/*
+MigratorOptionsBuilder is a builder for MigratorOptions.
+*/
+type MigratorOptionsBuilder struct {
+	lockTimeout time.Duration
+}
+
+const defaultLockTimeout = time.Minute
+
+/*
+NewMigratorOptionsBuilder creates a new MigratorOptionsBuilder, waiting up to one minute for the migration lock.
+*/
+func NewMigratorOptionsBuilder() MigratorOptionsBuilder {
+	return MigratorOptionsBuilder{
+		lockTimeout: defaultLockTimeout,
+	}
+}
+
+/*
+WithLockTimeout sets the maximum time waited for the migration lock held by another runner.
+*/
+func (b MigratorOptionsBuilder) WithLockTimeout(lockTimeout time.Duration) MigratorOptionsBuilder {
+	b.lockTimeout = lockTimeout
+	return b
+}
+
+/*
+Build builds the MigratorOptions.
+*/
+func (b MigratorOptionsBuilder) Build() MigratorOptions {
+	return MigratorOptions{
+		LockTimeout: b.lockTimeout,
+	}
+}
@@ -0,0 +1,26 @@
+package migration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/migration"
+)
+
+func TestDefaultMigratorOptionsBuilder(t *testing.T) {
+	opts := migration.NewMigratorOptionsBuilder().Build()
+
+	if opts.LockTimeout != time.Minute {
+		t.Errorf("Expected LockTimeout to be %v, got %v", time.Minute, opts.LockTimeout)
+	}
+}
+
+func TestMigratorOptionsBuilder(t *testing.T) {
+	opts := migration.NewMigratorOptionsBuilder().
+		WithLockTimeout(time.Second).
+		Build()
+
+	if opts.LockTimeout != time.Second {
+		t.Errorf("Expected LockTimeout to be %v, got %v", time.Second, opts.LockTimeout)
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Package migration applies versioned migration steps to the data of a subtree, recording the applied ones in Zookeeper.
+*/
+package migration
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/migration/migrationerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/recipes/base"
+)
+
+const (
+	migrationsSuffix = "@migrations"
+	appliedNode      = "applied"
+	locksNode        = "locks"
+	lockPrefix       = "lock-"
+)
+
+/*
+Step is a migration step, identified by its version.
+*/
+type Step struct {
+	// Version orders the steps, it must be unique.
+	Version int64
+	// Description describes the step in the applied migrations.
+	Description string
+	// Up applies the step to the subtree at the given root.
+	Up func(zkFramework core.ZKFramework, root string) error
+	// Down reverts the step, nil when the step is irreversible.
+	Down func(zkFramework core.ZKFramework, root string) error
+}
+
+/*
+AppliedMigration is the record of an applied step.
+*/
+type AppliedMigration struct {
+	Version     int64     `json:"version"`
+	Description string    `json:"description"`
+	Applied     time.Time `json:"applied"`
+}
+
+/*
+Migrator applies migration steps to a subtree, recording the applied ones in the <root>@migrations node.
+
+Runners sharing the subtree are serialized by a lock made of ephemeral-sequential nodes, a step is recorded right after it is applied:
+a failed step stops the migration, leaving the previous steps applied.
+*/
+type Migrator struct {
+	framework core.ZKFramework
+	root      string
+	steps     []Step
+	options   MigratorOptions
+}
+
+/*
+NewMigrator creates a migrator of the subtree at the given root, using the default options.
+*/
+func NewMigrator(zkFramework core.ZKFramework, root string, steps ...Step) *Migrator {
+	return NewMigratorWithOptions(zkFramework, root, NewMigratorOptionsBuilder().Build(), steps...)
+}
+
+/*
+NewMigratorWithOptions creates a migrator of the subtree at the given root, specifying the migrator options.
+*/
+func NewMigratorWithOptions(zkFramework core.ZKFramework, root string, options MigratorOptions, steps ...Step) *Migrator {
+	sorted := slices.Clone(steps)
+	slices.SortStableFunc(sorted, func(a, b Step) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return &Migrator{
+		framework: zkFramework,
+		root:      root,
+		steps:     sorted,
+		options:   options,
+	}
+}
+
+/*
+Applied returns the applied migrations, ordered by version.
+*/
+func (m *Migrator) Applied() ([]AppliedMigration, error) {
+	applied, _, err := m.readApplied()
+	return applied, err
+}
+
+/*
+Pending returns the steps not applied yet, ordered by version.
+*/
+func (m *Migrator) Pending() ([]Step, error) {
+	applied, _, err := m.readApplied()
+	if err != nil {
+		return nil, err
+	}
+	return m.pending(applied), nil
+}
+
+/*
+Up applies the pending steps, returning the number of applied steps.
+*/
+func (m *Migrator) Up() (int, error) {
+	return m.UpTo(-1)
+}
+
+/*
+UpTo applies the pending steps up to the given version included, a negative version applying all of them; it returns the number of applied steps.
+*/
+func (m *Migrator) UpTo(version int64) (int, error) {
+	if err := m.validate(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := m.locked(func() error {
+		applied, recordVersion, err := m.readApplied()
+		if err != nil {
+			return err
+		}
+
+		for _, step := range m.pending(applied) {
+			if version >= 0 && step.Version > version {
+				break
+			}
+			m.framework.Logger().Info("applying migration", "root", m.root, "version", step.Version, "description", step.Description)
+			if err := step.Up(m.framework, m.root); err != nil {
+				return fmt.Errorf("migration %d: %w", step.Version, err)
+			}
+
+			applied = append(applied, AppliedMigration{Version: step.Version, Description: step.Description, Applied: time.Now()})
+			if recordVersion, err = m.writeApplied(applied, recordVersion); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+/*
+DownTo reverts the applied steps with a version greater than the given one, latest first, returning the number of reverted steps.
+*/
+func (m *Migrator) DownTo(version int64) (int, error) {
+	if err := m.validate(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := m.locked(func() error {
+		applied, recordVersion, err := m.readApplied()
+		if err != nil {
+			return err
+		}
+
+		for len(applied) > 0 && applied[len(applied)-1].Version > version {
+			last := applied[len(applied)-1]
+			i := slices.IndexFunc(m.steps, func(step Step) bool { return step.Version == last.Version })
+			if i < 0 {
+				return fmt.Errorf("%w: %d", migrationerr.ErrUnknownMigration, last.Version)
+			}
+			if m.steps[i].Down == nil {
+				return fmt.Errorf("%w: %d", migrationerr.ErrIrreversible, last.Version)
+			}
+
+			m.framework.Logger().Info("reverting migration", "root", m.root, "version", last.Version, "description", last.Description)
+			if err := m.steps[i].Down(m.framework, m.root); err != nil {
+				return fmt.Errorf("migration %d: %w", last.Version, err)
+			}
+
+			applied = applied[:len(applied)-1]
+			if recordVersion, err = m.writeApplied(applied, recordVersion); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (m *Migrator) validate() error {
+	for i := 1; i < len(m.steps); i++ {
+		if m.steps[i].Version == m.steps[i-1].Version {
+			return fmt.Errorf("%w: %d", migrationerr.ErrDuplicateVersion, m.steps[i].Version)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) pending(applied []AppliedMigration) []Step {
+	pending := []Step{}
+	for _, step := range m.steps {
+		if !slices.ContainsFunc(applied, func(a AppliedMigration) bool { return a.Version == step.Version }) {
+			pending = append(pending, step)
+		}
+	}
+	return pending
+}
+
+func (m *Migrator) appliedNode() string {
+	return path.Join(m.root+migrationsSuffix, appliedNode)
+}
+
+/*
+readApplied reads the applied migrations and the version of their record, -1 when there is no record yet.
+*/
+func (m *Migrator) readApplied() ([]AppliedMigration, int32, error) {
+	data, stat, err := operation.GetWithStat(m.framework, m.appliedNode())
+	if errors.Is(err, zk.ErrNoNode) {
+		return []AppliedMigration{}, -1, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	applied := []AppliedMigration{}
+	if err := json.Unmarshal(data, &applied); err != nil {
+		return nil, 0, err
+	}
+	return applied, stat.Version, nil
+}
+
+func (m *Migrator) writeApplied(applied []AppliedMigration, version int32) (int32, error) {
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return 0, err
+	}
+	if version < 0 {
+		return 0, operation.CreateWithOptions(m.framework, m.appliedNode(), operation.NewCreateOptionsBuilder().WithData(data).Build())
+	}
+	return operation.UpdateWithVersion(m.framework, m.appliedNode(), data, version)
+}
+
+/*
+locked runs the function holding the migration lock, waiting up to the lock timeout for the other runners.
+*/
+func (m *Migrator) locked(run func() error) error {
+	participant, err := base.Join(m.framework, path.Join(m.root+migrationsSuffix, locksNode), lockPrefix, nil)
+	if err != nil {
+		return err
+	}
+	defer base.Leave(m.framework, participant)
+
+	deadline := time.After(m.options.LockTimeout)
+	for {
+		events, waiting, err := base.WatchPredecessor(m.framework, participant)
+		if err != nil {
+			return err
+		}
+		if !waiting {
+			return run()
+		}
+
+		select {
+		case <-events:
+		case <-deadline:
+			return migrationerr.ErrLockTimeout
+		}
+	}
+}
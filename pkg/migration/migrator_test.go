@@ -0,0 +1,168 @@
+package migration_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/migration"
+	"github.com/morphy76/zk/pkg/migration/migrationerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func createStep(version int64, child string) migration.Step {
+	return migration.Step{
+		Version:     version,
+		Description: "create " + child,
+		Up: func(zkFramework core.ZKFramework, root string) error {
+			return operation.Create(zkFramework, path.Join(root, child))
+		},
+		Down: func(zkFramework core.ZKFramework, root string) error {
+			return operation.Delete(zkFramework, path.Join(root, child))
+		},
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Apply and revert migrations", func(t *testing.T) {
+		t.Log("Apply and revert migrations")
+		root := uuid.New().String()
+		migrator := migration.NewMigrator(zkFramework, root, createStep(2, "b"), createStep(1, "a"))
+
+		count, err := migrator.Up()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if count != 2 {
+			t.Errorf("expected 2 applied migrations, got %d", count)
+		}
+		if count, err := migrator.Up(); err != nil || count != 0 {
+			t.Errorf("expected no migration to be applied again, got %d, %v", count, err)
+		}
+
+		migrator = migration.NewMigrator(zkFramework, root, createStep(1, "a"), createStep(2, "b"), createStep(3, "c"), createStep(4, "d"))
+		pending, err := migrator.Pending()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(pending) != 2 || pending[0].Version != 3 {
+			t.Errorf("expected migrations 3 and 4 to be pending, got %v", pending)
+		}
+		if count, err := migrator.UpTo(3); err != nil || count != 1 {
+			t.Errorf("expected one migration to be applied, got %d, %v", count, err)
+		}
+
+		applied, err := migrator.Applied()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(applied) != 3 || applied[2].Version != 3 || applied[2].Description != "create c" {
+			t.Errorf("expected migrations 1 to 3 to be applied, got %v", applied)
+		}
+		children, err := operation.Ls(zkFramework, root)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(children) != 3 {
+			t.Errorf("expected 3 children, got %v", children)
+		}
+
+		if count, err := migrator.DownTo(1); err != nil || count != 2 {
+			t.Errorf("expected two migrations to be reverted, got %d, %v", count, err)
+		}
+		if exists, err := operation.Exists(zkFramework, path.Join(root, "b")); err != nil || exists {
+			t.Errorf("expected the migration 2 to be reverted, got %v, %v", exists, err)
+		}
+	})
+
+	t.Run("Reject invalid migrations", func(t *testing.T) {
+		t.Log("Reject invalid migrations")
+		root := uuid.New().String()
+		if _, err := migration.NewMigrator(zkFramework, root, createStep(1, "a"), createStep(1, "b")).Up(); !migrationerr.IsDuplicateVersion(err) {
+			t.Errorf("expected %v, got %v", migrationerr.ErrDuplicateVersion, err)
+		}
+
+		irreversible := createStep(1, "a")
+		irreversible.Down = nil
+		migrator := migration.NewMigrator(zkFramework, root, irreversible)
+		if _, err := migrator.Up(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := migrator.DownTo(0); !migrationerr.IsIrreversible(err) {
+			t.Errorf("expected %v, got %v", migrationerr.ErrIrreversible, err)
+		}
+		if _, err := migration.NewMigrator(zkFramework, root).DownTo(0); !migrationerr.IsUnknownMigration(err) {
+			t.Errorf("expected %v, got %v", migrationerr.ErrUnknownMigration, err)
+		}
+	})
+
+	t.Run("Serialize concurrent runners", func(t *testing.T) {
+		t.Log("Serialize concurrent runners")
+		root := uuid.New().String()
+		release := make(chan bool)
+		started := make(chan bool)
+		slow := migration.Step{
+			Version: 1,
+			Up: func(core.ZKFramework, string) error {
+				close(started)
+				<-release
+				return nil
+			},
+		}
+
+		done := make(chan error)
+		go func() {
+			_, err := migration.NewMigrator(zkFramework, root, slow).Up()
+			done <- err
+		}()
+		<-started
+
+		opts := migration.NewMigratorOptionsBuilder().WithLockTimeout(100 * time.Millisecond).Build()
+		if _, err := migration.NewMigratorWithOptions(zkFramework, root, opts, slow).Up(); !migrationerr.IsLockTimeout(err) {
+			t.Errorf("expected %v, got %v", migrationerr.ErrLockTimeout, err)
+		}
+
+		close(release)
+		if err := <-done; err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Package mirror materializes a subtree as files in a local directory, keeping them updated as the subtree changes.
+
+Leaf nodes are mirrored as files holding their data, nodes with children as directories, the subtree root being the mirror directory itself.
+*/
+package mirror
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/watcher"
+)
+
+/*
+Mirror keeps a local directory in sync with a subtree.
+*/
+type Mirror struct {
+	framework core.ZKFramework
+	root      string
+	dir       string
+	watcher   *watcher.TreeWatcher
+
+	data     map[string][]byte
+	children map[string]int
+	lock     sync.Mutex
+}
+
+/*
+NewMirror creates a mirror of the subtree at the given path into the given directory.
+*/
+func NewMirror(zkFramework core.ZKFramework, root string, dir string) *Mirror {
+	m := &Mirror{
+		framework: zkFramework,
+		root:      strings.Trim(path.Clean("/"+root), "/"),
+		dir:       dir,
+		data:      make(map[string][]byte),
+		children:  make(map[string]int),
+	}
+	m.watcher = watcher.NewTreeWatcher(zkFramework, root, m.onEvent)
+	return m
+}
+
+/*
+Start writes the current subtree into the directory and starts following its changes.
+*/
+func (m *Mirror) Start() error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	return m.watcher.Start()
+}
+
+/*
+Stop stops following the subtree changes, the directory is left as is.
+*/
+func (m *Mirror) Stop() {
+	m.watcher.Stop()
+}
+
+func (m *Mirror) onEvent(event watcher.TreeEvent) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	relativePath := strings.TrimPrefix(strings.TrimPrefix(event.Path, m.root), "/")
+	if relativePath == "" {
+		return
+	}
+
+	var err error
+	switch event.Type {
+	case watcher.TreeNodeAdded:
+		m.data[relativePath] = event.Data
+		m.children[path.Dir(relativePath)]++
+		err = m.materialize(relativePath)
+	case watcher.TreeNodeUpdated:
+		m.data[relativePath] = event.Data
+		err = m.materialize(relativePath)
+	case watcher.TreeNodeRemoved:
+		err = m.remove(relativePath)
+	}
+	if err != nil {
+		m.framework.Logger().Error("mirror error mirroring", "dir", m.dir, "path", event.Path, "error", err)
+	}
+}
+
+func (m *Mirror) materialize(relativePath string) error {
+	if m.children[relativePath] > 0 {
+		return nil
+	}
+
+	parent := path.Dir(relativePath)
+	if parent != "." {
+		if err := m.ensureDir(parent); err != nil {
+			return err
+		}
+	}
+	return writeFile(m.target(relativePath), m.data[relativePath])
+}
+
+func (m *Mirror) remove(relativePath string) error {
+	delete(m.data, relativePath)
+	delete(m.children, relativePath)
+	if err := os.RemoveAll(m.target(relativePath)); err != nil {
+		return err
+	}
+
+	parent := path.Dir(relativePath)
+	m.children[parent]--
+	if parent == "." || m.children[parent] > 0 {
+		return nil
+	}
+
+	// the parent became a leaf, it is mirrored as a file again
+	delete(m.children, parent)
+	if err := os.RemoveAll(m.target(parent)); err != nil {
+		return err
+	}
+	return writeFile(m.target(parent), m.data[parent])
+}
+
+func (m *Mirror) ensureDir(relativePath string) error {
+	target := m.target(relativePath)
+	if info, err := os.Stat(target); err == nil && !info.IsDir() {
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+	}
+	return os.MkdirAll(target, 0o755)
+}
+
+func (m *Mirror) target(relativePath string) string {
+	return filepath.Join(m.dir, filepath.FromSlash(relativePath))
+}
+
+func writeFile(target string, data []byte) error {
+	tmp := filepath.Join(filepath.Dir(target), "."+filepath.Base(target)+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, target)
+}
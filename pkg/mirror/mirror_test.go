@@ -0,0 +1,112 @@
+package mirror_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/mirror"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestMirror(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	if err := operation.CreateWithOptions(zkFramework, root+"/app/db.properties", operation.NewCreateOptionsBuilder().WithData([]byte("url=db")).Build()); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	dir := t.TempDir()
+	fsMirror := mirror.NewMirror(zkFramework, root, dir)
+	if err := fsMirror.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer fsMirror.Stop()
+
+	dbFile := filepath.Join(dir, "app", "db.properties")
+	waitForContent(t, dbFile, "url=db")
+
+	t.Run("Updates are mirrored", func(t *testing.T) {
+		t.Log("Updates are mirrored")
+		if _, err := operation.Update(zkFramework, root+"/app/db.properties", []byte("url=other")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		waitForContent(t, dbFile, "url=other")
+	})
+
+	t.Run("Created nodes are mirrored", func(t *testing.T) {
+		t.Log("Created nodes are mirrored")
+		if err := operation.CreateWithOptions(zkFramework, root+"/app/cache.properties", operation.NewCreateOptionsBuilder().WithData([]byte("size=1")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		waitForContent(t, filepath.Join(dir, "app", "cache.properties"), "size=1")
+	})
+
+	t.Run("Deleted nodes are removed", func(t *testing.T) {
+		t.Log("Deleted nodes are removed")
+		if err := operation.Delete(zkFramework, root+"/app/cache.properties"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if _, err := os.Stat(filepath.Join(dir, "app", "cache.properties")); os.IsNotExist(err) {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("expected the file to be removed")
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+}
+
+func waitForContent(t *testing.T, file string, expected string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(file)
+		if err == nil && string(data) == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s to contain %s, got %s (%v)", file, expected, data, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
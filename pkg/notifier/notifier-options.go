@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/morphy76/zk/pkg/deadletter"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+/*
+NotifierOptions is used to configure the notifier.
+*/
+type NotifierOptions struct {
+	// BatchSize is the number of events triggering a delivery.
+	BatchSize int
+	// FlushInterval is the maximum delay before pending events are delivered.
+	FlushInterval time.Duration
+	// RetryPolicy decides the retries of a failed delivery, the batch is dropped when it gives up.
+	RetryPolicy retry.Policy
+	// DeadLetters captures the batches dropped when the retry policy gives up, nil meaning they are only logged.
+	DeadLetters *deadletter.Buffer
+	// Logger logs the dead-lettered and dropped batches, usually the logger of the framework.
+	Logger *slog.Logger
+}
+
+/*
+NotifierOptionsBuilder is a builder for NotifierOptions.
+*/
+type NotifierOptionsBuilder struct {
+	batchSize     int
+	flushInterval time.Duration
+	retryPolicy   retry.Policy
+	deadLetters   *deadletter.Buffer
+	logger        *slog.Logger
+}
+
+const (
+	defaultBatchSize      = 100
+	defaultFlushInterval  = time.Second
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+	defaultRetryAttempts  = 5
+)
+
+/*
+NewNotifierOptionsBuilder creates a new NotifierOptionsBuilder.
+*/
+func NewNotifierOptionsBuilder() NotifierOptionsBuilder {
+	return NotifierOptionsBuilder{
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		retryPolicy:   retry.NewMaxAttempts(retry.NewExponentialBackoff(defaultRetryBaseDelay, defaultRetryMaxDelay), defaultRetryAttempts),
+		logger:        slog.Default(),
+	}
+}
+
+/*
+WithBatchSize sets the number of events triggering a delivery.
+*/
+func (b NotifierOptionsBuilder) WithBatchSize(batchSize int) NotifierOptionsBuilder {
+	b.batchSize = batchSize
+	return b
+}
+
+/*
+WithFlushInterval sets the maximum delay before pending events are delivered.
+*/
+func (b NotifierOptionsBuilder) WithFlushInterval(flushInterval time.Duration) NotifierOptionsBuilder {
+	b.flushInterval = flushInterval
+	return b
+}
+
+/*
+WithRetryPolicy sets the policy deciding the retries of a failed delivery.
+*/
+func (b NotifierOptionsBuilder) WithRetryPolicy(retryPolicy retry.Policy) NotifierOptionsBuilder {
+	b.retryPolicy = retryPolicy
+	return b
+}
+
+/*
+WithDeadLetters sets the buffer capturing the batches dropped when the retry policy gives up.
+*/
+func (b NotifierOptionsBuilder) WithDeadLetters(deadLetters *deadletter.Buffer) NotifierOptionsBuilder {
+	b.deadLetters = deadLetters
+	return b
+}
+
+/*
+WithLogger sets the logger of the dead-lettered and dropped batches, usually the logger of the framework.
+*/
+func (b NotifierOptionsBuilder) WithLogger(logger *slog.Logger) NotifierOptionsBuilder {
+	b.logger = logger
+	return b
+}
+
+/*
+Build builds the NotifierOptions.
+*/
+func (b NotifierOptionsBuilder) Build() NotifierOptions {
+	return NotifierOptions{
+		BatchSize:     b.batchSize,
+		FlushInterval: b.flushInterval,
+		RetryPolicy:   b.retryPolicy,
+		DeadLetters:   b.deadLetters,
+		Logger:        b.logger,
+	}
+}
@@ -0,0 +1,61 @@
+package notifier_test
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/deadletter"
+	"github.com/morphy76/zk/pkg/notifier"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+func TestDefaultNotifierOptionsBuilder(t *testing.T) {
+	opts := notifier.NewNotifierOptionsBuilder().Build()
+
+	if opts.BatchSize != 100 {
+		t.Errorf("Expected BatchSize to be 100, got %d", opts.BatchSize)
+	}
+	if opts.FlushInterval != time.Second {
+		t.Errorf("Expected FlushInterval to be %v, got %v", time.Second, opts.FlushInterval)
+	}
+	if opts.RetryPolicy == nil {
+		t.Error("Expected RetryPolicy to be set")
+	}
+	if opts.DeadLetters != nil {
+		t.Errorf("Expected DeadLetters to be nil, got %v", opts.DeadLetters)
+	}
+	if opts.Logger != slog.Default() {
+		t.Errorf("Expected Logger to be the default logger, got %v", opts.Logger)
+	}
+}
+
+func TestNotifierOptionsBuilder(t *testing.T) {
+	policy := retry.NewExponentialBackoff(time.Millisecond, 0)
+	deadLetters := deadletter.NewBuffer(10)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	opts := notifier.NewNotifierOptionsBuilder().
+		WithBatchSize(10).
+		WithFlushInterval(time.Minute).
+		WithRetryPolicy(policy).
+		WithDeadLetters(deadLetters).
+		WithLogger(logger).
+		Build()
+
+	if opts.BatchSize != 10 {
+		t.Errorf("Expected BatchSize to be 10, got %d", opts.BatchSize)
+	}
+	if opts.FlushInterval != time.Minute {
+		t.Errorf("Expected FlushInterval to be %v, got %v", time.Minute, opts.FlushInterval)
+	}
+	if opts.RetryPolicy != policy {
+		t.Errorf("Expected RetryPolicy to be %v, got %v", policy, opts.RetryPolicy)
+	}
+	if opts.DeadLetters != deadLetters {
+		t.Errorf("Expected DeadLetters to be %v, got %v", deadLetters, opts.DeadLetters)
+	}
+	if opts.Logger != logger {
+		t.Errorf("Expected Logger to be %v, got %v", logger, opts.Logger)
+	}
+}
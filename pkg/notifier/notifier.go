@@ -0,0 +1,273 @@
+/*
+Package notifier forwards the changes of a subtree to external systems, e.g. webhooks or message brokers.
+*/
+package notifier
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/morphy76/zk/pkg/cdc"
+)
+
+/*
+Event is the integration event delivered to the sinks.
+*/
+type Event struct {
+	// Type is the type of the change: created, updated or deleted.
+	Type string `json:"type"`
+	// Path is the path of the changed node, relative to the namespace.
+	Path string `json:"path"`
+	// Data is the data of the node, empty when deleted.
+	Data []byte `json:"data,omitempty"`
+	// Zxid is the transaction ID of the change.
+	Zxid int64 `json:"zxid"`
+	// Time is the time the change was notified.
+	Time time.Time `json:"time"`
+}
+
+/*
+SinkStats are the delivery metrics of a sink.
+*/
+type SinkStats struct {
+	// Delivered is the number of events delivered.
+	Delivered int64
+	// Dropped is the number of events dropped after the retry policy gave up.
+	Dropped int64
+	// DeadLettered is the number of events captured as dead letters after the retry policy gave up.
+	DeadLettered int64
+	// Retries is the number of retried deliveries.
+	Retries int64
+	// LastError is the error of the last failed delivery.
+	LastError error
+	// LastDelivery is the time of the last successful delivery.
+	LastDelivery time.Time
+}
+
+var eventTypes = map[cdc.ChangeType]string{
+	cdc.Created: "created",
+	cdc.Updated: "updated",
+	cdc.Deleted: "deleted",
+}
+
+/*
+Notifier batches the notified changes and delivers them to every sink, retrying failed deliveries.
+*/
+type Notifier struct {
+	sinks   []Sink
+	options NotifierOptions
+
+	events  chan Event
+	stop    chan bool
+	done    chan bool
+	running bool
+	stats   map[string]SinkStats
+	lock    sync.Mutex
+}
+
+/*
+NewNotifier creates a notifier delivering to the given sinks, using the default options.
+*/
+func NewNotifier(sinks ...Sink) *Notifier {
+	return NewNotifierWithOptions(NewNotifierOptionsBuilder().Build(), sinks...)
+}
+
+/*
+NewNotifierWithOptions creates a notifier delivering to the given sinks, specifying the notifier options.
+*/
+func NewNotifierWithOptions(options NotifierOptions, sinks ...Sink) *Notifier {
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+	stats := make(map[string]SinkStats, len(sinks))
+	for _, sink := range sinks {
+		stats[sink.Name()] = SinkStats{}
+	}
+	return &Notifier{
+		sinks:   sinks,
+		options: options,
+		events:  make(chan Event),
+		stats:   stats,
+	}
+}
+
+/*
+Start starts batching and delivering the notified changes.
+*/
+func (n *Notifier) Start() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.running {
+		return
+	}
+	n.running = true
+	n.stop = make(chan bool)
+	n.done = make(chan bool)
+	go n.deliveryLoop(n.stop, n.done)
+}
+
+/*
+Stop delivers the pending events and stops the notifier.
+*/
+func (n *Notifier) Stop() {
+	n.lock.Lock()
+	if !n.running {
+		n.lock.Unlock()
+		return
+	}
+	n.running = false
+	close(n.stop)
+	done := n.done
+	n.lock.Unlock()
+
+	<-done
+}
+
+/*
+Notify queues a change for delivery, blocking while a full batch is being delivered. Changes notified to a stopped notifier are discarded.
+*/
+func (n *Notifier) Notify(change cdc.Change) {
+	n.lock.Lock()
+	stop := n.stop
+	running := n.running
+	n.lock.Unlock()
+	if !running {
+		return
+	}
+
+	event := Event{
+		Type: eventTypes[change.Type],
+		Path: change.Path,
+		Data: change.Data,
+		Zxid: change.Zxid,
+		Time: time.Now(),
+	}
+	select {
+	case n.events <- event:
+	case <-stop:
+	}
+}
+
+/*
+Forward notifies every change received from the channel, e.g. a cdc.Stream, until the channel is closed.
+*/
+func (n *Notifier) Forward(changes <-chan cdc.Change) {
+	for change := range changes {
+		n.Notify(change)
+	}
+}
+
+/*
+Stats returns the delivery metrics of each sink, by sink name.
+*/
+func (n *Notifier) Stats() map[string]SinkStats {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	stats := make(map[string]SinkStats, len(n.stats))
+	for name, sinkStats := range n.stats {
+		stats[name] = sinkStats
+	}
+	return stats
+}
+
+func (n *Notifier) deliveryLoop(stop chan bool, done chan bool) {
+	defer close(done)
+
+	ticker := time.NewTicker(n.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := []Event{}
+	for {
+		select {
+		case event := <-n.events:
+			batch = append(batch, event)
+			if len(batch) < n.options.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+		case <-stop:
+			n.deliver(batch)
+			return
+		}
+
+		n.deliver(batch)
+		batch = []Event{}
+	}
+}
+
+func (n *Notifier) deliver(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+
+	for _, sink := range n.sinks {
+		n.deliverTo(sink, batch)
+	}
+}
+
+func (n *Notifier) deliverTo(sink Sink, batch []Event) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := sink.Send(batch)
+		if err == nil {
+			n.updateStats(sink, func(stats *SinkStats) {
+				stats.Delivered += int64(len(batch))
+				stats.LastDelivery = time.Now()
+			})
+			return
+		}
+
+		delay, ok := n.options.RetryPolicy.NextDelay(attempt, time.Since(start))
+		if !ok && n.options.DeadLetters != nil {
+			n.options.Logger.Warn("notifier dead-lettering events", "events", len(batch), "sink", sink.Name(), "error", err)
+			n.options.DeadLetters.Add(sink.Name(), batch, err, attempt, func() error {
+				return n.replay(sink, batch)
+			})
+			n.updateStats(sink, func(stats *SinkStats) {
+				stats.DeadLettered += int64(len(batch))
+				stats.LastError = err
+			})
+			return
+		}
+		if !ok {
+			n.options.Logger.Error("notifier dropping events", "events", len(batch), "sink", sink.Name(), "error", err)
+			n.updateStats(sink, func(stats *SinkStats) {
+				stats.Dropped += int64(len(batch))
+				stats.LastError = err
+			})
+			return
+		}
+
+		n.updateStats(sink, func(stats *SinkStats) {
+			stats.Retries++
+			stats.LastError = err
+		})
+		<-time.After(delay)
+	}
+}
+
+func (n *Notifier) replay(sink Sink, batch []Event) error {
+	if err := sink.Send(batch); err != nil {
+		n.updateStats(sink, func(stats *SinkStats) {
+			stats.LastError = err
+		})
+		return err
+	}
+	n.updateStats(sink, func(stats *SinkStats) {
+		stats.Delivered += int64(len(batch))
+		stats.LastDelivery = time.Now()
+	})
+	return nil
+}
+
+func (n *Notifier) updateStats(sink Sink, update func(stats *SinkStats)) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	stats := n.stats[sink.Name()]
+	update(&stats)
+	n.stats[sink.Name()] = stats
+}
@@ -0,0 +1,172 @@
+package notifier_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/cdc"
+	"github.com/morphy76/zk/pkg/deadletter"
+	"github.com/morphy76/zk/pkg/notifier"
+	"github.com/morphy76/zk/pkg/notifier/notifiererr"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+type recordingPublisher struct {
+	messages map[string][][]byte
+	failures int
+	lock     sync.Mutex
+}
+
+func (p *recordingPublisher) Publish(subject string, data []byte) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("unavailable")
+	}
+	p.messages[subject] = append(p.messages[subject], data)
+	return nil
+}
+
+func (p *recordingPublisher) Produce(topic string, key []byte, value []byte) error {
+	return p.Publish(topic+"/"+string(key), value)
+}
+
+func TestNotifierBatching(t *testing.T) {
+	received := make(chan []notifier.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var events []notifier.Event
+		if err := json.NewDecoder(r.Body).Decode(&events); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- events
+	}))
+	defer server.Close()
+
+	options := notifier.NewNotifierOptionsBuilder().WithBatchSize(2).WithFlushInterval(time.Hour).Build()
+	webhook := notifier.NewWebhookSink(server.URL, nil)
+	n := notifier.NewNotifierWithOptions(options, webhook)
+	n.Start()
+
+	n.Notify(cdc.Change{Type: cdc.Created, Path: "a", Zxid: 1})
+	n.Notify(cdc.Change{Type: cdc.Deleted, Path: "b", Zxid: 2})
+	n.Notify(cdc.Change{Type: cdc.Updated, Path: "c", Data: []byte("c"), Zxid: 3})
+
+	select {
+	case events := <-received:
+		if len(events) != 2 || events[0].Type != "created" || events[1].Type != "deleted" {
+			t.Errorf("unexpected batch %+v", events)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a full batch to be delivered")
+	}
+
+	n.Stop()
+	select {
+	case events := <-received:
+		if len(events) != 1 || events[0].Path != "c" || string(events[0].Data) != "c" {
+			t.Errorf("unexpected batch %+v", events)
+		}
+	default:
+		t.Fatal("expected the pending events to be delivered on stop")
+	}
+
+	if stats := n.Stats()[webhook.Name()]; stats.Delivered != 3 {
+		t.Errorf("expected 3 delivered events, got %+v", stats)
+	}
+}
+
+func TestNotifierRetries(t *testing.T) {
+	publisher := &recordingPublisher{messages: make(map[string][][]byte), failures: 2}
+	sink := notifier.NewNATSSink(publisher, "changes")
+
+	options := notifier.NewNotifierOptionsBuilder().
+		WithFlushInterval(10 * time.Millisecond).
+		WithRetryPolicy(retry.NewMaxAttempts(retry.NewExponentialBackoff(time.Millisecond, 0), 3)).
+		Build()
+	n := notifier.NewNotifierWithOptions(options, sink)
+	n.Start()
+	n.Notify(cdc.Change{Type: cdc.Created, Path: "a"})
+	n.Stop()
+
+	stats := n.Stats()[sink.Name()]
+	if stats.Delivered != 1 || stats.Retries != 2 {
+		t.Errorf("expected 1 delivered event after 2 retries, got %+v", stats)
+	}
+	if len(publisher.messages["changes"]) != 1 {
+		t.Errorf("expected 1 published message, got %d", len(publisher.messages["changes"]))
+	}
+}
+
+func TestNotifierDrop(t *testing.T) {
+	publisher := &recordingPublisher{messages: make(map[string][][]byte), failures: 10}
+	sink := notifier.NewKafkaSink(publisher, "changes")
+
+	options := notifier.NewNotifierOptionsBuilder().
+		WithRetryPolicy(retry.NewMaxAttempts(retry.NewExponentialBackoff(time.Millisecond, 0), 1)).
+		Build()
+	n := notifier.NewNotifierWithOptions(options, sink)
+	n.Start()
+	n.Notify(cdc.Change{Type: cdc.Created, Path: "a"})
+	n.Stop()
+
+	stats := n.Stats()[sink.Name()]
+	if stats.Dropped != 1 || stats.LastError == nil {
+		t.Errorf("expected 1 dropped event, got %+v", stats)
+	}
+}
+
+func TestNotifierDeadLetters(t *testing.T) {
+	publisher := &recordingPublisher{messages: make(map[string][][]byte), failures: 2}
+	sink := notifier.NewNATSSink(publisher, "changes")
+	deadLetters := deadletter.NewBuffer(10)
+
+	options := notifier.NewNotifierOptionsBuilder().
+		WithRetryPolicy(retry.NewMaxAttempts(retry.NewExponentialBackoff(time.Millisecond, 0), 1)).
+		WithDeadLetters(deadLetters).
+		Build()
+	n := notifier.NewNotifierWithOptions(options, sink)
+	n.Start()
+	n.Notify(cdc.Change{Type: cdc.Created, Path: "a"})
+	n.Stop()
+
+	stats := n.Stats()[sink.Name()]
+	if stats.Dropped != 0 || stats.DeadLettered != 1 {
+		t.Errorf("expected 1 dead-lettered event, got %+v", stats)
+	}
+	letters := deadLetters.Letters()
+	if len(letters) != 1 || letters[0].Source != sink.Name() {
+		t.Fatalf("expected 1 dead letter from %s, got %+v", sink.Name(), letters)
+	}
+	if batch, ok := letters[0].Payload.([]notifier.Event); !ok || len(batch) != 1 || batch[0].Path != "a" {
+		t.Errorf("expected the failed batch as payload, got %+v", letters[0].Payload)
+	}
+
+	if err := deadLetters.Replay(letters[0].ID); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(publisher.messages["changes"]) != 1 || deadLetters.Len() != 0 {
+		t.Errorf("expected the dead letter to be delivered on replay")
+	}
+	if stats := n.Stats()[sink.Name()]; stats.Delivered != 1 {
+		t.Errorf("expected 1 delivered event, got %+v", stats)
+	}
+}
+
+func TestWebhookSinkRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := notifier.NewWebhookSink(server.URL, nil)
+	if err := sink.Send([]notifier.Event{{Type: "created", Path: "a"}}); !notifiererr.IsDeliveryFailed(err) {
+		t.Errorf("expected error %v, got %v", notifiererr.ErrDeliveryFailed, err)
+	}
+}
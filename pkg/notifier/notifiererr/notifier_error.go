@@ -0,0 +1,18 @@
+/*
+Package notifiererr provides error types for the notifier package.
+*/
+package notifiererr
+
+import "errors"
+
+/*
+ErrDeliveryFailed is returned when a sink rejects a batch of events.
+*/
+var ErrDeliveryFailed = errors.New("delivery failed")
+
+/*
+IsDeliveryFailed checks if the error is, or wraps, ErrDeliveryFailed.
+*/
+func IsDeliveryFailed(err error) bool {
+	return errors.Is(err, ErrDeliveryFailed)
+}
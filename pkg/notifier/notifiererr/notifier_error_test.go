@@ -0,0 +1,23 @@
+package notifiererr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/notifier/notifiererr"
+)
+
+func TestIsDeliveryFailed(t *testing.T) {
+	err := fmt.Errorf("%w: 500 Internal Server Error", notifiererr.ErrDeliveryFailed)
+	if !notifiererr.IsDeliveryFailed(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsDeliveryFailedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if notifiererr.IsDeliveryFailed(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,165 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/morphy76/zk/pkg/notifier/notifiererr"
+)
+
+/*
+Sink delivers batches of events to an external system.
+*/
+type Sink interface {
+	// Name identifies the sink in the delivery metrics.
+	Name() string
+	// Send delivers a batch of events, an error makes the notifier retry the whole batch.
+	Send(events []Event) error
+}
+
+/*
+WebhookSink posts each batch of events as a JSON array to an HTTP endpoint, any non-2xx response is a failed delivery.
+*/
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+/*
+NewWebhookSink creates a sink posting to the given URL with the given HTTP client, nil meaning the default client.
+*/
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookSink{
+		url:    url,
+		client: client,
+	}
+}
+
+/*
+Name returns the URL of the webhook.
+*/
+func (s *WebhookSink) Name() string {
+	return s.url
+}
+
+/*
+Send posts the batch of events.
+*/
+func (s *WebhookSink) Send(events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("%w: %s: %s", notifiererr.ErrDeliveryFailed, s.url, response.Status)
+	}
+	return nil
+}
+
+/*
+KafkaProducer is the subset of a Kafka producer client used by KafkaSink.
+*/
+type KafkaProducer interface {
+	Produce(topic string, key []byte, value []byte) error
+}
+
+/*
+KafkaSink produces each event as a JSON message keyed by the node path, so the changes of a node stay ordered within a partition.
+*/
+type KafkaSink struct {
+	producer KafkaProducer
+	topic    string
+}
+
+/*
+NewKafkaSink creates a sink producing to the given topic.
+*/
+func NewKafkaSink(producer KafkaProducer, topic string) *KafkaSink {
+	return &KafkaSink{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+/*
+Name returns the Kafka topic.
+*/
+func (s *KafkaSink) Name() string {
+	return "kafka:" + s.topic
+}
+
+/*
+Send produces the batch of events.
+*/
+func (s *KafkaSink) Send(events []Event) error {
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if err := s.producer.Produce(s.topic, []byte(event.Path), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+NATSPublisher is the subset of a NATS connection used by NATSSink, as implemented by nats.Conn.
+*/
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+/*
+NATSSink publishes each event as a JSON message.
+*/
+type NATSSink struct {
+	publisher NATSPublisher
+	subject   string
+}
+
+/*
+NewNATSSink creates a sink publishing to the given subject.
+*/
+func NewNATSSink(publisher NATSPublisher, subject string) *NATSSink {
+	return &NATSSink{
+		publisher: publisher,
+		subject:   subject,
+	}
+}
+
+/*
+Name returns the NATS subject.
+*/
+func (s *NATSSink) Name() string {
+	return "nats:" + s.subject
+}
+
+/*
+Send publishes the batch of events.
+*/
+func (s *NATSSink) Send(events []Event) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if err := s.publisher.Publish(s.subject, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package operation
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+Permission is a set of operations allowed by an access rule.
+*/
+type Permission int32
+
+const (
+	// PermissionRead allows listing, checking and getting nodes and their ACL.
+	PermissionRead Permission = 1 << iota
+	// PermissionWrite allows updating nodes.
+	PermissionWrite
+	// PermissionCreate allows creating nodes.
+	PermissionCreate
+	// PermissionDelete allows deleting nodes.
+	PermissionDelete
+	// PermissionAdmin allows setting the ACL of nodes.
+	PermissionAdmin
+	// PermissionAll allows every operation.
+	PermissionAll = PermissionRead | PermissionWrite | PermissionCreate | PermissionDelete | PermissionAdmin
+)
+
+var permissionNames = []string{"read", "write", "create", "delete", "admin"}
+
+/*
+String returns the names of the allowed operations, separated by |.
+*/
+func (p Permission) String() string {
+	names := []string{}
+	for i, name := range permissionNames {
+		if p&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+/*
+AccessRule allows a set of operations on the nodes matching a path pattern.
+
+The pattern is relative to the namespace, each segment is matched with path.Match and a ** segment matches any number of segments.
+*/
+type AccessRule struct {
+	// Pattern is the path pattern of the nodes covered by the rule.
+	Pattern string
+	// Allow is the set of operations allowed on the matching nodes.
+	Allow Permission
+}
+
+/*
+AccessPolicy maps each logical role to its access rules.
+*/
+type AccessPolicy map[string][]AccessRule
+
+type accessPolicyKey struct{}
+
+type accessBinding struct {
+	bound bool
+	role  string
+	rules []AccessRule
+	lock  sync.RWMutex
+}
+
+func accessBindingOf(zkFramework core.ZKFramework) *accessBinding {
+	return zkFramework.Extension(accessPolicyKey{}, func() any {
+		return &accessBinding{}
+	}).(*accessBinding)
+}
+
+/*
+WithAccessPolicy is the framework option binding the access policy to the framework, see SetAccessPolicy.
+*/
+func WithAccessPolicy(policy AccessPolicy, role string) framework.Option {
+	return framework.WithExtension(func(zkFramework core.ZKFramework) {
+		SetAccessPolicy(zkFramework, policy, role)
+	})
+}
+
+/*
+SetAccessPolicy binds the access policy to the framework, operations run through it, or through any of its views, act as the given role.
+
+Once bound, an operation fails fast with operr.ErrAccessDenied unless a rule of the role matching the node allows it; unknown roles are denied everything.
+The policy is a client-side guardrail complementing, not replacing, the server ACLs.
+*/
+func SetAccessPolicy(zkFramework core.ZKFramework, policy AccessPolicy, role string) {
+	rules := make([]AccessRule, 0, len(policy[role]))
+	for _, rule := range policy[role] {
+		rules = append(rules, AccessRule{
+			Pattern: path.Join(zkFramework.Namespace(), rule.Pattern),
+			Allow:   rule.Allow,
+		})
+	}
+
+	binding := accessBindingOf(zkFramework)
+	binding.lock.Lock()
+	defer binding.lock.Unlock()
+	binding.bound = true
+	binding.role = role
+	binding.rules = rules
+}
+
+/*
+RemoveAccessPolicy unbinds the access policy from the framework, allowing every operation again.
+*/
+func RemoveAccessPolicy(zkFramework core.ZKFramework) {
+	binding := accessBindingOf(zkFramework)
+	binding.lock.Lock()
+	defer binding.lock.Unlock()
+	binding.bound = false
+	binding.role = ""
+	binding.rules = nil
+}
+
+func authorize(zkFramework core.ZKFramework, actualPath string, permission Permission) error {
+	binding := accessBindingOf(zkFramework)
+	binding.lock.RLock()
+	defer binding.lock.RUnlock()
+
+	if !binding.bound {
+		return nil
+	}
+
+	var allowed Permission
+	for _, rule := range binding.rules {
+		if matchPattern(splitPath(rule.Pattern), splitPath(actualPath)) {
+			allowed |= rule.Allow
+		}
+	}
+	if allowed&permission != permission {
+		return fmt.Errorf("%w: role %s cannot %s %s", operr.ErrAccessDenied, binding.role, permission, actualPath)
+	}
+	return nil
+}
+
+func matchPattern(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPattern(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+		return false
+	}
+	return matchPattern(pattern[1:], segments[1:])
+}
+
+func splitPath(nodePath string) []string {
+	trimmed := strings.Trim(nodePath, "/")
+	if trimmed == "" {
+		return []string{}
+	}
+	return strings.Split(trimmed, "/")
+}
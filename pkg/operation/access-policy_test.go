@@ -0,0 +1,118 @@
+package operation_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestPermissionString(t *testing.T) {
+	if name := (operation.PermissionRead | operation.PermissionDelete).String(); name != "read|delete" {
+		t.Errorf("expected read|delete, got %s", name)
+	}
+}
+
+func TestAccessPolicy(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	if err := operation.Create(zkFramework, root+"/config/db"); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	policy := operation.AccessPolicy{
+		"reader": {
+			{Pattern: root + "/**", Allow: operation.PermissionRead},
+		},
+		"operator": {
+			{Pattern: root + "/**", Allow: operation.PermissionRead},
+			{Pattern: root + "/config/*", Allow: operation.PermissionWrite | operation.PermissionCreate},
+		},
+	}
+	defer operation.RemoveAccessPolicy(zkFramework)
+
+	t.Run("Reader", func(t *testing.T) {
+		operation.SetAccessPolicy(zkFramework, policy, "reader")
+
+		if _, err := operation.Get(zkFramework, root+"/config/db"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := operation.Ls(zkFramework, root); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := operation.Update(zkFramework, root+"/config/db", []byte("x")); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+		if err := operation.Delete(zkFramework, root+"/config/db"); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+	})
+
+	t.Run("Operator", func(t *testing.T) {
+		operation.SetAccessPolicy(zkFramework, policy, "operator")
+
+		if _, err := operation.Update(zkFramework, root+"/config/db", []byte("x")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Create(zkFramework, root+"/config/cache"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Create(zkFramework, root+"/other"); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+		if _, err := operation.Get(zkFramework, "elsewhere"); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+	})
+
+	t.Run("UnknownRole", func(t *testing.T) {
+		operation.SetAccessPolicy(zkFramework, policy, "guest")
+
+		if _, err := operation.Exists(zkFramework, root); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+	})
+
+	t.Run("View", func(t *testing.T) {
+		operation.SetAccessPolicy(zkFramework, policy, "reader")
+
+		view := zkFramework.UsingOperationTimeout(time.Second).UsingNamespace(root)
+		if _, err := operation.Update(view, "config/db", []byte("x")); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		operation.RemoveAccessPolicy(zkFramework)
+
+		if err := operation.Delete(zkFramework, root+"/config/cache"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	})
+}
+
+func TestAccessPolicyOption(t *testing.T) {
+	policy := operation.AccessPolicy{
+		"reader": {
+			{Pattern: "**", Allow: operation.PermissionRead},
+		},
+	}
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), operation.WithAccessPolicy(policy, "reader"))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	if err := operation.Delete(zkFramework.UsingNamespace("view"), "node"); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+}
@@ -0,0 +1,163 @@
+package operation
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+GetACL gets the ACL of a node at the given path.
+*/
+func GetACL(zkFramework core.ZKFramework, nodeName string) ([]zk.ACL, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("getting ACL of node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, err
+	}
+
+	outChan, errChan := execute(zkFramework, getNodeACL(actualPath))
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
+
+/*
+SetACL sets the ACL of a node at the given path.
+*/
+func SetACL(zkFramework core.ZKFramework, nodeName string, nodeACL []zk.ACL) error {
+	if err := acl.Validate(nodeACL); err != nil {
+		return err
+	}
+
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("setting ACL of node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionAdmin); err != nil {
+		return err
+	}
+
+	outChan, errChan := execute(zkFramework, setNodeACL(actualPath, nodeACL))
+
+	select {
+	case <-outChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+/*
+SetACLRecursive sets the ACL of a node and of all its descendants, returning the affected nodes.
+
+Nodes are updated with bounded concurrency, the first error stops scheduling further updates. In dry-run mode the affected nodes are only listed.
+*/
+func SetACLRecursive(zkFramework core.ZKFramework, root string, nodeACL []zk.ACL, options SetACLOptions) ([]string, error) {
+	if err := acl.Validate(nodeACL); err != nil {
+		return nil, err
+	}
+
+	nodes, err := collectTree(zkFramework, root)
+	if err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		return nodes, nil
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	slots := make(chan struct{}, concurrency)
+	for _, nodeName := range nodes {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(nodeName string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			err := SetACL(zkFramework, nodeName, nodeACL)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			done++
+			if options.Progress != nil {
+				options.Progress(nodeName, done, len(nodes))
+			}
+		}(nodeName)
+	}
+	wg.Wait()
+
+	return nodes, firstErr
+}
+
+func collectTree(zkFramework core.ZKFramework, root string) ([]string, error) {
+	nodes := []string{root}
+	children, err := Ls(zkFramework, root)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		descendants, err := collectTree(zkFramework, path.Join(root, child))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, descendants...)
+	}
+	return nodes, nil
+}
+
+func getNodeACL(path string) connectionConsumer[[]zk.ACL] {
+	return func(ctx context.Context, executor core.Executor, outChan chan []zk.ACL) error {
+		nodeACL, _, err := executor.GetACL(ctx, path)
+		if err != nil {
+			return err
+		}
+		outChan <- nodeACL
+		return nil
+	}
+}
+
+func setNodeACL(path string, nodeACL []zk.ACL) connectionConsumer[bool] {
+	return func(ctx context.Context, executor core.Executor, outChan chan bool) error {
+		_, err := executor.SetACL(ctx, path, nodeACL, -1)
+		if err != nil {
+			return err
+		}
+		outChan <- true
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package operation_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestSetACLRecursive(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for _, nodeName := range []string{path.Join(root, "a", "b"), path.Join(root, "c")} {
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+	readOnly := zk.WorldACL(zk.PermRead | zk.PermAdmin)
+
+	dryRun := operation.NewSetACLOptionsBuilder().WithDryRun(true).Build()
+	nodes, err := operation.SetACLRecursive(zkFramework, root, readOnly, dryRun)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if len(nodes) != 4 {
+		t.Errorf("expected 4 nodes, got %v", nodes)
+	}
+	nodeACL, err := operation.GetACL(zkFramework, path.Join(root, "c"))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if nodeACL[0].Perms != zk.PermAll {
+		t.Errorf("expected dry run to leave ACL unchanged, got %v", nodeACL)
+	}
+
+	progressCalls := 0
+	opts := operation.NewSetACLOptionsBuilder().
+		WithConcurrency(2).
+		WithProgress(func(string, int, int) { progressCalls++ }).
+		Build()
+	if _, err := operation.SetACLRecursive(zkFramework, root, readOnly, opts); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if progressCalls != 4 {
+		t.Errorf("expected 4 progress calls, got %d", progressCalls)
+	}
+	nodeACL, err = operation.GetACL(zkFramework, path.Join(root, "a", "b"))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if nodeACL[0].Perms != zk.PermRead|zk.PermAdmin {
+		t.Errorf("expected ACL to be updated, got %v", nodeACL)
+	}
+}
+
+func TestCreateWithSubtreePolicy(t *testing.T) {
+	provider, err := acl.NewSubtreeProvider(zk.WorldACL(zk.PermAll))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), framework.WithACLProvider(provider))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.StartAndWait(10 * time.Second); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	policy := acl.Policy{DefaultACL: zk.WorldACL(zk.PermRead | zk.PermCreate | zk.PermAdmin)}
+	if err := provider.SetSubtreePolicy(path.Join(zkFramework.Namespace(), root), policy); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	nodeName := path.Join(root, "a", "b")
+	if err := operation.Create(zkFramework, nodeName); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	for _, name := range []string{path.Join(root, "a"), nodeName} {
+		nodeACL, err := operation.GetACL(zkFramework, name)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if nodeACL[0].Perms != policy.DefaultACL[0].Perms {
+			t.Errorf("expected ACL %v, got %v", policy.DefaultACL, nodeACL)
+		}
+	}
+}
+
+func TestCreateWithACLProvider(t *testing.T) {
+	provider, err := acl.NewSubtreeProvider(zk.WorldACL(zk.PermAll))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), framework.WithACLProvider(provider))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.StartAndWait(10 * time.Second); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	secured := zk.WorldACL(zk.PermRead | zk.PermCreate | zk.PermAdmin)
+	if err := provider.SetSubtreeACL(path.Join(zkFramework.Namespace(), root, "secured"), secured); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	for nodeName, expected := range map[string]int32{
+		path.Join(root, "open"):            zk.PermAll,
+		path.Join(root, "secured", "node"): secured[0].Perms,
+	} {
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		nodeACL, err := operation.GetACL(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if nodeACL[0].Perms != expected {
+			t.Errorf("%s: expected perms %d, got %v", nodeName, expected, nodeACL)
+		}
+	}
+
+	nodeACL, err := operation.GetACL(zkFramework, path.Join(root, "secured"))
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if nodeACL[0].Perms != secured[0].Perms {
+		t.Errorf("expected the parent to get the subtree ACL, got %v", nodeACL)
+	}
+}
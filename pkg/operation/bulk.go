@@ -0,0 +1,152 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/core"
+)
+
+const defaultBulkConcurrency = 8
+
+/*
+BulkOperation is an operation queued in a bulk, it should give up when the context is cancelled.
+*/
+type BulkOperation func(ctx context.Context, zkFramework core.ZKFramework) error
+
+type bulkEntry struct {
+	description string
+	operation   BulkOperation
+}
+
+/*
+BulkBuilder queues heterogeneous operations run concurrently against a framework.
+*/
+type BulkBuilder struct {
+	ctx         context.Context
+	zkFramework core.ZKFramework
+	concurrency int
+	collectAll  bool
+	entries     []bulkEntry
+}
+
+/*
+Bulk creates a BulkBuilder running the queued operations against the framework, at most 8 at a time and stopping at the first error by default.
+*/
+func Bulk(ctx context.Context, zkFramework core.ZKFramework) BulkBuilder {
+	return BulkBuilder{
+		ctx:         ctx,
+		zkFramework: zkFramework,
+		concurrency: defaultBulkConcurrency,
+	}
+}
+
+/*
+WithConcurrency sets the maximum number of operations running at the same time.
+*/
+func (b BulkBuilder) WithConcurrency(concurrency int) BulkBuilder {
+	b.concurrency = max(concurrency, 1)
+	return b
+}
+
+/*
+WithCollectAllErrors runs every queued operation regardless of the failures, instead of cancelling the remaining ones at the first error.
+*/
+func (b BulkBuilder) WithCollectAllErrors() BulkBuilder {
+	b.collectAll = true
+	return b
+}
+
+/*
+Queue queues an operation, the description prefixes its error.
+*/
+func (b BulkBuilder) Queue(description string, operation BulkOperation) BulkBuilder {
+	b.entries = append(slices.Clip(b.entries), bulkEntry{description: description, operation: operation})
+	return b
+}
+
+/*
+Create queues the creation of a node with the given options.
+*/
+func (b BulkBuilder) Create(nodeName string, options CreateOptions) BulkBuilder {
+	return b.Queue("create "+nodeName, func(_ context.Context, zkFramework core.ZKFramework) error {
+		return CreateWithOptions(zkFramework, nodeName, options)
+	})
+}
+
+/*
+Update queues the update of the data of a node.
+*/
+func (b BulkBuilder) Update(nodeName string, data []byte) BulkBuilder {
+	return b.Queue("update "+nodeName, func(_ context.Context, zkFramework core.ZKFramework) error {
+		_, err := Update(zkFramework, nodeName, data)
+		return err
+	})
+}
+
+/*
+Delete queues the deletion of a node.
+*/
+func (b BulkBuilder) Delete(nodeName string) BulkBuilder {
+	return b.Queue("delete "+nodeName, func(_ context.Context, zkFramework core.ZKFramework) error {
+		return Delete(zkFramework, nodeName)
+	})
+}
+
+/*
+Run runs the queued operations and waits for them to complete.
+
+It returns the first error, the remaining operations being cancelled, or all the errors joined when collecting all errors. The operations not started because of the cancellation report the context error.
+*/
+func (b BulkBuilder) Run() error {
+	b.zkFramework.Logger().Debug("running operations in bulk", "operations", len(b.entries))
+
+	ctx, cancel := context.WithCancel(b.ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	slots := make(chan struct{}, b.concurrency)
+	for _, entry := range b.entries {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("%s: %w", entry.description, ctx.Err()))
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := entry.operation(ctx, b.zkFramework); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", entry.description, err))
+				mu.Unlock()
+				if !b.collectAll {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return nil
+	}
+	if !b.collectAll {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
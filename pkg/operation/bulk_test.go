@@ -0,0 +1,125 @@
+package operation_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestBulk(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Bulk create, update and delete", func(t *testing.T) {
+		t.Log("Bulk create, update and delete")
+		root := uuid.New().String()
+		if err := operation.Create(zkFramework, root); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		bulk := operation.Bulk(context.Background(), zkFramework).WithConcurrency(4)
+		for _, child := range []string{"a", "b", "c", "d", "e"} {
+			bulk = bulk.Create(root+"/"+child, operation.NewCreateOptionsBuilder().WithData([]byte(child)).Build())
+		}
+		if err := bulk.Run(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		err := operation.Bulk(context.Background(), zkFramework).
+			Update(root+"/a", []byte("updated")).
+			Delete(root + "/b").
+			Run()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		children, err := operation.Ls(zkFramework, root)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(children) != 4 {
+			t.Errorf("expected 4 children, got %v", children)
+		}
+		data, err := operation.Get(zkFramework, root+"/a")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if string(data) != "updated" {
+			t.Errorf("expected updated, got %s", data)
+		}
+	})
+
+	t.Run("Bulk stops at the first error", func(t *testing.T) {
+		t.Log("Bulk stops at the first error")
+		var run atomic.Int32
+		bulk := operation.Bulk(context.Background(), zkFramework).
+			WithConcurrency(1).
+			Delete(uuid.New().String())
+		for range 5 {
+			bulk = bulk.Queue("count", func(ctx context.Context, _ core.ZKFramework) error {
+				run.Add(1)
+				return nil
+			})
+		}
+		err := bulk.Run()
+		if !errors.Is(err, zk.ErrNoNode) {
+			t.Errorf("expected %v, got %v", zk.ErrNoNode, err)
+		}
+		if run.Load() != 0 {
+			t.Errorf("expected the remaining operations to be cancelled, %d ran", run.Load())
+		}
+	})
+
+	t.Run("Bulk collects all errors", func(t *testing.T) {
+		t.Log("Bulk collects all errors")
+		var run atomic.Int32
+		bulk := operation.Bulk(context.Background(), zkFramework).
+			WithCollectAllErrors().
+			Delete(uuid.New().String()).
+			Queue("failing", func(ctx context.Context, _ core.ZKFramework) error {
+				return errors.New("failing")
+			})
+		for range 5 {
+			bulk = bulk.Queue("count", func(ctx context.Context, _ core.ZKFramework) error {
+				run.Add(1)
+				return nil
+			})
+		}
+		err := bulk.Run()
+		if !errors.Is(err, zk.ErrNoNode) || len(err.(interface{ Unwrap() []error }).Unwrap()) != 2 {
+			t.Errorf("expected both errors, got %v", err)
+		}
+		if run.Load() != 5 {
+			t.Errorf("expected all the operations to run, %d ran", run.Load())
+		}
+	})
+
+	t.Run("Bulk honours the context", func(t *testing.T) {
+		t.Log("Bulk honours the context")
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := operation.Bulk(ctx, zkFramework).
+			WithConcurrency(1).
+			Queue("slow", func(ctx context.Context, _ core.ZKFramework) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}).
+			Queue("never", func(ctx context.Context, _ core.ZKFramework) error {
+				return nil
+			}).
+			Run()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+}
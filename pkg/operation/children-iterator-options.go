@@ -0,0 +1,90 @@
+package operation
+
+/*
+ChildrenOrder is the order in which a ChildrenIterator returns the children.
+*/
+type ChildrenOrder int
+
+const (
+	// Lexicographic returns the children sorted by name.
+	Lexicographic ChildrenOrder = iota
+	// SequenceNumber returns the children sorted by their trailing sequence number, children without one come last by name.
+	SequenceNumber
+)
+
+/*
+ChildrenIteratorOptions represents the options of a ChildrenIterator.
+*/
+type ChildrenIteratorOptions struct {
+	// Order is the order of the returned children.
+	Order ChildrenOrder
+	// Prefix, when not empty, skips the children whose name does not start with it.
+	Prefix string
+	// Suffix, when not empty, skips the children whose name does not end with it.
+	Suffix string
+	// Limit is the maximum number of returned children, zero means no limit.
+	Limit int
+}
+
+/*
+ChildrenIteratorOptionsBuilder is a builder for ChildrenIteratorOptions.
+*/
+type ChildrenIteratorOptionsBuilder struct {
+	order  ChildrenOrder
+	prefix string
+	suffix string
+	limit  int
+}
+
+/*
+NewChildrenIteratorOptionsBuilder creates a new ChildrenIteratorOptionsBuilder, returning every child in lexicographic order.
+*/
+func NewChildrenIteratorOptionsBuilder() ChildrenIteratorOptionsBuilder {
+	return ChildrenIteratorOptionsBuilder{
+		order: Lexicographic,
+	}
+}
+
+/*
+WithOrder sets the order of the returned children.
+*/
+func (b ChildrenIteratorOptionsBuilder) WithOrder(order ChildrenOrder) ChildrenIteratorOptionsBuilder {
+	b.order = order
+	return b
+}
+
+/*
+WithPrefix sets the prefix the name of the returned children starts with.
+*/
+func (b ChildrenIteratorOptionsBuilder) WithPrefix(prefix string) ChildrenIteratorOptionsBuilder {
+	b.prefix = prefix
+	return b
+}
+
+/*
+WithSuffix sets the suffix the name of the returned children ends with.
+*/
+func (b ChildrenIteratorOptionsBuilder) WithSuffix(suffix string) ChildrenIteratorOptionsBuilder {
+	b.suffix = suffix
+	return b
+}
+
+/*
+WithLimit sets the maximum number of returned children.
+*/
+func (b ChildrenIteratorOptionsBuilder) WithLimit(limit int) ChildrenIteratorOptionsBuilder {
+	b.limit = limit
+	return b
+}
+
+/*
+Build builds the ChildrenIteratorOptions.
+*/
+func (b ChildrenIteratorOptionsBuilder) Build() ChildrenIteratorOptions {
+	return ChildrenIteratorOptions{
+		Order:  b.order,
+		Prefix: b.prefix,
+		Suffix: b.suffix,
+		Limit:  b.limit,
+	}
+}
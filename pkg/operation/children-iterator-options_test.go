@@ -0,0 +1,43 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestDefaultChildrenIteratorOptionsBuilder(t *testing.T) {
+	opts := operation.NewChildrenIteratorOptionsBuilder().Build()
+
+	if opts.Order != operation.Lexicographic {
+		t.Errorf("Expected Order to be %v, got %v", operation.Lexicographic, opts.Order)
+	}
+	if opts.Prefix != "" || opts.Suffix != "" {
+		t.Errorf("Expected no filters, got prefix %q and suffix %q", opts.Prefix, opts.Suffix)
+	}
+	if opts.Limit != 0 {
+		t.Errorf("Expected Limit to be 0, got %d", opts.Limit)
+	}
+}
+
+func TestChildrenIteratorOptionsBuilder(t *testing.T) {
+	opts := operation.NewChildrenIteratorOptionsBuilder().
+		WithOrder(operation.SequenceNumber).
+		WithPrefix("lock-").
+		WithSuffix("-x").
+		WithLimit(5).
+		Build()
+
+	if opts.Order != operation.SequenceNumber {
+		t.Errorf("Expected Order to be %v, got %v", operation.SequenceNumber, opts.Order)
+	}
+	if opts.Prefix != "lock-" {
+		t.Errorf("Expected Prefix to be lock-, got %s", opts.Prefix)
+	}
+	if opts.Suffix != "-x" {
+		t.Errorf("Expected Suffix to be -x, got %s", opts.Suffix)
+	}
+	if opts.Limit != 5 {
+		t.Errorf("Expected Limit to be 5, got %d", opts.Limit)
+	}
+}
@@ -0,0 +1,141 @@
+package operation
+
+import (
+	"container/heap"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/morphy76/zk/pkg/core"
+)
+
+const sequenceSuffixLength = 10
+
+/*
+ChildrenIterator returns the children of a node one at a time, in order and filtered.
+
+The children are listed once, then ordered lazily with a heap: consuming the first k of n children costs O(n + k log n) instead of sorting the whole list.
+*/
+type ChildrenIterator struct {
+	framework core.ZKFramework
+	parent    string
+	children  childrenHeap
+	remaining int
+	current   string
+}
+
+/*
+NewChildrenIterator lists the children of the node at the given path and returns an iterator over them.
+*/
+func NewChildrenIterator(zkFramework core.ZKFramework, nodeName string, options ChildrenIteratorOptions) (*ChildrenIterator, error) {
+	children, err := Ls(zkFramework, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := children[:0]
+	for _, child := range children {
+		if strings.HasPrefix(child, options.Prefix) && strings.HasSuffix(child, options.Suffix) {
+			filtered = append(filtered, child)
+		}
+	}
+
+	remaining := len(filtered)
+	if options.Limit > 0 && options.Limit < remaining {
+		remaining = options.Limit
+	}
+
+	it := &ChildrenIterator{
+		framework: zkFramework,
+		parent:    nodeName,
+		children:  childrenHeap{names: filtered, order: options.Order},
+		remaining: remaining,
+	}
+	heap.Init(&it.children)
+	return it, nil
+}
+
+/*
+Next advances to the next child, returning false once the children or the limit are exhausted.
+*/
+func (it *ChildrenIterator) Next() bool {
+	if it.remaining == 0 {
+		it.current = ""
+		return false
+	}
+	it.remaining--
+	it.current = heap.Pop(&it.children).(string)
+	return true
+}
+
+/*
+Name returns the name of the current child.
+*/
+func (it *ChildrenIterator) Name() string {
+	return it.current
+}
+
+/*
+Path returns the path of the current child, relative to the namespace.
+*/
+func (it *ChildrenIterator) Path() string {
+	return path.Join(it.parent, it.current)
+}
+
+/*
+Data gets the data of the current child.
+*/
+func (it *ChildrenIterator) Data() ([]byte, error) {
+	return Get(it.framework, it.Path())
+}
+
+/*
+SequenceOf returns the trailing sequence number of a sequential node name, or false when the name has none.
+*/
+func SequenceOf(name string) (int64, bool) {
+	if len(name) < sequenceSuffixLength {
+		return 0, false
+	}
+	sequence, err := strconv.ParseInt(name[len(name)-sequenceSuffixLength:], 10, 64)
+	if err != nil || sequence < 0 {
+		return 0, false
+	}
+	return sequence, true
+}
+
+type childrenHeap struct {
+	names []string
+	order ChildrenOrder
+}
+
+func (h childrenHeap) Len() int {
+	return len(h.names)
+}
+
+func (h childrenHeap) Less(i, j int) bool {
+	if h.order == SequenceNumber {
+		iSequence, iOk := SequenceOf(h.names[i])
+		jSequence, jOk := SequenceOf(h.names[j])
+		if iOk != jOk {
+			return iOk
+		}
+		if iOk && iSequence != jSequence {
+			return iSequence < jSequence
+		}
+	}
+	return h.names[i] < h.names[j]
+}
+
+func (h childrenHeap) Swap(i, j int) {
+	h.names[i], h.names[j] = h.names[j], h.names[i]
+}
+
+func (h *childrenHeap) Push(x any) {
+	h.names = append(h.names, x.(string))
+}
+
+func (h *childrenHeap) Pop() any {
+	last := h.names[len(h.names)-1]
+	h.names = h.names[:len(h.names)-1]
+	return last
+}
@@ -0,0 +1,99 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestSequenceOf(t *testing.T) {
+	if sequence, ok := operation.SequenceOf("lock-0000000042"); !ok || sequence != 42 {
+		t.Errorf("expected sequence 42, got %d, %v", sequence, ok)
+	}
+	if _, ok := operation.SequenceOf("config"); ok {
+		t.Errorf("expected no sequence")
+	}
+}
+
+func TestChildrenIterator(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	options := operation.NewCreateOptionsBuilder().WithMode(zk.FlagSequence).Build()
+	for _, prefix := range []string{"b-", "a-", "b-", "a-"} {
+		if err := operation.CreateWithOptions(zkFramework, root+"/"+prefix, options); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+	}
+	if err := operation.Create(zkFramework, root+"/config"); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	collect := func(options operation.ChildrenIteratorOptions) []string {
+		it, err := operation.NewChildrenIterator(zkFramework, root, options)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		names := []string{}
+		for it.Next() {
+			names = append(names, it.Name())
+		}
+		return names
+	}
+	assertNames := func(expected []string, actual []string) {
+		if len(expected) != len(actual) {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+		for i := range expected {
+			if expected[i] != actual[i] {
+				t.Errorf("expected %v, got %v", expected, actual)
+				return
+			}
+		}
+	}
+
+	t.Run("Lexicographic", func(t *testing.T) {
+		names := collect(operation.NewChildrenIteratorOptionsBuilder().Build())
+		assertNames([]string{"a-0000000001", "a-0000000003", "b-0000000000", "b-0000000002", "config"}, names)
+	})
+
+	t.Run("SequenceNumber", func(t *testing.T) {
+		names := collect(operation.NewChildrenIteratorOptionsBuilder().WithOrder(operation.SequenceNumber).Build())
+		assertNames([]string{"b-0000000000", "a-0000000001", "b-0000000002", "a-0000000003", "config"}, names)
+	})
+
+	t.Run("Filters and limit", func(t *testing.T) {
+		names := collect(operation.NewChildrenIteratorOptionsBuilder().
+			WithOrder(operation.SequenceNumber).
+			WithPrefix("a-").
+			WithSuffix("3").
+			Build())
+		assertNames([]string{"a-0000000003"}, names)
+
+		names = collect(operation.NewChildrenIteratorOptionsBuilder().WithPrefix("b-").WithLimit(1).Build())
+		assertNames([]string{"b-0000000000"}, names)
+	})
+
+	t.Run("Data", func(t *testing.T) {
+		it, err := operation.NewChildrenIterator(zkFramework, root, operation.NewChildrenIteratorOptionsBuilder().WithPrefix("config").Build())
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if !it.Next() || it.Path() != root+"/config" {
+			t.Fatalf("expected the config child, got %s", it.Path())
+		}
+		if _, err := it.Data(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if it.Next() {
+			t.Errorf("expected no further children")
+		}
+	})
+}
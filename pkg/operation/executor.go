@@ -0,0 +1,125 @@
+package operation
+
+import (
+	"context"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+Executor returns the executor of the framework checking each request against the access policy bound to the framework, see SetAccessPolicy,
+for the packages running raw requests on absolute paths, e.g. the recipes.
+*/
+func Executor(zkFramework core.ZKFramework) core.Executor {
+	return policyExecutor{Executor: zkFramework.Executor(), zkFramework: zkFramework}
+}
+
+type policyExecutor struct {
+	core.Executor
+	zkFramework core.ZKFramework
+}
+
+func (e policyExecutor) Get(ctx context.Context, actualPath string) ([]byte, *zk.Stat, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, nil, err
+	}
+	return e.Executor.Get(ctx, actualPath)
+}
+
+func (e policyExecutor) GetW(ctx context.Context, actualPath string) ([]byte, *zk.Stat, <-chan zk.Event, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, nil, nil, err
+	}
+	return e.Executor.GetW(ctx, actualPath)
+}
+
+func (e policyExecutor) Set(ctx context.Context, actualPath string, data []byte, version int32) (*zk.Stat, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionWrite); err != nil {
+		return nil, err
+	}
+	return e.Executor.Set(ctx, actualPath, data, version)
+}
+
+func (e policyExecutor) Create(ctx context.Context, actualPath string, data []byte, flags int32, acl []zk.ACL) (string, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionCreate); err != nil {
+		return "", err
+	}
+	return e.Executor.Create(ctx, actualPath, data, flags, acl)
+}
+
+func (e policyExecutor) Delete(ctx context.Context, actualPath string, version int32) error {
+	if err := authorize(e.zkFramework, actualPath, PermissionDelete); err != nil {
+		return err
+	}
+	return e.Executor.Delete(ctx, actualPath, version)
+}
+
+func (e policyExecutor) Children(ctx context.Context, actualPath string) ([]string, *zk.Stat, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, nil, err
+	}
+	return e.Executor.Children(ctx, actualPath)
+}
+
+func (e policyExecutor) ChildrenW(ctx context.Context, actualPath string) ([]string, *zk.Stat, <-chan zk.Event, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, nil, nil, err
+	}
+	return e.Executor.ChildrenW(ctx, actualPath)
+}
+
+func (e policyExecutor) Exists(ctx context.Context, actualPath string) (bool, *zk.Stat, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return false, nil, err
+	}
+	return e.Executor.Exists(ctx, actualPath)
+}
+
+func (e policyExecutor) ExistsW(ctx context.Context, actualPath string) (bool, *zk.Stat, <-chan zk.Event, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return false, nil, nil, err
+	}
+	return e.Executor.ExistsW(ctx, actualPath)
+}
+
+func (e policyExecutor) GetACL(ctx context.Context, actualPath string) ([]zk.ACL, *zk.Stat, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, nil, err
+	}
+	return e.Executor.GetACL(ctx, actualPath)
+}
+
+func (e policyExecutor) SetACL(ctx context.Context, actualPath string, acl []zk.ACL, version int32) (*zk.Stat, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionAdmin); err != nil {
+		return nil, err
+	}
+	return e.Executor.SetACL(ctx, actualPath, acl, version)
+}
+
+func (e policyExecutor) Multi(ctx context.Context, ops ...any) ([]zk.MultiResponse, error) {
+	for _, op := range ops {
+		var err error
+		switch request := op.(type) {
+		case *zk.CreateRequest:
+			err = authorize(e.zkFramework, request.Path, PermissionCreate)
+		case *zk.DeleteRequest:
+			err = authorize(e.zkFramework, request.Path, PermissionDelete)
+		case *zk.SetDataRequest:
+			err = authorize(e.zkFramework, request.Path, PermissionWrite)
+		case *zk.CheckVersionRequest:
+			err = authorize(e.zkFramework, request.Path, PermissionRead)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e.Executor.Multi(ctx, ops...)
+}
+
+func (e policyExecutor) Sync(ctx context.Context, actualPath string) (string, error) {
+	if err := authorize(e.zkFramework, actualPath, PermissionRead); err != nil {
+		return "", err
+	}
+	return e.Executor.Sync(ctx, actualPath)
+}
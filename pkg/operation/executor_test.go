@@ -0,0 +1,33 @@
+package operation_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestExecutorAccessPolicy(t *testing.T) {
+	policy := operation.AccessPolicy{
+		"reader": {
+			{Pattern: "**", Allow: operation.PermissionRead},
+		},
+	}
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), operation.WithAccessPolicy(policy, "reader"))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	executor := operation.Executor(zkFramework)
+	ctx := context.Background()
+
+	if _, err := executor.Create(ctx, "/node", []byte{}, 0, zk.WorldACL(zk.PermAll)); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+	if _, err := executor.Multi(ctx, &zk.CheckVersionRequest{Path: "/node", Version: 0}, &zk.DeleteRequest{Path: "/node", Version: -1}); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+}
@@ -0,0 +1,78 @@
+package operation
+
+import (
+	"path"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+Find walks the subtree at the given path, root included, streaming the paths of the nodes satisfying the matcher.
+
+The paths channel is closed when the walk completes, the errors channel receives the error stopping the walk, if any, and is closed afterwards.
+*/
+func Find(zkFramework core.ZKFramework, root string, matcher Matcher) (<-chan string, <-chan error) {
+	zkFramework.Logger().Debug("finding nodes", "path", path.Join(zkFramework.Namespace(), root))
+
+	paths := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(paths)
+
+		if _, err := path.Match(matcher.NamePattern, ""); err != nil {
+			errs <- operr.ErrInvalidPattern
+			return
+		}
+		if err := find(zkFramework, root, 0, matcher, paths); err != nil {
+			errs <- err
+		}
+	}()
+
+	return paths, errs
+}
+
+func find(zkFramework core.ZKFramework, nodeName string, depth int, matcher Matcher, paths chan<- string) error {
+	matches, err := matchNode(zkFramework, nodeName, matcher)
+	if err != nil {
+		return err
+	}
+	if matches {
+		paths <- nodeName
+	}
+
+	if matcher.MaxDepth > 0 && depth >= matcher.MaxDepth {
+		return nil
+	}
+
+	children, err := Ls(zkFramework, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := find(zkFramework, path.Join(nodeName, child), depth+1, matcher, paths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchNode(zkFramework core.ZKFramework, nodeName string, matcher Matcher) (bool, error) {
+	if matcher.NamePattern != "" {
+		matches, _ := path.Match(matcher.NamePattern, path.Base(nodeName))
+		if !matches {
+			return false, nil
+		}
+	}
+
+	if matcher.DataPredicate == nil {
+		return true, nil
+	}
+	data, err := Get(zkFramework, nodeName)
+	if err != nil {
+		return false, err
+	}
+	return matcher.DataPredicate(data), nil
+}
@@ -0,0 +1,75 @@
+package operation_test
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestFind(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for nodeName, data := range map[string]string{
+		root + "/a.json":          `{"enabled":true}`,
+		root + "/b.json":          `{"enabled":false}`,
+		root + "/c/d.json":        `{"enabled":true}`,
+		root + "/c/e/f.json":      `{"enabled":true}`,
+		root + "/c/e/not-matched": `{"enabled":true}`,
+	} {
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte(data)).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+
+	collect := func(matcher operation.Matcher) ([]string, error) {
+		paths, errs := operation.Find(zkFramework, root, matcher)
+		found := []string{}
+		for nodeName := range paths {
+			found = append(found, nodeName)
+		}
+		slices.Sort(found)
+		return found, <-errs
+	}
+
+	t.Run("Find by name and depth", func(t *testing.T) {
+		t.Log("Find by name and depth")
+		found, err := collect(operation.NewMatcherBuilder().WithNamePattern("*.json").WithMaxDepth(2).Build())
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		expected := []string{root + "/a.json", root + "/b.json", root + "/c/d.json"}
+		if !slices.Equal(found, expected) {
+			t.Errorf("expected %v, got %v", expected, found)
+		}
+	})
+
+	t.Run("Find by data", func(t *testing.T) {
+		t.Log("Find by data")
+		enabled := func(data []byte) bool { return bytes.Contains(data, []byte(`"enabled":true`)) }
+		found, err := collect(operation.NewMatcherBuilder().WithNamePattern("*.json").WithDataPredicate(enabled).Build())
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		expected := []string{root + "/a.json", root + "/c/d.json", root + "/c/e/f.json"}
+		if !slices.Equal(found, expected) {
+			t.Errorf("expected %v, got %v", expected, found)
+		}
+	})
+
+	t.Run("Find with an invalid pattern", func(t *testing.T) {
+		t.Log("Find with an invalid pattern")
+		if _, err := collect(operation.NewMatcherBuilder().WithNamePattern("[").Build()); !operr.IsInvalidPattern(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrInvalidPattern, err)
+		}
+	})
+}
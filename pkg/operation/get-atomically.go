@@ -0,0 +1,83 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation/operr"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+/*
+GetAtomically gets the nodes at the given paths as a mutually consistent snapshot, retrying with the default policy when they change while being read.
+
+The nodes are read one by one, then a multi transaction made of version checks confirms none of them changed in between. The data is returned in the order of the paths.
+*/
+func GetAtomically(zkFramework core.ZKFramework, nodeNames ...string) ([][]byte, error) {
+	policy := retry.NewMaxAttempts(retry.NewExponentialBackoff(defaultConflictBaseDelay, defaultConflictMaxDelay), defaultConflictAttempts)
+	return GetAtomicallyWithPolicy(zkFramework, policy, nodeNames...)
+}
+
+/*
+GetAtomicallyWithPolicy gets the nodes at the given paths as a mutually consistent snapshot, retrying as decided by the given policy when they change while being read.
+*/
+func GetAtomicallyWithPolicy(zkFramework core.ZKFramework, policy retry.Policy, nodeNames ...string) ([][]byte, error) {
+	actualPaths := make([]string, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+		if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+			return nil, err
+		}
+		actualPaths = append(actualPaths, actualPath)
+	}
+	zkFramework.Logger().Debug("getting nodes atomically", "paths", actualPaths)
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		outChan, errChan := execute(zkFramework, getNodesConsistently(actualPaths))
+
+		var err error
+		select {
+		case out := <-outChan:
+			return out, nil
+		case err = <-errChan:
+		}
+		if !errors.Is(err, zk.ErrBadVersion) {
+			return nil, err
+		}
+
+		delay, ok := policy.NextDelay(attempt, time.Since(start))
+		if !ok {
+			return nil, operr.ErrTooManyConflicts
+		}
+		<-time.After(delay)
+	}
+}
+
+func getNodesConsistently(actualPaths []string) connectionConsumer[[][]byte] {
+	return func(ctx context.Context, executor core.Executor, outChan chan [][]byte) error {
+		data := make([][]byte, 0, len(actualPaths))
+		checks := make([]interface{}, 0, len(actualPaths))
+		for _, actualPath := range actualPaths {
+			nodeData, stat, err := executor.Get(ctx, actualPath)
+			if err != nil {
+				return err
+			}
+			data = append(data, nodeData)
+			checks = append(checks, &zk.CheckVersionRequest{Path: actualPath, Version: stat.Version})
+		}
+
+		if len(checks) > 1 {
+			if err := multi(ctx, executor, checks...); err != nil {
+				return err
+			}
+		}
+		outChan <- data
+		return nil
+	}
+}
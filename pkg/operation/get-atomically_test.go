@@ -0,0 +1,41 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestGetAtomically(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for _, name := range []string{"host", "port"} {
+		options := operation.NewCreateOptionsBuilder().WithData([]byte(name)).Build()
+		if err := operation.CreateWithOptions(zkFramework, root+"/"+name, options); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+	}
+
+	t.Run("Snapshot", func(t *testing.T) {
+		data, err := operation.GetAtomically(zkFramework, root+"/port", root+"/host")
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if len(data) != 2 || string(data[0]) != "port" || string(data[1]) != "host" {
+			t.Errorf("unexpected snapshot %q", data)
+		}
+	})
+
+	t.Run("Unknown node", func(t *testing.T) {
+		if _, err := operation.GetAtomically(zkFramework, root+"/host", root+"/missing"); err == nil {
+			t.Errorf("expected an error reading a missing node")
+		}
+	})
+}
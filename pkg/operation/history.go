@@ -0,0 +1,221 @@
+package operation
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+HistoryRoot is the node below which the snapshots of the nodes with history enabled are kept, mirroring their actual paths.
+*/
+const HistoryRoot = "/zk-history"
+
+const snapshotPrefix = "snapshot-"
+
+/*
+Snapshot is a value written to a node with history enabled.
+*/
+type Snapshot struct {
+	// ID identifies the snapshot among the ones of the same node, IDs sort in write order.
+	ID string
+	// Data is the value written to the node.
+	Data []byte
+	// Created is the time the value was written.
+	Created time.Time
+}
+
+type historyKey struct{}
+
+type history struct {
+	retentions map[string]int
+	lock       sync.RWMutex
+}
+
+func historyOf(zkFramework core.ZKFramework) *history {
+	return zkFramework.Extension(historyKey{}, func() any {
+		return &history{retentions: make(map[string]int)}
+	}).(*history)
+}
+
+/*
+WithHistory is the framework option recording the history of the nodes at or below the given path prefix, see EnableHistory.
+*/
+func WithHistory(prefix string, retention int) framework.Option {
+	return framework.WithExtension(func(zkFramework core.ZKFramework) {
+		EnableHistory(zkFramework, prefix, retention)
+	})
+}
+
+/*
+EnableHistory records a snapshot of every value written by Update to the nodes at or below the given path prefix, keeping the latest retention snapshots of each node.
+*/
+func EnableHistory(zkFramework core.ZKFramework, prefix string, retention int) {
+	actualPrefix := path.Join(zkFramework.Namespace(), prefix)
+
+	frameworkHistory := historyOf(zkFramework)
+	frameworkHistory.lock.Lock()
+	defer frameworkHistory.lock.Unlock()
+	frameworkHistory.retentions[actualPrefix] = retention
+}
+
+/*
+DisableHistory stops recording snapshots for the given path prefix, the snapshots already recorded are kept.
+*/
+func DisableHistory(zkFramework core.ZKFramework, prefix string) {
+	actualPrefix := path.Join(zkFramework.Namespace(), prefix)
+
+	frameworkHistory := historyOf(zkFramework)
+	frameworkHistory.lock.Lock()
+	defer frameworkHistory.lock.Unlock()
+	delete(frameworkHistory.retentions, actualPrefix)
+}
+
+/*
+History lists the snapshots of the node at the given path, oldest first.
+*/
+func History(zkFramework core.ZKFramework, nodeName string) ([]Snapshot, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("listing history of node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, err
+	}
+
+	outChan, errChan := execute(zkFramework, listSnapshots(historyPathOf(actualPath)))
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
+
+/*
+Restore writes back the value of the given snapshot to the node at the given path, the restored value is recorded as a new snapshot.
+*/
+func Restore(zkFramework core.ZKFramework, nodeName string, snapshotID string) (int32, error) {
+	snapshots, err := History(zkFramework, nodeName)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.ID == snapshotID {
+			return Update(zkFramework, nodeName, snapshot.Data)
+		}
+	}
+	return 0, operr.ErrSnapshotNotFound
+}
+
+/*
+historyRetention returns the retention of the history recorded for the node at the given path, the one of the longest enabled prefix.
+*/
+func historyRetention(zkFramework core.ZKFramework, actualPath string) (int, bool) {
+	frameworkHistory := historyOf(zkFramework)
+	frameworkHistory.lock.RLock()
+	defer frameworkHistory.lock.RUnlock()
+
+	longest, retention, found := "", 0, false
+	for prefix, prefixRetention := range frameworkHistory.retentions {
+		if isAtOrBelow(actualPath, prefix) && (!found || len(prefix) > len(longest)) {
+			longest, retention, found = prefix, prefixRetention, true
+		}
+	}
+	return retention, found
+}
+
+func historyPathOf(actualPath string) string {
+	return path.Join(HistoryRoot, actualPath)
+}
+
+func updateNodeWithHistory(logger *slog.Logger, aclProvider core.ACLProvider, actualPath string, data []byte, version int32, retention int) connectionConsumer[int32] {
+	return func(ctx context.Context, executor core.Executor, outChan chan int32) error {
+		historyPath := historyPathOf(actualPath)
+		snapshotPath := path.Join(historyPath, snapshotPrefix)
+		if err := recursivelyGrantParent(ctx, executor, aclProvider, snapshotPath); err != nil {
+			return err
+		}
+
+		responses, err := executor.Multi(ctx,
+			&zk.SetDataRequest{Path: actualPath, Data: data, Version: version},
+			&zk.CreateRequest{Path: snapshotPath, Data: data, Acl: nodeacl.DefaultFor(aclProvider, historyPath), Flags: zk.FlagSequence},
+		)
+		if err == nil {
+			err = multiError(responses)
+		}
+		if err == zk.ErrNoNode && version == -1 {
+			return coreerr.ErrUnknownNode
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := trimHistory(ctx, executor, historyPath, retention); err != nil {
+			logger.Error("error trimming history of node", "path", actualPath, "error", err)
+		}
+
+		outChan <- responses[0].Stat.Version
+		return nil
+	}
+}
+
+func trimHistory(ctx context.Context, executor core.Executor, historyPath string, retention int) error {
+	children, _, err := executor.Children(ctx, historyPath)
+	if err != nil {
+		return err
+	}
+	sort.Strings(children)
+
+	for len(children) > retention {
+		if err := executor.Delete(ctx, path.Join(historyPath, children[0]), -1); err != nil && err != zk.ErrNoNode {
+			return err
+		}
+		children = children[1:]
+	}
+	return nil
+}
+
+func listSnapshots(historyPath string) connectionConsumer[[]Snapshot] {
+	return func(ctx context.Context, executor core.Executor, outChan chan []Snapshot) error {
+		children, _, err := executor.Children(ctx, historyPath)
+		if err == zk.ErrNoNode {
+			outChan <- []Snapshot{}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		sort.Strings(children)
+
+		snapshots := make([]Snapshot, 0, len(children))
+		for _, child := range children {
+			data, stat, err := executor.Get(ctx, path.Join(historyPath, child))
+			if err == zk.ErrNoNode {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			snapshots = append(snapshots, Snapshot{
+				ID:      child,
+				Data:    data,
+				Created: time.UnixMilli(stat.Ctime),
+			})
+		}
+		outChan <- snapshots
+		return nil
+	}
+}
@@ -0,0 +1,201 @@
+package operation_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestHistory(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	prefix := uuid.New().String()
+	operation.EnableHistory(zkFramework, prefix, 2)
+	defer operation.DisableHistory(zkFramework, prefix)
+
+	t.Run("Updates are recorded with bounded retention", func(t *testing.T) {
+		t.Log("Updates are recorded with bounded retention")
+		nodeName := path.Join(prefix, uuid.New().String())
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		for _, value := range []string{"v1", "v2", "v3"} {
+			if _, err := operation.Update(zkFramework, nodeName, []byte(value)); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+
+		snapshots, err := operation.History(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(snapshots) != 2 {
+			t.Fatalf("expected 2 snapshots, got %d", len(snapshots))
+		}
+		if string(snapshots[0].Data) != "v2" || string(snapshots[1].Data) != "v3" {
+			t.Errorf("expected [v2 v3], got [%s %s]", snapshots[0].Data, snapshots[1].Data)
+		}
+
+		if _, err := operation.Restore(zkFramework, nodeName, snapshots[0].ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		data, err := operation.Get(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if string(data) != "v2" {
+			t.Errorf("expected v2, got %s", data)
+		}
+	})
+
+	t.Run("Restore an unknown snapshot", func(t *testing.T) {
+		t.Log("Restore an unknown snapshot")
+		nodeName := path.Join(prefix, uuid.New().String())
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if _, err := operation.Restore(zkFramework, nodeName, "unknown"); !operr.IsSnapshotNotFound(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrSnapshotNotFound, err)
+		}
+	})
+
+	t.Run("Nodes without history", func(t *testing.T) {
+		t.Log("Nodes without history")
+		nodeName := uuid.New().String()
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := operation.Update(zkFramework, nodeName, []byte("v1")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		snapshots, err := operation.History(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(snapshots) != 0 {
+			t.Errorf("expected no snapshots, got %d", len(snapshots))
+		}
+	})
+
+	t.Run("Update an unknown node with history", func(t *testing.T) {
+		t.Log("Update an unknown node with history")
+		nodeName := path.Join(prefix, uuid.New().String())
+
+		if _, err := operation.Update(zkFramework, nodeName, []byte("v1")); !coreerr.IsUnknownNode(err) {
+			t.Errorf("expected error %v, got %v", coreerr.ErrUnknownNode, err)
+		}
+	})
+
+	t.Run("Nested prefixes use the longest one", func(t *testing.T) {
+		t.Log("Nested prefixes use the longest one")
+		nested := path.Join(prefix, uuid.New().String())
+		operation.EnableHistory(zkFramework, nested, 1)
+		defer operation.DisableHistory(zkFramework, nested)
+
+		nodeName := path.Join(nested, uuid.New().String())
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		for _, value := range []string{"v1", "v2", "v3"} {
+			if _, err := operation.Update(zkFramework, nodeName, []byte(value)); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+
+		snapshots, err := operation.History(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(snapshots) != 1 || string(snapshots[0].Data) != "v3" {
+			t.Errorf("expected only the v3 snapshot, got %d snapshots", len(snapshots))
+		}
+	})
+
+	t.Run("History is scoped to the framework", func(t *testing.T) {
+		t.Log("History is scoped to the framework")
+		otherFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer otherFramework.Stop()
+
+		nodeName := path.Join(prefix, uuid.New().String())
+		if err := operation.Create(otherFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := operation.Update(otherFramework, nodeName, []byte("v1")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := operation.Update(zkFramework.UsingNamespace(prefix), path.Base(nodeName), []byte("v2")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		snapshots, err := operation.History(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(snapshots) != 1 || string(snapshots[0].Data) != "v2" {
+			t.Errorf("expected only the snapshot written through the framework, got %d snapshots", len(snapshots))
+		}
+	})
+}
+
+func TestHistoryOption(t *testing.T) {
+	prefix := uuid.New().String()
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), operation.WithHistory(prefix, 1))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	if err := zkFramework.Start(); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+	if err := zkFramework.WaitConnection(10 * time.Second); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	nodeName := path.Join(prefix, uuid.New().String())
+	if err := operation.Create(zkFramework, nodeName); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if _, err := operation.Update(zkFramework, nodeName, []byte("v1")); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	snapshots, err := operation.History(zkFramework, nodeName)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("expected 1 snapshot, got %d", len(snapshots))
+	}
+}
+
+func TestHistoryAccessPolicy(t *testing.T) {
+	policy := operation.AccessPolicy{
+		"guest": {},
+	}
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), operation.WithAccessPolicy(policy, "guest"))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	if _, err := operation.History(zkFramework, "node"); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+}
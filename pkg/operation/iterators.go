@@ -0,0 +1,93 @@
+package operation
+
+import (
+	"errors"
+	"iter"
+	"path"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+/*
+Children iterates over the name and the data of the children of the node at the given path, in lexicographic order.
+
+The children are listed when the iteration starts and their data is fetched lazily, one child at a time. Children deleted while iterating are skipped, any other error stops the iteration and is logged: use NewChildrenIterator to handle it.
+*/
+func Children(zkFramework core.ZKFramework, parent string) iter.Seq2[string, []byte] {
+	return func(yield func(string, []byte) bool) {
+		it, err := NewChildrenIterator(zkFramework, parent, NewChildrenIteratorOptionsBuilder().Build())
+		if err != nil {
+			zkFramework.Logger().Error("iterating children stopped", "path", parent, "error", err)
+			return
+		}
+		for name, data := range it.All() {
+			if !yield(name, data) {
+				return
+			}
+		}
+	}
+}
+
+/*
+Tree iterates depth-first over the path and the data of the nodes in the subtree at the given path, root included, children in lexicographic order.
+
+The children of a node are listed only when the iteration reaches it, so breaking out early spares the rest of the subtree. Nodes deleted while iterating are skipped, any other error stops the iteration and is logged: use Find to handle it.
+*/
+func Tree(zkFramework core.ZKFramework, root string) iter.Seq2[string, []byte] {
+	return func(yield func(string, []byte) bool) {
+		if err := walkTree(zkFramework, root, yield); err != nil && !errors.Is(err, errStopIteration) {
+			zkFramework.Logger().Error("iterating tree stopped", "path", root, "error", err)
+		}
+	}
+}
+
+/*
+All iterates over the name and the data of the remaining children, the data being fetched lazily; the iteration stops at the first error, other than a child deleted while iterating, which is logged.
+*/
+func (it *ChildrenIterator) All() iter.Seq2[string, []byte] {
+	return func(yield func(string, []byte) bool) {
+		for it.Next() {
+			data, err := it.Data()
+			if errors.Is(err, zk.ErrNoNode) {
+				continue
+			}
+			if err != nil {
+				it.framework.Logger().Error("iterating children stopped", "path", it.parent, "error", err)
+				return
+			}
+			if !yield(it.Name(), data) {
+				return
+			}
+		}
+	}
+}
+
+var errStopIteration = errors.New("iteration stopped")
+
+func walkTree(zkFramework core.ZKFramework, nodeName string, yield func(string, []byte) bool) error {
+	data, err := Get(zkFramework, nodeName)
+	if errors.Is(err, zk.ErrNoNode) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !yield(nodeName, data) {
+		return errStopIteration
+	}
+
+	it, err := NewChildrenIterator(zkFramework, nodeName, NewChildrenIteratorOptionsBuilder().Build())
+	if errors.Is(err, zk.ErrNoNode) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for it.Next() {
+		if err := walkTree(zkFramework, path.Join(nodeName, it.Name()), yield); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package operation_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestIterators(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for _, nodeName := range []string{root + "/b/d", root + "/a", root + "/b/c"} {
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte(nodeName)).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+
+	t.Run("Range over children", func(t *testing.T) {
+		t.Log("Range over children")
+		names := []string{}
+		for name, data := range operation.Children(zkFramework, root) {
+			if name == "a" && string(data) != root+"/a" {
+				t.Errorf("expected %s, got %s", root+"/a", data)
+			}
+			names = append(names, name)
+		}
+		if !slices.Equal(names, []string{"a", "b"}) {
+			t.Errorf("expected [a b], got %v", names)
+		}
+	})
+
+	t.Run("Range over tree", func(t *testing.T) {
+		t.Log("Range over tree")
+		paths := []string{}
+		for nodeName, data := range operation.Tree(zkFramework, root) {
+			if nodeName == root+"/b/d" && string(data) != root+"/b/d" {
+				t.Errorf("expected %s, got %s", root+"/b/d", data)
+			}
+			paths = append(paths, nodeName)
+		}
+		expected := []string{root, root + "/a", root + "/b", root + "/b/c", root + "/b/d"}
+		if !slices.Equal(paths, expected) {
+			t.Errorf("expected %v, got %v", expected, paths)
+		}
+	})
+
+	t.Run("Break out of tree", func(t *testing.T) {
+		t.Log("Break out of tree")
+		paths := []string{}
+		for nodeName := range operation.Tree(zkFramework, root) {
+			paths = append(paths, nodeName)
+			if nodeName == root+"/b" {
+				break
+			}
+		}
+		expected := []string{root, root + "/a", root + "/b"}
+		if !slices.Equal(paths, expected) {
+			t.Errorf("expected %v, got %v", expected, paths)
+		}
+	})
+
+	t.Run("Range over missing node", func(t *testing.T) {
+		t.Log("Range over missing node")
+		for nodeName := range operation.Tree(zkFramework, uuid.New().String()) {
+			t.Errorf("expected no node, got %s", nodeName)
+		}
+		for name := range operation.Children(zkFramework, uuid.New().String()) {
+			t.Errorf("expected no child, got %s", name)
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package operation
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+Query gets a node at the given path and evaluates the JSONPath expression over its data.
+
+The supported JSONPath subset covers the root ($), child members (.name or ['name']), array indexes ([0], negative indexes count from the end) and wildcards (.* or [*]), matching the members of an object in the order of their names.
+*/
+func Query(zkFramework core.ZKFramework, nodeName string, jsonPath string) (any, error) {
+	data, err := Get(zkFramework, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	return EvaluateJSONPath(data, jsonPath)
+}
+
+/*
+EvaluateJSONPath evaluates the JSONPath expression over the given JSON document.
+
+When the expression contains a wildcard the result is a []any holding all the matched values.
+*/
+func EvaluateJSONPath(data []byte, jsonPath string) (any, error) {
+	steps, err := parseJSONPath(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	matches := []any{document}
+	wildcard := false
+	for _, step := range steps {
+		if step == "*" {
+			wildcard = true
+		}
+		next := []any{}
+		for _, match := range matches {
+			next = append(next, applyJSONPathStep(match, step)...)
+		}
+		matches = next
+	}
+
+	if wildcard {
+		return matches, nil
+	}
+	if len(matches) == 0 {
+		return nil, operr.ErrJSONPathNotFound
+	}
+	return matches[0], nil
+}
+
+func applyJSONPathStep(value any, step string) []any {
+	switch typed := value.(type) {
+	case map[string]any:
+		if step == "*" {
+			values := make([]any, 0, len(typed))
+			for _, key := range slices.Sorted(maps.Keys(typed)) {
+				values = append(values, typed[key])
+			}
+			return values
+		}
+		if v, ok := typed[step]; ok {
+			return []any{v}
+		}
+	case []any:
+		if step == "*" {
+			return typed
+		}
+		index, err := strconv.Atoi(step)
+		if err != nil {
+			return nil
+		}
+		if index < 0 {
+			index += len(typed)
+		}
+		if index >= 0 && index < len(typed) {
+			return []any{typed[index]}
+		}
+	}
+	return nil
+}
+
+func parseJSONPath(jsonPath string) ([]string, error) {
+	if !strings.HasPrefix(jsonPath, "$") {
+		return nil, operr.ErrInvalidJSONPath
+	}
+
+	steps := []string{}
+	rest := jsonPath[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, operr.ErrInvalidJSONPath
+			}
+			steps = append(steps, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, operr.ErrInvalidJSONPath
+			}
+			step := strings.Trim(rest[1:end], "'\"")
+			if step == "" {
+				return nil, operr.ErrInvalidJSONPath
+			}
+			steps = append(steps, step)
+			rest = rest[end+1:]
+		default:
+			return nil, operr.ErrInvalidJSONPath
+		}
+	}
+	return steps, nil
+}
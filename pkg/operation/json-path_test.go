@@ -0,0 +1,95 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+const jsonDocument = `{"name":"svc","ports":[80,443],"db":{"host":"localhost","replicas":[{"id":1},{"id":2}]}}`
+
+func TestEvaluateJSONPath(t *testing.T) {
+	cases := map[string]any{
+		"$.name":                 "svc",
+		"$.ports[1]":             float64(443),
+		"$.ports[-1]":            float64(443),
+		"$['db']['host']":        "localhost",
+		"$.db.replicas[0].id":    float64(1),
+		"$.db.replicas[*].id":    []any{float64(1), float64(2)},
+		"$.ports.*":              []any{float64(80), float64(443)},
+		"$.db.replicas[*].other": []any{},
+	}
+
+	for jsonPath, expected := range cases {
+		value, err := operation.EvaluateJSONPath([]byte(jsonDocument), jsonPath)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if expectedSlice, ok := expected.([]any); ok {
+			valueSlice, ok := value.([]any)
+			if !ok || len(valueSlice) != len(expectedSlice) {
+				t.Errorf("%s: expected %v, got %v", jsonPath, expected, value)
+				continue
+			}
+			for i := range expectedSlice {
+				if valueSlice[i] != expectedSlice[i] {
+					t.Errorf("%s: expected %v, got %v", jsonPath, expected, value)
+				}
+			}
+			continue
+		}
+		if value != expected {
+			t.Errorf("%s: expected %v, got %v", jsonPath, expected, value)
+		}
+	}
+}
+
+func TestEvaluateJSONPathWildcardOrder(t *testing.T) {
+	value, err := operation.EvaluateJSONPath([]byte(`{"c":3,"a":1,"b":2}`), "$.*")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	values, ok := value.([]any)
+	if !ok || len(values) != 3 || values[0] != float64(1) || values[1] != float64(2) || values[2] != float64(3) {
+		t.Errorf("expected the members in the order of their names, got %v", value)
+	}
+}
+
+func TestEvaluateInvalidJSONPath(t *testing.T) {
+	for _, jsonPath := range []string{"name", "$..name", "$[0", "$x"} {
+		if _, err := operation.EvaluateJSONPath([]byte(jsonDocument), jsonPath); !operr.IsInvalidJSONPath(err) {
+			t.Errorf("%s: expected error %v, got %v", jsonPath, operr.ErrInvalidJSONPath, err)
+		}
+	}
+}
+
+func TestEvaluateJSONPathNotFound(t *testing.T) {
+	if _, err := operation.EvaluateJSONPath([]byte(jsonDocument), "$.missing"); !operr.IsJSONPathNotFound(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrJSONPathNotFound, err)
+	}
+}
+
+func TestQueryNode(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	nodeName := uuid.New().String()
+	opts := operation.NewCreateOptionsBuilder().WithData([]byte(jsonDocument)).Build()
+	if err := operation.CreateWithOptions(zkFramework, nodeName, opts); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	value, err := operation.Query(zkFramework, nodeName, "$.db.host")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if value != "localhost" {
+		t.Errorf("expected value to be localhost, got %v", value)
+	}
+}
@@ -0,0 +1,64 @@
+package operation
+
+/*
+Matcher represents the criteria of a Find operation, a node matches when it satisfies all of them.
+*/
+type Matcher struct {
+	// NamePattern is a glob, as in path.Match, matched against the node name, empty matches any name.
+	NamePattern string
+	// MaxDepth limits the depth of the search below the root, zero means no limit.
+	MaxDepth int
+	// DataPredicate, when not nil, is called with the data of the nodes matching the other criteria.
+	DataPredicate func(data []byte) bool
+}
+
+/*
+MatcherBuilder is a builder for Matcher.
+*/
+type MatcherBuilder struct {
+	namePattern   string
+	maxDepth      int
+	dataPredicate func(data []byte) bool
+}
+
+/*
+NewMatcherBuilder creates a new MatcherBuilder, matching every node.
+*/
+func NewMatcherBuilder() MatcherBuilder {
+	return MatcherBuilder{}
+}
+
+/*
+WithNamePattern sets the glob matched against the node name.
+*/
+func (mb MatcherBuilder) WithNamePattern(namePattern string) MatcherBuilder {
+	mb.namePattern = namePattern
+	return mb
+}
+
+/*
+WithMaxDepth sets the maximum depth of the search below the root.
+*/
+func (mb MatcherBuilder) WithMaxDepth(maxDepth int) MatcherBuilder {
+	mb.maxDepth = maxDepth
+	return mb
+}
+
+/*
+WithDataPredicate sets the predicate on the node data.
+*/
+func (mb MatcherBuilder) WithDataPredicate(dataPredicate func(data []byte) bool) MatcherBuilder {
+	mb.dataPredicate = dataPredicate
+	return mb
+}
+
+/*
+Build builds the Matcher.
+*/
+func (mb MatcherBuilder) Build() Matcher {
+	return Matcher{
+		NamePattern:   mb.namePattern,
+		MaxDepth:      mb.maxDepth,
+		DataPredicate: mb.dataPredicate,
+	}
+}
@@ -0,0 +1,39 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestDefaultMatcherBuilder(t *testing.T) {
+	matcher := operation.NewMatcherBuilder().Build()
+
+	if matcher.NamePattern != "" {
+		t.Errorf("Expected NamePattern to be empty, got %s", matcher.NamePattern)
+	}
+	if matcher.MaxDepth != 0 {
+		t.Errorf("Expected MaxDepth to be 0, got %d", matcher.MaxDepth)
+	}
+	if matcher.DataPredicate != nil {
+		t.Error("Expected DataPredicate to be nil")
+	}
+}
+
+func TestMatcherBuilder(t *testing.T) {
+	matcher := operation.NewMatcherBuilder().
+		WithNamePattern("*.json").
+		WithMaxDepth(2).
+		WithDataPredicate(func(data []byte) bool { return len(data) > 0 }).
+		Build()
+
+	if matcher.NamePattern != "*.json" {
+		t.Errorf("Expected NamePattern to be *.json, got %s", matcher.NamePattern)
+	}
+	if matcher.MaxDepth != 2 {
+		t.Errorf("Expected MaxDepth to be 2, got %d", matcher.MaxDepth)
+	}
+	if matcher.DataPredicate == nil || !matcher.DataPredicate([]byte("a")) {
+		t.Error("Expected DataPredicate to be set")
+	}
+}
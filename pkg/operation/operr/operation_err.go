@@ -3,7 +3,15 @@ Package operr provides operation errors.
 */
 package operr
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/breaker/breakererr"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
 
 /*
 ErrFrameworkNotReady is returned when the framework is not ready.
@@ -16,3 +24,237 @@ IsFrameworkNotReady checks if the error is ErrFrameworkNotReady.
 func IsFrameworkNotReady(err error) bool {
 	return err == ErrFrameworkNotReady
 }
+
+/*
+ErrInvalidJSONPath is returned when a JSONPath expression cannot be parsed.
+*/
+var ErrInvalidJSONPath = errors.New("invalid JSONPath expression")
+
+/*
+ErrJSONPathNotFound is returned when a JSONPath expression does not match any value.
+*/
+var ErrJSONPathNotFound = errors.New("JSONPath expression did not match")
+
+/*
+IsInvalidJSONPath checks if the error is ErrInvalidJSONPath.
+*/
+func IsInvalidJSONPath(err error) bool {
+	return err == ErrInvalidJSONPath
+}
+
+/*
+IsJSONPathNotFound checks if the error is ErrJSONPathNotFound.
+*/
+func IsJSONPathNotFound(err error) bool {
+	return err == ErrJSONPathNotFound
+}
+
+/*
+ErrInvalidPayload is returned when a node payload is rejected by a validator.
+*/
+var ErrInvalidPayload = errors.New("invalid payload")
+
+/*
+IsInvalidPayload checks if the error is, or wraps, ErrInvalidPayload.
+*/
+func IsInvalidPayload(err error) bool {
+	return errors.Is(err, ErrInvalidPayload)
+}
+
+/*
+ErrTooManyConflicts is returned when a transactional update keeps conflicting with concurrent writers until the retry policy gives up.
+*/
+var ErrTooManyConflicts = errors.New("too many version conflicts")
+
+/*
+IsTooManyConflicts checks if the error is ErrTooManyConflicts.
+*/
+func IsTooManyConflicts(err error) bool {
+	return err == ErrTooManyConflicts
+}
+
+/*
+ErrSnapshotNotFound is returned when restoring a snapshot missing from the history of a node.
+*/
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+/*
+IsSnapshotNotFound checks if the error is ErrSnapshotNotFound.
+*/
+func IsSnapshotNotFound(err error) bool {
+	return err == ErrSnapshotNotFound
+}
+
+/*
+ErrTrashEntryNotFound is returned when restoring an entry missing from the trash.
+*/
+var ErrTrashEntryNotFound = errors.New("trash entry not found")
+
+/*
+IsTrashEntryNotFound checks if the error is ErrTrashEntryNotFound.
+*/
+func IsTrashEntryNotFound(err error) bool {
+	return err == ErrTrashEntryNotFound
+}
+
+/*
+ErrQuotaNotFound is returned when no quota is set on a node.
+*/
+var ErrQuotaNotFound = errors.New("quota not found")
+
+/*
+ErrInvalidQuota is returned when the quota data of a node cannot be parsed.
+*/
+var ErrInvalidQuota = errors.New("invalid quota")
+
+/*
+IsQuotaNotFound checks if the error is ErrQuotaNotFound.
+*/
+func IsQuotaNotFound(err error) bool {
+	return err == ErrQuotaNotFound
+}
+
+/*
+IsInvalidQuota checks if the error is, or wraps, ErrInvalidQuota.
+*/
+func IsInvalidQuota(err error) bool {
+	return errors.Is(err, ErrInvalidQuota)
+}
+
+/*
+ErrInvalidPattern is returned when a name pattern is not a valid glob.
+*/
+var ErrInvalidPattern = errors.New("invalid name pattern")
+
+/*
+IsInvalidPattern checks if the error is ErrInvalidPattern.
+*/
+func IsInvalidPattern(err error) bool {
+	return err == ErrInvalidPattern
+}
+
+/*
+ErrAccessDenied is returned when an operation is not allowed by the access policy bound to the framework.
+*/
+var ErrAccessDenied = errors.New("access denied")
+
+/*
+IsAccessDenied checks if the error is, or wraps, an access denied error.
+*/
+func IsAccessDenied(err error) bool {
+	return errors.Is(err, ErrAccessDenied)
+}
+
+/*
+ErrDeadlineExceeded is returned when an operation does not complete within the operation deadline of the framework.
+*/
+var ErrDeadlineExceeded = errors.New("operation deadline exceeded")
+
+/*
+IsDeadlineExceeded checks if the error is, or wraps, ErrDeadlineExceeded.
+*/
+func IsDeadlineExceeded(err error) bool {
+	return errors.Is(err, ErrDeadlineExceeded)
+}
+
+/*
+IsTransient checks if the error is, or wraps, a connection-level failure expected to clear on its own: connection losses, expired or moved sessions and timeouts.
+*/
+func IsTransient(err error) bool {
+	return errors.Is(err, zk.ErrConnectionClosed) ||
+		errors.Is(err, zk.ErrNoServer) ||
+		errors.Is(err, zk.ErrSessionExpired) ||
+		errors.Is(err, zk.ErrSessionMoved) ||
+		errors.Is(err, frwkerr.ErrConnectionTimeout) ||
+		errors.Is(err, ErrDeadlineExceeded) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+/*
+IsAuth checks if the error is, or wraps, an authentication or authorization failure, either from the Zookeeper server or from the access policy bound to the framework.
+*/
+func IsAuth(err error) bool {
+	return errors.Is(err, zk.ErrNoAuth) ||
+		errors.Is(err, zk.ErrAuthFailed) ||
+		errors.Is(err, ErrAccessDenied)
+}
+
+/*
+IsFatal checks if the error is, or wraps, a failure that retrying cannot fix: a framework which is not started, failed or closing, an authentication failure a request rejected as invalid or an operation which panicked.
+*/
+func IsFatal(err error) bool {
+	return IsAuth(err) ||
+		errors.Is(err, ErrFrameworkNotReady) ||
+		errors.Is(err, frwkerr.ErrFrameworkFailed) ||
+		errors.Is(err, frwkerr.ErrFrameworkNotYetStarted) ||
+		errors.Is(err, zk.ErrClosing) ||
+		errors.Is(err, zk.ErrBadArguments) ||
+		errors.Is(err, zk.ErrInvalidACL) ||
+		errors.Is(err, zk.ErrInvalidPath) ||
+		errors.Is(err, ErrInvalidPayload) ||
+		errors.Is(err, ErrInvalidJSONPath) ||
+		errors.Is(err, ErrInvalidPattern) ||
+		errors.Is(err, ErrInvalidQuota) ||
+		errors.Is(err, ErrInvalidQuery) ||
+		errors.Is(err, ErrOperationPanicked)
+}
+
+/*
+IsRetryable checks if the same request may succeed when attempted again after a delay: the error is transient or the circuit breaker of the framework is failing operations fast.
+
+Errors about nodes, e.g. zk.ErrNoNode or zk.ErrBadVersion, are not retryable as they require the request to change.
+*/
+func IsRetryable(err error) bool {
+	return err != nil && !IsFatal(err) && (IsTransient(err) || errors.Is(err, breakererr.ErrCircuitOpen))
+}
+
+/*
+ErrOperationPanicked is wrapped by the PanicError returned when an operation panics.
+*/
+var ErrOperationPanicked = errors.New("operation panicked")
+
+/*
+PanicError is returned instead of crashing the process when an operation panics, capturing the recovered value and the stack of the panicking goroutine.
+*/
+type PanicError struct {
+	// Value is the value recovered from the panic.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+/*
+Error returns the description of the recovered value.
+*/
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrOperationPanicked, e.Value)
+}
+
+/*
+Unwrap returns ErrOperationPanicked, along with the recovered value when it is an error.
+*/
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrOperationPanicked, err}
+	}
+	return []error{ErrOperationPanicked}
+}
+
+/*
+IsOperationPanicked checks if the error is, or wraps, a PanicError; use errors.As to access the recovered value and the stack.
+*/
+func IsOperationPanicked(err error) bool {
+	return errors.Is(err, ErrOperationPanicked)
+}
+
+/*
+ErrInvalidQuery is returned when a tree query expression cannot be parsed.
+*/
+var ErrInvalidQuery = errors.New("invalid query expression")
+
+/*
+IsInvalidQuery checks if the error is, or wraps, ErrInvalidQuery.
+*/
+func IsInvalidQuery(err error) bool {
+	return errors.Is(err, ErrInvalidQuery)
+}
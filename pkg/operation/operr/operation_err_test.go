@@ -1,9 +1,14 @@
 package operr_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/breaker/breakererr"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
 	"github.com/morphy76/zk/pkg/operation/operr"
 )
 
@@ -20,3 +25,262 @@ func TestIsFrameworkNotReadyFalse(t *testing.T) {
 		t.Errorf("expected false, got true")
 	}
 }
+
+func TestIsInvalidJSONPath(t *testing.T) {
+	err := operr.ErrInvalidJSONPath
+	if !operr.IsInvalidJSONPath(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidJSONPathFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsInvalidJSONPath(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsJSONPathNotFound(t *testing.T) {
+	err := operr.ErrJSONPathNotFound
+	if !operr.IsJSONPathNotFound(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsJSONPathNotFoundFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsJSONPathNotFound(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidPayload(t *testing.T) {
+	err := fmt.Errorf("%w: /a: bad", operr.ErrInvalidPayload)
+	if !operr.IsInvalidPayload(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidPayloadFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsInvalidPayload(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsTooManyConflicts(t *testing.T) {
+	err := operr.ErrTooManyConflicts
+	if !operr.IsTooManyConflicts(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsTooManyConflictsFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsTooManyConflicts(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsSnapshotNotFound(t *testing.T) {
+	err := operr.ErrSnapshotNotFound
+	if !operr.IsSnapshotNotFound(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsSnapshotNotFoundFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsSnapshotNotFound(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsTrashEntryNotFound(t *testing.T) {
+	err := operr.ErrTrashEntryNotFound
+	if !operr.IsTrashEntryNotFound(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsTrashEntryNotFoundFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsTrashEntryNotFound(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsQuotaNotFound(t *testing.T) {
+	err := operr.ErrQuotaNotFound
+	if !operr.IsQuotaNotFound(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsQuotaNotFoundFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsQuotaNotFound(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidQuota(t *testing.T) {
+	err := fmt.Errorf("%w: count", operr.ErrInvalidQuota)
+	if !operr.IsInvalidQuota(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidQuotaFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsInvalidQuota(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidPattern(t *testing.T) {
+	err := operr.ErrInvalidPattern
+	if !operr.IsInvalidPattern(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidPatternFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsInvalidPattern(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsAccessDenied(t *testing.T) {
+	err := fmt.Errorf("%w: role reader cannot write /config", operr.ErrAccessDenied)
+	if !operr.IsAccessDenied(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsAccessDeniedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsAccessDenied(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsDeadlineExceeded(t *testing.T) {
+	err := fmt.Errorf("%w: %w", operr.ErrDeadlineExceeded, context.DeadlineExceeded)
+	if !operr.IsDeadlineExceeded(err) {
+		t.Errorf("expected true, got false")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context error to be wrapped")
+	}
+}
+
+func TestIsDeadlineExceededFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsDeadlineExceeded(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	for _, err := range []error{
+		zk.ErrConnectionClosed,
+		fmt.Errorf("get /config: %w", zk.ErrNoServer),
+		zk.ErrSessionExpired,
+		frwkerr.ErrConnectionTimeout,
+		fmt.Errorf("%w: %w", operr.ErrDeadlineExceeded, context.DeadlineExceeded),
+	} {
+		if !operr.IsTransient(err) {
+			t.Errorf("expected %v to be transient", err)
+		}
+	}
+	for _, err := range []error{zk.ErrNoNode, zk.ErrBadVersion, operr.ErrAccessDenied, errors.New("some error")} {
+		if operr.IsTransient(err) {
+			t.Errorf("expected %v not to be transient", err)
+		}
+	}
+}
+
+func TestIsAuth(t *testing.T) {
+	for _, err := range []error{zk.ErrNoAuth, fmt.Errorf("set /config: %w", zk.ErrAuthFailed), fmt.Errorf("%w: role reader", operr.ErrAccessDenied)} {
+		if !operr.IsAuth(err) {
+			t.Errorf("expected %v to be an auth error", err)
+		}
+	}
+	if operr.IsAuth(zk.ErrNoNode) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsFatal(t *testing.T) {
+	for _, err := range []error{
+		zk.ErrNoAuth,
+		operr.ErrFrameworkNotReady,
+		frwkerr.ErrFrameworkFailed,
+		zk.ErrClosing,
+		zk.ErrInvalidACL,
+		fmt.Errorf("%w: missing field", operr.ErrInvalidPayload),
+	} {
+		if !operr.IsFatal(err) {
+			t.Errorf("expected %v to be fatal", err)
+		}
+	}
+	for _, err := range []error{zk.ErrConnectionClosed, zk.ErrNoNode, errors.New("some error")} {
+		if operr.IsFatal(err) {
+			t.Errorf("expected %v not to be fatal", err)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, err := range []error{zk.ErrConnectionClosed, fmt.Errorf("%w: %w", operr.ErrDeadlineExceeded, context.DeadlineExceeded), breakererr.ErrCircuitOpen} {
+		if !operr.IsRetryable(err) {
+			t.Errorf("expected %v to be retryable", err)
+		}
+	}
+	for _, err := range []error{nil, zk.ErrNoNode, zk.ErrBadVersion, zk.ErrNoAuth, frwkerr.ErrFrameworkFailed, errors.New("some error")} {
+		if operr.IsRetryable(err) {
+			t.Errorf("expected %v not to be retryable", err)
+		}
+	}
+}
+
+func TestIsOperationPanicked(t *testing.T) {
+	err := error(&operr.PanicError{Value: zk.ErrNoNode, Stack: []byte("goroutine 1")})
+	if !operr.IsOperationPanicked(err) {
+		t.Errorf("expected true, got false")
+	}
+	if !errors.Is(err, zk.ErrNoNode) {
+		t.Errorf("expected the recovered error to be wrapped")
+	}
+	if !operr.IsFatal(err) {
+		t.Errorf("expected a panic to be fatal")
+	}
+
+	var panicErr *operr.PanicError
+	if !errors.As(fmt.Errorf("get /config: %w", err), &panicErr) || string(panicErr.Stack) != "goroutine 1" {
+		t.Errorf("expected the stack to be accessible")
+	}
+}
+
+func TestIsOperationPanickedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsOperationPanicked(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidQuery(t *testing.T) {
+	err := fmt.Errorf("%w: unexpected )", operr.ErrInvalidQuery)
+	if !operr.IsInvalidQuery(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidQueryFalse(t *testing.T) {
+	err := errors.New("some error")
+	if operr.IsInvalidQuery(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,230 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+QuotaRoot is the Zookeeper node below which the quotas are kept, mirroring the actual paths they apply to.
+*/
+const QuotaRoot = "/zookeeper/quota"
+
+const (
+	quotaLimitsNode = "zookeeper_limits"
+	quotaStatsNode  = "zookeeper_stats"
+	noQuotaLimit    = -1
+)
+
+/*
+Quota holds the count and byte figures of a quota, either the limits or the current usage of a subtree. A negative value means no limit.
+*/
+type Quota struct {
+	// Count is the number of nodes of the subtree, including its root.
+	Count int64
+	// Bytes is the total data size of the subtree.
+	Bytes int64
+}
+
+/*
+NoQuota returns a quota without any limit.
+*/
+func NoQuota() Quota {
+	return Quota{Count: noQuotaLimit, Bytes: noQuotaLimit}
+}
+
+/*
+GetQuota reads the quota limits set on the node at the given path and the current usage of its subtree.
+
+Zookeeper only logs a warning when a quota is exceeded, the usage is meant to be monitored.
+*/
+func GetQuota(zkFramework core.ZKFramework, nodeName string) (Quota, Quota, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("getting quota", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return Quota{}, Quota{}, err
+	}
+
+	outChan, errChan := execute(zkFramework, getQuota(actualPath))
+
+	select {
+	case out := <-outChan:
+		return out[0], out[1], nil
+	case err := <-errChan:
+		return Quota{}, Quota{}, err
+	}
+}
+
+/*
+SetQuota sets the quota limits of the node at the given path, replacing the limits already set.
+*/
+func SetQuota(zkFramework core.ZKFramework, nodeName string, limits Quota) error {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("setting quota", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionWrite|PermissionAdmin); err != nil {
+		return err
+	}
+
+	outChan, errChan := execute(zkFramework, setQuota(actualPath, limits))
+
+	select {
+	case <-outChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+/*
+DeleteQuota removes the quota of the node at the given path.
+*/
+func DeleteQuota(zkFramework core.ZKFramework, nodeName string) error {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("deleting quota", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionWrite|PermissionAdmin); err != nil {
+		return err
+	}
+
+	outChan, errChan := execute(zkFramework, deleteQuota(actualPath))
+
+	select {
+	case <-outChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+func getQuota(actualPath string) connectionConsumer[[2]Quota] {
+	return func(ctx context.Context, executor core.Executor, outChan chan [2]Quota) error {
+		quotaPath := path.Join(QuotaRoot, actualPath)
+
+		limitsData, _, err := executor.Get(ctx, path.Join(quotaPath, quotaLimitsNode))
+		if err == zk.ErrNoNode {
+			return operr.ErrQuotaNotFound
+		}
+		if err != nil {
+			return err
+		}
+		limits, err := parseQuota(string(limitsData))
+		if err != nil {
+			return err
+		}
+
+		usage := Quota{}
+		statsData, _, err := executor.Get(ctx, path.Join(quotaPath, quotaStatsNode))
+		if err != nil && err != zk.ErrNoNode {
+			return err
+		}
+		if err == nil {
+			if usage, err = parseQuota(string(statsData)); err != nil {
+				return err
+			}
+		}
+
+		outChan <- [2]Quota{limits, usage}
+		return nil
+	}
+}
+
+func setQuota(actualPath string, limits Quota) connectionConsumer[bool] {
+	return func(ctx context.Context, executor core.Executor, outChan chan bool) error {
+		exists, _, err := executor.Exists(ctx, actualPath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return zk.ErrNoNode
+		}
+
+		quotaPath := path.Join(QuotaRoot, actualPath)
+		if err := createPersistentPath(ctx, executor, quotaPath); err != nil {
+			return err
+		}
+
+		limitsPath := path.Join(quotaPath, quotaLimitsNode)
+		_, err = executor.Create(ctx, limitsPath, []byte(formatQuota(limits)), 0, zk.WorldACL(zk.PermAll))
+		if err == zk.ErrNodeExists {
+			_, err = executor.Set(ctx, limitsPath, []byte(formatQuota(limits)), -1)
+		}
+		if err != nil {
+			return err
+		}
+
+		// the server computes the usage of the subtree when the stats node is created
+		_, err = executor.Create(ctx, path.Join(quotaPath, quotaStatsNode), []byte(formatQuota(Quota{})), 0, zk.WorldACL(zk.PermAll))
+		if err != nil && err != zk.ErrNodeExists {
+			return err
+		}
+
+		outChan <- true
+		return nil
+	}
+}
+
+func deleteQuota(actualPath string) connectionConsumer[bool] {
+	return func(ctx context.Context, executor core.Executor, outChan chan bool) error {
+		quotaPath := path.Join(QuotaRoot, actualPath)
+
+		err := executor.Delete(ctx, path.Join(quotaPath, quotaLimitsNode), -1)
+		if err == zk.ErrNoNode {
+			return operr.ErrQuotaNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := executor.Delete(ctx, path.Join(quotaPath, quotaStatsNode), -1); err != nil && err != zk.ErrNoNode {
+			return err
+		}
+
+		outChan <- true
+		return nil
+	}
+}
+
+func createPersistentPath(ctx context.Context, executor core.Executor, nodePath string) error {
+	current := ""
+	for _, part := range strings.Split(strings.Trim(nodePath, "/"), "/") {
+		current = current + "/" + part
+		_, err := executor.Create(ctx, current, []byte{}, 0, zk.WorldACL(zk.PermAll))
+		if err != nil && err != zk.ErrNodeExists {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatQuota(quota Quota) string {
+	return fmt.Sprintf("count=%d,bytes=%d", quota.Count, quota.Bytes)
+}
+
+func parseQuota(value string) (Quota, error) {
+	quota := NoQuota()
+	for _, field := range strings.Split(strings.TrimSpace(value), ",") {
+		key, rawValue, found := strings.Cut(field, "=")
+		if !found {
+			return quota, fmt.Errorf("%w: %s", operr.ErrInvalidQuota, value)
+		}
+		parsed, err := strconv.ParseInt(rawValue, 10, 64)
+		if err != nil {
+			return quota, fmt.Errorf("%w: %s", operr.ErrInvalidQuota, value)
+		}
+		switch key {
+		case "count":
+			quota.Count = parsed
+		case "bytes":
+			quota.Bytes = parsed
+		}
+	}
+	return quota, nil
+}
@@ -0,0 +1,84 @@
+package operation_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestQuota(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Set, get and delete a quota", func(t *testing.T) {
+		t.Log("Set, get and delete a quota")
+		nodeName := uuid.New().String()
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte("data")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if err := operation.SetQuota(zkFramework, nodeName, operation.Quota{Count: 10, Bytes: -1}); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		limits, usage, err := operation.GetQuota(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if limits.Count != 10 || limits.Bytes != -1 {
+			t.Errorf("expected limits count=10,bytes=-1, got %+v", limits)
+		}
+		if usage.Count != 1 || usage.Bytes != 4 {
+			t.Errorf("expected usage count=1,bytes=4, got %+v", usage)
+		}
+
+		if err := operation.DeleteQuota(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, _, err := operation.GetQuota(zkFramework, nodeName); !operr.IsQuotaNotFound(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrQuotaNotFound, err)
+		}
+	})
+
+	t.Run("Get the quota of a node without quota", func(t *testing.T) {
+		t.Log("Get the quota of a node without quota")
+		nodeName := uuid.New().String()
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if _, _, err := operation.GetQuota(zkFramework, nodeName); !operr.IsQuotaNotFound(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrQuotaNotFound, err)
+		}
+	})
+}
+
+func TestQuotaAccessPolicy(t *testing.T) {
+	policy := operation.AccessPolicy{
+		"reader": {
+			{Pattern: "elsewhere/**", Allow: operation.PermissionRead},
+		},
+	}
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), operation.WithAccessPolicy(policy, "reader"))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	if _, _, err := operation.GetQuota(zkFramework, "node"); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+	if err := operation.SetQuota(zkFramework, "node", operation.NoQuota()); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+	if err := operation.DeleteQuota(zkFramework, "node"); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+}
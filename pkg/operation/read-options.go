@@ -0,0 +1,53 @@
+package operation
+
+/*
+ReadConsistency is the consistency guarantee of a read operation.
+*/
+type ReadConsistency int
+
+const (
+	// Eventual reads from the connected server, which may lag behind the leader.
+	Eventual ReadConsistency = iota
+	// Linearizable syncs the connected server with the leader before reading, giving read-your-writes across clients.
+	Linearizable
+)
+
+/*
+ReadOptions represents the options for a read operation.
+*/
+type ReadOptions struct {
+	Consistency ReadConsistency
+}
+
+/*
+ReadOptionsBuilder is a builder for ReadOptions.
+*/
+type ReadOptionsBuilder struct {
+	consistency ReadConsistency
+}
+
+/*
+NewReadOptionsBuilder creates a new ReadOptionsBuilder.
+*/
+func NewReadOptionsBuilder() ReadOptionsBuilder {
+	return ReadOptionsBuilder{
+		consistency: Eventual,
+	}
+}
+
+/*
+WithConsistency sets the consistency of the read operation.
+*/
+func (rob ReadOptionsBuilder) WithConsistency(consistency ReadConsistency) ReadOptionsBuilder {
+	rob.consistency = consistency
+	return rob
+}
+
+/*
+Build builds the ReadOptions.
+*/
+func (rob ReadOptionsBuilder) Build() ReadOptions {
+	return ReadOptions{
+		Consistency: rob.consistency,
+	}
+}
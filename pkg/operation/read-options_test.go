@@ -0,0 +1,25 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestDefaultReadOptionsBuilder(t *testing.T) {
+	opts := operation.NewReadOptionsBuilder().Build()
+
+	if opts.Consistency != operation.Eventual {
+		t.Errorf("Expected Consistency to be %v, got %v", operation.Eventual, opts.Consistency)
+	}
+}
+
+func TestReadOptionsBuilder(t *testing.T) {
+	opts := operation.NewReadOptionsBuilder().
+		WithConsistency(operation.Linearizable).
+		Build()
+
+	if opts.Consistency != operation.Linearizable {
+		t.Errorf("Expected Consistency to be %v, got %v", operation.Linearizable, opts.Consistency)
+	}
+}
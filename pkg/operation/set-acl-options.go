@@ -0,0 +1,70 @@
+package operation
+
+/*
+SetACLOptions represents the options for a recursive set ACL operation.
+*/
+type SetACLOptions struct {
+	// Concurrency is the maximum number of nodes updated at the same time.
+	Concurrency int
+	// DryRun is a flag to only list the affected nodes without changing them.
+	DryRun bool
+	// Progress, when not nil, is called after each node is processed.
+	Progress func(nodeName string, done int, total int)
+}
+
+/*
+SetACLOptionsBuilder is a builder for SetACLOptions.
+*/
+type SetACLOptionsBuilder struct {
+	concurrency int
+	dryRun      bool
+	progress    func(nodeName string, done int, total int)
+}
+
+const (
+	defaultSetACLConcurrency = 8
+)
+
+/*
+NewSetACLOptionsBuilder creates a new SetACLOptionsBuilder.
+*/
+func NewSetACLOptionsBuilder() SetACLOptionsBuilder {
+	return SetACLOptionsBuilder{
+		concurrency: defaultSetACLConcurrency,
+	}
+}
+
+/*
+WithConcurrency sets the maximum number of nodes updated at the same time.
+*/
+func (b SetACLOptionsBuilder) WithConcurrency(concurrency int) SetACLOptionsBuilder {
+	b.concurrency = concurrency
+	return b
+}
+
+/*
+WithDryRun sets the flag to only list the affected nodes without changing them.
+*/
+func (b SetACLOptionsBuilder) WithDryRun(dryRun bool) SetACLOptionsBuilder {
+	b.dryRun = dryRun
+	return b
+}
+
+/*
+WithProgress sets the callback called after each node is processed.
+*/
+func (b SetACLOptionsBuilder) WithProgress(progress func(nodeName string, done int, total int)) SetACLOptionsBuilder {
+	b.progress = progress
+	return b
+}
+
+/*
+Build builds the SetACLOptions.
+*/
+func (b SetACLOptionsBuilder) Build() SetACLOptions {
+	return SetACLOptions{
+		Concurrency: b.concurrency,
+		DryRun:      b.dryRun,
+		Progress:    b.progress,
+	}
+}
@@ -0,0 +1,39 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestDefaultSetACLOptionsBuilder(t *testing.T) {
+	opts := operation.NewSetACLOptionsBuilder().Build()
+
+	if opts.Concurrency <= 0 {
+		t.Errorf("Expected Concurrency to be > 0, got %d", opts.Concurrency)
+	}
+	if opts.DryRun {
+		t.Errorf("Expected DryRun to be false, got true")
+	}
+	if opts.Progress != nil {
+		t.Errorf("Expected Progress to be nil")
+	}
+}
+
+func TestSetACLOptionsBuilder(t *testing.T) {
+	opts := operation.NewSetACLOptionsBuilder().
+		WithConcurrency(2).
+		WithDryRun(true).
+		WithProgress(func(string, int, int) {}).
+		Build()
+
+	if opts.Concurrency != 2 {
+		t.Errorf("Expected Concurrency to be 2, got %d", opts.Concurrency)
+	}
+	if !opts.DryRun {
+		t.Errorf("Expected DryRun to be true, got false")
+	}
+	if opts.Progress == nil {
+		t.Errorf("Expected Progress to be set")
+	}
+}
@@ -0,0 +1,61 @@
+package operation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation/operr"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+const (
+	defaultConflictBaseDelay = 10 * time.Millisecond
+	defaultConflictMaxDelay  = time.Second
+	defaultConflictAttempts  = 10
+)
+
+/*
+Mutation computes the new data of a node from its current data.
+*/
+type Mutation func(old []byte) ([]byte, error)
+
+/*
+UpdateTransactionally applies the mutation to the data of the node at the given path with a read-modify-write loop, retrying on version conflicts with the default policy.
+*/
+func UpdateTransactionally(zkFramework core.ZKFramework, nodeName string, mutation Mutation) (int32, error) {
+	policy := retry.NewMaxAttempts(retry.NewExponentialBackoff(defaultConflictBaseDelay, defaultConflictMaxDelay), defaultConflictAttempts)
+	return UpdateTransactionallyWithPolicy(zkFramework, nodeName, mutation, policy)
+}
+
+/*
+UpdateTransactionallyWithPolicy applies the mutation to the data of the node at the given path with a read-modify-write loop, retrying on version conflicts as decided by the given policy.
+
+The mutation may be called several times and must not have side effects, an error returned by the mutation aborts the update.
+*/
+func UpdateTransactionallyWithPolicy(zkFramework core.ZKFramework, nodeName string, mutation Mutation, policy retry.Policy) (int32, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		data, stat, err := GetWithStat(zkFramework, nodeName)
+		if err != nil {
+			return 0, err
+		}
+
+		newData, err := mutation(data)
+		if err != nil {
+			return 0, err
+		}
+
+		version, err := UpdateWithVersion(zkFramework, nodeName, newData, stat.Version)
+		if !errors.Is(err, zk.ErrBadVersion) {
+			return version, err
+		}
+
+		delay, ok := policy.NextDelay(attempt, time.Since(start))
+		if !ok {
+			return 0, operr.ErrTooManyConflicts
+		}
+		<-time.After(delay)
+	}
+}
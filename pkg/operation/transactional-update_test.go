@@ -0,0 +1,92 @@
+package operation_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+func TestUpdateTransactionally(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	increment := func(old []byte) ([]byte, error) {
+		value, err := strconv.Atoi(string(old))
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.Itoa(value + 1)), nil
+	}
+
+	t.Run("Concurrent increments", func(t *testing.T) {
+		t.Log("Concurrent increments")
+		nodeName := uuid.New().String()
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte("0")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		policy := retry.NewMaxAttempts(retry.NewExponentialBackoff(time.Millisecond, 50*time.Millisecond), 100)
+		wg := sync.WaitGroup{}
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := operation.UpdateTransactionallyWithPolicy(zkFramework, nodeName, increment, policy); err != nil {
+					t.Errorf(unexpectedErrorFmt, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		data, err := operation.Get(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if string(data) != "5" {
+			t.Errorf("expected 5, got %s", data)
+		}
+	})
+
+	t.Run("Mutation error aborts the update", func(t *testing.T) {
+		t.Log("Mutation error aborts the update")
+		nodeName := uuid.New().String()
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte("0")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		abort := errors.New("abort")
+		if _, err := operation.UpdateTransactionally(zkFramework, nodeName, func([]byte) ([]byte, error) { return nil, abort }); err != abort {
+			t.Errorf("expected error %v, got %v", abort, err)
+		}
+	})
+
+	t.Run("Give up on persistent conflicts", func(t *testing.T) {
+		t.Log("Give up on persistent conflicts")
+		nodeName := uuid.New().String()
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte("0")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		conflicting := func(old []byte) ([]byte, error) {
+			if _, err := operation.Update(zkFramework, nodeName, old); err != nil {
+				return nil, err
+			}
+			return old, nil
+		}
+		policy := retry.NewMaxAttempts(retry.NewExponentialBackoff(time.Millisecond, 0), 2)
+		if _, err := operation.UpdateTransactionallyWithPolicy(zkFramework, nodeName, conflicting, policy); !operr.IsTooManyConflicts(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrTooManyConflicts, err)
+		}
+	})
+}
@@ -0,0 +1,346 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+TrashNode is the node, relative to the namespace, holding the nodes deleted while the trash is enabled.
+*/
+const TrashNode = ".trash"
+
+const trashEntryPrefix = "entry-"
+
+/*
+TrashEntry is a node moved to the trash by Delete.
+*/
+type TrashEntry struct {
+	// ID identifies the entry in the trash of the namespace.
+	ID string `json:"-"`
+	// Path is the original path of the node, relative to the namespace.
+	Path string `json:"path"`
+	// Data is the data of the node when it was deleted.
+	Data []byte `json:"data"`
+	// ACL is the ACL of the node when it was deleted.
+	ACL []zk.ACL `json:"acl"`
+	// Deleted is the time the node was deleted.
+	Deleted time.Time `json:"deleted"`
+}
+
+type trashKey struct{}
+
+type trash struct {
+	retentions map[string]time.Duration
+	lock       sync.RWMutex
+}
+
+func trashOf(zkFramework core.ZKFramework) *trash {
+	return zkFramework.Extension(trashKey{}, func() any {
+		return &trash{retentions: make(map[string]time.Duration)}
+	}).(*trash)
+}
+
+/*
+WithTrash is the framework option enabling the trash of the framework namespace, see EnableTrash.
+*/
+func WithTrash(retention time.Duration) framework.Option {
+	return framework.WithExtension(func(zkFramework core.ZKFramework) {
+		EnableTrash(zkFramework, retention)
+	})
+}
+
+/*
+EnableTrash makes Delete move the nodes of the framework namespace, the ones deleted through the views of its child namespaces included, into its trash
+instead of destroying them; PurgeTrash destroys the entries older than the given retention.
+*/
+func EnableTrash(zkFramework core.ZKFramework, retention time.Duration) {
+	frameworkTrash := trashOf(zkFramework)
+	frameworkTrash.lock.Lock()
+	defer frameworkTrash.lock.Unlock()
+	frameworkTrash.retentions[zkFramework.Namespace()] = retention
+}
+
+/*
+DisableTrash makes Delete destroy the nodes of the framework namespace again, the entries already in the trash are kept.
+*/
+func DisableTrash(zkFramework core.ZKFramework) {
+	frameworkTrash := trashOf(zkFramework)
+	frameworkTrash.lock.Lock()
+	defer frameworkTrash.lock.Unlock()
+	delete(frameworkTrash.retentions, zkFramework.Namespace())
+}
+
+/*
+Trash lists the entries in the trash of the framework namespace, or of the closest enclosing namespace with the trash enabled, oldest first.
+*/
+func Trash(zkFramework core.ZKFramework) ([]TrashEntry, error) {
+	trashPath := path.Join(trashNamespace(zkFramework), TrashNode)
+	zkFramework.Logger().Debug("listing trash", "path", trashPath)
+
+	if err := authorize(zkFramework, trashPath, PermissionRead); err != nil {
+		return nil, err
+	}
+
+	outChan, errChan := execute(zkFramework, listTrashEntries(trashPath))
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
+
+/*
+RestoreFromTrash recreates the node of the given trash entry at its original path and removes the entry from the trash.
+*/
+func RestoreFromTrash(zkFramework core.ZKFramework, entryID string) error {
+	namespace := trashNamespace(zkFramework)
+	trashPath := path.Join(namespace, TrashNode)
+	entryPath := path.Join(trashPath, entryID)
+	zkFramework.Logger().Debug("restoring trash entry", "entry", entryPath)
+
+	if err := authorize(zkFramework, entryPath, PermissionRead|PermissionDelete); err != nil {
+		return err
+	}
+
+	outChan, errChan := execute(zkFramework, restoreTrashEntry(zkFramework, namespace, entryPath))
+
+	select {
+	case <-outChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+/*
+PurgeTrash destroys the entries in the trash of the framework namespace older than the configured retention, returning the number of destroyed entries;
+it does nothing unless the trash is enabled.
+*/
+func PurgeTrash(zkFramework core.ZKFramework) (int, error) {
+	namespace, retention, ok := trashSetting(zkFramework)
+	if !ok {
+		return 0, nil
+	}
+
+	entries, err := Trash(zkFramework)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, entry := range entries {
+		if time.Since(entry.Deleted) < retention {
+			continue
+		}
+		err := purgeTrashEntry(zkFramework, path.Join(namespace, TrashNode, entry.ID))
+		if err != nil && !coreerr.IsUnknownNode(err) {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func purgeTrashEntry(zkFramework core.ZKFramework, entryPath string) error {
+	zkFramework.Logger().Debug("purging trash entry", "entry", entryPath)
+
+	if err := authorize(zkFramework, entryPath, PermissionDelete); err != nil {
+		return err
+	}
+
+	outChan, errChan := execute(zkFramework, deleteNode(entryPath))
+
+	select {
+	case <-outChan:
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+/*
+trashSetting resolves the trash covering the framework namespace, the one enabled on the closest enclosing namespace, returning its namespace and retention.
+*/
+func trashSetting(zkFramework core.ZKFramework) (string, time.Duration, bool) {
+	frameworkTrash := trashOf(zkFramework)
+	frameworkTrash.lock.RLock()
+	defer frameworkTrash.lock.RUnlock()
+
+	namespace, retention, found := "", time.Duration(0), false
+	for enabled, enabledRetention := range frameworkTrash.retentions {
+		if isAtOrBelow(zkFramework.Namespace(), enabled) && (!found || len(enabled) > len(namespace)) {
+			namespace, retention, found = enabled, enabledRetention, true
+		}
+	}
+	return namespace, retention, found
+}
+
+func trashNamespace(zkFramework core.ZKFramework) string {
+	if namespace, _, ok := trashSetting(zkFramework); ok {
+		return namespace
+	}
+	return zkFramework.Namespace()
+}
+
+/*
+trashedBy returns the namespace of the trash receiving the node at the given path when deleted, false when the node is destroyed,
+either the trash being disabled or the node being in the trash already.
+*/
+func trashedBy(zkFramework core.ZKFramework, actualPath string) (string, bool) {
+	namespace, _, ok := trashSetting(zkFramework)
+	if !ok || isAtOrBelow(actualPath, path.Join(namespace, TrashNode)) {
+		return "", false
+	}
+	return namespace, true
+}
+
+/*
+isAtOrBelow tells whether the path is the given ancestor or one of its descendants.
+*/
+func isAtOrBelow(actualPath string, ancestor string) bool {
+	return actualPath == ancestor || strings.HasPrefix(actualPath, strings.TrimSuffix(ancestor, "/")+"/")
+}
+
+func moveToTrash(aclProvider core.ACLProvider, namespace string, actualPath string) connectionConsumer[bool] {
+	return func(ctx context.Context, executor core.Executor, outChan chan bool) error {
+		data, stat, err := executor.Get(ctx, actualPath)
+		if err == zk.ErrNoNode {
+			return coreerr.ErrUnknownNode
+		}
+		if err != nil {
+			return err
+		}
+		acl, _, err := executor.GetACL(ctx, actualPath)
+		if err != nil {
+			return err
+		}
+
+		entry, err := json.Marshal(TrashEntry{
+			Path:    strings.TrimPrefix(strings.TrimPrefix(actualPath, namespace), "/"),
+			Data:    data,
+			ACL:     acl,
+			Deleted: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		trashPath := path.Join(namespace, TrashNode)
+		entryPath := path.Join(trashPath, trashEntryPrefix)
+		if err := recursivelyGrantParent(ctx, executor, aclProvider, entryPath); err != nil {
+			return err
+		}
+
+		if err := multi(ctx, executor,
+			&zk.CreateRequest{Path: entryPath, Data: entry, Acl: nodeacl.DefaultFor(aclProvider, trashPath), Flags: zk.FlagSequence},
+			&zk.DeleteRequest{Path: actualPath, Version: stat.Version},
+		); err != nil {
+			return err
+		}
+		outChan <- true
+		return nil
+	}
+}
+
+/*
+restoreTrashEntry recreates the node of the trash entry, the original path being known once the entry is read it is authorized here.
+*/
+func restoreTrashEntry(zkFramework core.ZKFramework, namespace string, entryPath string) connectionConsumer[bool] {
+	return func(ctx context.Context, executor core.Executor, outChan chan bool) error {
+		data, stat, err := executor.Get(ctx, entryPath)
+		if err == zk.ErrNoNode {
+			return operr.ErrTrashEntryNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var entry TrashEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+
+		originalPath := path.Join(namespace, entry.Path)
+		if err := authorize(zkFramework, originalPath, PermissionCreate); err != nil {
+			return err
+		}
+		if err := recursivelyGrantParent(ctx, executor, zkFramework.ACLProvider(), originalPath); err != nil {
+			return err
+		}
+
+		if err := multi(ctx, executor,
+			&zk.CreateRequest{Path: originalPath, Data: entry.Data, Acl: entry.ACL},
+			&zk.DeleteRequest{Path: entryPath, Version: stat.Version},
+		); err != nil {
+			return err
+		}
+		outChan <- true
+		return nil
+	}
+}
+
+func listTrashEntries(trashPath string) connectionConsumer[[]TrashEntry] {
+	return func(ctx context.Context, executor core.Executor, outChan chan []TrashEntry) error {
+		children, _, err := executor.Children(ctx, trashPath)
+		if err == zk.ErrNoNode {
+			outChan <- []TrashEntry{}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		sort.Strings(children)
+
+		entries := make([]TrashEntry, 0, len(children))
+		for _, child := range children {
+			data, _, err := executor.Get(ctx, path.Join(trashPath, child))
+			if err == zk.ErrNoNode {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			var entry TrashEntry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return err
+			}
+			entry.ID = child
+			entries = append(entries, entry)
+		}
+		outChan <- entries
+		return nil
+	}
+}
+
+func multi(ctx context.Context, executor core.Executor, ops ...interface{}) error {
+	responses, err := executor.Multi(ctx, ops...)
+	if err != nil {
+		return err
+	}
+	return multiError(responses)
+}
+
+func multiError(responses []zk.MultiResponse) error {
+	for _, response := range responses {
+		if response.Error != nil {
+			return response.Error
+		}
+	}
+	return nil
+}
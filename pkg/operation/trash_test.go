@@ -0,0 +1,262 @@
+package operation_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestTrash(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Delete moves the node to the trash and restore brings it back", func(t *testing.T) {
+		t.Log("Delete moves the node to the trash and restore brings it back")
+		scoped := zkFramework.UsingNamespace(uuid.New().String())
+		operation.EnableTrash(scoped, time.Hour)
+		defer operation.DisableTrash(scoped)
+
+		nodeName := "a/b"
+		if err := operation.CreateWithOptions(scoped, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte("data")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Delete(scoped, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if exists, _ := operation.Exists(scoped, nodeName); exists {
+			t.Error("expected the node to be deleted")
+		}
+
+		entries, err := operation.Trash(scoped)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("expected 1 trash entry, got %d", len(entries))
+		}
+		if entries[0].Path != nodeName || string(entries[0].Data) != "data" {
+			t.Errorf("expected entry of %s with data, got %s with %s", nodeName, entries[0].Path, entries[0].Data)
+		}
+
+		if err := operation.RestoreFromTrash(scoped, entries[0].ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		data, err := operation.Get(scoped, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if string(data) != "data" {
+			t.Errorf("expected data, got %s", data)
+		}
+		if entries, _ := operation.Trash(scoped); len(entries) != 0 {
+			t.Errorf("expected empty trash, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("Purge the expired entries", func(t *testing.T) {
+		t.Log("Purge the expired entries")
+		scoped := zkFramework.UsingNamespace(uuid.New().String())
+		operation.EnableTrash(scoped, 0)
+		defer operation.DisableTrash(scoped)
+
+		if err := operation.Create(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Delete(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		purged, err := operation.PurgeTrash(scoped)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if purged != 1 {
+			t.Errorf("expected 1 purged entry, got %d", purged)
+		}
+	})
+
+	t.Run("Views of child namespaces delete into the trash", func(t *testing.T) {
+		t.Log("Views of child namespaces delete into the trash")
+		scoped := zkFramework.UsingNamespace(uuid.New().String())
+		operation.EnableTrash(scoped, time.Hour)
+		defer operation.DisableTrash(scoped)
+
+		child := scoped.UsingNamespace("child")
+		if err := operation.Create(child, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Delete(child, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		entries, err := operation.Trash(child)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(entries) != 1 || entries[0].Path != "child/node" {
+			t.Fatalf("expected the entry of child/node, got %v", entries)
+		}
+		if err := operation.RestoreFromTrash(child, entries[0].ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if exists, _ := operation.Exists(child, "node"); !exists {
+			t.Error("expected the node to be restored")
+		}
+	})
+
+	t.Run("Nodes in the trash are destroyed", func(t *testing.T) {
+		t.Log("Nodes in the trash are destroyed")
+		scoped := zkFramework.UsingNamespace(uuid.New().String())
+		operation.EnableTrash(scoped, time.Hour)
+		defer operation.DisableTrash(scoped)
+
+		if err := operation.Create(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Delete(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		entries, err := operation.Trash(scoped)
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("expected 1 trash entry, got %d and %v", len(entries), err)
+		}
+
+		if err := operation.Delete(scoped, operation.TrashNode+"/"+entries[0].ID); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if entries, _ := operation.Trash(scoped); len(entries) != 0 {
+			t.Errorf("expected empty trash, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("Purge without the trash enabled", func(t *testing.T) {
+		t.Log("Purge without the trash enabled")
+		scoped := zkFramework.UsingNamespace(uuid.New().String())
+		operation.EnableTrash(scoped, time.Hour)
+		if err := operation.Create(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Delete(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		operation.DisableTrash(scoped)
+
+		purged, err := operation.PurgeTrash(scoped)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if purged != 0 {
+			t.Errorf("expected no purged entry, got %d", purged)
+		}
+		if entries, _ := operation.Trash(scoped); len(entries) != 1 {
+			t.Errorf("expected the entry to be kept, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("Restore an unknown entry", func(t *testing.T) {
+		t.Log("Restore an unknown entry")
+		scoped := zkFramework.UsingNamespace(uuid.New().String())
+
+		if err := operation.RestoreFromTrash(scoped, "unknown"); !operr.IsTrashEntryNotFound(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrTrashEntryNotFound, err)
+		}
+	})
+
+	t.Run("Restore and purge are checked against the access policy", func(t *testing.T) {
+		t.Log("Restore and purge are checked against the access policy")
+		scoped := zkFramework.UsingNamespace(uuid.New().String())
+		operation.EnableTrash(scoped, 0)
+		defer operation.DisableTrash(scoped)
+
+		if err := operation.Create(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Delete(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		entries, err := operation.Trash(scoped)
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("expected 1 trash entry, got %d and %v", len(entries), err)
+		}
+
+		policy := operation.AccessPolicy{
+			"cleaner": {
+				{Pattern: "**", Allow: operation.PermissionRead},
+				{Pattern: operation.TrashNode + "/*", Allow: operation.PermissionDelete},
+			},
+			"reader": {
+				{Pattern: "**", Allow: operation.PermissionRead},
+			},
+		}
+		defer operation.RemoveAccessPolicy(scoped)
+
+		operation.SetAccessPolicy(scoped, policy, "cleaner")
+		if err := operation.RestoreFromTrash(scoped, entries[0].ID); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+		if exists, _ := operation.Exists(scoped, "node"); exists {
+			t.Error("expected the node not to be restored")
+		}
+
+		operation.SetAccessPolicy(scoped, policy, "reader")
+		if _, err := operation.PurgeTrash(scoped); !operr.IsAccessDenied(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+		}
+		if entries, _ := operation.Trash(scoped); len(entries) != 1 {
+			t.Errorf("expected the entry to be kept, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("Trash is scoped to the framework", func(t *testing.T) {
+		t.Log("Trash is scoped to the framework")
+		namespace := uuid.New().String()
+		operation.EnableTrash(zkFramework.UsingNamespace(namespace), time.Hour)
+		defer operation.DisableTrash(zkFramework.UsingNamespace(namespace))
+
+		otherFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer otherFramework.Stop()
+
+		scoped := otherFramework.UsingNamespace(namespace)
+		if err := operation.Create(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := operation.Delete(scoped, "node"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if entries, _ := operation.Trash(scoped); len(entries) != 0 {
+			t.Errorf("expected empty trash, got %d entries", len(entries))
+		}
+	})
+}
+
+func TestTrashAccessPolicy(t *testing.T) {
+	policy := operation.AccessPolicy{
+		"guest": {},
+	}
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), operation.WithAccessPolicy(policy, "guest"), operation.WithTrash(time.Hour))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	if _, err := operation.Trash(zkFramework); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+	if err := operation.RestoreFromTrash(zkFramework, "entry-0000000000"); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+	if _, err := operation.PurgeTrash(zkFramework); !operr.IsAccessDenied(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrAccessDenied, err)
+	}
+}
@@ -0,0 +1,554 @@
+package operation
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+TreeQuery is a compiled tree query expression, see CompileTreeQuery.
+*/
+type TreeQuery struct {
+	expression string
+	predicate  queryPredicate
+	walkRoot   string
+	pathGlobs  [][]string
+}
+
+/*
+CompileTreeQuery compiles a tree query expression, e.g. path:/services/** AND data.version>=2.
+
+An expression combines terms with AND, OR, NOT and parentheses; the terms are:
+  - path:<glob> matches the path of the node, relative to the namespace, segment by segment as in path.Match, ** matching any number of segments;
+  - name:<glob> matches the name of the node;
+  - data:<text> matches the nodes whose data contains the text;
+  - data.<field><op><value> compares a member of the JSON data, dot separated, missing members never match;
+  - stat.<field><op><value> compares a stat field: version, cversion, aversion, dataLength, numChildren, ephemeralOwner, czxid, mzxid, ctime or mtime.
+
+The operators are =, !=, >, >=, <, <=; values are compared as numbers when both sides are numbers, as strings otherwise, and are quoted when they contain spaces or parentheses.
+*/
+func CompileTreeQuery(expression string) (TreeQuery, error) {
+	tokens, err := tokenizeQuery(expression)
+	if err != nil {
+		return TreeQuery{}, err
+	}
+
+	parser := &queryParser{tokens: tokens}
+	predicate, err := parser.parseOr()
+	if err != nil {
+		return TreeQuery{}, err
+	}
+	if parser.pos < len(tokens) {
+		return TreeQuery{}, fmt.Errorf("%w: unexpected %s", operr.ErrInvalidQuery, tokens[parser.pos])
+	}
+
+	query := TreeQuery{expression: expression, predicate: predicate, walkRoot: "/"}
+	for _, conjunct := range conjuncts(predicate) {
+		if term, ok := conjunct.(pathTerm); ok {
+			query.pathGlobs = append(query.pathGlobs, term.segments)
+		}
+	}
+	if len(query.pathGlobs) > 0 {
+		query.walkRoot = "/" + path.Join(literalPrefix(query.pathGlobs[0])...)
+	}
+	return query, nil
+}
+
+/*
+String returns the expression of the query.
+*/
+func (q TreeQuery) String() string {
+	return q.expression
+}
+
+/*
+Search walks the tree streaming the paths, relative to the namespace, of the nodes matching the query expression, see CompileTreeQuery.
+
+The path terms required by the whole expression are pushed down: the walk starts from their longest literal prefix and skips the subtrees they cannot match;
+the data and the stat of a node are only read when the other terms do not decide the match.
+The paths channel is closed when the walk completes, the errors channel receives the error stopping the walk, if any, and is closed afterwards.
+*/
+func Search(zkFramework core.ZKFramework, expression string) (<-chan string, <-chan error) {
+	query, err := CompileTreeQuery(expression)
+	if err != nil {
+		paths := make(chan string)
+		errs := make(chan error, 1)
+		close(paths)
+		errs <- err
+		close(errs)
+		return paths, errs
+	}
+	return SearchWithQuery(zkFramework, query)
+}
+
+/*
+SearchWithQuery walks the tree streaming the paths of the nodes matching the compiled query, as Search does.
+*/
+func SearchWithQuery(zkFramework core.ZKFramework, query TreeQuery) (<-chan string, <-chan error) {
+	zkFramework.Logger().Debug("searching nodes", "path", path.Join(zkFramework.Namespace(), query.walkRoot), "query", query.String())
+
+	paths := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(paths)
+
+		if err := query.search(zkFramework, query.walkRoot, paths); err != nil {
+			errs <- err
+		}
+	}()
+
+	return paths, errs
+}
+
+func (q TreeQuery) search(zkFramework core.ZKFramework, nodeName string, paths chan<- string) error {
+	segments := pathSegments(nodeName)
+	for _, glob := range q.pathGlobs {
+		if !matchSegments(glob, segments, true) {
+			return nil
+		}
+	}
+
+	matches, err := q.predicate.eval(&queryCandidate{framework: zkFramework, path: nodeName})
+	if err != nil {
+		return err
+	}
+	if matches {
+		paths <- nodeName
+	}
+
+	children, err := Ls(zkFramework, nodeName)
+	if errors.Is(err, zk.ErrNoNode) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := q.search(zkFramework, path.Join(nodeName, child), paths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+queryCandidate is a node evaluated by a query, its data and stat being read on first use.
+*/
+type queryCandidate struct {
+	framework core.ZKFramework
+	path      string
+	loaded    bool
+	missing   bool
+	data      []byte
+	stat      *zk.Stat
+}
+
+func (c *queryCandidate) load() error {
+	if c.loaded {
+		return nil
+	}
+	data, stat, err := GetWithStat(c.framework, c.path)
+	if err != nil && !errors.Is(err, zk.ErrNoNode) {
+		return err
+	}
+	c.loaded, c.missing, c.data, c.stat = true, err != nil, data, stat
+	return nil
+}
+
+type queryPredicate interface {
+	eval(candidate *queryCandidate) (bool, error)
+	// cheap reports whether the predicate is decided without reading the node.
+	cheap() bool
+}
+
+type andPredicate struct {
+	left, right queryPredicate
+}
+
+func (p andPredicate) eval(candidate *queryCandidate) (bool, error) {
+	first, second := p.left, p.right
+	if !first.cheap() && second.cheap() {
+		first, second = second, first
+	}
+	matches, err := first.eval(candidate)
+	if err != nil || !matches {
+		return false, err
+	}
+	return second.eval(candidate)
+}
+
+func (p andPredicate) cheap() bool {
+	return p.left.cheap() && p.right.cheap()
+}
+
+type orPredicate struct {
+	left, right queryPredicate
+}
+
+func (p orPredicate) eval(candidate *queryCandidate) (bool, error) {
+	first, second := p.left, p.right
+	if !first.cheap() && second.cheap() {
+		first, second = second, first
+	}
+	matches, err := first.eval(candidate)
+	if err != nil || matches {
+		return matches, err
+	}
+	return second.eval(candidate)
+}
+
+func (p orPredicate) cheap() bool {
+	return p.left.cheap() && p.right.cheap()
+}
+
+type notPredicate struct {
+	operand queryPredicate
+}
+
+func (p notPredicate) eval(candidate *queryCandidate) (bool, error) {
+	matches, err := p.operand.eval(candidate)
+	return !matches, err
+}
+
+func (p notPredicate) cheap() bool {
+	return p.operand.cheap()
+}
+
+type pathTerm struct {
+	segments []string
+}
+
+func (t pathTerm) eval(candidate *queryCandidate) (bool, error) {
+	return matchSegments(t.segments, pathSegments(candidate.path), false), nil
+}
+
+func (t pathTerm) cheap() bool {
+	return true
+}
+
+type nameTerm struct {
+	pattern string
+}
+
+func (t nameTerm) eval(candidate *queryCandidate) (bool, error) {
+	matches, _ := path.Match(t.pattern, path.Base(candidate.path))
+	return matches, nil
+}
+
+func (t nameTerm) cheap() bool {
+	return true
+}
+
+type dataTerm struct {
+	text string
+}
+
+func (t dataTerm) eval(candidate *queryCandidate) (bool, error) {
+	if err := candidate.load(); err != nil || candidate.missing {
+		return false, err
+	}
+	return strings.Contains(string(candidate.data), t.text), nil
+}
+
+func (t dataTerm) cheap() bool {
+	return false
+}
+
+type fieldTerm struct {
+	jsonPath string
+	op       string
+	value    string
+}
+
+func (t fieldTerm) eval(candidate *queryCandidate) (bool, error) {
+	if err := candidate.load(); err != nil || candidate.missing {
+		return false, err
+	}
+	actual, err := EvaluateJSONPath(candidate.data, t.jsonPath)
+	if err != nil {
+		return false, nil
+	}
+	return compareQueryValue(actual, t.op, t.value), nil
+}
+
+func (t fieldTerm) cheap() bool {
+	return false
+}
+
+type statTerm struct {
+	field string
+	op    string
+	value string
+}
+
+var statFields = map[string]func(stat *zk.Stat) int64{
+	"version":        func(stat *zk.Stat) int64 { return int64(stat.Version) },
+	"cversion":       func(stat *zk.Stat) int64 { return int64(stat.Cversion) },
+	"aversion":       func(stat *zk.Stat) int64 { return int64(stat.Aversion) },
+	"dataLength":     func(stat *zk.Stat) int64 { return int64(stat.DataLength) },
+	"numChildren":    func(stat *zk.Stat) int64 { return int64(stat.NumChildren) },
+	"ephemeralOwner": func(stat *zk.Stat) int64 { return stat.EphemeralOwner },
+	"czxid":          func(stat *zk.Stat) int64 { return stat.Czxid },
+	"mzxid":          func(stat *zk.Stat) int64 { return stat.Mzxid },
+	"ctime":          func(stat *zk.Stat) int64 { return stat.Ctime },
+	"mtime":          func(stat *zk.Stat) int64 { return stat.Mtime },
+}
+
+func (t statTerm) eval(candidate *queryCandidate) (bool, error) {
+	if err := candidate.load(); err != nil || candidate.missing {
+		return false, err
+	}
+	return compareQueryValue(float64(statFields[t.field](candidate.stat)), t.op, t.value), nil
+}
+
+func (t statTerm) cheap() bool {
+	return false
+}
+
+func compareQueryValue(actual any, op string, value string) bool {
+	var result int
+	number, isNumber := actual.(float64)
+	expected, err := strconv.ParseFloat(value, 64)
+	switch {
+	case isNumber && err == nil:
+		result = cmp.Compare(number, expected)
+	default:
+		var text string
+		switch actual := actual.(type) {
+		case string:
+			text = actual
+		case bool:
+			text = strconv.FormatBool(actual)
+		default:
+			encoded, _ := json.Marshal(actual)
+			text = string(encoded)
+		}
+		result = strings.Compare(text, value)
+	}
+
+	switch op {
+	case "=", ":":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	default:
+		return result <= 0
+	}
+}
+
+type queryParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *queryParser) keyword(keyword string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseOr() (queryPredicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orPredicate{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryPredicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andPredicate{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (queryPredicate, error) {
+	if p.keyword("NOT") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notPredicate{operand: operand}, nil
+	}
+	if p.keyword("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, fmt.Errorf("%w: missing )", operr.ErrInvalidQuery)
+		}
+		return inner, nil
+	}
+	if p.pos == len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected end of expression", operr.ErrInvalidQuery)
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return parseQueryTerm(token)
+}
+
+func parseQueryTerm(token string) (queryPredicate, error) {
+	keyEnd := strings.IndexFunc(token, func(r rune) bool {
+		return !(r == '.' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if keyEnd <= 0 {
+		return nil, fmt.Errorf("%w: %s is not a term", operr.ErrInvalidQuery, token)
+	}
+	key, rest := token[:keyEnd], token[keyEnd:]
+
+	op := ""
+	for _, candidate := range []string{":", "!=", ">=", "<=", "=", ">", "<"} {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("%w: %s has no operator", operr.ErrInvalidQuery, token)
+	}
+	value := rest[len(op):]
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s has an invalid quoted value", operr.ErrInvalidQuery, token)
+		}
+		value = unquoted
+	}
+
+	switch {
+	case key == "path" && op == ":":
+		segments := pathSegments(value)
+		for _, segment := range segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("%w: %s is not a valid glob", operr.ErrInvalidQuery, value)
+			}
+		}
+		return pathTerm{segments: segments}, nil
+	case key == "name" && op == ":":
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("%w: %s is not a valid glob", operr.ErrInvalidQuery, value)
+		}
+		return nameTerm{pattern: value}, nil
+	case key == "data" && op == ":":
+		return dataTerm{text: value}, nil
+	case strings.HasPrefix(key, "data.") && len(key) > len("data."):
+		return fieldTerm{jsonPath: "$" + strings.TrimPrefix(key, "data"), op: op, value: value}, nil
+	case strings.HasPrefix(key, "stat.") && statFields[strings.TrimPrefix(key, "stat.")] != nil:
+		return statTerm{field: strings.TrimPrefix(key, "stat."), op: op, value: value}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown term %s", operr.ErrInvalidQuery, token)
+}
+
+func tokenizeQuery(expression string) ([]string, error) {
+	tokens := []string{}
+	for i := 0; i < len(expression); {
+		switch c := expression[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			start, quoted := i, false
+			for ; i < len(expression); i++ {
+				c := expression[i]
+				if c == '"' && (i == 0 || expression[i-1] != '\\') {
+					quoted = !quoted
+				}
+				if !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '(' || c == ')') {
+					break
+				}
+			}
+			if quoted {
+				return nil, fmt.Errorf("%w: unterminated quote", operr.ErrInvalidQuery)
+			}
+			tokens = append(tokens, expression[start:i])
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", operr.ErrInvalidQuery)
+	}
+	return tokens, nil
+}
+
+/*
+conjuncts returns the predicates which must all match for the given predicate to match.
+*/
+func conjuncts(predicate queryPredicate) []queryPredicate {
+	if and, ok := predicate.(andPredicate); ok {
+		return append(conjuncts(and.left), conjuncts(and.right)...)
+	}
+	return []queryPredicate{predicate}
+}
+
+func literalPrefix(glob []string) []string {
+	for i, segment := range glob {
+		if strings.ContainsAny(segment, `*?[\`) {
+			return glob[:i]
+		}
+	}
+	return glob
+}
+
+func pathSegments(nodeName string) []string {
+	trimmed := strings.Trim(path.Clean("/"+nodeName), "/")
+	if trimmed == "" {
+		return []string{}
+	}
+	return strings.Split(trimmed, "/")
+}
+
+/*
+matchSegments matches a glob made of path segments, ** matching any number of segments; as a prefix, it checks whether the descendants may match.
+*/
+func matchSegments(glob []string, segments []string, prefix bool) bool {
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+	if prefix && len(segments) == 0 {
+		return true
+	}
+	if glob[0] == "**" {
+		return matchSegments(glob[1:], segments, prefix) || (len(segments) > 0 && matchSegments(glob, segments[1:], prefix))
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	matches, _ := path.Match(glob[0], segments[0])
+	return matches && matchSegments(glob[1:], segments[1:], prefix)
+}
@@ -0,0 +1,75 @@
+package operation_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestSearch(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for nodeName, data := range map[string]string{
+		root + "/services/api":        `{"version":1,"owner":"team a"}`,
+		root + "/services/db":         `{"version":2,"owner":"team b"}`,
+		root + "/services/db/replica": `{"version":3,"owner":"team b"}`,
+		root + "/config/api":          `{"version":5}`,
+	} {
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte(data)).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+
+	collect := func(expression string) ([]string, error) {
+		paths, errs := operation.Search(zkFramework, expression)
+		found := []string{}
+		for nodeName := range paths {
+			found = append(found, nodeName)
+		}
+		slices.Sort(found)
+		return found, <-errs
+	}
+
+	t.Run("Search by path and data field", func(t *testing.T) {
+		t.Log("Search by path and data field")
+		found, err := collect("path:/" + root + "/services/** AND data.version>=2")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		expected := []string{"/" + root + "/services/db", "/" + root + "/services/db/replica"}
+		if !slices.Equal(found, expected) {
+			t.Errorf("expected %v, got %v", expected, found)
+		}
+	})
+
+	t.Run("Search with boolean operators", func(t *testing.T) {
+		t.Log("Search with boolean operators")
+		found, err := collect(`path:/` + root + `/** AND (name:api OR data.owner="team b") AND NOT stat.numChildren>0`)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		expected := []string{"/" + root + "/config/api", "/" + root + "/services/api", "/" + root + "/services/db/replica"}
+		if !slices.Equal(found, expected) {
+			t.Errorf("expected %v, got %v", expected, found)
+		}
+	})
+
+	t.Run("Search with an invalid expression", func(t *testing.T) {
+		t.Log("Search with an invalid expression")
+		if _, err := collect("path:/" + root + " AND ("); !operr.IsInvalidQuery(err) {
+			t.Errorf("expected %v, got %v", operr.ErrInvalidQuery, err)
+		}
+		if _, err := operation.CompileTreeQuery("version>=2"); !operr.IsInvalidQuery(err) {
+			t.Errorf("expected %v, got %v", operr.ErrInvalidQuery, err)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package operation
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+)
+
+const treeStatsLargestNodes = 10
+
+/*
+NodeSize is the data size of a node.
+*/
+type NodeSize struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// Bytes is the data size of the node.
+	Bytes int64
+}
+
+/*
+Stats represents the usage statistics of a subtree.
+*/
+type Stats struct {
+	// NodeCount is the number of nodes of the subtree, including its root.
+	NodeCount int64
+	// TotalBytes is the total data size of the subtree.
+	TotalBytes int64
+	// MaxDepth is the depth of the deepest node, the root having depth 0.
+	MaxDepth int
+	// EphemeralCount is the number of ephemeral nodes of the subtree.
+	EphemeralCount int64
+	// LargestNodes are the largest nodes of the subtree, largest first.
+	LargestNodes []NodeSize
+}
+
+/*
+TreeStats walks the subtree at the given path and reports its usage statistics.
+*/
+func TreeStats(zkFramework core.ZKFramework, root string) (Stats, error) {
+	zkFramework.Logger().Debug("collecting tree statistics", "path", path.Join(zkFramework.Namespace(), root))
+
+	stats := Stats{LargestNodes: []NodeSize{}}
+	if err := collectStats(zkFramework, root, 0, &stats); err != nil {
+		return Stats{}, err
+	}
+	return stats, nil
+}
+
+func collectStats(zkFramework core.ZKFramework, nodeName string, depth int, stats *Stats) error {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+
+	outChan, errChan := execute(zkFramework, statNode(actualPath))
+
+	var stat *zk.Stat
+	select {
+	case stat = <-outChan:
+	case err := <-errChan:
+		return err
+	}
+
+	stats.NodeCount++
+	stats.TotalBytes += int64(stat.DataLength)
+	if stat.EphemeralOwner != 0 {
+		stats.EphemeralCount++
+	}
+	if depth > stats.MaxDepth {
+		stats.MaxDepth = depth
+	}
+	stats.LargestNodes = append(stats.LargestNodes, NodeSize{Path: nodeName, Bytes: int64(stat.DataLength)})
+	sort.SliceStable(stats.LargestNodes, func(i, j int) bool {
+		return stats.LargestNodes[i].Bytes > stats.LargestNodes[j].Bytes
+	})
+	if len(stats.LargestNodes) > treeStatsLargestNodes {
+		stats.LargestNodes = stats.LargestNodes[:treeStatsLargestNodes]
+	}
+
+	if stat.NumChildren == 0 {
+		return nil
+	}
+	children, err := Ls(zkFramework, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := collectStats(zkFramework, path.Join(nodeName, child), depth+1, stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func statNode(path string) connectionConsumer[*zk.Stat] {
+	return func(ctx context.Context, executor core.Executor, outChan chan *zk.Stat) error {
+		exists, stat, err := executor.Exists(ctx, path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return zk.ErrNoNode
+		}
+		outChan <- stat
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestTreeStats(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	for nodeName, data := range map[string]string{
+		root + "/a":   "12345",
+		root + "/a/b": "123",
+		root + "/c":   "1",
+	} {
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte(data)).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	}
+	ephemeral := operation.NewCreateOptionsBuilder().WithMode(zk.FlagEphemeral).Build()
+	if err := operation.CreateWithOptions(zkFramework, root+"/e", ephemeral); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	stats, err := operation.TreeStats(zkFramework, root)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if stats.NodeCount != 5 {
+		t.Errorf("expected 5 nodes, got %d", stats.NodeCount)
+	}
+	if stats.TotalBytes != 9 {
+		t.Errorf("expected 9 bytes, got %d", stats.TotalBytes)
+	}
+	if stats.MaxDepth != 2 {
+		t.Errorf("expected max depth 2, got %d", stats.MaxDepth)
+	}
+	if stats.EphemeralCount != 1 {
+		t.Errorf("expected 1 ephemeral node, got %d", stats.EphemeralCount)
+	}
+	if len(stats.LargestNodes) == 0 || stats.LargestNodes[0].Path != root+"/a" {
+		t.Errorf("expected %s/a to be the largest node, got %v", root, stats.LargestNodes)
+	}
+}
@@ -0,0 +1,101 @@
+package operation
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+/*
+Validator checks a node payload before it is written, returning an error when the payload is invalid.
+*/
+type Validator func(data []byte) error
+
+type validatorsKey struct{}
+
+type validators struct {
+	byPrefix map[string][]Validator
+	lock     sync.RWMutex
+}
+
+func validatorsOf(zkFramework core.ZKFramework) *validators {
+	return zkFramework.Extension(validatorsKey{}, func() any {
+		return &validators{byPrefix: make(map[string][]Validator)}
+	}).(*validators)
+}
+
+/*
+WithValidator is the framework option attaching a validator to every node at or below the given path prefix, see AddValidator.
+*/
+func WithValidator(prefix string, validator Validator) framework.Option {
+	return framework.WithExtension(func(zkFramework core.ZKFramework) {
+		AddValidator(zkFramework, prefix, validator)
+	})
+}
+
+/*
+AddValidator attaches a validator to every node at or below the given path prefix, Create and Update run through the framework, or through any of its views,
+reject payloads it refuses.
+*/
+func AddValidator(zkFramework core.ZKFramework, prefix string, validator Validator) {
+	actualPrefix := path.Join(zkFramework.Namespace(), prefix)
+
+	frameworkValidators := validatorsOf(zkFramework)
+	frameworkValidators.lock.Lock()
+	defer frameworkValidators.lock.Unlock()
+	frameworkValidators.byPrefix[actualPrefix] = append(frameworkValidators.byPrefix[actualPrefix], validator)
+}
+
+/*
+RemoveValidators detaches all the validators attached to the given path prefix.
+*/
+func RemoveValidators(zkFramework core.ZKFramework, prefix string) {
+	actualPrefix := path.Join(zkFramework.Namespace(), prefix)
+
+	frameworkValidators := validatorsOf(zkFramework)
+	frameworkValidators.lock.Lock()
+	defer frameworkValidators.lock.Unlock()
+	delete(frameworkValidators.byPrefix, actualPrefix)
+}
+
+/*
+JSONValidator returns a validator accepting only JSON objects containing all the given top level fields.
+*/
+func JSONValidator(requiredFields ...string) Validator {
+	return func(data []byte) error {
+		var document map[string]any
+		if err := json.Unmarshal(data, &document); err != nil {
+			return err
+		}
+		for _, field := range requiredFields {
+			if _, ok := document[field]; !ok {
+				return fmt.Errorf("missing required field %s", field)
+			}
+		}
+		return nil
+	}
+}
+
+func validate(zkFramework core.ZKFramework, actualPath string, data []byte) error {
+	frameworkValidators := validatorsOf(zkFramework)
+	frameworkValidators.lock.RLock()
+	defer frameworkValidators.lock.RUnlock()
+
+	for prefix, prefixValidators := range frameworkValidators.byPrefix {
+		if actualPath != prefix && !strings.HasPrefix(actualPath, strings.TrimSuffix(prefix, "/")+"/") {
+			continue
+		}
+		for _, validator := range prefixValidators {
+			if err := validator(data); err != nil {
+				return fmt.Errorf("%w: %s: %v", operr.ErrInvalidPayload, actualPath, err)
+			}
+		}
+	}
+	return nil
+}
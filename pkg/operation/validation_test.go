@@ -0,0 +1,75 @@
+package operation_test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+)
+
+func TestJSONValidator(t *testing.T) {
+	validator := operation.JSONValidator("name")
+
+	if err := validator([]byte(`{"name":"a"}`)); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := validator([]byte(`{"other":"a"}`)); err == nil {
+		t.Error("expected error to be not nil")
+	}
+	if err := validator([]byte(`not json`)); err == nil {
+		t.Error("expected error to be not nil")
+	}
+}
+
+func TestValidatedWrites(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	prefix := uuid.New().String()
+	operation.AddValidator(zkFramework, prefix, operation.JSONValidator("name"))
+	defer operation.RemoveValidators(zkFramework, prefix)
+
+	nodeName := path.Join(prefix, uuid.New().String())
+	invalid := operation.NewCreateOptionsBuilder().WithData([]byte(`{}`)).Build()
+	if err := operation.CreateWithOptions(zkFramework, nodeName, invalid); !operr.IsInvalidPayload(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrInvalidPayload, err)
+	}
+
+	valid := operation.NewCreateOptionsBuilder().WithData([]byte(`{"name":"a"}`)).Build()
+	if err := operation.CreateWithOptions(zkFramework, nodeName, valid); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	if _, err := operation.Update(zkFramework, nodeName, []byte(`[]`)); !operr.IsInvalidPayload(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrInvalidPayload, err)
+	}
+
+	siblingName := uuid.New().String()
+	if err := operation.Create(zkFramework, siblingName); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+}
+
+func TestValidatorOption(t *testing.T) {
+	zkFramework, err := framework.CreateFrameworkWithOptions(os.Getenv(zkHostEnv), operation.WithValidator("config", operation.JSONValidator("name")))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	invalid := operation.NewCreateOptionsBuilder().WithData([]byte(`{}`)).Build()
+	if err := operation.CreateWithOptions(zkFramework.UsingOperationTimeout(time.Second), "config/node", invalid); !operr.IsInvalidPayload(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrInvalidPayload, err)
+	}
+	if _, err := operation.Update(zkFramework.UsingNamespace("config"), "node", []byte(`[]`)); !operr.IsInvalidPayload(err) {
+		t.Errorf("expected error %v, got %v", operr.ErrInvalidPayload, err)
+	}
+}
@@ -0,0 +1,65 @@
+package operation
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
+)
+
+/*
+WaitOutcome tells how a WaitOrCreate operation obtained the node.
+*/
+type WaitOutcome int
+
+const (
+	// NodeAppeared means the node existed or was created by someone else.
+	NodeAppeared WaitOutcome = iota
+	// NodeCreated means the node was created by the fallback once the deadline expired.
+	NodeCreated
+)
+
+/*
+WaitOrCreate watches for the node at the given path to exist and, when it does not appear within the timeout, creates it with the given options.
+
+The fallback is race-safe: when another client creates the node first, the outcome is NodeAppeared.
+*/
+func WaitOrCreate(zkFramework core.ZKFramework, nodeName string, timeout time.Duration, options CreateOptions) (WaitOutcome, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("waiting for node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return NodeAppeared, err
+	}
+	if !zkFramework.Started() {
+		return NodeAppeared, frwkerr.ErrFrameworkNotYetStarted
+	}
+
+	deadline := time.After(timeout)
+	for {
+		exists, _, events, err := zkFramework.Executor().ExistsW(context.Background(), actualPath)
+		if err != nil {
+			return NodeAppeared, err
+		}
+		if exists {
+			return NodeAppeared, nil
+		}
+
+		select {
+		case <-events:
+		case <-deadline:
+			err := CreateWithOptions(zkFramework, nodeName, options)
+			if err == zk.ErrNodeExists {
+				return NodeAppeared, nil
+			}
+			if err != nil {
+				return NodeAppeared, err
+			}
+			return NodeCreated, nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package operation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+func TestWaitOrCreate(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	defaults := operation.NewCreateOptionsBuilder().WithData([]byte("defaults")).Build()
+
+	t.Run("Created on deadline", func(t *testing.T) {
+		nodeName := uuid.New().String()
+		outcome, err := operation.WaitOrCreate(zkFramework, nodeName, 100*time.Millisecond, defaults)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if outcome != operation.NodeCreated {
+			t.Errorf("expected outcome %v, got %v", operation.NodeCreated, outcome)
+		}
+		if data, err := operation.Get(zkFramework, nodeName); err != nil || string(data) != "defaults" {
+			t.Errorf("unexpected data %s, error %v", data, err)
+		}
+	})
+
+	t.Run("Appeared before deadline", func(t *testing.T) {
+		nodeName := uuid.New().String()
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			operation.Create(zkFramework, nodeName)
+		}()
+
+		outcome, err := operation.WaitOrCreate(zkFramework, nodeName, 10*time.Second, defaults)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if outcome != operation.NodeAppeared {
+			t.Errorf("expected outcome %v, got %v", operation.NodeAppeared, outcome)
+		}
+		if data, err := operation.Get(zkFramework, nodeName); err != nil || string(data) == "defaults" {
+			t.Errorf("expected the node created by the other client, got %s, error %v", data, err)
+		}
+	})
+
+	t.Run("Already existing", func(t *testing.T) {
+		nodeName := uuid.New().String()
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+
+		outcome, err := operation.WaitOrCreate(zkFramework, nodeName, time.Second, defaults)
+		if err != nil || outcome != operation.NodeAppeared {
+			t.Errorf("expected outcome %v, got %v, error %v", operation.NodeAppeared, outcome, err)
+		}
+	})
+}
@@ -5,25 +5,34 @@ package operation
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
 	"path"
+	"runtime/debug"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/go-zookeeper/zk"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
 	"github.com/morphy76/zk/pkg/core"
 	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/framework"
 	"github.com/morphy76/zk/pkg/framework/frwkerr"
+	"github.com/morphy76/zk/pkg/operation/operr"
 )
 
-type connectionConsumer[T any] func(*zk.Conn, chan T) error
+type connectionConsumer[T any] func(context.Context, core.Executor, chan T) error
 
 /*
 Ls lists the nodes at the given path.
 */
 func Ls(zkFramework core.ZKFramework, paths ...string) ([]string, error) {
 	actualPath := path.Join(append([]string{zkFramework.Namespace()}, paths...)...)
-	log.Println("Listing nodes at path:", actualPath)
+	zkFramework.Logger().Debug("listing nodes", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, err
+	}
 
 	outChan, errChan := execute(zkFramework, listNodes(actualPath))
 
@@ -39,16 +48,33 @@ func Ls(zkFramework core.ZKFramework, paths ...string) ([]string, error) {
 CreateWithOptions creates a node at the given path with the given options.
 */
 func CreateWithOptions(zkFramework core.ZKFramework, nodeName string, options CreateOptions) error {
+	_, err := CreateAndGetPath(zkFramework, nodeName, options)
+	return err
+}
+
+/*
+CreateAndGetPath creates a node at the given path with the given options, returning the actual path of the created node,
+e.g. with the sequence number appended by the server to the name of a sequential node.
+*/
+func CreateAndGetPath(zkFramework core.ZKFramework, nodeName string, options CreateOptions) (string, error) {
 	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
-	log.Println("Creating node at path:", actualPath)
+	zkFramework.Logger().Debug("creating node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionCreate); err != nil {
+		return "", err
+	}
 
-	outChan, errChan := execute(zkFramework, createNode(actualPath, &options))
+	if err := validate(zkFramework, actualPath, options.Data); err != nil {
+		return "", err
+	}
+
+	outChan, errChan := execute(zkFramework, createNode(zkFramework.Logger(), zkFramework.ACLProvider(), actualPath, &options))
 
 	select {
-	case <-outChan:
-		return nil
+	case out := <-outChan:
+		return out, nil
 	case err := <-errChan:
-		return err
+		return "", err
 	}
 }
 
@@ -57,9 +83,17 @@ Create creates a node at the given path.
 */
 func Create(zkFramework core.ZKFramework, nodeName string) error {
 	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
-	log.Println("Creating node at path:", actualPath)
+	zkFramework.Logger().Debug("creating node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionCreate); err != nil {
+		return err
+	}
+
+	if err := validate(zkFramework, actualPath, []byte{}); err != nil {
+		return err
+	}
 
-	outChan, errChan := execute(zkFramework, createNode(actualPath, nil))
+	outChan, errChan := execute(zkFramework, createNode(zkFramework.Logger(), zkFramework.ACLProvider(), actualPath, nil))
 
 	path.Join()
 	select {
@@ -75,7 +109,11 @@ Exists checks if a node exists at the given path.
 */
 func Exists(zkFramework core.ZKFramework, nodeName string) (bool, error) {
 	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
-	log.Println("Checking if node exists at path:", actualPath)
+	zkFramework.Logger().Debug("checking if node exists", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return false, err
+	}
 
 	outChan, errChan := execute(zkFramework, existsNode(actualPath))
 
@@ -87,14 +125,44 @@ func Exists(zkFramework core.ZKFramework, nodeName string) (bool, error) {
 	}
 }
 
+/*
+Stat gets the stat of a node at the given path, without its data.
+*/
+func Stat(zkFramework core.ZKFramework, nodeName string) (*zk.Stat, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("getting stat of node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, err
+	}
+
+	outChan, errChan := execute(zkFramework, statNode(actualPath))
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
+
 /*
 Delete deletes a node at the given path.
 */
 func Delete(zkFramework core.ZKFramework, nodeName string) error {
 	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
-	log.Println("Deleting node at path:", actualPath)
+	zkFramework.Logger().Debug("deleting node", "path", actualPath)
 
-	outChan, errChan := execute(zkFramework, deleteNode(actualPath))
+	if err := authorize(zkFramework, actualPath, PermissionDelete); err != nil {
+		return err
+	}
+
+	cnConsumer := deleteNode(actualPath)
+	if namespace, ok := trashedBy(zkFramework, actualPath); ok {
+		cnConsumer = moveToTrash(zkFramework.ACLProvider(), namespace, actualPath)
+	}
+
+	outChan, errChan := execute(zkFramework, cnConsumer)
 
 	select {
 	case <-outChan:
@@ -109,9 +177,22 @@ Update updates a node at the given path.
 */
 func Update(zkFramework core.ZKFramework, nodeName string, data []byte) (int32, error) {
 	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
-	log.Println("Updating node at path:", actualPath)
+	zkFramework.Logger().Debug("updating node", "path", actualPath)
 
-	outChan, errChan := execute(zkFramework, updateNode(actualPath, data))
+	if err := authorize(zkFramework, actualPath, PermissionWrite); err != nil {
+		return 0, err
+	}
+
+	if err := validate(zkFramework, actualPath, data); err != nil {
+		return 0, err
+	}
+
+	cnConsumer := updateNode(actualPath, data)
+	if retention, ok := historyRetention(zkFramework, actualPath); ok {
+		cnConsumer = updateNodeWithHistory(zkFramework.Logger(), zkFramework.ACLProvider(), actualPath, data, -1, retention)
+	}
+
+	outChan, errChan := execute(zkFramework, cnConsumer)
 
 	select {
 	case out := <-outChan:
@@ -127,7 +208,11 @@ Get gets a node at the given path.
 func Get(zkFramework core.ZKFramework, nodeName string) ([]byte, error) {
 	// TODO with stats
 	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
-	log.Println("Getting node at path:", actualPath)
+	zkFramework.Logger().Debug("getting node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, err
+	}
 
 	outChan, errChan := execute(zkFramework, getNode(actualPath))
 
@@ -139,9 +224,135 @@ func Get(zkFramework core.ZKFramework, nodeName string) ([]byte, error) {
 	}
 }
 
+/*
+GetWithStat gets a node at the given path together with its stat.
+*/
+func GetWithStat(zkFramework core.ZKFramework, nodeName string) ([]byte, *zk.Stat, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("getting node with stat", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, nil, err
+	}
+
+	outChan, errChan := execute(zkFramework, getNodeWithStat(actualPath))
+
+	select {
+	case out := <-outChan:
+		return out.data, out.stat, nil
+	case err := <-errChan:
+		return nil, nil, err
+	}
+}
+
+/*
+UpdateWithVersion updates a node at the given path only if its current version matches the given one.
+*/
+func UpdateWithVersion(zkFramework core.ZKFramework, nodeName string, data []byte, version int32) (int32, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("updating node with version", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionWrite); err != nil {
+		return 0, err
+	}
+
+	if err := validate(zkFramework, actualPath, data); err != nil {
+		return 0, err
+	}
+
+	cnConsumer := updateNodeWithVersion(actualPath, data, version)
+	if retention, ok := historyRetention(zkFramework, actualPath); ok {
+		cnConsumer = updateNodeWithHistory(zkFramework.Logger(), zkFramework.ACLProvider(), actualPath, data, version, retention)
+	}
+
+	outChan, errChan := execute(zkFramework, cnConsumer)
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return 0, err
+	}
+}
+
+/*
+LsWithOptions lists the nodes at the given path with the given read options.
+*/
+func LsWithOptions(zkFramework core.ZKFramework, nodeName string, options ReadOptions) ([]string, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("listing nodes", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, err
+	}
+
+	outChan, errChan := execute(zkFramework, withConsistency(actualPath, options, listNodes(actualPath)))
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
+
+/*
+ExistsWithOptions checks if a node exists at the given path with the given read options.
+*/
+func ExistsWithOptions(zkFramework core.ZKFramework, nodeName string, options ReadOptions) (bool, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("checking if node exists", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return false, err
+	}
+
+	outChan, errChan := execute(zkFramework, withConsistency(actualPath, options, existsNode(actualPath)))
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return false, err
+	}
+}
+
+/*
+GetWithOptions gets a node at the given path with the given read options.
+*/
+func GetWithOptions(zkFramework core.ZKFramework, nodeName string, options ReadOptions) ([]byte, error) {
+	actualPath := path.Join(append([]string{zkFramework.Namespace()}, strings.Split(nodeName, "/")...)...)
+	zkFramework.Logger().Debug("getting node", "path", actualPath)
+
+	if err := authorize(zkFramework, actualPath, PermissionRead); err != nil {
+		return nil, err
+	}
+
+	outChan, errChan := execute(zkFramework, withConsistency(actualPath, options, getNode(actualPath)))
+
+	select {
+	case out := <-outChan:
+		return out, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
+
+func withConsistency[T any](path string, options ReadOptions, cnConsumer connectionConsumer[T]) connectionConsumer[T] {
+	if options.Consistency != Linearizable {
+		return cnConsumer
+	}
+	return func(ctx context.Context, executor core.Executor, outChan chan T) error {
+		if _, err := executor.Sync(ctx, path); err != nil {
+			return err
+		}
+		return cnConsumer(ctx, executor, outChan)
+	}
+}
+
 func listNodes(path string) connectionConsumer[[]string] {
-	return func(cn *zk.Conn, outChan chan []string) error {
-		children, _, err := cn.Children(path)
+	return func(ctx context.Context, executor core.Executor, outChan chan []string) error {
+		children, _, err := executor.Children(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -150,22 +361,22 @@ func listNodes(path string) connectionConsumer[[]string] {
 	}
 }
 
-func createNode(path string, options *CreateOptions) connectionConsumer[bool] {
-	return func(cn *zk.Conn, outChan chan bool) error {
-		recursivelyGrantParent(path, cn)
-		data, flag, acl := parseOptions(options)
-		_, err := cn.Create(path, data, flag, acl)
+func createNode(logger *slog.Logger, aclProvider core.ACLProvider, path string, options *CreateOptions) connectionConsumer[string] {
+	return func(ctx context.Context, executor core.Executor, outChan chan string) error {
+		recursivelyGrantParent(ctx, executor, aclProvider, path)
+		data, flag, acl := parseOptions(logger, aclProvider, path, options)
+		created, err := executor.Create(ctx, path, data, flag, acl)
 		if err != nil {
 			return err
 		}
-		outChan <- true
+		outChan <- created
 		return nil
 	}
 }
 
-func parseOptions(options *CreateOptions) ([]byte, int32, []zk.ACL) {
+func parseOptions(logger *slog.Logger, aclProvider core.ACLProvider, nodePath string, options *CreateOptions) ([]byte, int32, []zk.ACL) {
 	if options == nil {
-		return []byte{}, 0, zk.WorldACL(zk.PermAll)
+		return []byte{}, 0, nodeacl.DefaultFor(aclProvider, nodePath)
 	}
 
 	data := options.Data
@@ -181,15 +392,17 @@ func parseOptions(options *CreateOptions) ([]byte, int32, []zk.ACL) {
 	}
 
 	if acl == nil {
-		acl = zk.WorldACL(zk.PermAll)
+		acl = nodeacl.DefaultFor(aclProvider, nodePath)
+	} else if nodeacl.IsWeakerThanDefault(aclProvider, nodePath, acl) {
+		logger.Warn("ACL weaker than the default one", "path", nodePath, "acl", acl, "default", aclProvider.GetACLForPath(nodePath))
 	}
 
 	return data, flag, acl
 }
 
 func deleteNode(path string) connectionConsumer[bool] {
-	return func(cn *zk.Conn, outChan chan bool) error {
-		exists, _, err := cn.Exists(path)
+	return func(ctx context.Context, executor core.Executor, outChan chan bool) error {
+		exists, _, err := executor.Exists(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -198,7 +411,7 @@ func deleteNode(path string) connectionConsumer[bool] {
 			return coreerr.ErrUnknownNode
 		}
 
-		err = cn.Delete(path, -1)
+		err = executor.Delete(ctx, path, -1)
 		if err != nil {
 			return err
 		}
@@ -208,8 +421,8 @@ func deleteNode(path string) connectionConsumer[bool] {
 }
 
 func updateNode(path string, data []byte) connectionConsumer[int32] {
-	return func(cn *zk.Conn, outChan chan int32) error {
-		exists, _, err := cn.Exists(path)
+	return func(ctx context.Context, executor core.Executor, outChan chan int32) error {
+		exists, _, err := executor.Exists(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -218,7 +431,7 @@ func updateNode(path string, data []byte) connectionConsumer[int32] {
 			return coreerr.ErrUnknownNode
 		}
 
-		stat, err := cn.Set(path, data, -1)
+		stat, err := executor.Set(ctx, path, data, -1)
 		if err != nil {
 			return err
 		}
@@ -228,8 +441,8 @@ func updateNode(path string, data []byte) connectionConsumer[int32] {
 }
 
 func getNode(path string) connectionConsumer[[]byte] {
-	return func(cn *zk.Conn, outChan chan []byte) error {
-		data, _, err := cn.Get(path)
+	return func(ctx context.Context, executor core.Executor, outChan chan []byte) error {
+		data, _, err := executor.Get(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -238,23 +451,50 @@ func getNode(path string) connectionConsumer[[]byte] {
 	}
 }
 
-func recursivelyGrantParent(nodeName string, cn *zk.Conn) error {
+type nodeWithStat struct {
+	data []byte
+	stat *zk.Stat
+}
+
+func getNodeWithStat(path string) connectionConsumer[nodeWithStat] {
+	return func(ctx context.Context, executor core.Executor, outChan chan nodeWithStat) error {
+		data, stat, err := executor.Get(ctx, path)
+		if err != nil {
+			return err
+		}
+		outChan <- nodeWithStat{data: data, stat: stat}
+		return nil
+	}
+}
+
+func updateNodeWithVersion(path string, data []byte, version int32) connectionConsumer[int32] {
+	return func(ctx context.Context, executor core.Executor, outChan chan int32) error {
+		stat, err := executor.Set(ctx, path, data, version)
+		if err != nil {
+			return err
+		}
+		outChan <- stat.Version
+		return nil
+	}
+}
+
+func recursivelyGrantParent(ctx context.Context, executor core.Executor, aclProvider core.ACLProvider, nodeName string) error {
 	parent := path.Dir(nodeName)
 	if parent == "/" {
 		return nil
 	}
 
-	exists, _, err := cn.Exists(parent)
+	exists, _, err := executor.Exists(ctx, parent)
 	if err != nil {
 		return err
 	}
 
 	if !exists {
-		err := recursivelyGrantParent(parent, cn)
+		err := recursivelyGrantParent(ctx, executor, aclProvider, parent)
 		if err != nil {
 			return err
 		}
-		_, err = cn.Create(parent, []byte{}, zk.FlagContainer, zk.WorldACL(zk.PermAll))
+		_, err = executor.Create(ctx, parent, []byte{}, zk.FlagContainer, nodeacl.DefaultFor(aclProvider, parent))
 		if err != nil {
 			return err
 		}
@@ -263,8 +503,8 @@ func recursivelyGrantParent(nodeName string, cn *zk.Conn) error {
 }
 
 func existsNode(path string) connectionConsumer[bool] {
-	return func(cn *zk.Conn, outChan chan bool) error {
-		exists, _, err := cn.Exists(path)
+	return func(ctx context.Context, executor core.Executor, outChan chan bool) error {
+		exists, _, err := executor.Exists(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -275,33 +515,79 @@ func existsNode(path string) connectionConsumer[bool] {
 
 func execute[T any](zkFramework core.ZKFramework, cnConsumer connectionConsumer[T]) (chan T, chan error) {
 
-	outChan := make(chan T)
-	errChan := make(chan error)
+	// both channels hold the single result, neither the consumer nor the operation block once the caller has gone
+	outChan := make(chan T, 1)
+	errChan := make(chan error, 1)
 
 	if !zkFramework.Started() {
 		errChan <- frwkerr.ErrFrameworkNotYetStarted
+		return outChan, errChan
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	go func() {
-		defer close(errChan)
+	if zkFramework.Failed() {
+		errChan <- frwkerr.ErrFrameworkFailed
+		return outChan, errChan
+	}
+
+	begun, err := framework.BeginOperation(zkFramework)
+	if err != nil {
+		errChan <- err
+		return outChan, errChan
+	}
+	end := sync.OnceFunc(begun)
+
+	breaker := zkFramework.CircuitBreaker()
+	if breaker != nil {
+		if err := breaker.Allow(); err != nil {
+			end()
+			errChan <- err
+			return outChan, errChan
+		}
+	}
+	var recordOnce sync.Once
+	record := func(err error) {
+		if breaker != nil {
+			recordOnce.Do(func() { breaker.Done(err) })
+		}
+	}
 
+	ctx, cancel := context.WithTimeout(framework.WithinOperation(context.Background()), zkFramework.OperationTimeout())
+	go func() {
+		consumed := make(chan error, 1)
 		go func() {
-			defer close(outChan)
+			err := consumeSafely(ctx, zkFramework.Executor(), cnConsumer, outChan)
+			end()
+			record(err)
+			consumed <- err
+		}()
 
-			err := cnConsumer(zkFramework.Cn(), outChan)
+		select {
+		case err := <-consumed:
+			cancel()
 			if err != nil {
 				errChan <- err
+				return
 			}
-			cancel()
-		}()
-
-		<-ctx.Done()
-		if ctx.Err() != nil {
-			errChan <- ctx.Err()
+			close(outChan)
+		case <-ctx.Done():
+			end()
+			err := fmt.Errorf("%w: %w", operr.ErrDeadlineExceeded, ctx.Err())
+			record(err)
+			errChan <- err
 		}
-
 	}()
 
 	return outChan, errChan
 }
+
+/*
+consumeSafely runs the connection consumer, converting a panic into an operr.PanicError.
+*/
+func consumeSafely[T any](ctx context.Context, executor core.Executor, cnConsumer connectionConsumer[T], outChan chan T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &operr.PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return cnConsumer(ctx, executor, outChan)
+}
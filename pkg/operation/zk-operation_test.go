@@ -3,11 +3,18 @@ package operation_test
 import (
 	"os"
 	"path"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-zookeeper/zk"
 	"github.com/google/uuid"
 	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/framework/frwkerr"
 	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
 )
 
 const (
@@ -77,6 +84,25 @@ func TestZKOperation(t *testing.T) {
 		}
 	})
 
+	t.Run("Create a sequential node and get its path", func(t *testing.T) {
+		t.Log("Create a sequential node and get its path")
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		nodeName := uuid.New().String() + "-"
+
+		created, err := operation.CreateAndGetPath(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithMode(zk.FlagSequence).Build())
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if !strings.HasPrefix(path.Base(created), nodeName) || path.Base(created) == nodeName {
+			t.Errorf("expected the sequence number appended to %s, got %s", nodeName, created)
+		}
+	})
+
 	t.Run("Create a duplicated node", func(t *testing.T) {
 		t.Log("Create node")
 		zkFramework, err := testutil.ConnectFramework()
@@ -212,4 +238,193 @@ func TestZKOperation(t *testing.T) {
 			t.Error("expected error to be not nil")
 		}
 	})
+
+	t.Run("Get node with stat", func(t *testing.T) {
+		t.Log("Get node with stat")
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		nodeName := path.Join(uuid.New().String(), uuid.New().String())
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		_, stat, err := operation.GetWithStat(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if stat == nil || stat.Version != 0 {
+			t.Errorf("expected version to be 0, got %v", stat)
+		}
+	})
+
+	t.Run("Update node with version", func(t *testing.T) {
+		t.Log("Update node with version")
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		nodeName := path.Join(uuid.New().String(), uuid.New().String())
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		version, err := operation.UpdateWithVersion(zkFramework, nodeName, []byte(uuid.New().String()), 0)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if version != 1 {
+			t.Errorf("expected version to be 1, got %d", version)
+		}
+
+		if _, err := operation.UpdateWithVersion(zkFramework, nodeName, []byte(uuid.New().String()), 0); err == nil {
+			t.Error("expected error to be not nil")
+		}
+	})
+
+	t.Run("Linearizable reads", func(t *testing.T) {
+		t.Log("Linearizable reads")
+		writer, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer writer.Stop()
+		reader, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer reader.Stop()
+
+		parent := uuid.New().String()
+		nodeName := path.Join(parent, uuid.New().String())
+		data := []byte(uuid.New().String())
+		opts := operation.NewCreateOptionsBuilder().WithData(data).Build()
+		if err := operation.CreateWithOptions(writer, nodeName, opts); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		linearizable := operation.NewReadOptionsBuilder().WithConsistency(operation.Linearizable).Build()
+		exists, err := operation.ExistsWithOptions(reader, nodeName, linearizable)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if !exists {
+			t.Errorf("expected node to exist")
+		}
+		readData, err := operation.GetWithOptions(reader, nodeName, linearizable)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if string(readData) != string(data) {
+			t.Errorf("expected data to be %s, got %s", string(data), string(readData))
+		}
+		children, err := operation.LsWithOptions(reader, parent, linearizable)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(children) != 1 {
+			t.Errorf("expected 1 child, got %d", len(children))
+		}
+	})
+
+	t.Run("Stat of a node", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		nodeName := uuid.New().String()
+		opts := operation.NewCreateOptionsBuilder().WithData([]byte("data")).Build()
+		if err := operation.CreateWithOptions(zkFramework, nodeName, opts); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		stat, err := operation.Stat(zkFramework, nodeName)
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if stat.DataLength != 4 {
+			t.Errorf("expected data length 4, got %d", stat.DataLength)
+		}
+
+		if _, err := operation.Stat(zkFramework, uuid.New().String()); err != zk.ErrNoNode {
+			t.Errorf("expected error %v, got %v", zk.ErrNoNode, err)
+		}
+	})
+
+	t.Run("Operation deadline", func(t *testing.T) {
+		t.Log("Operation deadline")
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		hurried := zkFramework.UsingOperationTimeout(time.Nanosecond)
+		if hurried.OperationTimeout() != time.Nanosecond {
+			t.Errorf("expected the operation timeout to be overridden, got %s", hurried.OperationTimeout())
+		}
+		if _, err := operation.Exists(hurried, uuid.New().String()); !operr.IsDeadlineExceeded(err) {
+			t.Errorf("expected error %v, got %v", operr.ErrDeadlineExceeded, err)
+		}
+		if _, err := operation.Exists(zkFramework, uuid.New().String()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	})
+}
+
+func TestOperationsBeforeStart(t *testing.T) {
+	zkFramework, err := framework.CreateFramework(os.Getenv(zkHostEnv))
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	if _, err := operation.Get(zkFramework, uuid.New().String()); !frwkerr.IsFrameworkNotYetStarted(err) {
+		t.Errorf("expected error %v, got %v", frwkerr.ErrFrameworkNotYetStarted, err)
+	}
+}
+
+func TestOperationsDoNotLeak(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		if _, err := operation.Exists(zkFramework, uuid.New().String()); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	if after := runtime.NumGoroutine(); after > before+10 {
+		t.Errorf("expected the operations to release their goroutines, got %d goroutines from %d", after, before)
+	}
+}
+
+func TestTimedOutOperationsDoNotLeak(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	hurried := zkFramework.UsingOperationTimeout(time.Millisecond)
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		if _, err := operation.Exists(hurried, uuid.New().String()); err != nil && !operr.IsDeadlineExceeded(err) {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+	}
+	time.Sleep(time.Second)
+	if after := runtime.NumGoroutine(); after > before+10 {
+		t.Errorf("expected the timed out operations to release their goroutines, got %d goroutines from %d", after, before)
+	}
 }
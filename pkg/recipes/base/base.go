@@ -0,0 +1,187 @@
+/*
+Package base provides the building blocks shared by the coordination recipes, e.g. locks, elections, semaphores and queues:
+participants joining a parent node as protected ephemeral-sequential children, ordered by sequence number, each watching its predecessor.
+*/
+package base
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/operation/operr"
+	"github.com/morphy76/zk/pkg/recipes/base/baseerr"
+)
+
+const (
+	// ProtectedPrefix starts the name of the participant nodes, followed by the participant ID and a dash.
+	ProtectedPrefix = "_c_"
+	joinAttempts    = 3
+	idLength        = 36
+	sequenceLength  = 10
+)
+
+/*
+Participant is a child node taking part in a recipe, named [_c_<id>-]<prefix><sequence>.
+*/
+type Participant struct {
+	// Name is the name of the node.
+	Name string
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// ID identifies the participant, empty for nodes not created by Join.
+	ID string
+	// Prefix is the part of the name preceding the sequence number, e.g. lock-.
+	Prefix string
+	// Sequence is the sequence number assigned by the server.
+	Sequence int64
+}
+
+/*
+ParseName parses the name of a participant node, returning false when it has no sequence number.
+*/
+func ParseName(parent string, name string) (Participant, bool) {
+	sequence, ok := operation.SequenceOf(name)
+	if !ok {
+		return Participant{}, false
+	}
+
+	participant := Participant{Name: name, Path: path.Join(parent, name), Sequence: sequence}
+	rest := name
+	if strings.HasPrefix(name, ProtectedPrefix) && len(name) > len(ProtectedPrefix)+idLength && name[len(ProtectedPrefix)+idLength] == '-' {
+		participant.ID = name[len(ProtectedPrefix) : len(ProtectedPrefix)+idLength]
+		rest = name[len(ProtectedPrefix)+idLength+1:]
+	}
+	participant.Prefix = rest[:len(rest)-sequenceLength]
+	return participant, true
+}
+
+/*
+Join creates an ephemeral-sequential participant node under the parent node, created if missing.
+
+The name of the node embeds a random ID: when the connection is lost before the response arrives, the children are searched for the ID
+instead of creating a second node, which would stay around until the session expires and block the other participants.
+*/
+func Join(zkFramework core.ZKFramework, parent string, prefix string, data []byte) (Participant, error) {
+	id := uuid.New().String()
+	actualPath := path.Join(zkFramework.Namespace(), parent, ProtectedPrefix+id+"-"+prefix)
+	zkFramework.Logger().Debug("joining as participant", "path", actualPath)
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), zkFramework.OperationTimeout())
+		created, err := operation.Executor(zkFramework).Create(ctx, actualPath, data, zk.FlagEphemeral|zk.FlagSequence, nodeacl.DefaultFor(zkFramework.ACLProvider(), actualPath))
+		cancel()
+		if err == nil {
+			participant, _ := ParseName(parent, path.Base(created))
+			return participant, nil
+		}
+		if attempt == joinAttempts {
+			return Participant{}, err
+		}
+
+		switch {
+		case errors.Is(err, zk.ErrNoNode):
+			if err := operation.Create(zkFramework, parent); err != nil && !errors.Is(err, zk.ErrNodeExists) {
+				return Participant{}, err
+			}
+		case operr.IsTransient(err):
+			if err := zkFramework.WaitConnection(zkFramework.OperationTimeout()); err != nil {
+				return Participant{}, err
+			}
+			participants, err := Participants(zkFramework, parent, prefix)
+			if err != nil {
+				return Participant{}, err
+			}
+			if i := slices.IndexFunc(participants, func(p Participant) bool { return p.ID == id }); i >= 0 {
+				return participants[i], nil
+			}
+		default:
+			return Participant{}, err
+		}
+	}
+}
+
+/*
+Leave deletes the node of the participant, a node already deleted is not an error.
+*/
+func Leave(zkFramework core.ZKFramework, participant Participant) error {
+	if err := operation.Delete(zkFramework, participant.Path); err != nil && !errors.Is(err, zk.ErrNoNode) && !coreerr.IsUnknownNode(err) {
+		return err
+	}
+	return nil
+}
+
+/*
+Participants lists the participant nodes under the parent node having the given prefix, ordered by sequence number.
+*/
+func Participants(zkFramework core.ZKFramework, parent string, prefix string) ([]Participant, error) {
+	children, err := operation.Ls(zkFramework, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]Participant, 0, len(children))
+	for _, child := range children {
+		if participant, ok := ParseName(parent, child); ok && participant.Prefix == prefix {
+			participants = append(participants, participant)
+		}
+	}
+	slices.SortFunc(participants, func(a, b Participant) int {
+		return cmp.Compare(a.Sequence, b.Sequence)
+	})
+	return participants, nil
+}
+
+/*
+Predecessor returns the participant preceding the given one, false when it comes first or is not among the participants.
+*/
+func Predecessor(participants []Participant, participant Participant) (Participant, bool) {
+	i := slices.IndexFunc(participants, func(p Participant) bool { return p.Name == participant.Name })
+	if i <= 0 {
+		return Participant{}, false
+	}
+	return participants[i-1], true
+}
+
+/*
+WatchPredecessor watches the participant preceding the given one, returning a channel receiving an event when the predecessor node changes,
+or false when the participant comes first.
+
+Only the predecessor is watched, so that the deletion of a node wakes up a single participant instead of all of them.
+*/
+func WatchPredecessor(zkFramework core.ZKFramework, participant Participant) (<-chan zk.Event, bool, error) {
+	parent := path.Dir(participant.Path)
+	for {
+		participants, err := Participants(zkFramework, parent, participant.Prefix)
+		if err != nil {
+			return nil, false, err
+		}
+		if !slices.ContainsFunc(participants, func(p Participant) bool { return p.Name == participant.Name }) {
+			return nil, false, fmt.Errorf("%w: %s", baseerr.ErrParticipantNotFound, participant.Path)
+		}
+
+		predecessor, ok := Predecessor(participants, participant)
+		if !ok {
+			return nil, false, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), zkFramework.OperationTimeout())
+		exists, _, events, err := operation.Executor(zkFramework).ExistsW(ctx, path.Join(zkFramework.Namespace(), predecessor.Path))
+		cancel()
+		if err != nil {
+			return nil, false, err
+		}
+		if exists {
+			return events, true, nil
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package base_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/recipes/base"
+	"github.com/morphy76/zk/pkg/recipes/base/baseerr"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestParseName(t *testing.T) {
+	t.Run("Parse protected name", func(t *testing.T) {
+		t.Log("Parse protected name")
+		id := uuid.New().String()
+		participant, ok := base.ParseName("/locks", base.ProtectedPrefix+id+"-lock-0000000042")
+		if !ok {
+			t.Fatalf("expected the name to be parsed")
+		}
+		if participant.ID != id || participant.Prefix != "lock-" || participant.Sequence != 42 || participant.Path != "/locks/"+base.ProtectedPrefix+id+"-lock-0000000042" {
+			t.Errorf("unexpected participant %+v", participant)
+		}
+	})
+
+	t.Run("Parse unprotected name", func(t *testing.T) {
+		t.Log("Parse unprotected name")
+		participant, ok := base.ParseName("/locks", "lock-0000000007")
+		if !ok {
+			t.Fatalf("expected the name to be parsed")
+		}
+		if participant.ID != "" || participant.Prefix != "lock-" || participant.Sequence != 7 {
+			t.Errorf("unexpected participant %+v", participant)
+		}
+	})
+
+	t.Run("Parse name without sequence", func(t *testing.T) {
+		t.Log("Parse name without sequence")
+		if _, ok := base.ParseName("/locks", "lock"); ok {
+			t.Errorf("expected the name not to be parsed")
+		}
+	})
+}
+
+func TestParticipants(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Join and list participants", func(t *testing.T) {
+		t.Log("Join and list participants")
+		parent := uuid.New().String()
+		first, err := base.Join(zkFramework, parent, "lock-", nil)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		second, err := base.Join(zkFramework, parent, "lock-", nil)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := base.Join(zkFramework, parent, "read-", nil); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		participants, err := base.Participants(zkFramework, parent, "lock-")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(participants) != 2 || participants[0].Name != first.Name || participants[1].Name != second.Name {
+			t.Errorf("expected %s and %s, got %v", first.Name, second.Name, participants)
+		}
+		if predecessor, ok := base.Predecessor(participants, second); !ok || predecessor.Name != first.Name {
+			t.Errorf("expected predecessor %s, got %s", first.Name, predecessor.Name)
+		}
+		if _, ok := base.Predecessor(participants, first); ok {
+			t.Errorf("expected no predecessor")
+		}
+	})
+
+	t.Run("Watch predecessor", func(t *testing.T) {
+		t.Log("Watch predecessor")
+		parent := uuid.New().String()
+		first, err := base.Join(zkFramework, parent, "lock-", nil)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		second, err := base.Join(zkFramework, parent, "lock-", nil)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		if _, waiting, err := base.WatchPredecessor(zkFramework, first); err != nil || waiting {
+			t.Errorf("expected the first participant not to wait, got %v, %v", waiting, err)
+		}
+		events, waiting, err := base.WatchPredecessor(zkFramework, second)
+		if err != nil || !waiting {
+			t.Fatalf("expected the second participant to wait, got %v, %v", waiting, err)
+		}
+		if err := base.Leave(zkFramework, first); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		select {
+		case <-events:
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected the predecessor deletion to be notified")
+		}
+		if _, waiting, err := base.WatchPredecessor(zkFramework, second); err != nil || waiting {
+			t.Errorf("expected the second participant not to wait, got %v, %v", waiting, err)
+		}
+
+		if err := base.Leave(zkFramework, second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := base.Leave(zkFramework, second); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, _, err := base.WatchPredecessor(zkFramework, second); !baseerr.IsParticipantNotFound(err) {
+			t.Errorf("expected %v, got %v", baseerr.ErrParticipantNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+/*
+Package baseerr provides error types for the base package.
+*/
+package baseerr
+
+import "errors"
+
+/*
+ErrParticipantNotFound is returned when the node of a participant is no longer among the children of its parent, e.g. after its session expired.
+*/
+var ErrParticipantNotFound = errors.New("participant not found")
+
+/*
+IsParticipantNotFound checks if the error is, or wraps, ErrParticipantNotFound.
+*/
+func IsParticipantNotFound(err error) bool {
+	return errors.Is(err, ErrParticipantNotFound)
+}
@@ -0,0 +1,23 @@
+package baseerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/recipes/base/baseerr"
+)
+
+func TestIsParticipantNotFound(t *testing.T) {
+	err := fmt.Errorf("%w: /locks/_c_id-lock-0000000001", baseerr.ErrParticipantNotFound)
+	if !baseerr.IsParticipantNotFound(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsParticipantNotFoundFalse(t *testing.T) {
+	err := errors.New("some error")
+	if baseerr.IsParticipantNotFound(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,75 @@
+package lease
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+LeaseOptions is used to configure the leases.
+*/
+type LeaseOptions struct {
+	// Holder identifies the holder of the acquired leases.
+	Holder string
+	// ExpiryNotice is how long before the expiry of a held lease the expiring callbacks are invoked.
+	ExpiryNotice time.Duration
+	// OnExpiring are the callbacks invoked when a held lease is about to expire, unless it is renewed or released before.
+	OnExpiring []func(lease *Lease)
+}
+
+/*
+LeaseOptionsBuilder is a builder for LeaseOptions.
+*/
+type LeaseOptionsBuilder struct {
+	holder       string
+	expiryNotice time.Duration
+	onExpiring   []func(lease *Lease)
+}
+
+const defaultExpiryNotice = 5 * time.Second
+
+/*
+NewLeaseOptionsBuilder creates a new LeaseOptionsBuilder, holding the leases as a random holder and notifying 5 seconds before expiry.
+*/
+func NewLeaseOptionsBuilder() LeaseOptionsBuilder {
+	return LeaseOptionsBuilder{
+		holder:       uuid.New().String(),
+		expiryNotice: defaultExpiryNotice,
+	}
+}
+
+/*
+WithHolder sets the identity of the holder of the acquired leases, e.g. the host name of the worker.
+*/
+func (b LeaseOptionsBuilder) WithHolder(holder string) LeaseOptionsBuilder {
+	b.holder = holder
+	return b
+}
+
+/*
+WithExpiryNotice sets how long before the expiry of a held lease the expiring callbacks are invoked.
+*/
+func (b LeaseOptionsBuilder) WithExpiryNotice(expiryNotice time.Duration) LeaseOptionsBuilder {
+	b.expiryNotice = expiryNotice
+	return b
+}
+
+/*
+WithOnExpiring registers a callback invoked when a held lease is about to expire.
+*/
+func (b LeaseOptionsBuilder) WithOnExpiring(callback func(lease *Lease)) LeaseOptionsBuilder {
+	b.onExpiring = append(b.onExpiring, callback)
+	return b
+}
+
+/*
+Build builds the LeaseOptions.
+*/
+func (b LeaseOptionsBuilder) Build() LeaseOptions {
+	return LeaseOptions{
+		Holder:       b.holder,
+		ExpiryNotice: b.expiryNotice,
+		OnExpiring:   b.onExpiring,
+	}
+}
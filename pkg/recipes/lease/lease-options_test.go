@@ -0,0 +1,40 @@
+package lease_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/recipes/lease"
+)
+
+func TestDefaultLeaseOptionsBuilder(t *testing.T) {
+	opts := lease.NewLeaseOptionsBuilder().Build()
+
+	if opts.Holder == "" {
+		t.Errorf("Expected a random Holder, got an empty one")
+	}
+	if opts.ExpiryNotice != 5*time.Second {
+		t.Errorf("Expected ExpiryNotice to be %v, got %v", 5*time.Second, opts.ExpiryNotice)
+	}
+	if len(opts.OnExpiring) != 0 {
+		t.Errorf("Expected no OnExpiring callback, got %d", len(opts.OnExpiring))
+	}
+}
+
+func TestLeaseOptionsBuilder(t *testing.T) {
+	opts := lease.NewLeaseOptionsBuilder().
+		WithHolder("worker-1").
+		WithExpiryNotice(time.Second).
+		WithOnExpiring(func(*lease.Lease) {}).
+		Build()
+
+	if opts.Holder != "worker-1" {
+		t.Errorf("Expected Holder to be worker-1, got %s", opts.Holder)
+	}
+	if opts.ExpiryNotice != time.Second {
+		t.Errorf("Expected ExpiryNotice to be %v, got %v", time.Second, opts.ExpiryNotice)
+	}
+	if len(opts.OnExpiring) != 1 {
+		t.Errorf("Expected one OnExpiring callback, got %d", len(opts.OnExpiring))
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Package lease provides named leases: ownership of a resource granted for a duration, kept by renewing it explicitly,
+e.g. to own a long-running task. Unlike a lock, a lease survives the session of its holder until it expires.
+*/
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/recipes/lease/leaseerr"
+)
+
+type record struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+/*
+Leases grants the leases stored under a root node, one node per lease holding its holder and expiry time.
+
+The expiry is checked against the local clock, so the clocks of the holders are expected to be synchronized within a fraction of the TTLs.
+*/
+type Leases struct {
+	framework core.ZKFramework
+	root      string
+	options   LeaseOptions
+}
+
+/*
+NewLeases creates the leases rooted at the given node, using the default options.
+*/
+func NewLeases(zkFramework core.ZKFramework, root string) *Leases {
+	return NewLeasesWithOptions(zkFramework, root, NewLeaseOptionsBuilder().Build())
+}
+
+/*
+NewLeasesWithOptions creates the leases rooted at the given node, specifying the lease options.
+*/
+func NewLeasesWithOptions(zkFramework core.ZKFramework, root string, options LeaseOptions) *Leases {
+	return &Leases{
+		framework: zkFramework,
+		root:      root,
+		options:   options,
+	}
+}
+
+/*
+Acquire acquires the named lease for the given duration, when it is free, expired or already held by the same holder.
+*/
+func (m *Leases) Acquire(name string, ttl time.Duration) (*Lease, error) {
+	nodeName := path.Join(m.root, name)
+	for {
+		data, stat, err := operation.GetWithStat(m.framework, nodeName)
+		if err != nil && !errors.Is(err, zk.ErrNoNode) {
+			return nil, err
+		}
+
+		expires := time.Now().Add(ttl)
+		encoded, encodeErr := json.Marshal(record{Holder: m.options.Holder, Expires: expires})
+		if encodeErr != nil {
+			return nil, encodeErr
+		}
+
+		version := int32(0)
+		if err != nil {
+			err = operation.CreateWithOptions(m.framework, nodeName, operation.NewCreateOptionsBuilder().WithData(encoded).Build())
+		} else {
+			current := record{}
+			if err := json.Unmarshal(data, &current); err != nil {
+				return nil, err
+			}
+			if current.Holder != m.options.Holder && time.Now().Before(current.Expires) {
+				return nil, fmt.Errorf("%w: %s until %s", leaseerr.ErrLeaseHeld, current.Holder, current.Expires.Format(time.RFC3339))
+			}
+			version, err = operation.UpdateWithVersion(m.framework, nodeName, encoded, stat.Version)
+		}
+		if errors.Is(err, zk.ErrNodeExists) || errors.Is(err, zk.ErrBadVersion) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		lease := &Lease{leases: m, name: name, expires: expires, version: version}
+		lease.lock.Lock()
+		lease.schedule()
+		lease.lock.Unlock()
+		return lease, nil
+	}
+}
+
+/*
+Holder returns the holder of the named lease and its expiry time, an empty holder when the lease is free or expired.
+*/
+func (m *Leases) Holder(name string) (string, time.Time, error) {
+	data, err := operation.Get(m.framework, path.Join(m.root, name))
+	if errors.Is(err, zk.ErrNoNode) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	current := record{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return "", time.Time{}, err
+	}
+	if !time.Now().Before(current.Expires) {
+		return "", current.Expires, nil
+	}
+	return current.Holder, current.Expires, nil
+}
+
+/*
+WaitFree waits until the named lease is released or expires, so that the caller can attempt to take it over.
+*/
+func (m *Leases) WaitFree(ctx context.Context, name string) error {
+	actualPath := path.Join(m.framework.Namespace(), m.root, name)
+	for {
+		getCtx, cancel := context.WithTimeout(ctx, m.framework.OperationTimeout())
+		data, _, events, err := operation.Executor(m.framework).GetW(getCtx, actualPath)
+		cancel()
+		if errors.Is(err, zk.ErrNoNode) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		current := record{}
+		if err := json.Unmarshal(data, &current); err != nil {
+			return err
+		}
+		wait := time.Until(current.Expires)
+		if wait <= 0 {
+			return nil
+		}
+
+		select {
+		case <-events:
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+/*
+Lease is a lease held by the local holder.
+*/
+type Lease struct {
+	leases  *Leases
+	name    string
+	expires time.Time
+	version int32
+	timer   *time.Timer
+	lock    sync.Mutex
+}
+
+/*
+Name returns the name of the lease.
+*/
+func (l *Lease) Name() string {
+	return l.name
+}
+
+/*
+Holder returns the holder of the lease.
+*/
+func (l *Lease) Holder() string {
+	return l.leases.options.Holder
+}
+
+/*
+Expires returns the expiry time of the lease.
+*/
+func (l *Lease) Expires() time.Time {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.expires
+}
+
+/*
+Renew extends the lease for the given duration from now, failing with ErrLeaseLost when it was taken over meanwhile.
+*/
+func (l *Lease) Renew(ttl time.Duration) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	expires := time.Now().Add(ttl)
+	data, err := json.Marshal(record{Holder: l.leases.options.Holder, Expires: expires})
+	if err != nil {
+		return err
+	}
+
+	version, err := operation.UpdateWithVersion(l.leases.framework, path.Join(l.leases.root, l.name), data, l.version)
+	if errors.Is(err, zk.ErrBadVersion) || errors.Is(err, zk.ErrNoNode) {
+		l.cancel()
+		return leaseerr.ErrLeaseLost
+	}
+	if err != nil {
+		return err
+	}
+
+	l.version = version
+	l.expires = expires
+	l.schedule()
+	return nil
+}
+
+/*
+Release releases the lease, waking up the holders waiting for it; it fails with ErrLeaseLost when the lease was taken over meanwhile.
+*/
+func (l *Lease) Release() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), l.leases.framework.OperationTimeout())
+	defer cancel()
+	err := operation.Executor(l.leases.framework).Delete(ctx, path.Join(l.leases.framework.Namespace(), l.leases.root, l.name), l.version)
+	if errors.Is(err, zk.ErrBadVersion) {
+		return leaseerr.ErrLeaseLost
+	}
+	if err != nil && !errors.Is(err, zk.ErrNoNode) {
+		return err
+	}
+	return nil
+}
+
+/*
+schedule schedules the expiring callbacks, the lock must be held.
+*/
+func (l *Lease) schedule() {
+	l.cancel()
+	if len(l.leases.options.OnExpiring) == 0 {
+		return
+	}
+	delay := max(time.Until(l.expires)-l.leases.options.ExpiryNotice, 0)
+	l.timer = time.AfterFunc(delay, func() {
+		for _, callback := range l.leases.options.OnExpiring {
+			callback(l)
+		}
+	})
+}
+
+func (l *Lease) cancel() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+}
@@ -0,0 +1,144 @@
+package lease_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/recipes/lease"
+	"github.com/morphy76/zk/pkg/recipes/lease/leaseerr"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestLease(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Acquire, renew and release", func(t *testing.T) {
+		t.Log("Acquire, renew and release")
+		root := uuid.New().String()
+		first := lease.NewLeasesWithOptions(zkFramework, root, lease.NewLeaseOptionsBuilder().WithHolder("first").Build())
+		second := lease.NewLeasesWithOptions(zkFramework, root, lease.NewLeaseOptionsBuilder().WithHolder("second").Build())
+
+		held, err := first.Acquire("task", time.Minute)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := second.Acquire("task", time.Minute); !leaseerr.IsLeaseHeld(err) {
+			t.Errorf("expected %v, got %v", leaseerr.ErrLeaseHeld, err)
+		}
+		if holder, _, err := second.Holder("task"); err != nil || holder != "first" {
+			t.Errorf("expected the lease to be held by first, got %s, %v", holder, err)
+		}
+
+		expires := held.Expires()
+		if err := held.Renew(2 * time.Minute); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if !held.Expires().After(expires) {
+			t.Errorf("expected the renewal to extend the lease, got %v", held.Expires())
+		}
+
+		if err := held.Release(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := second.Acquire("task", time.Minute); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	})
+
+	t.Run("Take over an expired lease", func(t *testing.T) {
+		t.Log("Take over an expired lease")
+		root := uuid.New().String()
+		expiring := make(chan string, 1)
+		opts := lease.NewLeaseOptionsBuilder().
+			WithHolder("first").
+			WithExpiryNotice(100 * time.Millisecond).
+			WithOnExpiring(func(l *lease.Lease) { expiring <- l.Name() }).
+			Build()
+		first := lease.NewLeasesWithOptions(zkFramework, root, opts)
+		second := lease.NewLeasesWithOptions(zkFramework, root, lease.NewLeaseOptionsBuilder().WithHolder("second").Build())
+
+		held, err := first.Acquire("task", 300*time.Millisecond)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		select {
+		case name := <-expiring:
+			if name != "task" {
+				t.Errorf("expected the task lease to be expiring, got %s", name)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("expected the expiring callback to be invoked")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := second.WaitFree(ctx, "task"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := second.Acquire("task", time.Minute); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := held.Renew(time.Minute); !leaseerr.IsLeaseLost(err) {
+			t.Errorf("expected %v, got %v", leaseerr.ErrLeaseLost, err)
+		}
+	})
+
+	t.Run("Wake up on release", func(t *testing.T) {
+		t.Log("Wake up on release")
+		root := uuid.New().String()
+		leases := lease.NewLeases(zkFramework, root)
+		held, err := leases.Acquire("task", time.Minute)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		freed := make(chan error)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			freed <- leases.WaitFree(ctx, "task")
+		}()
+		time.Sleep(100 * time.Millisecond)
+		if err := held.Release(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := <-freed; err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+/*
+Package leaseerr provides error types for the lease package.
+*/
+package leaseerr
+
+import "errors"
+
+/*
+ErrLeaseHeld is returned when a lease is held by another holder and has not expired yet.
+*/
+var ErrLeaseHeld = errors.New("lease held by another holder")
+
+/*
+ErrLeaseLost is returned when a lease is renewed or released after it was taken over by another holder.
+*/
+var ErrLeaseLost = errors.New("lease lost")
+
+/*
+IsLeaseHeld checks if the error is, or wraps, ErrLeaseHeld.
+*/
+func IsLeaseHeld(err error) bool {
+	return errors.Is(err, ErrLeaseHeld)
+}
+
+/*
+IsLeaseLost checks if the error is ErrLeaseLost.
+*/
+func IsLeaseLost(err error) bool {
+	return err == ErrLeaseLost
+}
@@ -0,0 +1,37 @@
+package leaseerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/recipes/lease/leaseerr"
+)
+
+func TestIsLeaseHeld(t *testing.T) {
+	err := fmt.Errorf("%w: worker-1", leaseerr.ErrLeaseHeld)
+	if !leaseerr.IsLeaseHeld(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsLeaseHeldFalse(t *testing.T) {
+	err := errors.New("some error")
+	if leaseerr.IsLeaseHeld(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsLeaseLost(t *testing.T) {
+	err := leaseerr.ErrLeaseLost
+	if !leaseerr.IsLeaseLost(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsLeaseLostFalse(t *testing.T) {
+	err := errors.New("some error")
+	if leaseerr.IsLeaseLost(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import "time"
+
+/*
+DispatcherOptions is used to configure an outbox dispatcher.
+*/
+type DispatcherOptions struct {
+	// RetryInterval is the delay before publishing an entry again after the sink failed.
+	RetryInterval time.Duration
+}
+
+/*
+DispatcherOptionsBuilder is a builder for DispatcherOptions.
+*/
+type DispatcherOptionsBuilder struct {
+	retryInterval time.Duration
+}
+
+const defaultRetryInterval = time.Second
+
+/*
+NewDispatcherOptionsBuilder creates a new DispatcherOptionsBuilder, retrying a failed publication every second.
+*/
+func NewDispatcherOptionsBuilder() DispatcherOptionsBuilder {
+	return DispatcherOptionsBuilder{
+		retryInterval: defaultRetryInterval,
+	}
+}
+
+/*
+WithRetryInterval sets the delay before publishing an entry again after the sink failed.
+*/
+func (b DispatcherOptionsBuilder) WithRetryInterval(retryInterval time.Duration) DispatcherOptionsBuilder {
+	b.retryInterval = retryInterval
+	return b
+}
+
+/*
+Build builds the DispatcherOptions.
+*/
+func (b DispatcherOptionsBuilder) Build() DispatcherOptions {
+	return DispatcherOptions{
+		RetryInterval: b.retryInterval,
+	}
+}
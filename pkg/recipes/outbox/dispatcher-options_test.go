@@ -0,0 +1,26 @@
+package outbox_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/recipes/outbox"
+)
+
+func TestDefaultDispatcherOptionsBuilder(t *testing.T) {
+	opts := outbox.NewDispatcherOptionsBuilder().Build()
+
+	if opts.RetryInterval != time.Second {
+		t.Errorf("Expected RetryInterval to be %v, got %v", time.Second, opts.RetryInterval)
+	}
+}
+
+func TestDispatcherOptionsBuilder(t *testing.T) {
+	opts := outbox.NewDispatcherOptionsBuilder().
+		WithRetryInterval(10 * time.Millisecond).
+		Build()
+
+	if opts.RetryInterval != 10*time.Millisecond {
+		t.Errorf("Expected RetryInterval to be %v, got %v", 10*time.Millisecond, opts.RetryInterval)
+	}
+}
@@ -0,0 +1,240 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/recipes/base"
+	"github.com/morphy76/zk/pkg/recipes/base/baseerr"
+	"github.com/morphy76/zk/pkg/recipes/outbox/outboxerr"
+)
+
+const (
+	dispatchersNode  = "dispatchers"
+	dispatcherPrefix = "dispatcher-"
+)
+
+/*
+Sink publishes the outbox entries to an external system.
+*/
+type Sink interface {
+	// Name identifies the sink in the logs.
+	Name() string
+	// Publish publishes a record, an error makes the dispatcher publish it again after the retry interval.
+	Publish(record Record) error
+}
+
+/*
+Dispatcher publishes the entries of an outbox to a sink, oldest first, deleting each entry once published.
+
+Among the dispatchers of an outbox only the leader publishes, the others wait for it to leave. An entry is published again when the leader
+fails between the publication and the deletion, so the sink is expected to discard the records it already received by ID.
+*/
+type Dispatcher struct {
+	outbox  *Outbox
+	sink    Sink
+	options DispatcherOptions
+
+	leader  bool
+	stop    context.CancelFunc
+	running bool
+	lock    sync.Mutex
+	done    sync.WaitGroup
+}
+
+/*
+NewDispatcher creates a dispatcher of the outbox rooted at the given node, using the default options.
+*/
+func NewDispatcher(zkFramework core.ZKFramework, root string, sink Sink) *Dispatcher {
+	return NewDispatcherWithOptions(zkFramework, root, sink, NewDispatcherOptionsBuilder().Build())
+}
+
+/*
+NewDispatcherWithOptions creates a dispatcher of the outbox rooted at the given node, specifying the dispatcher options.
+*/
+func NewDispatcherWithOptions(zkFramework core.ZKFramework, root string, sink Sink, options DispatcherOptions) *Dispatcher {
+	return &Dispatcher{
+		outbox:  NewOutbox(zkFramework, root),
+		sink:    sink,
+		options: options,
+	}
+}
+
+/*
+IsLeader returns true when the dispatcher is the one publishing the entries.
+*/
+func (d *Dispatcher) IsLeader() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.leader
+}
+
+/*
+Start runs for the leadership and publishes the entries once elected, until stopped.
+*/
+func (d *Dispatcher) Start() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.running {
+		return outboxerr.ErrDispatcherAlreadyStarted
+	}
+	d.running = true
+
+	ctx, stop := context.WithCancel(context.Background())
+	d.stop = stop
+	d.done.Add(1)
+	go d.run(ctx)
+	return nil
+}
+
+/*
+Stop stops publishing and gives up the leadership, a running publication is completed.
+*/
+func (d *Dispatcher) Stop() {
+	d.lock.Lock()
+	if !d.running {
+		d.lock.Unlock()
+		return
+	}
+	d.running = false
+	d.stop()
+	d.lock.Unlock()
+
+	d.done.Wait()
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	defer d.done.Done()
+
+	for ctx.Err() == nil {
+		if err := d.lead(ctx); err != nil && ctx.Err() == nil {
+			d.outbox.framework.Logger().Error("dispatching outbox failed", "outbox", d.outbox.root, "sink", d.sink.Name(), "error", err)
+			select {
+			case <-time.After(d.options.RetryInterval):
+			case <-ctx.Done():
+			}
+		}
+	}
+}
+
+/*
+lead waits for the leadership and then publishes the entries as they are written, until the context is done or the leadership is lost.
+*/
+func (d *Dispatcher) lead(ctx context.Context) error {
+	participant, err := base.Join(d.outbox.framework, path.Join(d.outbox.root, dispatchersNode), dispatcherPrefix, nil)
+	if err != nil {
+		return err
+	}
+	defer base.Leave(d.outbox.framework, participant)
+
+	for {
+		events, waiting, err := base.WatchPredecessor(d.outbox.framework, participant)
+		if err != nil {
+			return err
+		}
+		if !waiting {
+			break
+		}
+		select {
+		case <-events:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	d.outbox.framework.Logger().Info("dispatcher elected leader of outbox", "dispatcher", participant.Name, "outbox", d.outbox.root)
+	d.setLeader(true)
+	defer d.setLeader(false)
+
+	for {
+		children, events, err := d.watchEntries()
+		if err != nil {
+			return err
+		}
+		for _, name := range sortEntries(children) {
+			if err := d.dispatch(ctx, participant, name); err != nil || ctx.Err() != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-events:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) setLeader(leader bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.leader = leader
+}
+
+/*
+watchEntries lists the entries, watching for new ones; a missing entries node is watched for creation.
+*/
+func (d *Dispatcher) watchEntries() ([]string, <-chan zk.Event, error) {
+	actualPath := path.Join(d.outbox.framework.Namespace(), d.outbox.root, entriesNode)
+	executor := operation.Executor(d.outbox.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), d.outbox.framework.OperationTimeout())
+	defer cancel()
+
+	children, _, events, err := executor.ChildrenW(ctx, actualPath)
+	if errors.Is(err, zk.ErrNoNode) {
+		exists, _, existsEvents, err := executor.ExistsW(ctx, actualPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if exists {
+			return d.watchEntries()
+		}
+		return []string{}, existsEvents, nil
+	}
+	return children, events, err
+}
+
+/*
+dispatch publishes an entry until the sink accepts it, then deletes it; the leadership is checked before each publication.
+*/
+func (d *Dispatcher) dispatch(ctx context.Context, participant base.Participant, name string) error {
+	record, ok, err := d.outbox.read(name)
+	if err != nil || !ok {
+		return err
+	}
+
+	for {
+		exists, err := operation.Exists(d.outbox.framework, participant.Path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s", baseerr.ErrParticipantNotFound, participant.Path)
+		}
+
+		err = d.sink.Publish(record)
+		if err == nil {
+			break
+		}
+		d.outbox.framework.Logger().Error("publishing outbox entry failed", "entry", name, "sink", d.sink.Name(), "error", err)
+		select {
+		case <-time.After(d.options.RetryInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	if err := operation.Delete(d.outbox.framework, path.Join(d.outbox.root, entriesNode, name)); err != nil && !coreerr.IsUnknownNode(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+Package outbox implements the transactional outbox recipe: application nodes are updated together with the append of an outbox entry,
+in a single multi operation, and a leader-elected dispatcher publishes the entries to an external sink.
+*/
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const (
+	entriesNode = "entries"
+	entryPrefix = "entry-"
+)
+
+/*
+Record is an outbox entry, as published by the dispatcher.
+*/
+type Record struct {
+	// ID is the name of the entry node, unique in the outbox, to let the sink discard the records published twice.
+	ID string
+	// Data is the data of the entry.
+	Data []byte
+	// Created is the time the entry was written.
+	Created time.Time
+}
+
+type changeKind int
+
+const (
+	changeSetData changeKind = iota
+	changeCreate
+	changeDelete
+	changeCheck
+)
+
+/*
+Change is a change of an application node, applied atomically with the append of an outbox entry.
+*/
+type Change struct {
+	kind     changeKind
+	nodeName string
+	data     []byte
+	version  int32
+}
+
+/*
+SetData changes the data of the node, version -1 meaning any version.
+*/
+func SetData(nodeName string, data []byte, version int32) Change {
+	return Change{kind: changeSetData, nodeName: nodeName, data: data, version: version}
+}
+
+/*
+Create creates the node with the given data, its parent must exist.
+*/
+func Create(nodeName string, data []byte) Change {
+	return Change{kind: changeCreate, nodeName: nodeName, data: data}
+}
+
+/*
+Delete deletes the node, version -1 meaning any version.
+*/
+func Delete(nodeName string, version int32) Change {
+	return Change{kind: changeDelete, nodeName: nodeName, version: version}
+}
+
+/*
+Check checks the version of the node without changing it.
+*/
+func Check(nodeName string, version int32) Change {
+	return Change{kind: changeCheck, nodeName: nodeName, version: version}
+}
+
+func (c Change) request(zkFramework core.ZKFramework) any {
+	actualPath := path.Join(zkFramework.Namespace(), c.nodeName)
+	switch c.kind {
+	case changeCreate:
+		return &zk.CreateRequest{Path: actualPath, Data: c.data, Acl: nodeacl.DefaultFor(zkFramework.ACLProvider(), actualPath)}
+	case changeDelete:
+		return &zk.DeleteRequest{Path: actualPath, Version: c.version}
+	case changeCheck:
+		return &zk.CheckVersionRequest{Path: actualPath, Version: c.version}
+	default:
+		return &zk.SetDataRequest{Path: actualPath, Data: c.data, Version: c.version}
+	}
+}
+
+/*
+Outbox is an outbox rooted at the given node, holding the entries to publish as sequential nodes.
+*/
+type Outbox struct {
+	framework core.ZKFramework
+	root      string
+}
+
+/*
+NewOutbox creates an outbox rooted at the given node.
+*/
+func NewOutbox(zkFramework core.ZKFramework, root string) *Outbox {
+	return &Outbox{
+		framework: zkFramework,
+		root:      root,
+	}
+}
+
+/*
+Write applies the changes and appends an entry with the given data to the outbox, in a single multi operation, returning the ID of the entry.
+
+Either every change is applied and the entry is appended or nothing is, the error being the one of the first failed operation.
+*/
+func (o *Outbox) Write(data []byte, changes ...Change) (string, error) {
+	entryPath := path.Join(o.framework.Namespace(), o.root, entriesNode, entryPrefix)
+	ops := make([]any, 0, len(changes)+1)
+	for _, change := range changes {
+		ops = append(ops, change.request(o.framework))
+	}
+	ops = append(ops, &zk.CreateRequest{Path: entryPath, Data: data, Acl: nodeacl.DefaultFor(o.framework.ACLProvider(), entryPath), Flags: zk.FlagSequence})
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), o.framework.OperationTimeout())
+		responses, err := operation.Executor(o.framework).Multi(ctx, ops...)
+		cancel()
+		failed := slices.IndexFunc(responses, func(response zk.MultiResponse) bool { return response.Error != nil })
+		if failed < 0 {
+			if err != nil {
+				return "", err
+			}
+			return path.Base(responses[len(responses)-1].String), nil
+		}
+
+		err = responses[failed].Error
+		if failed != len(ops)-1 || !errors.Is(err, zk.ErrNoNode) {
+			return "", err
+		}
+		if err := operation.Create(o.framework, path.Join(o.root, entriesNode)); err != nil && !errors.Is(err, zk.ErrNodeExists) {
+			return "", err
+		}
+	}
+}
+
+/*
+Pending returns the entries not published yet, oldest first.
+*/
+func (o *Outbox) Pending() ([]Record, error) {
+	names, err := o.pendingNames()
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(names))
+	for _, name := range names {
+		record, ok, err := o.read(name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (o *Outbox) pendingNames() ([]string, error) {
+	children, err := operation.Ls(o.framework, o.root, entriesNode)
+	if errors.Is(err, zk.ErrNoNode) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sortEntries(children), nil
+}
+
+/*
+read reads an entry, returning false when it has been published and deleted meanwhile.
+*/
+func (o *Outbox) read(name string) (Record, bool, error) {
+	data, stat, err := operation.GetWithStat(o.framework, path.Join(o.root, entriesNode, name))
+	if errors.Is(err, zk.ErrNoNode) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, err
+	}
+	return Record{ID: name, Data: data, Created: time.UnixMilli(stat.Ctime)}, true, nil
+}
+
+func sortEntries(children []string) []string {
+	entries := []string{}
+	for _, child := range children {
+		if _, ok := operation.SequenceOf(child); ok && strings.HasPrefix(child, entryPrefix) {
+			entries = append(entries, child)
+		}
+	}
+	slices.Sort(entries)
+	return entries
+}
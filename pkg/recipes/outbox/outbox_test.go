@@ -0,0 +1,169 @@
+package outbox_test
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/recipes/outbox"
+	"github.com/morphy76/zk/pkg/recipes/outbox/outboxerr"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+type recordingSink struct {
+	failures int
+	records  []outbox.Record
+	lock     sync.Mutex
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Publish(record outbox.Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *recordingSink) published() []outbox.Record {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]outbox.Record{}, s.records...)
+}
+
+func TestOutbox(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Write changes and entries atomically", func(t *testing.T) {
+		t.Log("Write changes and entries atomically")
+		root := uuid.New().String()
+		app := uuid.New().String()
+		if err := operation.Create(zkFramework, app); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		box := outbox.NewOutbox(zkFramework, root)
+
+		id, err := box.Write([]byte("order created"), outbox.Create(app+"/order", []byte("new")), outbox.SetData(app, []byte("1 order"), 0))
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if data, err := operation.Get(zkFramework, app+"/order"); err != nil || string(data) != "new" {
+			t.Errorf("expected the order to be created, got %s, %v", data, err)
+		}
+
+		if _, err := box.Write([]byte("order updated"), outbox.SetData(app, []byte("stale"), 0)); !errors.Is(err, zk.ErrBadVersion) {
+			t.Errorf("expected %v, got %v", zk.ErrBadVersion, err)
+		}
+		pending, err := box.Pending()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(pending) != 1 || pending[0].ID != id || string(pending[0].Data) != "order created" {
+			t.Errorf("expected only the first entry to be pending, got %v", pending)
+		}
+	})
+
+	t.Run("Dispatch entries through the leader", func(t *testing.T) {
+		t.Log("Dispatch entries through the leader")
+		root := uuid.New().String()
+		box := outbox.NewOutbox(zkFramework, root)
+		for _, data := range []string{"a", "b"} {
+			if _, err := box.Write([]byte(data)); err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+
+		sink := &recordingSink{failures: 2}
+		opts := outbox.NewDispatcherOptionsBuilder().WithRetryInterval(10 * time.Millisecond).Build()
+		leader := outbox.NewDispatcherWithOptions(zkFramework, root, sink, opts)
+		if err := leader.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer leader.Stop()
+		if err := leader.Start(); !outboxerr.IsDispatcherAlreadyStarted(err) {
+			t.Errorf("expected %v, got %v", outboxerr.ErrDispatcherAlreadyStarted, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		follower := outbox.NewDispatcherWithOptions(zkFramework, root, sink, opts)
+		if err := follower.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer follower.Stop()
+
+		if _, err := box.Write([]byte("c")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		for range 50 {
+			if len(sink.published()) == 3 {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		published := sink.published()
+		if len(published) != 3 || string(published[0].Data) != "a" || string(published[2].Data) != "c" {
+			t.Errorf("expected a, b and c to be published in order, got %v", published)
+		}
+		if !leader.IsLeader() || follower.IsLeader() {
+			t.Errorf("expected the first dispatcher to lead, got %v and %v", leader.IsLeader(), follower.IsLeader())
+		}
+		if pending, err := box.Pending(); err != nil || len(pending) != 0 {
+			t.Errorf("expected no pending entry, got %v, %v", pending, err)
+		}
+
+		leader.Stop()
+		for range 50 {
+			if follower.IsLeader() {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if !follower.IsLeader() {
+			t.Errorf("expected the follower to take over the leadership")
+		}
+	})
+}
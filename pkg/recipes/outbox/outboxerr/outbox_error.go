@@ -0,0 +1,18 @@
+/*
+Package outboxerr provides error types for the outbox package.
+*/
+package outboxerr
+
+import "errors"
+
+/*
+ErrDispatcherAlreadyStarted is returned when a dispatcher is started twice.
+*/
+var ErrDispatcherAlreadyStarted = errors.New("dispatcher already started")
+
+/*
+IsDispatcherAlreadyStarted checks if the error is ErrDispatcherAlreadyStarted.
+*/
+func IsDispatcherAlreadyStarted(err error) bool {
+	return err == ErrDispatcherAlreadyStarted
+}
@@ -0,0 +1,22 @@
+package outboxerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/recipes/outbox/outboxerr"
+)
+
+func TestIsDispatcherAlreadyStarted(t *testing.T) {
+	err := outboxerr.ErrDispatcherAlreadyStarted
+	if !outboxerr.IsDispatcherAlreadyStarted(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsDispatcherAlreadyStartedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if outboxerr.IsDispatcherAlreadyStarted(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Package singleton runs tasks on a single process of the cluster at a time, electing the process running each task.
+*/
+package singleton
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/recipes/base"
+)
+
+const (
+	candidatePrefix = "candidate-"
+	retryInterval   = time.Second
+)
+
+/*
+Task is the function run by the elected process, it must return when its context is cancelled.
+*/
+type Task func(ctx context.Context) error
+
+/*
+Singleton elects, for each named task, the process running it among the processes sharing the root node.
+*/
+type Singleton struct {
+	framework core.ZKFramework
+	root      string
+}
+
+/*
+NewSingleton creates a singleton runner electing the processes under the given root node.
+*/
+func NewSingleton(zkFramework core.ZKFramework, root string) *Singleton {
+	return &Singleton{
+		framework: zkFramework,
+		root:      root,
+	}
+}
+
+/*
+RunExclusive runs the task once the process is elected for the given name, until the task returns or the context is done.
+
+When the leadership is lost, e.g. because the session expired, or can no longer be asserted because the connection is suspended,
+the context of the task is cancelled and, once the task returned,
+the process runs for the leadership again, restarting the task when elected. The result of the task is returned and the leadership released
+when the task returns by itself; the error of the context is returned when it is done.
+*/
+func (s *Singleton) RunExclusive(ctx context.Context, name string, task Task) error {
+	for {
+		lost, err := s.runOnce(ctx, name, task)
+		if !lost {
+			return err
+		}
+		if err != nil {
+			s.framework.Logger().Error("running singleton task failed", "task", name, "error", err)
+		}
+
+		select {
+		case <-time.After(retryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+/*
+runOnce joins the candidates, waits for the leadership and runs the task, returning true when it must run again: the leadership was lost or could not be acquired.
+*/
+func (s *Singleton) runOnce(ctx context.Context, name string, task Task) (bool, error) {
+	participant, err := base.Join(s.framework, path.Join(s.root, name), candidatePrefix, nil)
+	if err != nil {
+		return true, err
+	}
+	defer base.Leave(s.framework, participant)
+
+	for {
+		events, waiting, err := base.WatchPredecessor(s.framework, participant)
+		if err != nil {
+			return true, err
+		}
+		if !waiting {
+			break
+		}
+		select {
+		case <-events:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+
+	s.framework.Logger().Info("process elected to run singleton task", "process", participant.Name, "task", name)
+	disconnection := newDisconnectionListener()
+	if err := s.framework.AddStatusChangeListener(disconnection); err != nil {
+		return true, err
+	}
+	defer s.framework.RemoveStatusChangeListener(disconnection)
+	if !s.framework.ConnectionState().IsConnected() {
+		return true, nil
+	}
+
+	taskCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- task(taskCtx)
+	}()
+
+	for {
+		existsCtx, cancelExists := context.WithTimeout(context.Background(), s.framework.OperationTimeout())
+		exists, _, events, err := operation.Executor(s.framework).ExistsW(existsCtx, path.Join(s.framework.Namespace(), participant.Path))
+		cancelExists()
+		if err == nil && !exists {
+			s.framework.Logger().Warn("process lost the leadership of singleton task", "process", participant.Name, "task", name)
+			cancel()
+			return true, <-done
+		}
+		retry := time.After(retryInterval)
+		if err == nil {
+			retry = nil
+		}
+
+		select {
+		case err := <-done:
+			return false, err
+		case <-events:
+		case <-retry:
+		case <-disconnection.disconnected:
+			s.framework.Logger().Warn("process suspended the singleton task, connection lost", "process", participant.Name, "task", name)
+			cancel()
+			return true, <-done
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return false, ctx.Err()
+		}
+	}
+}
+
+/*
+disconnectionListener signals the suspension or the loss of the connection: the leadership can no longer be asserted, the session may be gone.
+*/
+type disconnectionListener struct {
+	id           string
+	disconnected chan struct{}
+	once         sync.Once
+}
+
+func newDisconnectionListener() *disconnectionListener {
+	return &disconnectionListener{
+		id:           uuid.New().String(),
+		disconnected: make(chan struct{}),
+	}
+}
+
+func (l *disconnectionListener) UUID() string {
+	return l.id
+}
+
+func (l *disconnectionListener) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	return nil
+}
+
+func (l *disconnectionListener) OnConnectionStateChange(zkFramework core.ZKFramework, state core.ConnectionState) error {
+	if state == core.ConnectionStateSuspended || state == core.ConnectionStateLost {
+		l.once.Do(func() { close(l.disconnected) })
+	}
+	return nil
+}
+
+func (l *disconnectionListener) Stop() {}
@@ -0,0 +1,178 @@
+package singleton_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/framework"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/recipes/singleton"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestSingleton(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Run a single task at a time", func(t *testing.T) {
+		t.Log("Run a single task at a time")
+		root := uuid.New().String()
+		running := atomic.Int32{}
+		started := atomic.Int32{}
+		task := func(ctx context.Context) error {
+			started.Add(1)
+			if running.Add(1) > 1 {
+				t.Errorf("expected a single running task")
+			}
+			defer running.Add(-1)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		firstCtx, stopFirst := context.WithCancel(context.Background())
+		first := make(chan error)
+		go func() {
+			first <- singleton.NewSingleton(zkFramework, root).RunExclusive(firstCtx, "task", task)
+		}()
+		time.Sleep(200 * time.Millisecond)
+
+		secondCtx, stopSecond := context.WithCancel(context.Background())
+		defer stopSecond()
+		go singleton.NewSingleton(zkFramework, root).RunExclusive(secondCtx, "task", task)
+		time.Sleep(200 * time.Millisecond)
+		if started.Load() != 1 {
+			t.Errorf("expected the task to be started once, got %d", started.Load())
+		}
+
+		stopFirst()
+		if err := <-first; !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v, got %v", context.Canceled, err)
+		}
+		for range 50 {
+			if started.Load() == 2 {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if started.Load() != 2 || running.Load() != 1 {
+			t.Errorf("expected the second process to take over, got %d started and %d running", started.Load(), running.Load())
+		}
+	})
+
+	t.Run("Restart the task after losing the leadership", func(t *testing.T) {
+		t.Log("Restart the task after losing the leadership")
+		root := uuid.New().String()
+		started := make(chan bool, 2)
+		task := func(ctx context.Context) error {
+			started <- true
+			<-ctx.Done()
+			return nil
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go singleton.NewSingleton(zkFramework, root).RunExclusive(ctx, "task", task)
+		<-started
+
+		candidates, err := operation.Ls(zkFramework, root, "task")
+		if err != nil || len(candidates) != 1 {
+			t.Fatalf("expected one candidate, got %v, %v", candidates, err)
+		}
+		if err := operation.Delete(zkFramework, path.Join(root, "task", candidates[0])); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected the task to be restarted")
+		}
+	})
+
+	t.Run("Cancel the task when the connection is suspended", func(t *testing.T) {
+		t.Log("Cancel the task when the connection is suspended")
+		proxy, err := testutil.StartProxy(os.Getenv(zkHostEnv))
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		proxiedFramework, err := framework.CreateFrameworkWithOptions(proxy.Addr(), framework.WithSessionTimeout(20*time.Second))
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		if err := proxiedFramework.Start(); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer proxiedFramework.Stop()
+		if err := proxiedFramework.WaitConnection(10 * time.Second); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+
+		started := make(chan bool, 1)
+		cancelled := make(chan bool, 1)
+		task := func(ctx context.Context) error {
+			started <- true
+			<-ctx.Done()
+			cancelled <- true
+			return nil
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go singleton.NewSingleton(proxiedFramework, uuid.New().String()).RunExclusive(ctx, "task", task)
+		<-started
+
+		proxy.Close()
+		select {
+		case <-cancelled:
+		case <-time.After(10 * time.Second):
+			t.Errorf("expected the task to be cancelled before the session expires")
+		}
+	})
+
+	t.Run("Return the result of the task", func(t *testing.T) {
+		t.Log("Return the result of the task")
+		failure := errors.New("task failed")
+		err := singleton.NewSingleton(zkFramework, uuid.New().String()).RunExclusive(context.Background(), "task", func(context.Context) error {
+			return failure
+		})
+		if err != failure {
+			t.Errorf("expected %v, got %v", failure, err)
+		}
+	})
+}
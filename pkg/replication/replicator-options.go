@@ -0,0 +1,66 @@
+package replication
+
+/*
+ConflictPolicy decides how a change is applied when the destination node was modified outside of the replicator.
+*/
+type ConflictPolicy int
+
+const (
+	// SourceWins overwrites the destination node with the source data.
+	SourceWins ConflictPolicy = iota
+	// DestinationWins keeps the destination node as is.
+	DestinationWins
+)
+
+/*
+ReplicatorOptions is used to configure the replicator.
+*/
+type ReplicatorOptions struct {
+	// DestinationRoot is the path of the replicated subtree on the destination, empty means the source path.
+	DestinationRoot string
+	// ConflictPolicy decides how conflicting changes are applied.
+	ConflictPolicy ConflictPolicy
+}
+
+/*
+ReplicatorOptionsBuilder is a builder for ReplicatorOptions.
+*/
+type ReplicatorOptionsBuilder struct {
+	destinationRoot string
+	conflictPolicy  ConflictPolicy
+}
+
+/*
+NewReplicatorOptionsBuilder creates a new ReplicatorOptionsBuilder.
+*/
+func NewReplicatorOptionsBuilder() ReplicatorOptionsBuilder {
+	return ReplicatorOptionsBuilder{
+		conflictPolicy: SourceWins,
+	}
+}
+
+/*
+WithDestinationRoot sets the path of the replicated subtree on the destination.
+*/
+func (b ReplicatorOptionsBuilder) WithDestinationRoot(destinationRoot string) ReplicatorOptionsBuilder {
+	b.destinationRoot = destinationRoot
+	return b
+}
+
+/*
+WithConflictPolicy sets the policy deciding how conflicting changes are applied.
+*/
+func (b ReplicatorOptionsBuilder) WithConflictPolicy(conflictPolicy ConflictPolicy) ReplicatorOptionsBuilder {
+	b.conflictPolicy = conflictPolicy
+	return b
+}
+
+/*
+Build builds the ReplicatorOptions.
+*/
+func (b ReplicatorOptionsBuilder) Build() ReplicatorOptions {
+	return ReplicatorOptions{
+		DestinationRoot: b.destinationRoot,
+		ConflictPolicy:  b.conflictPolicy,
+	}
+}
@@ -0,0 +1,32 @@
+package replication_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/replication"
+)
+
+func TestDefaultReplicatorOptionsBuilder(t *testing.T) {
+	opts := replication.NewReplicatorOptionsBuilder().Build()
+
+	if opts.DestinationRoot != "" {
+		t.Errorf("Expected DestinationRoot to be empty, got %s", opts.DestinationRoot)
+	}
+	if opts.ConflictPolicy != replication.SourceWins {
+		t.Errorf("Expected ConflictPolicy to be %v, got %v", replication.SourceWins, opts.ConflictPolicy)
+	}
+}
+
+func TestReplicatorOptionsBuilder(t *testing.T) {
+	opts := replication.NewReplicatorOptionsBuilder().
+		WithDestinationRoot("/replica").
+		WithConflictPolicy(replication.DestinationWins).
+		Build()
+
+	if opts.DestinationRoot != "/replica" {
+		t.Errorf("Expected DestinationRoot to be /replica, got %s", opts.DestinationRoot)
+	}
+	if opts.ConflictPolicy != replication.DestinationWins {
+		t.Errorf("Expected ConflictPolicy to be %v, got %v", replication.DestinationWins, opts.ConflictPolicy)
+	}
+}
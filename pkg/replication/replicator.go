@@ -0,0 +1,197 @@
+/*
+Package replication replicates a subtree from a source framework to a destination framework, e.g. another cluster.
+*/
+package replication
+
+import (
+	"bytes"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/watcher"
+)
+
+/*
+Stats are the replication metrics.
+*/
+type Stats struct {
+	// Applied is the number of changes applied to the destination.
+	Applied int64
+	// Conflicts is the number of changes conflicting with modifications made outside of the replicator.
+	Conflicts int64
+	// Errors is the number of changes that could not be applied.
+	Errors int64
+	// Lag is the delay between the last replicated modification on the source and its application on the destination.
+	Lag time.Duration
+	// LastApplied is the time the last change was applied.
+	LastApplied time.Time
+}
+
+/*
+Replicator watches a subtree on the source and applies its changes to the destination.
+
+Ephemeral nodes are not replicated, their lifecycle belongs to the sessions of the source.
+*/
+type Replicator struct {
+	source          core.ZKFramework
+	destination     core.ZKFramework
+	sourceRoot      string
+	destinationRoot string
+	conflictPolicy  ConflictPolicy
+	watcher         *watcher.TreeWatcher
+
+	versions map[string]int32
+	stats    Stats
+	lock     sync.Mutex
+}
+
+/*
+NewReplicator creates a replicator of the subtree at the given path, using the default options.
+*/
+func NewReplicator(source core.ZKFramework, destination core.ZKFramework, root string) *Replicator {
+	return NewReplicatorWithOptions(source, destination, root, NewReplicatorOptionsBuilder().Build())
+}
+
+/*
+NewReplicatorWithOptions creates a replicator of the subtree at the given path, specifying the replicator options.
+*/
+func NewReplicatorWithOptions(source core.ZKFramework, destination core.ZKFramework, root string, options ReplicatorOptions) *Replicator {
+	destinationRoot := options.DestinationRoot
+	if destinationRoot == "" {
+		destinationRoot = root
+	}
+
+	r := &Replicator{
+		source:          source,
+		destination:     destination,
+		sourceRoot:      strings.Trim(path.Clean("/"+root), "/"),
+		destinationRoot: destinationRoot,
+		conflictPolicy:  options.ConflictPolicy,
+		versions:        make(map[string]int32),
+	}
+	r.watcher = watcher.NewTreeWatcher(source, root, r.onEvent)
+	return r
+}
+
+/*
+Start replicates the current subtree and starts following its changes.
+*/
+func (r *Replicator) Start() error {
+	return r.watcher.Start()
+}
+
+/*
+Stop stops following the subtree changes.
+*/
+func (r *Replicator) Stop() {
+	r.watcher.Stop()
+}
+
+/*
+Stats returns the replication metrics.
+*/
+func (r *Replicator) Stats() Stats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.stats
+}
+
+func (r *Replicator) onEvent(event watcher.TreeEvent) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if event.Stat != nil && event.Stat.EphemeralOwner != 0 {
+		return
+	}
+
+	destinationPath := path.Join(r.destinationRoot, strings.TrimPrefix(strings.TrimPrefix(event.Path, r.sourceRoot), "/"))
+
+	var err error
+	switch event.Type {
+	case watcher.TreeNodeAdded, watcher.TreeNodeUpdated:
+		err = r.write(destinationPath, event.Data)
+	case watcher.TreeNodeRemoved:
+		err = r.remove(destinationPath)
+	}
+	if err != nil {
+		r.stats.Errors++
+		r.source.Logger().Error("replicator error replicating", "root", r.sourceRoot, "path", event.Path, "destination", destinationPath, "error", err)
+		return
+	}
+
+	r.stats.Applied++
+	r.stats.LastApplied = time.Now()
+	if event.Stat != nil {
+		r.stats.Lag = r.stats.LastApplied.Sub(time.UnixMilli(event.Stat.Mtime))
+	}
+}
+
+func (r *Replicator) write(destinationPath string, data []byte) error {
+	current, stat, err := operation.GetWithStat(r.destination, destinationPath)
+	if errors.Is(err, zk.ErrNoNode) {
+		err := operation.CreateWithOptions(r.destination, destinationPath, operation.NewCreateOptionsBuilder().WithData(data).Build())
+		if err != nil {
+			return err
+		}
+		_, stat, err := operation.GetWithStat(r.destination, destinationPath)
+		if err != nil {
+			return err
+		}
+		r.versions[destinationPath] = stat.Version
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(current, data) {
+		r.versions[destinationPath] = stat.Version
+		return nil
+	}
+
+	if version, tracked := r.versions[destinationPath]; !tracked || version != stat.Version {
+		r.stats.Conflicts++
+		if r.conflictPolicy == DestinationWins {
+			return nil
+		}
+	}
+
+	version, err := operation.UpdateWithVersion(r.destination, destinationPath, data, stat.Version)
+	if err != nil {
+		return err
+	}
+	r.versions[destinationPath] = version
+	return nil
+}
+
+func (r *Replicator) remove(destinationPath string) error {
+	_, stat, err := operation.GetWithStat(r.destination, destinationPath)
+	if errors.Is(err, zk.ErrNoNode) {
+		delete(r.versions, destinationPath)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if version, tracked := r.versions[destinationPath]; !tracked || version != stat.Version {
+		r.stats.Conflicts++
+		if r.conflictPolicy == DestinationWins {
+			return nil
+		}
+	}
+
+	delete(r.versions, destinationPath)
+	err = operation.Delete(r.destination, destinationPath)
+	if coreerr.IsUnknownNode(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+package replication_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/replication"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestReplicator(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	source := zkFramework.UsingNamespace(uuid.New().String())
+	destination := zkFramework.UsingNamespace(uuid.New().String())
+
+	if err := operation.CreateWithOptions(source, "config/a", operation.NewCreateOptionsBuilder().WithData([]byte("a")).Build()); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	replicator := replication.NewReplicator(source, destination, "config")
+	if err := replicator.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer replicator.Stop()
+
+	waitForData(t, destination, "config/a", "a")
+
+	t.Run("Updates are replicated", func(t *testing.T) {
+		t.Log("Updates are replicated")
+		if _, err := operation.Update(source, "config/a", []byte("b")); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		waitForData(t, destination, "config/a", "b")
+	})
+
+	t.Run("Deletes are replicated", func(t *testing.T) {
+		t.Log("Deletes are replicated")
+		if err := operation.Delete(source, "config/a"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			if exists, _ := operation.Exists(destination, "config/a"); !exists {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("expected the node to be deleted on the destination")
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		stats := replicator.Stats()
+		if stats.Applied < 3 {
+			t.Errorf("expected at least 3 applied changes, got %d", stats.Applied)
+		}
+		if stats.Errors != 0 {
+			t.Errorf("expected no errors, got %d", stats.Errors)
+		}
+	})
+}
+
+func TestReplicatorDestinationWins(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	source := zkFramework.UsingNamespace(uuid.New().String())
+	destination := zkFramework.UsingNamespace(uuid.New().String())
+
+	if err := operation.CreateWithOptions(source, "config/a", operation.NewCreateOptionsBuilder().WithData([]byte("source")).Build()); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if err := operation.CreateWithOptions(destination, "replica/a", operation.NewCreateOptionsBuilder().WithData([]byte("destination")).Build()); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	options := replication.NewReplicatorOptionsBuilder().
+		WithDestinationRoot("replica").
+		WithConflictPolicy(replication.DestinationWins).
+		Build()
+	replicator := replication.NewReplicatorWithOptions(source, destination, "config", options)
+	if err := replicator.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer replicator.Stop()
+
+	if stats := replicator.Stats(); stats.Conflicts != 1 {
+		t.Errorf("expected 1 conflict, got %d", stats.Conflicts)
+	}
+	data, err := operation.Get(destination, "replica/a")
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if string(data) != "destination" {
+		t.Errorf("expected destination, got %s", data)
+	}
+}
+
+func waitForData(t *testing.T, zkFramework core.ZKFramework, nodeName string, expected string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := operation.Get(zkFramework, nodeName)
+		if err == nil && string(data) == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s to contain %s, got %s (%v)", nodeName, expected, data, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
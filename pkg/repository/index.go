@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/go-zookeeper/zk"
+	nodeacl "github.com/morphy76/zk/pkg/acl"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/repository/repoerr"
+)
+
+const (
+	indexRootSuffix = "@indexes"
+	indexAttempts   = 5
+)
+
+type index[T any] struct {
+	name string
+	key  func(entity T) string
+}
+
+/*
+AddIndex maintains a unique secondary index mapping the key of each saved entity to its ID, so that FindBy looks entities up without reading all of them.
+
+The index nodes are stored under <root>@indexes/<name>, named after the SHA-256 of the key, and written along with the entity in a single multi operation.
+Entities with an empty key are not indexed; entities saved before the index was added are indexed when saved again.
+Indexes must be added before the repository is used.
+*/
+func (r *Repository[T]) AddIndex(name string, key func(entity T) string) error {
+	if validateID(name) != nil || slices.ContainsFunc(r.indexes, func(i index[T]) bool { return i.name == name }) {
+		return repoerr.ErrInvalidIndex
+	}
+	r.indexes = append(r.indexes, index[T]{name: name, key: key})
+	return nil
+}
+
+/*
+FindBy reads the entity mapped to the given key by the index.
+*/
+func (r *Repository[T]) FindBy(name string, key string) (T, error) {
+	var entity T
+	if !slices.ContainsFunc(r.indexes, func(i index[T]) bool { return i.name == name }) {
+		return entity, repoerr.ErrUnknownIndex
+	}
+
+	id, err := operation.Get(r.framework, r.indexNode(name, key))
+	if errors.Is(err, zk.ErrNoNode) {
+		return entity, repoerr.ErrEntityNotFound
+	}
+	if err != nil {
+		return entity, err
+	}
+	return r.FindByID(string(id))
+}
+
+func (r *Repository[T]) indexNode(name string, key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return path.Join(r.root+indexRootSuffix, name, hex.EncodeToString(hash[:]))
+}
+
+func (r *Repository[T]) actualPath(nodeName string) string {
+	return path.Join(r.framework.Namespace(), nodeName)
+}
+
+func (r *Repository[T]) withDeadline() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), r.framework.OperationTimeout())
+}
+
+/*
+writeIndexed writes the entity and its index nodes in a multi operation, based on the keys of the stored entity.
+*/
+func (r *Repository[T]) writeIndexed(id string, nodeName string, entity T, data []byte) (int32, error) {
+	for attempt := 1; ; attempt++ {
+		ops, err := r.indexedWriteOps(id, nodeName, entity, data)
+		if err != nil {
+			return 0, err
+		}
+
+		ctx, cancel := r.withDeadline()
+		responses, err := operation.Executor(r.framework).Multi(ctx, ops...)
+		cancel()
+		failed := slices.IndexFunc(responses, func(response zk.MultiResponse) bool { return response.Error != nil })
+		if failed < 0 {
+			if err != nil || responses[0].Stat == nil {
+				return 0, err
+			}
+			return responses[0].Stat.Version, nil
+		}
+		err = responses[failed].Error
+
+		switch op := ops[failed].(type) {
+		case *zk.CreateRequest:
+			if failed > 0 && errors.Is(err, zk.ErrNodeExists) {
+				return 0, fmt.Errorf("%w: %s", repoerr.ErrDuplicateIndexKey, path.Base(path.Dir(op.Path)))
+			}
+			if failed > 0 && errors.Is(err, zk.ErrNoNode) {
+				if err := r.createParent(op.Path); err != nil {
+					return 0, err
+				}
+				continue
+			}
+		}
+
+		concurrentChange := errors.Is(err, zk.ErrBadVersion) || errors.Is(err, zk.ErrNodeExists) || errors.Is(err, zk.ErrNoNode)
+		if concurrentChange && failed == 0 && r.optimisticLocking {
+			return 0, repoerr.ErrVersionConflict
+		}
+		if !concurrentChange || attempt == indexAttempts {
+			return 0, err
+		}
+	}
+}
+
+func (r *Repository[T]) indexedWriteOps(id string, nodeName string, entity T, data []byte) ([]any, error) {
+	storedData, stat, err := operation.GetWithStat(r.framework, nodeName)
+	exists := err == nil
+	if err != nil && !errors.Is(err, zk.ErrNoNode) {
+		return nil, err
+	}
+
+	if r.optimisticLocking {
+		r.versionsMu.Lock()
+		version, known := r.versions[id]
+		r.versionsMu.Unlock()
+		if known != exists || (known && version != stat.Version) {
+			return nil, repoerr.ErrVersionConflict
+		}
+	}
+
+	actualPath := r.actualPath(nodeName)
+	ops := []any{}
+	storedKeys := map[string]string{}
+	if exists {
+		ops = append(ops, &zk.SetDataRequest{Path: actualPath, Data: data, Version: stat.Version})
+		if stored, err := r.codec.Decode(storedData); err == nil {
+			for _, idx := range r.indexes {
+				storedKeys[idx.name] = idx.key(stored)
+			}
+		}
+	} else {
+		if err := r.createParent(actualPath); err != nil {
+			return nil, err
+		}
+		ops = append(ops, &zk.CreateRequest{Path: actualPath, Data: data, Acl: nodeacl.DefaultFor(r.framework.ACLProvider(), actualPath)})
+	}
+
+	for _, idx := range r.indexes {
+		key, storedKey := idx.key(entity), storedKeys[idx.name]
+		if key == storedKey {
+			continue
+		}
+		if storedKey != "" {
+			deleteOp, err := r.deleteIndexOp(id, idx.name, storedKey)
+			if err != nil {
+				return nil, err
+			}
+			if deleteOp != nil {
+				ops = append(ops, deleteOp)
+			}
+		}
+		if key != "" {
+			indexPath := r.actualPath(r.indexNode(idx.name, key))
+			ops = append(ops, &zk.CreateRequest{Path: indexPath, Data: []byte(id), Acl: nodeacl.DefaultFor(r.framework.ACLProvider(), indexPath)})
+		}
+	}
+	return ops, nil
+}
+
+/*
+deleteIndexed deletes the entity and its index nodes in a multi operation.
+*/
+func (r *Repository[T]) deleteIndexed(id string, nodeName string) error {
+	for attempt := 1; ; attempt++ {
+		storedData, stat, err := operation.GetWithStat(r.framework, nodeName)
+		if errors.Is(err, zk.ErrNoNode) {
+			return repoerr.ErrEntityNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		ops := []any{&zk.DeleteRequest{Path: r.actualPath(nodeName), Version: stat.Version}}
+		if stored, err := r.codec.Decode(storedData); err == nil {
+			for _, idx := range r.indexes {
+				if key := idx.key(stored); key != "" {
+					deleteOp, err := r.deleteIndexOp(id, idx.name, key)
+					if err != nil {
+						return err
+					}
+					if deleteOp != nil {
+						ops = append(ops, deleteOp)
+					}
+				}
+			}
+		}
+
+		ctx, cancel := r.withDeadline()
+		responses, err := operation.Executor(r.framework).Multi(ctx, ops...)
+		cancel()
+		failed := slices.IndexFunc(responses, func(response zk.MultiResponse) bool { return response.Error != nil })
+		if failed < 0 {
+			return err
+		}
+		err = responses[failed].Error
+		if !(errors.Is(err, zk.ErrBadVersion) || errors.Is(err, zk.ErrNoNode)) || attempt == indexAttempts {
+			return err
+		}
+	}
+}
+
+/*
+deleteIndexOp returns the deletion of the index node of the key, nil when the node does not map the key to the entity, e.g. it was saved before the index was added.
+*/
+func (r *Repository[T]) deleteIndexOp(id string, name string, key string) (*zk.DeleteRequest, error) {
+	indexNode := r.indexNode(name, key)
+	indexed, stat, err := operation.GetWithStat(r.framework, indexNode)
+	if errors.Is(err, zk.ErrNoNode) || (err == nil && string(indexed) != id) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &zk.DeleteRequest{Path: r.actualPath(indexNode), Version: stat.Version}, nil
+}
+
+func (r *Repository[T]) createParent(actualPath string) error {
+	parent := path.Dir(actualPath)
+	ctx, cancel := r.withDeadline()
+	_, err := operation.Executor(r.framework).Create(ctx, parent, []byte{}, 0, nodeacl.DefaultFor(r.framework.ACLProvider(), parent))
+	cancel()
+	if err != nil && !errors.Is(err, zk.ErrNodeExists) {
+		if !errors.Is(err, zk.ErrNoNode) {
+			return err
+		}
+		if err := r.createParent(parent); err != nil {
+			return err
+		}
+		return r.createParent(actualPath)
+	}
+	return nil
+}
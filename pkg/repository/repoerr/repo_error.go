@@ -0,0 +1,78 @@
+/*
+Package repoerr provides error types for the repository package.
+*/
+package repoerr
+
+import "errors"
+
+/*
+ErrEntityNotFound is returned when no entity is stored with the requested ID.
+*/
+var ErrEntityNotFound = errors.New("entity not found")
+
+/*
+ErrVersionConflict is returned when an entity was modified since it was read and optimistic locking is enabled.
+*/
+var ErrVersionConflict = errors.New("version conflict")
+
+/*
+ErrInvalidID is returned when an entity ID is empty, is . or .. or contains a path separator.
+*/
+var ErrInvalidID = errors.New("invalid entity ID")
+
+/*
+IsEntityNotFound checks if the error is ErrEntityNotFound.
+*/
+func IsEntityNotFound(err error) bool {
+	return err == ErrEntityNotFound
+}
+
+/*
+IsVersionConflict checks if the error is ErrVersionConflict.
+*/
+func IsVersionConflict(err error) bool {
+	return err == ErrVersionConflict
+}
+
+/*
+IsInvalidID checks if the error is ErrInvalidID.
+*/
+func IsInvalidID(err error) bool {
+	return err == ErrInvalidID
+}
+
+/*
+ErrInvalidIndex is returned when an index name is empty, is . or .., contains a path separator or is already used.
+*/
+var ErrInvalidIndex = errors.New("invalid index")
+
+/*
+ErrUnknownIndex is returned when looking up an index which was not added to the repository.
+*/
+var ErrUnknownIndex = errors.New("unknown index")
+
+/*
+ErrDuplicateIndexKey is returned when saving an entity whose index key is already mapped to another entity.
+*/
+var ErrDuplicateIndexKey = errors.New("duplicate index key")
+
+/*
+IsInvalidIndex checks if the error is ErrInvalidIndex.
+*/
+func IsInvalidIndex(err error) bool {
+	return err == ErrInvalidIndex
+}
+
+/*
+IsUnknownIndex checks if the error is ErrUnknownIndex.
+*/
+func IsUnknownIndex(err error) bool {
+	return err == ErrUnknownIndex
+}
+
+/*
+IsDuplicateIndexKey checks if the error is, or wraps, ErrDuplicateIndexKey.
+*/
+func IsDuplicateIndexKey(err error) bool {
+	return errors.Is(err, ErrDuplicateIndexKey)
+}
@@ -0,0 +1,93 @@
+package repoerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/repository/repoerr"
+)
+
+func TestIsEntityNotFound(t *testing.T) {
+	err := repoerr.ErrEntityNotFound
+	if !repoerr.IsEntityNotFound(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsEntityNotFoundFalse(t *testing.T) {
+	err := errors.New("some error")
+	if repoerr.IsEntityNotFound(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsVersionConflict(t *testing.T) {
+	err := repoerr.ErrVersionConflict
+	if !repoerr.IsVersionConflict(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsVersionConflictFalse(t *testing.T) {
+	err := errors.New("some error")
+	if repoerr.IsVersionConflict(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidID(t *testing.T) {
+	err := repoerr.ErrInvalidID
+	if !repoerr.IsInvalidID(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidIDFalse(t *testing.T) {
+	err := errors.New("some error")
+	if repoerr.IsInvalidID(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidIndex(t *testing.T) {
+	err := repoerr.ErrInvalidIndex
+	if !repoerr.IsInvalidIndex(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidIndexFalse(t *testing.T) {
+	err := errors.New("some error")
+	if repoerr.IsInvalidIndex(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsUnknownIndex(t *testing.T) {
+	err := repoerr.ErrUnknownIndex
+	if !repoerr.IsUnknownIndex(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsUnknownIndexFalse(t *testing.T) {
+	err := errors.New("some error")
+	if repoerr.IsUnknownIndex(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsDuplicateIndexKey(t *testing.T) {
+	err := fmt.Errorf("%w: by-email", repoerr.ErrDuplicateIndexKey)
+	if !repoerr.IsDuplicateIndexKey(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsDuplicateIndexKeyFalse(t *testing.T) {
+	err := errors.New("some error")
+	if repoerr.IsDuplicateIndexKey(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+/*
+RepositoryOptions is used to configure the repository.
+*/
+type RepositoryOptions struct {
+	// OptimisticLocking is a flag to reject saves of entities modified since they were last read.
+	OptimisticLocking bool
+	// CacheFindAll is a flag to keep the FindAll result in memory until the stored entities change.
+	CacheFindAll bool
+}
+
+/*
+RepositoryOptionsBuilder is a builder for RepositoryOptions.
+*/
+type RepositoryOptionsBuilder struct {
+	optimisticLocking bool
+	cacheFindAll      bool
+}
+
+/*
+NewRepositoryOptionsBuilder creates a new RepositoryOptionsBuilder.
+*/
+func NewRepositoryOptionsBuilder() RepositoryOptionsBuilder {
+	return RepositoryOptionsBuilder{}
+}
+
+/*
+WithOptimisticLocking sets the flag to reject saves of entities modified since they were last read.
+*/
+func (b RepositoryOptionsBuilder) WithOptimisticLocking(optimisticLocking bool) RepositoryOptionsBuilder {
+	b.optimisticLocking = optimisticLocking
+	return b
+}
+
+/*
+WithCacheFindAll sets the flag to keep the FindAll result in memory until the stored entities change.
+*/
+func (b RepositoryOptionsBuilder) WithCacheFindAll(cacheFindAll bool) RepositoryOptionsBuilder {
+	b.cacheFindAll = cacheFindAll
+	return b
+}
+
+/*
+Build builds the RepositoryOptions.
+*/
+func (b RepositoryOptionsBuilder) Build() RepositoryOptions {
+	return RepositoryOptions{
+		OptimisticLocking: b.optimisticLocking,
+		CacheFindAll:      b.cacheFindAll,
+	}
+}
@@ -0,0 +1,32 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/repository"
+)
+
+func TestDefaultRepositoryOptionsBuilder(t *testing.T) {
+	opts := repository.NewRepositoryOptionsBuilder().Build()
+
+	if opts.OptimisticLocking {
+		t.Errorf("Expected OptimisticLocking to be false, got true")
+	}
+	if opts.CacheFindAll {
+		t.Errorf("Expected CacheFindAll to be false, got true")
+	}
+}
+
+func TestRepositoryOptionsBuilder(t *testing.T) {
+	opts := repository.NewRepositoryOptionsBuilder().
+		WithOptimisticLocking(true).
+		WithCacheFindAll(true).
+		Build()
+
+	if !opts.OptimisticLocking {
+		t.Errorf("Expected OptimisticLocking to be true, got false")
+	}
+	if !opts.CacheFindAll {
+		t.Errorf("Expected CacheFindAll to be true, got false")
+	}
+}
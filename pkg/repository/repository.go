@@ -0,0 +1,368 @@
+/*
+Package repository provides a typed data access layer mapping entities onto the children of a node.
+*/
+package repository
+
+import (
+	"context"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/codec"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/repository/repoerr"
+)
+
+/*
+Repository stores entities of type T as children of a root node, one node per entity ID.
+*/
+type Repository[T any] struct {
+	framework         core.ZKFramework
+	root              string
+	codec             codec.Codec[T]
+	optimisticLocking bool
+	cacheFindAll      bool
+	indexes           []index[T]
+
+	versions   map[string]int32
+	versionsMu sync.Mutex
+
+	all         []T
+	entities    map[string]T
+	watched     map[string]bool
+	rootWatched bool
+	allMu       sync.Mutex
+}
+
+/*
+NewRepository creates a new repository rooted at the given node, using the default options.
+*/
+func NewRepository[T any](framework core.ZKFramework, root string, entityCodec codec.Codec[T]) *Repository[T] {
+	return NewRepositoryWithOptions(framework, root, entityCodec, NewRepositoryOptionsBuilder().Build())
+}
+
+/*
+NewRepositoryWithOptions creates a new repository rooted at the given node, specifying the repository options.
+*/
+func NewRepositoryWithOptions[T any](framework core.ZKFramework, root string, entityCodec codec.Codec[T], options RepositoryOptions) *Repository[T] {
+	return &Repository[T]{
+		framework:         framework,
+		root:              root,
+		codec:             entityCodec,
+		optimisticLocking: options.OptimisticLocking,
+		cacheFindAll:      options.CacheFindAll,
+		versions:          make(map[string]int32),
+		watched:           make(map[string]bool),
+	}
+}
+
+/*
+Save creates or updates the entity with the given ID.
+
+With optimistic locking enabled, an update only succeeds if the entity was not modified since it was last read or saved through this repository.
+*/
+func (r *Repository[T]) Save(id string, entity T) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	data, err := r.codec.Encode(entity)
+	if err != nil {
+		return err
+	}
+
+	nodeName := path.Join(r.root, id)
+	var version int32
+	if len(r.indexes) > 0 {
+		version, err = r.writeIndexed(id, nodeName, entity, data)
+	} else {
+		version, err = r.write(id, nodeName, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.trackVersion(id, version)
+	r.invalidate(id)
+	return nil
+}
+
+/*
+FindByID reads the entity with the given ID.
+*/
+func (r *Repository[T]) FindByID(id string) (T, error) {
+	var entity T
+	if err := validateID(id); err != nil {
+		return entity, err
+	}
+
+	data, stat, err := operation.GetWithStat(r.framework, path.Join(r.root, id))
+	if errors.Is(err, zk.ErrNoNode) {
+		return entity, repoerr.ErrEntityNotFound
+	}
+	if err != nil {
+		return entity, err
+	}
+
+	r.trackVersion(id, stat.Version)
+	return r.codec.Decode(data)
+}
+
+/*
+Delete deletes the entity with the given ID.
+*/
+func (r *Repository[T]) Delete(id string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+
+	var err error
+	if len(r.indexes) > 0 {
+		err = r.deleteIndexed(id, path.Join(r.root, id))
+	} else {
+		err = operation.Delete(r.framework, path.Join(r.root, id))
+	}
+	if coreerr.IsUnknownNode(err) {
+		return repoerr.ErrEntityNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	r.versionsMu.Lock()
+	delete(r.versions, id)
+	r.versionsMu.Unlock()
+	r.invalidate(id)
+	return nil
+}
+
+/*
+List lists the IDs of the stored entities.
+*/
+func (r *Repository[T]) List() ([]string, error) {
+	ids, err := operation.Ls(r.framework, r.root)
+	if errors.Is(err, zk.ErrNoNode) {
+		return []string{}, nil
+	}
+	return ids, err
+}
+
+/*
+FindAll reads all the stored entities.
+
+With the FindAll cache enabled, the result is kept in memory until a watch on the root node or on any entity fires, a missing root node being
+watched for its creation; the returned slice is a copy of the cached one. Rebuilding the result only reads again, and watches again, the entities
+whose watch fired or which were saved or deleted meanwhile.
+*/
+func (r *Repository[T]) FindAll() ([]T, error) {
+	if !r.cacheFindAll {
+		return r.loadAll()
+	}
+
+	r.allMu.Lock()
+	defer r.allMu.Unlock()
+
+	if r.all != nil {
+		return slices.Clone(r.all), nil
+	}
+
+	all, err := r.loadAllAndWatch()
+	if err != nil {
+		return nil, err
+	}
+	r.all = all
+	return slices.Clone(all), nil
+}
+
+func (r *Repository[T]) write(id string, nodeName string, data []byte) (int32, error) {
+	if r.optimisticLocking {
+		r.versionsMu.Lock()
+		version, known := r.versions[id]
+		r.versionsMu.Unlock()
+
+		if !known {
+			err := operation.CreateWithOptions(r.framework, nodeName, operation.NewCreateOptionsBuilder().WithData(data).Build())
+			if errors.Is(err, zk.ErrNodeExists) {
+				return 0, repoerr.ErrVersionConflict
+			}
+			return 0, err
+		}
+
+		newVersion, err := operation.UpdateWithVersion(r.framework, nodeName, data, version)
+		if errors.Is(err, zk.ErrBadVersion) || errors.Is(err, zk.ErrNoNode) {
+			return 0, repoerr.ErrVersionConflict
+		}
+		return newVersion, err
+	}
+
+	exists, err := operation.Exists(r.framework, nodeName)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, operation.CreateWithOptions(r.framework, nodeName, operation.NewCreateOptionsBuilder().WithData(data).Build())
+	}
+	return operation.Update(r.framework, nodeName, data)
+}
+
+func (r *Repository[T]) loadAll() ([]T, error) {
+	ids, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]T, 0, len(ids))
+	for _, id := range ids {
+		entity, err := r.FindByID(id)
+		if repoerr.IsEntityNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, entity)
+	}
+	return all, nil
+}
+
+/*
+loadAllAndWatch loads the entities, reusing the ones still cached and arming a watch only on the root node and on the entities not watched yet;
+it runs holding allMu.
+*/
+func (r *Repository[T]) loadAllAndWatch() ([]T, error) {
+	executor := operation.Executor(r.framework)
+	ctx, cancel := r.withDeadline()
+	defer cancel()
+	actualRoot := path.Join(r.framework.Namespace(), r.root)
+
+	ids, err := r.listAndWatch(ctx, executor, actualRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]T, 0, len(ids))
+	entities := make(map[string]T, len(ids))
+	for _, id := range ids {
+		entity, cached := r.entities[id]
+		if !cached {
+			var found bool
+			entity, found, err = r.loadAndWatch(ctx, executor, path.Join(actualRoot, id), id)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				continue
+			}
+		}
+		entities[id] = entity
+		all = append(all, entity)
+	}
+	r.entities = entities
+	return all, nil
+}
+
+func (r *Repository[T]) listAndWatch(ctx context.Context, executor core.Executor, actualRoot string) ([]string, error) {
+	if r.rootWatched {
+		ids, _, err := executor.Children(ctx, actualRoot)
+		if errors.Is(err, zk.ErrNoNode) {
+			return []string{}, nil
+		}
+		return ids, err
+	}
+
+	ids, _, rootEvents, err := executor.ChildrenW(ctx, actualRoot)
+	for errors.Is(err, zk.ErrNoNode) {
+		exists, _, existsEvents, existsErr := executor.ExistsW(ctx, actualRoot)
+		if existsErr != nil {
+			return nil, existsErr
+		}
+		if !exists {
+			r.rootWatched = true
+			go r.invalidateOn(existsEvents, "")
+			return []string{}, nil
+		}
+		ids, _, rootEvents, err = executor.ChildrenW(ctx, actualRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.rootWatched = true
+	go r.invalidateOn(rootEvents, "")
+	return ids, nil
+}
+
+func (r *Repository[T]) loadAndWatch(ctx context.Context, executor core.Executor, nodePath string, id string) (T, bool, error) {
+	var entity T
+	var data []byte
+	var err error
+	if r.watched[id] {
+		data, _, err = executor.Get(ctx, nodePath)
+	} else {
+		var entityEvents <-chan zk.Event
+		data, _, entityEvents, err = executor.GetW(ctx, nodePath)
+		if err == nil {
+			r.watched[id] = true
+			go r.invalidateOn(entityEvents, id)
+		}
+	}
+	if errors.Is(err, zk.ErrNoNode) {
+		return entity, false, nil
+	}
+	if err != nil {
+		return entity, false, err
+	}
+
+	entity, err = r.codec.Decode(data)
+	return entity, err == nil, err
+}
+
+/*
+invalidateOn invalidates the cached entities once the watch of the entity with the given ID, or of the root node when empty, fires.
+*/
+func (r *Repository[T]) invalidateOn(events <-chan zk.Event, id string) {
+	if e, ok := <-events; ok {
+		r.framework.Logger().Debug("repository invalidating cached entities", "root", r.root, "type", e.Type, "path", e.Path)
+	}
+
+	r.allMu.Lock()
+	defer r.allMu.Unlock()
+	if id == "" {
+		r.rootWatched = false
+	} else {
+		delete(r.watched, id)
+		delete(r.entities, id)
+	}
+	r.all = nil
+}
+
+/*
+invalidate invalidates the cached entities after the entity with the given ID was saved or deleted through the repository.
+*/
+func (r *Repository[T]) invalidate(id string) {
+	if !r.cacheFindAll {
+		return
+	}
+	r.allMu.Lock()
+	defer r.allMu.Unlock()
+	delete(r.entities, id)
+	r.all = nil
+}
+
+func (r *Repository[T]) trackVersion(id string, version int32) {
+	r.versionsMu.Lock()
+	defer r.versionsMu.Unlock()
+	r.versions[id] = version
+}
+
+func validateID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.Contains(id, "/") {
+		return repoerr.ErrInvalidID
+	}
+	return nil
+}
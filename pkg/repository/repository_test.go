@@ -0,0 +1,308 @@
+package repository_test
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/codec"
+	"github.com/morphy76/zk/pkg/repository"
+	"github.com/morphy76/zk/pkg/repository/repoerr"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+type user struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestRepository(t *testing.T) {
+
+	t.Run("Save, find and delete an entity", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		repo := repository.NewRepository(zkFramework, uuid.New().String(), codec.NewJSONCodec[user]())
+		entity := user{Name: "john", Email: "john@example.com"}
+
+		if err := repo.Save("john", entity); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		found, err := repo.FindByID("john")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if found != entity {
+			t.Errorf("expected %v, got %v", entity, found)
+		}
+
+		ids, err := repo.List()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(ids) != 1 || ids[0] != "john" {
+			t.Errorf("expected [john], got %v", ids)
+		}
+
+		if err := repo.Delete("john"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := repo.FindByID("john"); !repoerr.IsEntityNotFound(err) {
+			t.Errorf("expected error %v, got %v", repoerr.ErrEntityNotFound, err)
+		}
+	})
+
+	t.Run("Reject an invalid ID", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		repo := repository.NewRepository(zkFramework, uuid.New().String(), codec.NewJSONCodec[user]())
+		for _, id := range []string{"a/b", ".", ".."} {
+			if err := repo.Save(id, user{}); !repoerr.IsInvalidID(err) {
+				t.Errorf("expected error %v for %s, got %v", repoerr.ErrInvalidID, id, err)
+			}
+		}
+	})
+
+	t.Run("Optimistic locking detects concurrent updates", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		root := uuid.New().String()
+		opts := repository.NewRepositoryOptionsBuilder().WithOptimisticLocking(true).Build()
+		first := repository.NewRepositoryWithOptions(zkFramework, root, codec.NewJSONCodec[user](), opts)
+		second := repository.NewRepositoryWithOptions(zkFramework, root, codec.NewJSONCodec[user](), opts)
+
+		if err := first.Save("john", user{Name: "john"}); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := second.FindByID("john"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := first.Save("john", user{Name: "johnny"}); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := second.Save("john", user{Name: "jon"}); !repoerr.IsVersionConflict(err) {
+			t.Errorf("expected error %v, got %v", repoerr.ErrVersionConflict, err)
+		}
+	})
+
+	t.Run("FindAll with cache sees external changes", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		root := uuid.New().String()
+		opts := repository.NewRepositoryOptionsBuilder().WithCacheFindAll(true).Build()
+		cached := repository.NewRepositoryWithOptions(zkFramework, root, codec.NewJSONCodec[user](), opts)
+		other := repository.NewRepository(zkFramework, root, codec.NewJSONCodec[user]())
+
+		if err := cached.Save("john", user{Name: "john"}); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		all, err := cached.FindAll()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(all) != 1 {
+			t.Errorf("expected 1 entity, got %d", len(all))
+		}
+
+		if err := other.Save("jane", user{Name: "jane"}); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		for i := 0; i < 50 && len(all) != 2; i++ {
+			time.Sleep(100 * time.Millisecond)
+			all, err = cached.FindAll()
+			if err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+		if len(all) != 2 {
+			t.Errorf("expected 2 entities, got %d", len(all))
+		}
+	})
+
+	t.Run("FindAll with cache watches each entity once", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		root := uuid.New().String()
+		opts := repository.NewRepositoryOptionsBuilder().WithCacheFindAll(true).Build()
+		cached := repository.NewRepositoryWithOptions(zkFramework, root, codec.NewJSONCodec[user](), opts)
+		for i := 0; i < 20; i++ {
+			if err := cached.Save(fmt.Sprintf("user-%d", i), user{Name: "john"}); err != nil {
+				t.Fatalf(unexpectedErrorFmt, err)
+			}
+		}
+		if _, err := cached.FindAll(); err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+
+		before := runtime.NumGoroutine()
+		for i := 0; i < 20; i++ {
+			if err := cached.Save("user-0", user{Name: fmt.Sprintf("john-%d", i)}); err != nil {
+				t.Fatalf(unexpectedErrorFmt, err)
+			}
+			if _, err := cached.FindAll(); err != nil {
+				t.Fatalf(unexpectedErrorFmt, err)
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+		if after := runtime.NumGoroutine(); after > before+10 {
+			t.Errorf("expected the unchanged entities not to be watched again, got %d goroutines from %d", after, before)
+		}
+	})
+
+	t.Run("FindAll with cache on a missing root", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		root := uuid.New().String()
+		opts := repository.NewRepositoryOptionsBuilder().WithCacheFindAll(true).Build()
+		cached := repository.NewRepositoryWithOptions(zkFramework, root, codec.NewJSONCodec[user](), opts)
+		other := repository.NewRepository(zkFramework, root, codec.NewJSONCodec[user]())
+
+		all, err := cached.FindAll()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(all) != 0 {
+			t.Errorf("expected no entities, got %d", len(all))
+		}
+
+		if err := other.Save("jane", user{Name: "jane"}); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		for i := 0; i < 50 && len(all) != 1; i++ {
+			time.Sleep(100 * time.Millisecond)
+			all, err = cached.FindAll()
+			if err != nil {
+				t.Errorf(unexpectedErrorFmt, err)
+			}
+		}
+		if len(all) != 1 {
+			t.Fatalf("expected 1 entity, got %d", len(all))
+		}
+
+		all[0] = user{Name: "changed"}
+		if all, _ := cached.FindAll(); all[0].Name != "jane" {
+			t.Errorf("expected the cached entities to be unchanged, got %s", all[0].Name)
+		}
+	})
+
+	t.Run("Find entities by secondary index", func(t *testing.T) {
+		zkFramework, err := testutil.ConnectFramework()
+		if err != nil {
+			t.Fatalf(unexpectedErrorFmt, err)
+		}
+		defer zkFramework.Stop()
+
+		repo := repository.NewRepository(zkFramework, uuid.New().String(), codec.NewJSONCodec[user]())
+		if err := repo.AddIndex("by-email", func(u user) string { return u.Email }); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := repo.AddIndex("by-email", func(u user) string { return u.Email }); !repoerr.IsInvalidIndex(err) {
+			t.Errorf("expected error %v, got %v", repoerr.ErrInvalidIndex, err)
+		}
+		if err := repo.AddIndex("..", func(u user) string { return u.Email }); !repoerr.IsInvalidIndex(err) {
+			t.Errorf("expected error %v, got %v", repoerr.ErrInvalidIndex, err)
+		}
+
+		john := user{Name: "john", Email: "john@example.com"}
+		if err := repo.Save("john", john); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		found, err := repo.FindBy("by-email", "john@example.com")
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if found != john {
+			t.Errorf("expected %v, got %v", john, found)
+		}
+		if _, err := repo.FindBy("by-name", "john"); !repoerr.IsUnknownIndex(err) {
+			t.Errorf("expected error %v, got %v", repoerr.ErrUnknownIndex, err)
+		}
+
+		if err := repo.Save("jane", user{Name: "jane", Email: "john@example.com"}); !repoerr.IsDuplicateIndexKey(err) {
+			t.Errorf("expected error %v, got %v", repoerr.ErrDuplicateIndexKey, err)
+		}
+		if _, err := repo.FindByID("jane"); !repoerr.IsEntityNotFound(err) {
+			t.Errorf("expected the rejected entity not to be saved, got %v", err)
+		}
+
+		john.Email = "john@example.org"
+		if err := repo.Save("john", john); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := repo.FindBy("by-email", "john@example.com"); !repoerr.IsEntityNotFound(err) {
+			t.Errorf("expected the previous key to be unmapped, got %v", err)
+		}
+		if found, err := repo.FindBy("by-email", "john@example.org"); err != nil || found != john {
+			t.Errorf("expected %v, got %v, %v", john, found, err)
+		}
+
+		ids, err := repo.List()
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if len(ids) != 1 {
+			t.Errorf("expected the index nodes to be kept apart from the entities, got %v", ids)
+		}
+
+		if err := repo.Delete("john"); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if _, err := repo.FindBy("by-email", "john@example.org"); !repoerr.IsEntityNotFound(err) {
+			t.Errorf("expected the deleted entity to be unmapped, got %v", err)
+		}
+	})
+}
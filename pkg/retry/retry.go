@@ -0,0 +1,221 @@
+/*
+Package retry provides the policies deciding if and when a failed attempt is retried.
+*/
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+/*
+Policy decides the delay before each retry attempt.
+*/
+type Policy interface {
+	// NextDelay returns the delay before the given retry attempt, starting from 1, and false when no further attempt is allowed.
+	NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool)
+}
+
+/*
+ExponentialBackoff is a Policy doubling the delay at each attempt, retrying forever.
+*/
+type ExponentialBackoff struct {
+	// BaseDelay is the delay before the first retry attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, zero means no cap.
+	MaxDelay time.Duration
+}
+
+/*
+NewExponentialBackoff creates a new ExponentialBackoff policy.
+*/
+func NewExponentialBackoff(baseDelay time.Duration, maxDelay time.Duration) ExponentialBackoff {
+	return ExponentialBackoff{
+		BaseDelay: baseDelay,
+		MaxDelay:  maxDelay,
+	}
+}
+
+/*
+NextDelay returns BaseDelay * 2^(attempt-1), capped to MaxDelay.
+*/
+func (p ExponentialBackoff) NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < math.MaxInt64/2; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay, true
+}
+
+/*
+MaxAttempts is a Policy delegating the delays to another policy, giving up after a number of attempts.
+*/
+type MaxAttempts struct {
+	// Policy decides the delay of the allowed attempts.
+	Policy Policy
+	// Attempts is the maximum number of retry attempts.
+	Attempts int
+}
+
+/*
+NewMaxAttempts creates a new MaxAttempts policy.
+*/
+func NewMaxAttempts(policy Policy, attempts int) MaxAttempts {
+	return MaxAttempts{
+		Policy:   policy,
+		Attempts: attempts,
+	}
+}
+
+/*
+NextDelay returns the delay of the wrapped policy, or false once the attempts are exhausted.
+*/
+func (p MaxAttempts) NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	if attempt > p.Attempts {
+		return 0, false
+	}
+	return p.Policy.NextDelay(attempt, elapsed)
+}
+
+/*
+MaxDuration is a Policy delegating the delays to another policy, giving up once the attempts have been going on for a duration.
+*/
+type MaxDuration struct {
+	// Policy decides the delay of the allowed attempts.
+	Policy Policy
+	// Duration is the maximum time spent retrying, measured from the first failure.
+	Duration time.Duration
+}
+
+/*
+NewMaxDuration creates a new MaxDuration policy.
+*/
+func NewMaxDuration(policy Policy, duration time.Duration) MaxDuration {
+	return MaxDuration{
+		Policy:   policy,
+		Duration: duration,
+	}
+}
+
+/*
+NextDelay returns the delay of the wrapped policy, or false once the elapsed time reaches Duration.
+*/
+func (p MaxDuration) NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	if elapsed >= p.Duration {
+		return 0, false
+	}
+	return p.Policy.NextDelay(attempt, elapsed)
+}
+
+/*
+FixedDelay is a Policy waiting the same delay before each attempt, retrying forever.
+*/
+type FixedDelay struct {
+	// Delay is the delay before each retry attempt.
+	Delay time.Duration
+}
+
+/*
+NewRetryForever creates a FixedDelay policy, retrying forever after the given delay.
+*/
+func NewRetryForever(delay time.Duration) FixedDelay {
+	return FixedDelay{
+		Delay: delay,
+	}
+}
+
+/*
+NextDelay returns Delay.
+*/
+func (p FixedDelay) NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	return p.Delay, true
+}
+
+/*
+NewBoundedRetries creates a policy retrying up to the given number of attempts after a fixed delay.
+*/
+func NewBoundedRetries(attempts int, delay time.Duration) MaxAttempts {
+	return NewMaxAttempts(NewRetryForever(delay), attempts)
+}
+
+/*
+NewRetryUntilElapsed creates a policy retrying after a fixed delay until the attempts have been going on for the given duration.
+*/
+func NewRetryUntilElapsed(duration time.Duration, delay time.Duration) MaxDuration {
+	return NewMaxDuration(NewRetryForever(delay), duration)
+}
+
+/*
+JitterMode is the way Jitter randomizes the delays.
+*/
+type JitterMode int
+
+const (
+	// FullJitter waits a random delay between zero and the delay of the wrapped policy.
+	FullJitter JitterMode = iota
+	// EqualJitter waits half the delay of the wrapped policy plus a random delay up to the other half.
+	EqualJitter
+)
+
+/*
+Jitter is a Policy randomizing the delays of another policy, so that the clients sharing an outage do not retry all at once.
+*/
+type Jitter struct {
+	// Policy decides the delay being randomized and when to give up.
+	Policy Policy
+	// Mode is the way the delay is randomized.
+	Mode JitterMode
+}
+
+/*
+NewJitter creates a new Jitter policy.
+*/
+func NewJitter(policy Policy, mode JitterMode) Jitter {
+	return Jitter{
+		Policy: policy,
+		Mode:   mode,
+	}
+}
+
+/*
+NextDelay returns the delay of the wrapped policy randomized according to Mode, or false when the wrapped policy gives up.
+*/
+func (p Jitter) NextDelay(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	delay, ok := p.Policy.NextDelay(attempt, elapsed)
+	if !ok || delay <= 0 {
+		return delay, ok
+	}
+
+	switch p.Mode {
+	case EqualJitter:
+		half := delay / 2
+		return half + rand.N(delay-half+1), true
+	default:
+		return rand.N(delay + 1), true
+	}
+}
+
+/*
+Do runs the function until it succeeds, retrying the errors accepted by retryable as decided by the policy; nil retryable retries every error.
+
+The error of the last attempt is returned when the error is not retryable or the policy gives up.
+*/
+func Do(policy Policy, retryable func(err error) bool, run func() error) error {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		err := run()
+		if err == nil || (retryable != nil && !retryable(err)) {
+			return err
+		}
+
+		delay, ok := policy.NextDelay(attempt, time.Since(start))
+		if !ok {
+			return err
+		}
+		<-time.After(delay)
+	}
+}
@@ -0,0 +1,177 @@
+package retry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/retry"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	policy := retry.NewExponentialBackoff(100*time.Millisecond, 0)
+
+	for attempt, expected := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		4: 800 * time.Millisecond,
+	} {
+		delay, ok := policy.NextDelay(attempt, 0)
+		if !ok {
+			t.Errorf("expected attempt %d to be allowed", attempt)
+		}
+		if delay != expected {
+			t.Errorf("attempt %d: expected %v, got %v", attempt, expected, delay)
+		}
+	}
+}
+
+func TestExponentialBackoffMaxDelay(t *testing.T) {
+	policy := retry.NewExponentialBackoff(100*time.Millisecond, time.Second)
+
+	delay, ok := policy.NextDelay(100, 0)
+	if !ok {
+		t.Errorf("expected attempt to be allowed")
+	}
+	if delay != time.Second {
+		t.Errorf("expected %v, got %v", time.Second, delay)
+	}
+}
+
+func TestMaxAttempts(t *testing.T) {
+	policy := retry.NewMaxAttempts(retry.NewExponentialBackoff(100*time.Millisecond, 0), 2)
+
+	delay, ok := policy.NextDelay(2, 0)
+	if !ok {
+		t.Errorf("expected attempt to be allowed")
+	}
+	if delay != 200*time.Millisecond {
+		t.Errorf("expected %v, got %v", 200*time.Millisecond, delay)
+	}
+
+	if _, ok := policy.NextDelay(3, 0); ok {
+		t.Errorf("expected attempt to be refused")
+	}
+}
+
+func TestMaxDuration(t *testing.T) {
+	policy := retry.NewMaxDuration(retry.NewExponentialBackoff(100*time.Millisecond, 0), time.Second)
+
+	delay, ok := policy.NextDelay(3, 500*time.Millisecond)
+	if !ok {
+		t.Errorf("expected attempt to be allowed")
+	}
+	if delay != 400*time.Millisecond {
+		t.Errorf("expected %v, got %v", 400*time.Millisecond, delay)
+	}
+
+	if _, ok := policy.NextDelay(4, time.Second); ok {
+		t.Errorf("expected attempt to be refused")
+	}
+}
+
+func TestRetryForever(t *testing.T) {
+	policy := retry.NewRetryForever(100 * time.Millisecond)
+
+	delay, ok := policy.NextDelay(1000, time.Hour)
+	if !ok {
+		t.Errorf("expected attempt to be allowed")
+	}
+	if delay != 100*time.Millisecond {
+		t.Errorf("expected %v, got %v", 100*time.Millisecond, delay)
+	}
+}
+
+func TestBoundedRetries(t *testing.T) {
+	policy := retry.NewBoundedRetries(2, 100*time.Millisecond)
+
+	if delay, ok := policy.NextDelay(2, 0); !ok || delay != 100*time.Millisecond {
+		t.Errorf("expected %v, got %v, %v", 100*time.Millisecond, delay, ok)
+	}
+	if _, ok := policy.NextDelay(3, 0); ok {
+		t.Errorf("expected attempt to be refused")
+	}
+}
+
+func TestRetryUntilElapsed(t *testing.T) {
+	policy := retry.NewRetryUntilElapsed(time.Second, 100*time.Millisecond)
+
+	if delay, ok := policy.NextDelay(5, 500*time.Millisecond); !ok || delay != 100*time.Millisecond {
+		t.Errorf("expected %v, got %v, %v", 100*time.Millisecond, delay, ok)
+	}
+	if _, ok := policy.NextDelay(6, time.Second); ok {
+		t.Errorf("expected attempt to be refused")
+	}
+}
+
+func TestFullJitter(t *testing.T) {
+	policy := retry.NewJitter(retry.NewRetryForever(time.Second), retry.FullJitter)
+
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay, ok := policy.NextDelay(attempt, 0)
+		if !ok {
+			t.Errorf("expected attempt %d to be allowed", attempt)
+		}
+		if delay < 0 || delay > time.Second {
+			t.Errorf("attempt %d: expected a delay between 0 and %v, got %v", attempt, time.Second, delay)
+		}
+	}
+}
+
+func TestEqualJitter(t *testing.T) {
+	policy := retry.NewJitter(retry.NewRetryForever(time.Second), retry.EqualJitter)
+
+	for attempt := 1; attempt <= 100; attempt++ {
+		delay, ok := policy.NextDelay(attempt, 0)
+		if !ok {
+			t.Errorf("expected attempt %d to be allowed", attempt)
+		}
+		if delay < 500*time.Millisecond || delay > time.Second {
+			t.Errorf("attempt %d: expected a delay between %v and %v, got %v", attempt, 500*time.Millisecond, time.Second, delay)
+		}
+	}
+}
+
+func TestJitterGivesUp(t *testing.T) {
+	policy := retry.NewJitter(retry.NewBoundedRetries(1, time.Second), retry.FullJitter)
+
+	if _, ok := policy.NextDelay(2, 0); ok {
+		t.Errorf("expected the jitter to give up with the wrapped policy")
+	}
+}
+
+func TestDo(t *testing.T) {
+	transient := errors.New("transient")
+	fatal := errors.New("fatal")
+	retryable := func(err error) bool { return err == transient }
+
+	attempts := 0
+	err := retry.Do(retry.NewRetryForever(time.Millisecond), retryable, func() error {
+		attempts++
+		if attempts < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("expected success after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	attempts = 0
+	err = retry.Do(retry.NewRetryForever(time.Millisecond), retryable, func() error {
+		attempts++
+		return fatal
+	})
+	if err != fatal || attempts != 1 {
+		t.Errorf("expected %v after 1 attempt, got %v after %d", fatal, err, attempts)
+	}
+
+	attempts = 0
+	err = retry.Do(retry.NewBoundedRetries(2, time.Millisecond), nil, func() error {
+		attempts++
+		return transient
+	})
+	if err != transient || attempts != 3 {
+		t.Errorf("expected %v after 3 attempts, got %v after %d", transient, err, attempts)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Package script executes zkCli.sh-style command scripts through the framework.
+
+Supported commands are:
+
+	create [-e] [-s] [-c] path [data]
+	set path data
+	get path
+	ls path
+	delete path
+	deleteall path
+
+Empty lines and lines starting with '#' are ignored, data can be quoted with single or double quotes.
+*/
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/script/scripterr"
+)
+
+/*
+Command represents a parsed script line.
+*/
+type Command struct {
+	// Line is the line number of the command in the script, starting from 1.
+	Line int
+	// Name is the name of the command, e.g. create or set.
+	Name string
+	// Flags are the flags passed to the command, e.g. -e or -s.
+	Flags []string
+	// Args are the positional arguments of the command.
+	Args []string
+}
+
+type commandSpec struct {
+	flags   []string
+	minArgs int
+	maxArgs int
+}
+
+var commandSpecs = map[string]commandSpec{
+	"create":    {flags: []string{"-e", "-s", "-c"}, minArgs: 1, maxArgs: 2},
+	"set":       {minArgs: 2, maxArgs: 2},
+	"get":       {minArgs: 1, maxArgs: 1},
+	"ls":        {minArgs: 1, maxArgs: 1},
+	"delete":    {minArgs: 1, maxArgs: 1},
+	"deleteall": {minArgs: 1, maxArgs: 1},
+}
+
+/*
+Parse parses a script, validating every line before anything is executed.
+*/
+func Parse(in io.Reader) ([]Command, error) {
+	commands := []Command{}
+	scanner := bufio.NewScanner(in)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tokens, err := tokenize(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		command, err := parseCommand(lineNumber, tokens)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+/*
+Execute parses and runs a script, writing command output to out. Execution stops at the first failing command.
+*/
+func Execute(zkFramework core.ZKFramework, in io.Reader, out io.Writer) error {
+	commands, err := Parse(in)
+	if err != nil {
+		return err
+	}
+
+	for _, command := range commands {
+		if err := run(zkFramework, command, out); err != nil {
+			return fmt.Errorf("line %d: %s: %w", command.Line, command.Name, err)
+		}
+	}
+	return nil
+}
+
+func parseCommand(lineNumber int, tokens []string) (Command, error) {
+	command := Command{
+		Line:  lineNumber,
+		Name:  tokens[0],
+		Flags: []string{},
+		Args:  []string{},
+	}
+
+	spec, ok := commandSpecs[command.Name]
+	if !ok {
+		return command, fmt.Errorf("line %d: %s: %w", lineNumber, command.Name, scripterr.ErrUnknownCommand)
+	}
+
+	for _, token := range tokens[1:] {
+		if len(command.Args) == 0 && strings.HasPrefix(token, "-") {
+			if !slices.Contains(spec.flags, token) {
+				return command, fmt.Errorf("line %d: %s: unsupported flag %s: %w", lineNumber, command.Name, token, scripterr.ErrInvalidArguments)
+			}
+			command.Flags = append(command.Flags, token)
+			continue
+		}
+		command.Args = append(command.Args, token)
+	}
+
+	if len(command.Args) < spec.minArgs || len(command.Args) > spec.maxArgs {
+		return command, fmt.Errorf("line %d: %s: %w", lineNumber, command.Name, scripterr.ErrInvalidArguments)
+	}
+	return command, nil
+}
+
+func run(zkFramework core.ZKFramework, command Command, out io.Writer) error {
+	nodeName := command.Args[0]
+
+	switch command.Name {
+	case "create":
+		builder := operation.NewCreateOptionsBuilder().WithMode(createMode(command.Flags))
+		if len(command.Args) > 1 {
+			builder = builder.WithData([]byte(command.Args[1]))
+		}
+		created, err := operation.CreateAndGetPath(zkFramework, nodeName, builder.Build())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Created %s\n", path.Join(path.Dir(nodeName), path.Base(created)))
+	case "set":
+		if _, err := operation.Update(zkFramework, nodeName, []byte(command.Args[1])); err != nil {
+			return err
+		}
+	case "get":
+		data, err := operation.Get(zkFramework, nodeName)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s\n", data)
+	case "ls":
+		children, err := operation.Ls(zkFramework, nodeName)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "[%s]\n", strings.Join(children, ", "))
+	case "delete":
+		return operation.Delete(zkFramework, nodeName)
+	case "deleteall":
+		return deleteAll(zkFramework, nodeName)
+	}
+	return nil
+}
+
+func createMode(flags []string) int32 {
+	mode := int32(0)
+	for _, flag := range flags {
+		switch flag {
+		case "-e":
+			mode |= zk.FlagEphemeral
+		case "-s":
+			mode |= zk.FlagSequence
+		case "-c":
+			mode = zk.FlagContainer
+		}
+	}
+	return mode
+}
+
+func deleteAll(zkFramework core.ZKFramework, nodeName string) error {
+	children, err := operation.Ls(zkFramework, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if err := deleteAll(zkFramework, path.Join(nodeName, child)); err != nil {
+			return err
+		}
+	}
+	return operation.Delete(zkFramework, nodeName)
+}
+
+func tokenize(line string) ([]string, error) {
+	tokens := []string{}
+	var current strings.Builder
+	var quote rune
+	inToken := false
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t':
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, scripterr.ErrUnterminatedQuote
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
@@ -0,0 +1,151 @@
+package script_test
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/script"
+	"github.com/morphy76/zk/pkg/script/scripterr"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestParse(t *testing.T) {
+	in := strings.NewReader(`
+# a comment
+create -e /a "some data"
+set /a 'other data'
+ls /
+`)
+	commands, err := script.Parse(in)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if len(commands) != 3 {
+		t.Fatalf("expected 3 commands, got %d", len(commands))
+	}
+	if commands[0].Line != 3 || commands[0].Name != "create" {
+		t.Errorf("unexpected command %v", commands[0])
+	}
+	if len(commands[0].Flags) != 1 || commands[0].Flags[0] != "-e" {
+		t.Errorf("expected flag -e, got %v", commands[0].Flags)
+	}
+	if commands[0].Args[1] != "some data" {
+		t.Errorf("expected data to be %s, got %s", "some data", commands[0].Args[1])
+	}
+	if commands[1].Args[1] != "other data" {
+		t.Errorf("expected data to be %s, got %s", "other data", commands[1].Args[1])
+	}
+}
+
+func TestParseUnknownCommand(t *testing.T) {
+	_, err := script.Parse(strings.NewReader("rmdir /a"))
+	if !scripterr.IsUnknownCommand(err) {
+		t.Errorf("expected error %v, got %v", scripterr.ErrUnknownCommand, err)
+	}
+}
+
+func TestParseInvalidArguments(t *testing.T) {
+	_, err := script.Parse(strings.NewReader("set /a"))
+	if !scripterr.IsInvalidArguments(err) {
+		t.Errorf("expected error %v, got %v", scripterr.ErrInvalidArguments, err)
+	}
+}
+
+func TestParseUnterminatedQuote(t *testing.T) {
+	_, err := script.Parse(strings.NewReader(`create /a "data`))
+	if !scripterr.IsUnterminatedQuote(err) {
+		t.Errorf("expected error %v, got %v", scripterr.ErrUnterminatedQuote, err)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := "/" + uuid.New().String()
+	child := path.Join(root, "child")
+	in := strings.NewReader(strings.Join([]string{
+		"create " + root,
+		"create " + child + " first",
+		"set " + child + " second",
+		"get " + child,
+		"ls " + root,
+		"deleteall " + root,
+	}, "\n"))
+	out := &bytes.Buffer{}
+
+	if err := script.Execute(zkFramework, in, out); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	if !strings.Contains(out.String(), "second\n[child]\n") {
+		t.Errorf("unexpected output %s", out.String())
+	}
+
+	exists, err := operation.Exists(zkFramework, root)
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	if exists {
+		t.Errorf("expected node %s to be deleted", root)
+	}
+}
+
+func TestExecuteSequentialCreate(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := "/" + uuid.New().String()
+	in := strings.NewReader(strings.Join([]string{
+		"create " + root,
+		"create -s " + root + "/item-",
+	}, "\n"))
+	out := &bytes.Buffer{}
+
+	if err := script.Execute(zkFramework, in, out); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	if !strings.Contains(out.String(), "Created "+root+"/item-0000000000\n") {
+		t.Errorf("expected the sequential node name in the output, got %s", out.String())
+	}
+}
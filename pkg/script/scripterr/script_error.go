@@ -0,0 +1,42 @@
+/*
+Package scripterr provides error types for the script package.
+*/
+package scripterr
+
+import "errors"
+
+/*
+ErrUnknownCommand is returned when a script line contains a command that is not supported.
+*/
+var ErrUnknownCommand = errors.New("unknown command")
+
+/*
+ErrInvalidArguments is returned when a script command is called with wrong arguments.
+*/
+var ErrInvalidArguments = errors.New("invalid arguments")
+
+/*
+ErrUnterminatedQuote is returned when a script line contains an unterminated quoted string.
+*/
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+/*
+IsUnknownCommand checks if the error is an unknown command error.
+*/
+func IsUnknownCommand(err error) bool {
+	return errors.Is(err, ErrUnknownCommand)
+}
+
+/*
+IsInvalidArguments checks if the error is an invalid arguments error.
+*/
+func IsInvalidArguments(err error) bool {
+	return errors.Is(err, ErrInvalidArguments)
+}
+
+/*
+IsUnterminatedQuote checks if the error is an unterminated quote error.
+*/
+func IsUnterminatedQuote(err error) bool {
+	return errors.Is(err, ErrUnterminatedQuote)
+}
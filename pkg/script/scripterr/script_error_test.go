@@ -0,0 +1,58 @@
+package scripterr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/script/scripterr"
+)
+
+func TestIsUnknownCommand(t *testing.T) {
+	err := scripterr.ErrUnknownCommand
+	if !scripterr.IsUnknownCommand(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsUnknownCommandWrapped(t *testing.T) {
+	err := fmt.Errorf("line 1: %w", scripterr.ErrUnknownCommand)
+	if !scripterr.IsUnknownCommand(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidArguments(t *testing.T) {
+	err := scripterr.ErrInvalidArguments
+	if !scripterr.IsInvalidArguments(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsUnterminatedQuote(t *testing.T) {
+	err := scripterr.ErrUnterminatedQuote
+	if !scripterr.IsUnterminatedQuote(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsUnknownCommandFalse(t *testing.T) {
+	err := errors.New("some error")
+	if scripterr.IsUnknownCommand(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsInvalidArgumentsFalse(t *testing.T) {
+	err := errors.New("some error")
+	if scripterr.IsInvalidArguments(err) {
+		t.Errorf("expected false, got true")
+	}
+}
+
+func TestIsUnterminatedQuoteFalse(t *testing.T) {
+	err := errors.New("some error")
+	if scripterr.IsUnterminatedQuote(err) {
+		t.Errorf("expected false, got true")
+	}
+}
@@ -0,0 +1,58 @@
+package sequencer
+
+/*
+SequencerOptions is used to configure the sequencer.
+*/
+type SequencerOptions struct {
+	// BlockSize is the number of IDs reserved with a single write of the counter node.
+	BlockSize uint64
+	// RefillThreshold is the number of IDs left in the current block triggering the reservation of the next one in background, zero reserves it only on exhaustion.
+	RefillThreshold uint64
+}
+
+/*
+SequencerOptionsBuilder is a builder for SequencerOptions.
+*/
+type SequencerOptionsBuilder struct {
+	blockSize       uint64
+	refillThreshold uint64
+}
+
+const (
+	defaultBlockSize = 100
+)
+
+/*
+NewSequencerOptionsBuilder creates a new SequencerOptionsBuilder.
+*/
+func NewSequencerOptionsBuilder() SequencerOptionsBuilder {
+	return SequencerOptionsBuilder{
+		blockSize: defaultBlockSize,
+	}
+}
+
+/*
+WithBlockSize sets the number of IDs reserved with a single write of the counter node.
+*/
+func (b SequencerOptionsBuilder) WithBlockSize(blockSize uint64) SequencerOptionsBuilder {
+	b.blockSize = blockSize
+	return b
+}
+
+/*
+WithRefillThreshold sets the number of IDs left in the current block triggering the reservation of the next one in background.
+*/
+func (b SequencerOptionsBuilder) WithRefillThreshold(refillThreshold uint64) SequencerOptionsBuilder {
+	b.refillThreshold = refillThreshold
+	return b
+}
+
+/*
+Build builds the SequencerOptions.
+*/
+func (b SequencerOptionsBuilder) Build() SequencerOptions {
+	return SequencerOptions{
+		BlockSize:       b.blockSize,
+		RefillThreshold: b.refillThreshold,
+	}
+}
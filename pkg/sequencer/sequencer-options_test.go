@@ -0,0 +1,32 @@
+package sequencer_test
+
+import (
+	"testing"
+
+	"github.com/morphy76/zk/pkg/sequencer"
+)
+
+func TestDefaultSequencerOptionsBuilder(t *testing.T) {
+	opts := sequencer.NewSequencerOptionsBuilder().Build()
+
+	if opts.BlockSize != 100 {
+		t.Errorf("Expected BlockSize to be 100, got %d", opts.BlockSize)
+	}
+	if opts.RefillThreshold != 0 {
+		t.Errorf("Expected RefillThreshold to be 0, got %d", opts.RefillThreshold)
+	}
+}
+
+func TestSequencerOptionsBuilder(t *testing.T) {
+	opts := sequencer.NewSequencerOptionsBuilder().
+		WithBlockSize(10).
+		WithRefillThreshold(2).
+		Build()
+
+	if opts.BlockSize != 10 {
+		t.Errorf("Expected BlockSize to be 10, got %d", opts.BlockSize)
+	}
+	if opts.RefillThreshold != 2 {
+		t.Errorf("Expected RefillThreshold to be 2, got %d", opts.RefillThreshold)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Package sequencer provides unique IDs reserved in blocks from a counter node.
+*/
+package sequencer
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/sequencer/sequencererr"
+)
+
+/*
+Sequencer hands out unique IDs from blocks reserved with a compare-and-set on a counter node, one write per block.
+
+IDs are unique across all the sequencers sharing the counter node and increasing for a single sequencer, but not contiguous:
+the unused IDs of a block are lost when the sequencer is discarded.
+*/
+type Sequencer struct {
+	framework   core.ZKFramework
+	counterNode string
+	options     SequencerOptions
+
+	next    uint64
+	end     uint64
+	pending chan block
+	lock    sync.Mutex
+}
+
+type block struct {
+	start uint64
+	err   error
+}
+
+/*
+NewSequencer creates a new sequencer on the given counter node, with the default options.
+*/
+func NewSequencer(zkFramework core.ZKFramework, counterNode string) *Sequencer {
+	return NewSequencerWithOptions(zkFramework, counterNode, NewSequencerOptionsBuilder().Build())
+}
+
+/*
+NewSequencerWithOptions creates a new sequencer on the given counter node.
+*/
+func NewSequencerWithOptions(zkFramework core.ZKFramework, counterNode string, options SequencerOptions) *Sequencer {
+	if options.BlockSize == 0 {
+		options.BlockSize = 1
+	}
+	return &Sequencer{
+		framework:   zkFramework,
+		counterNode: counterNode,
+		options:     options,
+	}
+}
+
+/*
+Next returns the next ID, reserving a new block when the current one is exhausted.
+*/
+func (s *Sequencer) Next() (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.next == s.end {
+		var reserved block
+		if s.pending != nil {
+			reserved = <-s.pending
+			s.pending = nil
+		} else {
+			reserved = s.reserve()
+		}
+		if reserved.err != nil {
+			return 0, reserved.err
+		}
+		s.next = reserved.start
+		s.end = reserved.start + s.options.BlockSize
+	}
+
+	id := s.next
+	s.next++
+
+	if s.options.RefillThreshold > 0 && s.end-s.next <= s.options.RefillThreshold && s.pending == nil {
+		s.pending = make(chan block, 1)
+		go func(pending chan block) {
+			pending <- s.reserve()
+		}(s.pending)
+	}
+	return id, nil
+}
+
+func (s *Sequencer) reserve() block {
+	exists, err := operation.Exists(s.framework, s.counterNode)
+	if err != nil {
+		return block{err: err}
+	}
+	if !exists {
+		options := operation.NewCreateOptionsBuilder().WithData([]byte("0")).Build()
+		if err := operation.CreateWithOptions(s.framework, s.counterNode, options); err != nil && err != zk.ErrNodeExists {
+			return block{err: err}
+		}
+	}
+
+	var start uint64
+	_, err = operation.UpdateTransactionally(s.framework, s.counterNode, func(old []byte) ([]byte, error) {
+		current, err := parseCounter(old)
+		if err != nil {
+			return nil, err
+		}
+		start = current
+		return []byte(strconv.FormatUint(current+s.options.BlockSize, 10)), nil
+	})
+	return block{start: start, err: err}
+}
+
+func parseCounter(data []byte) (uint64, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	counter, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", sequencererr.ErrInvalidCounter, err)
+	}
+	return counter, nil
+}
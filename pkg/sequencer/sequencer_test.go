@@ -0,0 +1,107 @@
+package sequencer_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/sequencer"
+	"github.com/morphy76/zk/pkg/sequencer/sequencererr"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestSequencer(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	counterNode := uuid.New().String()
+	options := sequencer.NewSequencerOptionsBuilder().WithBlockSize(5).Build()
+	prefetching := sequencer.NewSequencerOptionsBuilder().WithBlockSize(5).WithRefillThreshold(2).Build()
+	sequencers := []*sequencer.Sequencer{
+		sequencer.NewSequencerWithOptions(zkFramework, counterNode, options),
+		sequencer.NewSequencerWithOptions(zkFramework, counterNode, prefetching),
+	}
+
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		seen = make(map[uint64]bool)
+	)
+	for _, s := range sequencers {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(s *sequencer.Sequencer) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					id, err := s.Next()
+					if err != nil {
+						t.Errorf(unexpectedErrorFmt, err)
+						return
+					}
+					lock.Lock()
+					if seen[id] {
+						t.Errorf("duplicated id %d", id)
+					}
+					seen[id] = true
+					lock.Unlock()
+				}
+			}(s)
+		}
+	}
+	wg.Wait()
+
+	if len(seen) != 80 {
+		t.Errorf("expected 80 unique ids, got %d", len(seen))
+	}
+}
+
+func TestSequencerInvalidCounter(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	counterNode := uuid.New().String()
+	options := operation.NewCreateOptionsBuilder().WithData([]byte("not a number")).Build()
+	if err := operation.CreateWithOptions(zkFramework, counterNode, options); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	if _, err := sequencer.NewSequencer(zkFramework, counterNode).Next(); !sequencererr.IsInvalidCounter(err) {
+		t.Errorf("expected error %v, got %v", sequencererr.ErrInvalidCounter, err)
+	}
+}
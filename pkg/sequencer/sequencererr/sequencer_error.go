@@ -0,0 +1,18 @@
+/*
+Package sequencererr provides error types for the sequencer package.
+*/
+package sequencererr
+
+import "errors"
+
+/*
+ErrInvalidCounter is returned when the counter node does not hold a decimal unsigned integer.
+*/
+var ErrInvalidCounter = errors.New("invalid counter")
+
+/*
+IsInvalidCounter checks if the error is, or wraps, ErrInvalidCounter.
+*/
+func IsInvalidCounter(err error) bool {
+	return errors.Is(err, ErrInvalidCounter)
+}
@@ -0,0 +1,23 @@
+package sequencererr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/sequencer/sequencererr"
+)
+
+func TestIsInvalidCounter(t *testing.T) {
+	err := fmt.Errorf("%w: not a number", sequencererr.ErrInvalidCounter)
+	if !sequencererr.IsInvalidCounter(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsInvalidCounterFalse(t *testing.T) {
+	err := errors.New("some error")
+	if sequencererr.IsInvalidCounter(err) {
+		t.Errorf("expected false, got true")
+	}
+}
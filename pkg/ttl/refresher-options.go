@@ -0,0 +1,74 @@
+package ttl
+
+import "time"
+
+/*
+RefresherOptions is used to configure a TTL node refresher.
+*/
+type RefresherOptions struct {
+	// Interval is the delay between two refreshes, it must be shorter than the TTL of the nodes.
+	Interval time.Duration
+	// FailureThreshold is the number of consecutive failed refreshes of a node triggering the failure callbacks.
+	FailureThreshold int
+	// OnFailure are the callbacks invoked when the refreshes of a node keep failing.
+	OnFailure []func(nodeName string, err error)
+}
+
+/*
+RefresherOptionsBuilder is a builder for RefresherOptions.
+*/
+type RefresherOptionsBuilder struct {
+	interval         time.Duration
+	failureThreshold int
+	onFailure        []func(nodeName string, err error)
+}
+
+const (
+	defaultRefreshInterval  = 10 * time.Second
+	defaultFailureThreshold = 3
+)
+
+/*
+NewRefresherOptionsBuilder creates a new RefresherOptionsBuilder, refreshing every 10 seconds and alerting after 3 consecutive failures.
+*/
+func NewRefresherOptionsBuilder() RefresherOptionsBuilder {
+	return RefresherOptionsBuilder{
+		interval:         defaultRefreshInterval,
+		failureThreshold: defaultFailureThreshold,
+	}
+}
+
+/*
+WithInterval sets the delay between two refreshes.
+*/
+func (b RefresherOptionsBuilder) WithInterval(interval time.Duration) RefresherOptionsBuilder {
+	b.interval = interval
+	return b
+}
+
+/*
+WithFailureThreshold sets the number of consecutive failed refreshes of a node triggering the failure callbacks.
+*/
+func (b RefresherOptionsBuilder) WithFailureThreshold(failureThreshold int) RefresherOptionsBuilder {
+	b.failureThreshold = failureThreshold
+	return b
+}
+
+/*
+WithOnFailure registers a callback invoked when the refreshes of a node keep failing, with the last error.
+*/
+func (b RefresherOptionsBuilder) WithOnFailure(callback func(nodeName string, err error)) RefresherOptionsBuilder {
+	b.onFailure = append(b.onFailure, callback)
+	return b
+}
+
+/*
+Build builds the RefresherOptions.
+*/
+func (b RefresherOptionsBuilder) Build() RefresherOptions {
+	return RefresherOptions{
+		Interval:         b.interval,
+		FailureThreshold: b.failureThreshold,
+		OnFailure:        b.onFailure,
+	}
+}
@@ -0,0 +1,40 @@
+package ttl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/morphy76/zk/pkg/ttl"
+)
+
+func TestDefaultRefresherOptionsBuilder(t *testing.T) {
+	opts := ttl.NewRefresherOptionsBuilder().Build()
+
+	if opts.Interval != 10*time.Second {
+		t.Errorf("Expected Interval to be %v, got %v", 10*time.Second, opts.Interval)
+	}
+	if opts.FailureThreshold != 3 {
+		t.Errorf("Expected FailureThreshold to be 3, got %d", opts.FailureThreshold)
+	}
+	if len(opts.OnFailure) != 0 {
+		t.Errorf("Expected no OnFailure callback, got %d", len(opts.OnFailure))
+	}
+}
+
+func TestRefresherOptionsBuilder(t *testing.T) {
+	opts := ttl.NewRefresherOptionsBuilder().
+		WithInterval(time.Second).
+		WithFailureThreshold(5).
+		WithOnFailure(func(string, error) {}).
+		Build()
+
+	if opts.Interval != time.Second {
+		t.Errorf("Expected Interval to be %v, got %v", time.Second, opts.Interval)
+	}
+	if opts.FailureThreshold != 5 {
+		t.Errorf("Expected FailureThreshold to be 5, got %d", opts.FailureThreshold)
+	}
+	if len(opts.OnFailure) != 1 {
+		t.Errorf("Expected one OnFailure callback, got %d", len(opts.OnFailure))
+	}
+}
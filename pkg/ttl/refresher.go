@@ -0,0 +1,178 @@
+/*
+Package ttl keeps TTL nodes, used as soft heartbeats, alive by touching them periodically.
+*/
+package ttl
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/ttl/ttlerr"
+)
+
+/*
+Refresher touches the registered TTL nodes at every interval, setting their data again to bump their modification time,
+so that the server does not delete them while their owner is alive.
+*/
+type Refresher struct {
+	framework core.ZKFramework
+	options   RefresherOptions
+
+	failures map[string]int
+	stop     chan bool
+	running  bool
+	lock     sync.Mutex
+}
+
+/*
+NewRefresher creates a refresher, using the default options.
+*/
+func NewRefresher(zkFramework core.ZKFramework) *Refresher {
+	return NewRefresherWithOptions(zkFramework, NewRefresherOptionsBuilder().Build())
+}
+
+/*
+NewRefresherWithOptions creates a refresher, specifying the refresher options.
+*/
+func NewRefresherWithOptions(zkFramework core.ZKFramework, options RefresherOptions) *Refresher {
+	return &Refresher{
+		framework: zkFramework,
+		options:   options,
+		failures:  map[string]int{},
+	}
+}
+
+/*
+Register adds a node to the refreshed ones.
+*/
+func (r *Refresher) Register(nodeName string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.failures[nodeName]; !ok {
+		r.failures[nodeName] = 0
+	}
+}
+
+/*
+Unregister removes a node from the refreshed ones, letting it expire.
+*/
+func (r *Refresher) Unregister(nodeName string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.failures, nodeName)
+}
+
+/*
+Nodes returns the refreshed nodes, sorted by path.
+*/
+func (r *Refresher) Nodes() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return slices.Sorted(maps.Keys(r.failures))
+}
+
+/*
+Failures returns the number of consecutive failed refreshes of a node.
+*/
+func (r *Refresher) Failures(nodeName string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.failures[nodeName]
+}
+
+/*
+Start refreshes the nodes immediately and then at every interval, until stopped.
+*/
+func (r *Refresher) Start() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.running {
+		return ttlerr.ErrRefresherAlreadyStarted
+	}
+	r.running = true
+	r.stop = make(chan bool)
+
+	go r.schedule(r.stop)
+	return nil
+}
+
+/*
+Stop stops refreshing the nodes, a running refresh is completed.
+*/
+func (r *Refresher) Stop() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if !r.running {
+		return
+	}
+	r.running = false
+	close(r.stop)
+}
+
+/*
+RefreshOnce touches every registered node, invoking the failure callbacks for the nodes reaching the failure threshold.
+
+A node written concurrently by another client counts as refreshed, as its modification time was bumped anyway.
+*/
+func (r *Refresher) RefreshOnce() error {
+	errs := []error{}
+	for _, nodeName := range r.Nodes() {
+		err := r.touch(nodeName)
+		if err != nil {
+			r.framework.Logger().Error("refreshing node failed", "node", nodeName, "error", err)
+			errs = append(errs, err)
+		}
+
+		r.lock.Lock()
+		failures, registered := r.failures[nodeName]
+		if registered {
+			if err != nil {
+				failures++
+			} else {
+				failures = 0
+			}
+			r.failures[nodeName] = failures
+		}
+		r.lock.Unlock()
+
+		if registered && err != nil && failures == r.options.FailureThreshold {
+			for _, callback := range r.options.OnFailure {
+				callback(nodeName, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Refresher) touch(nodeName string) error {
+	data, stat, err := operation.GetWithStat(r.framework, nodeName)
+	if err != nil {
+		return err
+	}
+	if _, err := operation.UpdateWithVersion(r.framework, nodeName, data, stat.Version); err != nil && !errors.Is(err, zk.ErrBadVersion) {
+		return err
+	}
+	return nil
+}
+
+func (r *Refresher) schedule(stop chan bool) {
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.RefreshOnce()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
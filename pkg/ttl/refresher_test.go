@@ -0,0 +1,127 @@
+package ttl_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/ttl"
+	"github.com/morphy76/zk/pkg/ttl/ttlerr"
+)
+
+const (
+	zkHostEnv          = "ZK_HOST"
+	unexpectedErrorFmt = "unexpected error %v"
+)
+
+func TestMain(m *testing.M) {
+	zkC, ctx, err := testutil.StartTestServer()
+	if err != nil {
+		panic(err)
+	}
+	defer zkC.Terminate(ctx)
+
+	host, err := zkC.Host(ctx)
+	if err != nil {
+		panic(err)
+	}
+	mappedPort, err := zkC.MappedPort(ctx, "2181")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(zkHostEnv, host+":"+mappedPort.Port())
+
+	exitCode := m.Run()
+
+	os.Unsetenv(zkHostEnv)
+	os.Exit(exitCode)
+}
+
+func TestRefresher(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	t.Run("Refresh registered nodes", func(t *testing.T) {
+		t.Log("Refresh registered nodes")
+		nodeName := uuid.New().String()
+		if err := operation.CreateWithOptions(zkFramework, nodeName, operation.NewCreateOptionsBuilder().WithData([]byte("alive")).Build()); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		_, before, err := operation.GetWithStat(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+
+		refresher := ttl.NewRefresherWithOptions(zkFramework, ttl.NewRefresherOptionsBuilder().WithInterval(100*time.Millisecond).Build())
+		refresher.Register(nodeName)
+		if err := refresher.Start(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		defer refresher.Stop()
+		if err := refresher.Start(); !ttlerr.IsRefresherAlreadyStarted(err) {
+			t.Errorf("expected %v, got %v", ttlerr.ErrRefresherAlreadyStarted, err)
+		}
+
+		<-time.After(500 * time.Millisecond)
+		data, after, err := operation.GetWithStat(zkFramework, nodeName)
+		if err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if string(data) != "alive" {
+			t.Errorf("expected the data to be kept, got %s", data)
+		}
+		if after.Version <= before.Version || after.Mtime < before.Mtime {
+			t.Errorf("expected the node to be touched, version %d then %d", before.Version, after.Version)
+		}
+	})
+
+	t.Run("Alert on repeated failures", func(t *testing.T) {
+		t.Log("Alert on repeated failures")
+		nodeName := uuid.New().String()
+		alerts := 0
+		refresher := ttl.NewRefresherWithOptions(zkFramework, ttl.NewRefresherOptionsBuilder().
+			WithFailureThreshold(2).
+			WithOnFailure(func(failing string, err error) {
+				if failing != nodeName || err != zk.ErrNoNode {
+					t.Errorf("unexpected alert for %s: %v", failing, err)
+				}
+				alerts++
+			}).
+			Build())
+		refresher.Register(nodeName)
+
+		for range 3 {
+			if err := refresher.RefreshOnce(); err == nil {
+				t.Errorf("expected the refresh to fail")
+			}
+		}
+		if alerts != 1 {
+			t.Errorf("expected one alert, got %d", alerts)
+		}
+		if refresher.Failures(nodeName) != 3 {
+			t.Errorf("expected 3 failures, got %d", refresher.Failures(nodeName))
+		}
+
+		if err := operation.Create(zkFramework, nodeName); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if err := refresher.RefreshOnce(); err != nil {
+			t.Errorf(unexpectedErrorFmt, err)
+		}
+		if refresher.Failures(nodeName) != 0 {
+			t.Errorf("expected the failures to be reset, got %d", refresher.Failures(nodeName))
+		}
+
+		refresher.Unregister(nodeName)
+		if len(refresher.Nodes()) != 0 {
+			t.Errorf("expected no node, got %v", refresher.Nodes())
+		}
+	})
+}
@@ -0,0 +1,18 @@
+/*
+Package ttlerr provides error types for the ttl package.
+*/
+package ttlerr
+
+import "errors"
+
+/*
+ErrRefresherAlreadyStarted is returned when a refresher is started twice.
+*/
+var ErrRefresherAlreadyStarted = errors.New("refresher already started")
+
+/*
+IsRefresherAlreadyStarted checks if the error is ErrRefresherAlreadyStarted.
+*/
+func IsRefresherAlreadyStarted(err error) bool {
+	return err == ErrRefresherAlreadyStarted
+}
@@ -0,0 +1,22 @@
+package ttlerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/morphy76/zk/pkg/ttl/ttlerr"
+)
+
+func TestIsRefresherAlreadyStarted(t *testing.T) {
+	err := ttlerr.ErrRefresherAlreadyStarted
+	if !ttlerr.IsRefresherAlreadyStarted(err) {
+		t.Errorf("expected true, got false")
+	}
+}
+
+func TestIsRefresherAlreadyStartedFalse(t *testing.T) {
+	err := errors.New("some error")
+	if ttlerr.IsRefresherAlreadyStarted(err) {
+		t.Errorf("expected false, got true")
+	}
+}
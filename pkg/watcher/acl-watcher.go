@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+ACLEvent is a change of the ACL of a watched node.
+*/
+type ACLEvent struct {
+	// Path is the path of the node, relative to the namespace.
+	Path string
+	// ACL is the new ACL of the node.
+	ACL []zk.ACL
+	// Aversion is the new ACL version of the node.
+	Aversion int32
+}
+
+/*
+ACLWatcher detects the changes of the ACL of a node, notifying a listener.
+
+Zookeeper does not fire watches on ACL changes, so the watcher polls the stat of the node and gets the ACL only when its version changes.
+Changes happening between two polls are coalesced.
+*/
+type ACLWatcher struct {
+	framework  core.ZKFramework
+	actualPath string
+	interval   time.Duration
+	listener   func(event ACLEvent)
+
+	stopCh chan struct{}
+	lock   sync.Mutex
+}
+
+/*
+NewACLWatcher creates a watcher of the ACL of the node at the given path, polling its stat at the given interval.
+*/
+func NewACLWatcher(zkFramework core.ZKFramework, nodeName string, interval time.Duration, listener func(event ACLEvent)) *ACLWatcher {
+	return &ACLWatcher{
+		framework:  zkFramework,
+		actualPath: path.Join(zkFramework.Namespace(), nodeName),
+		interval:   interval,
+		listener:   listener,
+	}
+}
+
+/*
+Start reads the current ACL version of the node, which is not notified, and starts polling it.
+*/
+func (w *ACLWatcher) Start() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopCh != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.framework.OperationTimeout())
+	defer cancel()
+	exists, stat, err := operation.Executor(w.framework).Exists(ctx, w.actualPath)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return coreerr.ErrUnknownNode
+	}
+
+	w.stopCh = make(chan struct{})
+	go w.poll(stat.Aversion, w.stopCh)
+	return nil
+}
+
+/*
+Stop stops polling the node.
+*/
+func (w *ACLWatcher) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopCh != nil {
+		close(w.stopCh)
+		w.stopCh = nil
+	}
+}
+
+/*
+changedACL reads the ACL of the node when its version differs from the given one, logging the errors.
+*/
+func (w *ACLWatcher) changedACL(aversion int32) ([]zk.ACL, *zk.Stat, bool) {
+	executor := operation.Executor(w.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), w.framework.OperationTimeout())
+	defer cancel()
+
+	exists, stat, err := executor.Exists(ctx, w.actualPath)
+	if err != nil {
+		w.framework.Logger().Error("ACL watcher error polling", "path", w.actualPath, "error", err)
+		return nil, nil, false
+	}
+	if !exists || stat.Aversion == aversion {
+		return nil, nil, false
+	}
+
+	acl, stat, err := executor.GetACL(ctx, w.actualPath)
+	if err != nil {
+		w.framework.Logger().Error("ACL watcher error getting the ACL", "path", w.actualPath, "error", err)
+		return nil, nil, false
+	}
+	return acl, stat, true
+}
+
+func (w *ACLWatcher) poll(aversion int32, stopCh chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		acl, stat, changed := w.changedACL(aversion)
+		if !changed {
+			continue
+		}
+		aversion = stat.Aversion
+		w.listener(ACLEvent{
+			Path:     strings.TrimPrefix(strings.TrimPrefix(w.actualPath, w.framework.Namespace()), "/"),
+			ACL:      acl,
+			Aversion: stat.Aversion,
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package watcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/watcher"
+)
+
+func TestACLWatcher(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	nodeName := uuid.New().String()
+	if err := operation.Create(zkFramework, nodeName); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	events := make(chan watcher.ACLEvent, 4)
+	aclWatcher := watcher.NewACLWatcher(zkFramework, nodeName, 10*time.Millisecond, func(event watcher.ACLEvent) {
+		events <- event
+	})
+	if err := aclWatcher.Start(); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer aclWatcher.Stop()
+
+	if _, err := operation.Update(zkFramework, nodeName, []byte("data")); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("expected no event on data changes, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := operation.SetACL(zkFramework, nodeName, zk.WorldACL(zk.PermRead|zk.PermAdmin)); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	select {
+	case event := <-events:
+		if event.Path != nodeName || event.Aversion != 1 || len(event.ACL) != 1 || event.ACL[0].Perms != zk.PermRead|zk.PermAdmin {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected an ACL change on %s", nodeName)
+	}
+}
+
+func TestACLWatcherUnknownNode(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	aclWatcher := watcher.NewACLWatcher(zkFramework, uuid.New().String(), time.Second, func(watcher.ACLEvent) {})
+	if err := aclWatcher.Start(); !coreerr.IsUnknownNode(err) {
+		t.Errorf("expected error %v, got %v", coreerr.ErrUnknownNode, err)
+	}
+}
@@ -0,0 +1,177 @@
+package watcher
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+const predicateWatcherRetryDelay = time.Second
+
+/*
+DataPredicate decides whether a change of the data of a node is notified, previous is nil when the node is created and current is nil when it is deleted.
+*/
+type DataPredicate func(previous []byte, current []byte) bool
+
+/*
+JSONFieldsChanged returns a predicate accepting the changes of at least one of the given fields of a JSON object, nested fields are separated by dots.
+
+Payloads which are not JSON objects are treated as empty objects.
+*/
+func JSONFieldsChanged(fields ...string) DataPredicate {
+	return func(previous []byte, current []byte) bool {
+		var previousDocument, currentDocument map[string]any
+		_ = json.Unmarshal(previous, &previousDocument)
+		_ = json.Unmarshal(current, &currentDocument)
+
+		for _, field := range fields {
+			if !reflect.DeepEqual(lookupField(previousDocument, field), lookupField(currentDocument, field)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+/*
+PredicateWatcher keeps a one-shot watch armed on a node, notifying a listener only of the changes accepted by a predicate.
+
+The predicate is evaluated inside the watcher, so consumers are not woken by irrelevant updates. Changes happening between two watches are coalesced, the predicate sees the last notified and the current data.
+*/
+type PredicateWatcher struct {
+	framework  core.ZKFramework
+	actualPath string
+	predicate  DataPredicate
+	listener   func(event TreeEvent)
+
+	stopCh chan struct{}
+	lock   sync.Mutex
+}
+
+/*
+NewPredicateWatcher creates a watcher of the node at the given path.
+*/
+func NewPredicateWatcher(zkFramework core.ZKFramework, nodeName string, predicate DataPredicate, listener func(event TreeEvent)) *PredicateWatcher {
+	return &PredicateWatcher{
+		framework:  zkFramework,
+		actualPath: path.Join(zkFramework.Namespace(), nodeName),
+		predicate:  predicate,
+		listener:   listener,
+	}
+}
+
+/*
+Start reads the current data of the node, which is not notified, and starts watching it.
+*/
+func (w *PredicateWatcher) Start() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopCh != nil {
+		return nil
+	}
+
+	data, _, events, err := w.read()
+	if err != nil {
+		return err
+	}
+	w.stopCh = make(chan struct{})
+	go w.watch(data, events, w.stopCh)
+	return nil
+}
+
+/*
+Stop stops notifying changes, the watch already armed is discarded when it fires.
+*/
+func (w *PredicateWatcher) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopCh != nil {
+		close(w.stopCh)
+		w.stopCh = nil
+	}
+}
+
+func (w *PredicateWatcher) watch(previous []byte, events <-chan zk.Event, stopCh chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-events:
+		}
+
+		current, stat, nextEvents, err := w.read()
+		for err != nil {
+			w.framework.Logger().Error("predicate watcher error watching", "path", w.actualPath, "error", err)
+			select {
+			case <-stopCh:
+				return
+			case <-time.After(predicateWatcherRetryDelay):
+			}
+			current, stat, nextEvents, err = w.read()
+		}
+		events = nextEvents
+
+		if w.predicate(previous, current) {
+			w.notify(previous, current, stat)
+			previous = current
+		}
+	}
+}
+
+func (w *PredicateWatcher) read() ([]byte, *zk.Stat, <-chan zk.Event, error) {
+	executor := operation.Executor(w.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), w.framework.OperationTimeout())
+	defer cancel()
+	for {
+		data, stat, events, err := executor.GetW(ctx, w.actualPath)
+		if err == nil && data == nil {
+			data = []byte{}
+		}
+		if err != zk.ErrNoNode {
+			return data, stat, events, err
+		}
+
+		exists, _, events, err := executor.ExistsW(ctx, w.actualPath)
+		if err != nil || !exists {
+			return nil, nil, events, err
+		}
+	}
+}
+
+func (w *PredicateWatcher) notify(previous []byte, current []byte, stat *zk.Stat) {
+	eventType := TreeNodeUpdated
+	switch {
+	case stat == nil:
+		eventType = TreeNodeRemoved
+	case previous == nil:
+		eventType = TreeNodeAdded
+	}
+	w.listener(TreeEvent{
+		Type: eventType,
+		Path: strings.TrimPrefix(strings.TrimPrefix(w.actualPath, w.framework.Namespace()), "/"),
+		Data: current,
+		Stat: stat,
+	})
+}
+
+func lookupField(document map[string]any, field string) any {
+	var value any = document
+	for _, name := range strings.Split(field, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
@@ -0,0 +1,87 @@
+package watcher_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	testutil "github.com/morphy76/zk/internal/test_util"
+	"github.com/morphy76/zk/pkg/operation"
+	"github.com/morphy76/zk/pkg/watcher"
+)
+
+func TestJSONFieldsChanged(t *testing.T) {
+	predicate := watcher.JSONFieldsChanged("level", "db.host")
+
+	for _, tc := range []struct {
+		previous string
+		current  string
+		expected bool
+	}{
+		{`{"level": "info", "other": 1}`, `{"level": "info", "other": 2}`, false},
+		{`{"level": "info"}`, `{"level": "debug"}`, true},
+		{`{"db": {"host": "a", "port": 1}}`, `{"db": {"host": "a", "port": 2}}`, false},
+		{`{"db": {"host": "a"}}`, `{"db": {"host": "b"}}`, true},
+		{`not json`, `{"level": "info"}`, true},
+	} {
+		if actual := predicate([]byte(tc.previous), []byte(tc.current)); actual != tc.expected {
+			t.Errorf("expected %v from %s to %s, got %v", tc.expected, tc.previous, tc.current, actual)
+		}
+	}
+}
+
+func TestPredicateWatcher(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	nodeName := uuid.New().String()
+	options := operation.NewCreateOptionsBuilder().WithData([]byte(`{"level": "info", "counter": 0}`)).Build()
+	if err := operation.CreateWithOptions(zkFramework, nodeName, options); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+
+	events := make(chan watcher.TreeEvent, 16)
+	predicateWatcher := watcher.NewPredicateWatcher(zkFramework, nodeName, watcher.JSONFieldsChanged("level"), func(event watcher.TreeEvent) {
+		events <- event
+	})
+	if err := predicateWatcher.Start(); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	defer predicateWatcher.Stop()
+
+	if _, err := operation.Update(zkFramework, nodeName, []byte(`{"level": "info", "counter": 1}`)); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("expected no event, got %+v", event)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if _, err := operation.Update(zkFramework, nodeName, []byte(`{"level": "debug", "counter": 1}`)); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	select {
+	case event := <-events:
+		if event.Type != watcher.TreeNodeUpdated || event.Path != nodeName || string(event.Data) != `{"level": "debug", "counter": 1}` {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected an event on %s", nodeName)
+	}
+
+	if err := operation.Delete(zkFramework, nodeName); err != nil {
+		t.Fatalf(unexpectedErrorFmt, err)
+	}
+	select {
+	case event := <-events:
+		if event.Type != watcher.TreeNodeRemoved {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected a removal of %s", nodeName)
+	}
+}
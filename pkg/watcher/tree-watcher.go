@@ -0,0 +1,306 @@
+package watcher
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/google/uuid"
+	"github.com/morphy76/zk/pkg/core"
+	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
+)
+
+/*
+TreeEventType is the type of a change in a watched subtree.
+*/
+type TreeEventType int
+
+const (
+	// TreeNodeAdded is notified for every node found when the watch starts and for every node created afterwards.
+	TreeNodeAdded TreeEventType = iota
+	// TreeNodeUpdated is notified when the data of a node changes.
+	TreeNodeUpdated
+	// TreeNodeRemoved is notified when a node is deleted.
+	TreeNodeRemoved
+)
+
+/*
+TreeEvent is a change in a watched subtree.
+*/
+type TreeEvent struct {
+	// Type is the type of the change.
+	Type TreeEventType
+	// Path is the path of the changed node, relative to the namespace.
+	Path string
+	// Data is the data of the node, nil when the node is removed.
+	Data []byte
+	// Stat is the stat of the node, nil when the node is removed.
+	Stat *zk.Stat
+}
+
+/*
+TreeWatcher keeps one-shot watches armed on every node of a subtree, notifying a listener of the nodes added, updated and removed.
+
+The listener is called sequentially, in the order the changes are observed, and must not block. After a reconnection the subtree is scanned again and only the actual differences are notified.
+*/
+type TreeWatcher struct {
+	id        string
+	framework core.ZKFramework
+	root      string
+	listener  func(event TreeEvent)
+
+	nodes            map[string]int32
+	watchingData     map[string]bool
+	watchingChildren map[string]bool
+	running          bool
+	lock             sync.Mutex
+}
+
+/*
+NewTreeWatcher creates a watcher of the subtree at the given path.
+*/
+func NewTreeWatcher(zkFramework core.ZKFramework, root string, listener func(event TreeEvent)) *TreeWatcher {
+	return &TreeWatcher{
+		id:               uuid.New().String(),
+		framework:        zkFramework,
+		root:             path.Join(zkFramework.Namespace(), root),
+		listener:         listener,
+		nodes:            make(map[string]int32),
+		watchingData:     make(map[string]bool),
+		watchingChildren: make(map[string]bool),
+	}
+}
+
+/*
+UUID returns the identifier of the watcher as a status change listener.
+*/
+func (w *TreeWatcher) UUID() string {
+	return w.id
+}
+
+/*
+Priority makes the watcher rescan the subtree before the application listeners are notified of the reconnection.
+*/
+func (w *TreeWatcher) Priority() int {
+	return core.PriorityInternal
+}
+
+/*
+Start scans the subtree, notifying every node as added, and starts watching it.
+*/
+func (w *TreeWatcher) Start() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.framework.OperationTimeout())
+	defer cancel()
+	exists, _, err := operation.Executor(w.framework).Exists(ctx, w.root)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return coreerr.ErrUnknownNode
+	}
+
+	if err := w.framework.AddStatusChangeListener(w); err != nil {
+		return err
+	}
+	w.nodes = make(map[string]int32)
+	w.running = true
+	return w.syncNode(w.root, true)
+}
+
+/*
+Stop stops notifying changes, the watches already armed are discarded when they fire.
+*/
+func (w *TreeWatcher) Stop() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.running {
+		return
+	}
+	w.running = false
+	w.framework.RemoveStatusChangeListener(w)
+}
+
+/*
+OnStatusChange rescans the subtree when the connection is established again.
+*/
+func (w *TreeWatcher) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
+	if current != zk.StateHasSession {
+		return nil
+	}
+
+	go func() {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+
+		if !w.running {
+			return
+		}
+		w.framework.Logger().Info("tree watcher rescanning", "watcher", w.id, "path", w.root)
+		if err := w.syncNode(w.root, true); err != nil {
+			w.framework.Logger().Error("tree watcher error rescanning", "watcher", w.id, "path", w.root, "error", err)
+		}
+	}()
+	return nil
+}
+
+func (w *TreeWatcher) syncNode(actualPath string, full bool) error {
+	exists, err := w.syncData(actualPath)
+	if err != nil || !exists {
+		return err
+	}
+	return w.syncChildren(actualPath, full)
+}
+
+func (w *TreeWatcher) syncData(actualPath string) (bool, error) {
+	executor := operation.Executor(w.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), w.framework.OperationTimeout())
+	defer cancel()
+
+	var data []byte
+	var stat *zk.Stat
+	var err error
+	if w.watchingData[actualPath] {
+		data, stat, err = executor.Get(ctx, actualPath)
+	} else {
+		var events <-chan zk.Event
+		data, stat, events, err = executor.GetW(ctx, actualPath)
+		if err == nil {
+			w.watchingData[actualPath] = true
+			go w.onDataEvent(actualPath, events)
+		}
+	}
+	if err == zk.ErrNoNode {
+		w.removeNode(actualPath)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	version, known := w.nodes[actualPath]
+	w.nodes[actualPath] = stat.Version
+	if !known {
+		w.notify(TreeNodeAdded, actualPath, data, stat)
+	} else if version != stat.Version {
+		w.notify(TreeNodeUpdated, actualPath, data, stat)
+	}
+	return true, nil
+}
+
+func (w *TreeWatcher) syncChildren(actualPath string, full bool) error {
+	executor := operation.Executor(w.framework)
+	ctx, cancel := context.WithTimeout(context.Background(), w.framework.OperationTimeout())
+	defer cancel()
+
+	var children []string
+	var err error
+	if w.watchingChildren[actualPath] {
+		children, _, err = executor.Children(ctx, actualPath)
+	} else {
+		var events <-chan zk.Event
+		children, _, events, err = executor.ChildrenW(ctx, actualPath)
+		if err == nil {
+			w.watchingChildren[actualPath] = true
+			go w.onChildrenEvent(actualPath, events)
+		}
+	}
+	if err == zk.ErrNoNode {
+		w.removeNode(actualPath)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]bool, len(children))
+	for _, child := range children {
+		current[path.Join(actualPath, child)] = true
+	}
+	for known := range w.nodes {
+		if path.Dir(known) == actualPath && known != actualPath && !current[known] {
+			w.removeNode(known)
+		}
+	}
+
+	sort.Strings(children)
+	for _, child := range children {
+		childPath := path.Join(actualPath, child)
+		if _, known := w.nodes[childPath]; known && !full {
+			continue
+		}
+		if err := w.syncNode(childPath, full); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *TreeWatcher) removeNode(actualPath string) {
+	removed := []string{}
+	for known := range w.nodes {
+		if known == actualPath || strings.HasPrefix(known, actualPath+"/") {
+			removed = append(removed, known)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(removed)))
+
+	for _, known := range removed {
+		delete(w.nodes, known)
+		w.notify(TreeNodeRemoved, known, nil, nil)
+	}
+}
+
+func (w *TreeWatcher) onDataEvent(actualPath string, events <-chan zk.Event) {
+	e := <-events
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.watchingData, actualPath)
+	if !w.running {
+		return
+	}
+
+	switch e.Type {
+	case zk.EventNodeDeleted:
+		w.removeNode(actualPath)
+	case zk.EventNodeDataChanged:
+		if _, err := w.syncData(actualPath); err != nil {
+			w.framework.Logger().Error("tree watcher error syncing", "watcher", w.id, "path", actualPath, "error", err)
+		}
+	}
+}
+
+func (w *TreeWatcher) onChildrenEvent(actualPath string, events <-chan zk.Event) {
+	e := <-events
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.watchingChildren, actualPath)
+	if !w.running || e.Type != zk.EventNodeChildrenChanged {
+		return
+	}
+
+	if err := w.syncChildren(actualPath, false); err != nil {
+		w.framework.Logger().Error("tree watcher error syncing children", "watcher", w.id, "path", actualPath, "error", err)
+	}
+}
+
+func (w *TreeWatcher) notify(eventType TreeEventType, actualPath string, data []byte, stat *zk.Stat) {
+	relativePath := strings.TrimPrefix(strings.TrimPrefix(actualPath, w.framework.Namespace()), "/")
+	w.listener(TreeEvent{
+		Type: eventType,
+		Path: relativePath,
+		Data: data,
+		Stat: stat,
+	})
+}
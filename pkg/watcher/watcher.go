@@ -4,8 +4,9 @@ Package watcher provides a way to watch for changes in Zookeeper nodes.
 package watcher
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"path"
 	"slices"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/go-zookeeper/zk"
 	"github.com/morphy76/zk/pkg/core"
 	"github.com/morphy76/zk/pkg/core/coreerr"
+	"github.com/morphy76/zk/pkg/operation"
 )
 
 var watchListeners = make(map[string]*watchListener)
@@ -25,14 +27,19 @@ type watchListener struct {
 	types        []zk.EventType
 	watching     bool
 	disconnected bool
+	logger       *slog.Logger
 }
 
 func (w watchListener) UUID() string {
 	return w.ID
 }
 
+func (w watchListener) Priority() int {
+	return core.PriorityInternal
+}
+
 func (w *watchListener) OnShutdown(zkFramework core.ZKFramework) error {
-	log.Printf("Watcher %s: OnShutdown\n", w.ID)
+	w.logger.Debug("watcher shutdown", "watcher", w.ID)
 	if !w.watching {
 		return nil
 	}
@@ -41,15 +48,15 @@ func (w *watchListener) OnShutdown(zkFramework core.ZKFramework) error {
 }
 
 func (w *watchListener) OnStatusChange(zkFramework core.ZKFramework, previous zk.State, current zk.State) error {
-	log.Printf("Watcher %s: State change from %s to %s\n", w.ID, previous, current)
+	w.logger.Debug("watcher status change", "watcher", w.ID, "previous", previous, "current", current)
 	if w.watching {
 		if !w.disconnected && !zkFramework.Connected() {
-			log.Printf("Watcher %s: Connection lost\n", w.ID)
+			w.logger.Warn("watcher connection lost", "watcher", w.ID)
 			w.disconnected = true
 			w.shutdownCh <- true
 		}
 		if w.disconnected && zkFramework.Connected() {
-			log.Printf("Watcher %s: Connection established\n", w.ID)
+			w.logger.Info("watcher connection established", "watcher", w.ID)
 			w.Start(zkFramework)
 			w.disconnected = false
 		}
@@ -59,10 +66,12 @@ func (w *watchListener) OnStatusChange(zkFramework core.ZKFramework, previous zk
 }
 
 func (w *watchListener) Start(zkFramework core.ZKFramework) error {
-	log.Printf("Watcher %v: Start\n", w)
+	w.logger.Debug("watcher start", "watcher", w.ID, "path", w.path)
 
-	cn := zkFramework.Cn()
-	exists, _, out, err := cn.ExistsW(w.path)
+	executor := operation.Executor(zkFramework)
+	ctx, cancel := context.WithTimeout(context.Background(), zkFramework.OperationTimeout())
+	defer cancel()
+	exists, _, out, err := executor.ExistsW(ctx, w.path)
 	if !exists {
 		return coreerr.ErrUnknownNode
 	}
@@ -73,7 +82,7 @@ func (w *watchListener) Start(zkFramework core.ZKFramework) error {
 		for {
 			select {
 			case <-w.shutdownCh:
-				log.Printf("Watcher %s: Shutdown\n", w.ID)
+				w.logger.Debug("watcher stopped watching", "watcher", w.ID)
 				return
 			case e := <-out:
 				if slices.Contains(w.types, e.Type) {
@@ -89,7 +98,7 @@ func (w *watchListener) Start(zkFramework core.ZKFramework) error {
 }
 
 func (w *watchListener) Stop() {
-	log.Printf("Watcher %v: Stop\n", w)
+	w.logger.Debug("watcher stop", "watcher", w.ID, "path", w.path)
 	w.watching = false
 	w.shutdownCh <- true
 }
@@ -122,8 +131,9 @@ func Set(zkFramework core.ZKFramework, nodeName string, outChan chan zk.Event, t
 		outCh:      outChan,
 		path:       actualPath,
 		types:      types,
+		logger:     zkFramework.Logger(),
 	}
-	log.Printf("Set watcher listener at path %s for types %v with name %s\n", actualPath, types, watchListeners[id].UUID())
+	zkFramework.Logger().Debug("setting watcher", "path", actualPath, "types", types, "watcher", watchListeners[id].UUID())
 
 	if err := zkFramework.AddShutdownListener(watchListeners[id]); err != nil {
 		return err
@@ -162,11 +172,11 @@ func UnSet(zkFramework core.ZKFramework, nodeName string, types ...zk.EventType)
 	id := namePartsToID(nameParts)
 
 	watchListeners[id].Stop()
-	if err := zkFramework.RemoveShutdownListener(watchListeners[id]); err != nil {
-		log.Printf("Error removing shutdown listener: %s\n", err)
+	if err := zkFramework.RemoveShutdownListenerByID(id); err != nil {
+		zkFramework.Logger().Error("error removing shutdown listener", "error", err)
 	}
-	if err := zkFramework.RemoveStatusChangeListener(watchListeners[id]); err != nil {
-		log.Printf("Error removing status change listener: %s\n", err)
+	if err := zkFramework.RemoveStatusChangeListenerByID(id); err != nil {
+		zkFramework.Logger().Error("error removing status change listener", "error", err)
 	}
 	delete(watchListeners, id)
 	return nil
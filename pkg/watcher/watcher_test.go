@@ -3,6 +3,7 @@ package watcher_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/go-zookeeper/zk"
 	"github.com/google/uuid"
@@ -133,3 +134,67 @@ func TestZKWatcher(t *testing.T) {
 		}
 	})
 }
+
+func TestTreeWatcher(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	root := uuid.New().String()
+	if err := operation.Create(zkFramework, root+"/a"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+
+	events := make(chan watcher.TreeEvent, 16)
+	treeWatcher := watcher.NewTreeWatcher(zkFramework, root, func(event watcher.TreeEvent) {
+		events <- event
+	})
+	if err := treeWatcher.Start(); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer treeWatcher.Stop()
+
+	expect := func(eventType watcher.TreeEventType, nodeName string) {
+		select {
+		case event := <-events:
+			if event.Type != eventType || event.Path != nodeName {
+				t.Errorf("expected event %d on %s, got %d on %s", eventType, nodeName, event.Type, event.Path)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected event %d on %s", eventType, nodeName)
+		}
+	}
+
+	expect(watcher.TreeNodeAdded, root)
+	expect(watcher.TreeNodeAdded, root+"/a")
+
+	if err := operation.Create(zkFramework, root+"/a/b"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	expect(watcher.TreeNodeAdded, root+"/a/b")
+
+	if _, err := operation.Update(zkFramework, root+"/a/b", []byte("data")); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	expect(watcher.TreeNodeUpdated, root+"/a/b")
+
+	if err := operation.Delete(zkFramework, root+"/a/b"); err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	expect(watcher.TreeNodeRemoved, root+"/a/b")
+}
+
+func TestTreeWatcherUnknownRoot(t *testing.T) {
+	zkFramework, err := testutil.ConnectFramework()
+	if err != nil {
+		t.Errorf(unexpectedErrorFmt, err)
+	}
+	defer zkFramework.Stop()
+
+	treeWatcher := watcher.NewTreeWatcher(zkFramework, uuid.New().String(), func(watcher.TreeEvent) {})
+	if err := treeWatcher.Start(); !coreerr.IsUnknownNode(err) {
+		t.Errorf("expected error %v, got %v", coreerr.ErrUnknownNode, err)
+	}
+}